@@ -54,6 +54,8 @@ func createAddress(ctx echo.Context) error {
 		Phone:     req.Phone,
 		Email:     req.Email,
 		CountryID: req.CountryID,
+		StateID:   req.StateID,
+		CityID:    req.CityID,
 		UserID:    userID,
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
@@ -110,6 +112,8 @@ func updateAddress(ctx echo.Context) error {
 		Phone:     req.Phone,
 		Email:     req.Email,
 		CountryID: req.CountryID,
+		StateID:   req.StateID,
+		CityID:    req.CityID,
 		UserID:    userID,
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
@@ -7,6 +7,8 @@ import (
 	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/errors"
 	"github.com/shopicano/shopicano-backend/middlewares"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"github.com/shopicano/shopicano-backend/validators"
 	"net/http"
@@ -26,7 +28,7 @@ func RegisterCategoryRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.HasStore())
 		g.Use(middlewares.IsStoreActive())
-		g.Use(middlewares.IsStoreManager())
+		g.Use(middlewares.HasStorePermission(models.StorePermCatalogManage))
 		g.POST("/", createCategory)
 		g.DELETE("/:category_id/", deleteCategory)
 		g.PATCH("/:category_id/", updateCategory)
@@ -53,6 +55,9 @@ func createCategory(ctx echo.Context) error {
 	db := app.DB()
 
 	c.StoreID = storeID
+	if c.GlobalCategoryID != nil && *c.GlobalCategoryID == "" {
+		c.GlobalCategoryID = nil
+	}
 
 	cu := data.NewCategoryRepository()
 	if err := cu.Create(db, c); err != nil {
@@ -222,11 +227,13 @@ func updateCategory(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	db := app.DB()
+	db := app.DB().Begin()
 	cu := data.NewCategoryRepository()
 
 	c, err := cu.GetAsStoreOwner(db, storeID, categoryID)
 	if err != nil {
+		db.Rollback()
+
 		if errors.IsRecordNotFoundError(err) {
 			resp.Title = "Category not found"
 			resp.Status = http.StatusNotFound
@@ -242,6 +249,8 @@ func updateCategory(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	prevName := c.Name
+
 	if pld.Name != nil {
 		c.Name = *pld.Name
 	}
@@ -254,10 +263,39 @@ func updateCategory(ctx echo.Context) error {
 	if pld.IsPublished != nil {
 		c.IsPublished = *pld.IsPublished
 	}
+	if pld.GlobalCategoryID != nil {
+		if *pld.GlobalCategoryID == "" {
+			c.GlobalCategoryID = nil
+		} else {
+			c.GlobalCategoryID = pld.GlobalCategoryID
+		}
+	}
 
 	c.UpdatedAt = time.Now().UTC()
 
 	if err := cu.Update(db, c); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if c.Name != prevName {
+		if err := services.RecordEvent(db, models.EventCategoryRenamed, c.ID, nil); err != nil {
+			db.Rollback()
+
+			resp.Title = "Database query failed"
+			resp.Status = http.StatusInternalServerError
+			resp.Code = errors.DatabaseQueryFailed
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+	}
+
+	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
 		resp.Code = errors.DatabaseQueryFailed
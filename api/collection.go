@@ -8,6 +8,7 @@ import (
 	"github.com/shopicano/shopicano-backend/errors"
 	"github.com/shopicano/shopicano-backend/middlewares"
 	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"github.com/shopicano/shopicano-backend/validators"
 	"net/http"
@@ -28,7 +29,7 @@ func RegisterCollectionRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.HasStore())
 		g.Use(middlewares.IsStoreActive())
-		g.Use(middlewares.IsStoreManager())
+		g.Use(middlewares.HasStorePermission(models.StorePermCatalogManage))
 		g.POST("/", createCollection)
 		g.DELETE("/:collection_id/", deleteCollection)
 		g.PATCH("/:collection_id/", updateCollection)
@@ -227,11 +228,13 @@ func updateCollection(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	db := app.DB()
+	db := app.DB().Begin()
 	cu := data.NewCollectionRepository()
 
 	c, err := cu.GetAsStoreOwner(db, storeID, collectionID)
 	if err != nil {
+		db.Rollback()
+
 		if errors.IsRecordNotFoundError(err) {
 			resp.Title = "Collection not found"
 			resp.Status = http.StatusNotFound
@@ -247,6 +250,8 @@ func updateCollection(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	prevName := c.Name
+
 	if pld.Name != nil {
 		c.Name = *pld.Name
 	}
@@ -263,6 +268,40 @@ func updateCollection(ctx echo.Context) error {
 	c.UpdatedAt = time.Now().UTC()
 
 	if err := cu.Update(db, c); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if c.Name != prevName {
+		pu := data.NewProductRepository()
+		productIDs, err := pu.ListIDsByCollection(db, c.ID)
+		if err != nil {
+			db.Rollback()
+
+			resp.Title = "Database query failed"
+			resp.Status = http.StatusInternalServerError
+			resp.Code = errors.DatabaseQueryFailed
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		if err := services.RecordEvent(db, models.EventCollectionProductsChanged, c.ID, &models.ProductsChange{ProductIDs: productIDs}); err != nil {
+			db.Rollback()
+
+			resp.Title = "Database query failed"
+			resp.Status = http.StatusInternalServerError
+			resp.Code = errors.DatabaseQueryFailed
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+	}
+
+	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
 		resp.Code = errors.DatabaseQueryFailed
@@ -398,6 +437,16 @@ func addCollectionProducts(ctx echo.Context) error {
 		}
 	}
 
+	if err := services.RecordEvent(db, models.EventCollectionProductsChanged, c.ID, &models.ProductsChange{ProductIDs: pld.ProductIDs}); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
 	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
@@ -455,6 +504,16 @@ func removeCollectionProducts(ctx echo.Context) error {
 		}
 	}
 
+	if err := services.RecordEvent(db, models.EventCollectionProductsChanged, c.ID, &models.ProductsChange{ProductIDs: pld.ProductIDs}); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
 	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
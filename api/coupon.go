@@ -22,7 +22,7 @@ func RegisterCouponRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.HasStore())
 		g.Use(middlewares.IsStoreActive())
-		g.Use(middlewares.IsStoreManager())
+		g.Use(middlewares.HasStorePermission(models.StorePermCouponsManage))
 		g.POST("/", createCoupon)
 		g.PATCH("/:coupon_id/", updateCoupon)
 		g.DELETE("/:coupon_id/", deleteCoupon)
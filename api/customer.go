@@ -19,7 +19,7 @@ func RegisterCustomerRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.HasStore())
 		g.Use(middlewares.IsStoreActive())
-		g.Use(middlewares.IsStoreManager())
+		g.Use(middlewares.HasStorePermission(models.StorePermCustomersRead))
 		g.GET("/", listCustomers)
 	}(*customersPlatformPath)
 }
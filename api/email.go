@@ -0,0 +1,116 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"net/http"
+	"strconv"
+)
+
+func searchEmails(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	filter := &data.EmailFilter{
+		Query:   ctx.QueryParam("query"),
+		Status:  models.EmailStatus(ctx.QueryParam("status")),
+		UserID:  ctx.QueryParam("user_id"),
+		OrderID: ctx.QueryParam("order_id"),
+	}
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	eu := data.NewEmailRepository()
+	emails, err := eu.Search(db, filter, int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = emails
+	return resp.ServerJSON(ctx)
+}
+
+func getEmail(ctx echo.Context) error {
+	emailID := ctx.Param("email_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	eu := data.NewEmailRepository()
+	e, err := eu.Get(db, emailID)
+	if err != nil {
+		return serveEmailQueryFailed(ctx, err)
+	}
+
+	e.Attachments, err = eu.ListAttachments(db, e.ID)
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	e.Deliveries, err = eu.ListDeliveryLogs(db, e.ID)
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = e
+	return resp.ServerJSON(ctx)
+}
+
+func resendEmail(ctx echo.Context) error {
+	emailID := ctx.Param("email_id")
+
+	resp := core.Response{}
+
+	e, err := services.ResendEmail(emailID)
+	if e == nil {
+		return serveEmailQueryFailed(ctx, err)
+	}
+	if err != nil {
+		log.Log().Errorln(err)
+
+		resp.Title = "Failed to deliver email"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.EmailDeliveryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = e
+	return resp.ServerJSON(ctx)
+}
+
+func serveEmailQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Email not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.EmailNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	return serveDatabaseQueryFailed(ctx, err)
+}
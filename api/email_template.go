@@ -0,0 +1,333 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func createEmailTemplate(ctx echo.Context) error {
+	req, err := validators.ValidateEmailTemplate(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.EmailTemplateDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	et := &models.EmailTemplate{
+		ID:        utils.NewUUID(),
+		Name:      req.Name,
+		Locale:    req.Locale,
+		Subject:   req.Subject,
+		IsActive:  req.IsActive,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	db := app.DB().Begin()
+
+	etu := data.NewEmailTemplateRepository()
+	if err := etu.Create(db, et); err != nil {
+		db.Rollback()
+
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.EmailTemplateAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	// Minio stored bodies are written once the row exists, so a conflicting template never overwrites another
+	if err := services.NewEmailTemplateStorage().Write(et, req.Body); err != nil {
+		db.Rollback()
+		return serveEmailTemplateStorageFailed(ctx, err)
+	}
+
+	if err := etu.Update(db, et); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	et.Body = req.Body
+
+	resp.Status = http.StatusCreated
+	resp.Data = et
+	return resp.ServerJSON(ctx)
+}
+
+func updateEmailTemplate(ctx echo.Context) error {
+	etID := ctx.Param("et_id")
+
+	req, err := validators.ValidateEmailTemplate(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.EmailTemplateDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	etu := data.NewEmailTemplateRepository()
+	et, err := etu.Get(db, etID)
+	if err != nil {
+		db.Rollback()
+		return serveEmailTemplateQueryFailed(ctx, err)
+	}
+
+	if et.Name != req.Name || et.Locale != req.Locale {
+		db.Rollback()
+
+		resp.Title = "Name and locale of a template can't be changed"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.EmailTemplateDataInvalid
+		return resp.ServerJSON(ctx)
+	}
+
+	et.Subject = req.Subject
+	et.IsActive = req.IsActive
+	et.UpdatedAt = time.Now().UTC()
+
+	if err := services.NewEmailTemplateStorage().Write(et, req.Body); err != nil {
+		db.Rollback()
+		return serveEmailTemplateStorageFailed(ctx, err)
+	}
+
+	if err := etu.Update(db, et); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	et.Body = req.Body
+
+	resp.Status = http.StatusOK
+	resp.Data = et
+	return resp.ServerJSON(ctx)
+}
+
+func deleteEmailTemplate(ctx echo.Context) error {
+	etID := ctx.Param("et_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	etu := data.NewEmailTemplateRepository()
+	et, err := etu.Get(db, etID)
+	if err != nil {
+		return serveEmailTemplateQueryFailed(ctx, err)
+	}
+
+	if err := etu.Delete(db, et.ID); err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := services.NewEmailTemplateStorage().Delete(et); err != nil {
+		log.Log().Errorln(err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func getEmailTemplate(ctx echo.Context) error {
+	etID := ctx.Param("et_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	etu := data.NewEmailTemplateRepository()
+	et, err := etu.Get(db, etID)
+	if err != nil {
+		return serveEmailTemplateQueryFailed(ctx, err)
+	}
+
+	body, err := services.NewEmailTemplateStorage().Read(et)
+	if err != nil {
+		return serveEmailTemplateStorageFailed(ctx, err)
+	}
+	et.Body = body
+
+	resp.Status = http.StatusOK
+	resp.Data = et
+	return resp.ServerJSON(ctx)
+}
+
+func listEmailTemplates(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+	name := ctx.Request().URL.Query().Get("name")
+	locale := ctx.Request().URL.Query().Get("locale")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	etu := data.NewEmailTemplateRepository()
+	templates, err := etu.List(db, name, locale, int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = templates
+	return resp.ServerJSON(ctx)
+}
+
+func previewEmailTemplate(ctx echo.Context) error {
+	req, err := validators.ValidateEmailTemplatePreview(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.EmailTemplateDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	subject, body, err := services.PreviewEmail(req.Name, req.StoreID, req.Subject, req.Body)
+	if err != nil {
+		log.Log().Errorln(err)
+
+		resp.Title = "Failed to render email template"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.EmailTemplateRenderFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = map[string]interface{}{
+		"subject": subject,
+		"body":    body,
+	}
+	return resp.ServerJSON(ctx)
+}
+
+func getStoreEmailBranding(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	etu := data.NewEmailTemplateRepository()
+	b, err := etu.GetStoreBranding(db, storeID)
+	if err != nil {
+		if !errors.IsRecordNotFoundError(err) {
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+		b = &models.StoreEmailBranding{StoreID: storeID}
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = b
+	return resp.ServerJSON(ctx)
+}
+
+func updateStoreEmailBranding(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+
+	req, err := validators.ValidateStoreEmailBranding(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.EmailBrandingDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	b := &models.StoreEmailBranding{
+		StoreID:      storeID,
+		LogoUrl:      req.LogoUrl,
+		PrimaryColor: req.PrimaryColor,
+		AccentColor:  req.AccentColor,
+		FooterText:   req.FooterText,
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	db := app.DB()
+
+	etu := data.NewEmailTemplateRepository()
+	if err := etu.SaveStoreBranding(db, b); err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = b
+	return resp.ServerJSON(ctx)
+}
+
+func serveEmailTemplateQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Email template not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.EmailTemplateNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	return serveDatabaseQueryFailed(ctx, err)
+}
+
+func serveEmailTemplateStorageFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	log.Log().Errorln(err)
+
+	resp.Title = "Failed to store email template"
+	resp.Status = http.StatusInternalServerError
+	resp.Code = errors.MinioServiceFailed
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
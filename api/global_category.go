@@ -0,0 +1,414 @@
+package api
+
+import (
+	"github.com/gosimple/slug"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func createGlobalCategory(ctx echo.Context) error {
+	req, err := validators.ValidateCreateGlobalCategory(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.GlobalCategoryDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+
+	db := app.DB()
+	au := data.NewMarketplaceRepository()
+
+	m := &models.GlobalCategory{
+		ID:          utils.NewUUID(),
+		Name:        req.Name,
+		Slug:        slug.Make(req.Name),
+		Description: req.Description,
+		Image:       req.Image,
+		IsPublished: req.IsPublished,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if req.ParentID != nil {
+		parent, err := au.GetGlobalCategory(db, *req.ParentID)
+		if err != nil {
+			if errors.IsRecordNotFoundError(err) {
+				resp.Title = "Parent global category not found"
+				resp.Status = http.StatusNotFound
+				resp.Code = errors.GlobalCategoryNotFound
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			resp.Title = "Database query failed"
+			resp.Status = http.StatusInternalServerError
+			resp.Code = errors.DatabaseQueryFailed
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		m.ParentID = &parent.ID
+		m.Slug = slug.Make(parent.Slug + " " + req.Name)
+	}
+
+	if err := au.CreateGlobalCategory(db, m); err != nil {
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.GlobalCategoryAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = m
+	return resp.ServerJSON(ctx)
+}
+
+func updateGlobalCategory(ctx echo.Context) error {
+	gcID := ctx.Param("gc_id")
+
+	req, err := validators.ValidateUpdateGlobalCategory(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.GlobalCategoryDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	gc, err := au.GetGlobalCategory(db, gcID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Global category not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.GlobalCategoryNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if req.ParentID != nil {
+		if *req.ParentID == "" {
+			gc.ParentID = nil
+		} else {
+			parent, err := au.GetGlobalCategory(db, *req.ParentID)
+			if err != nil {
+				if errors.IsRecordNotFoundError(err) {
+					resp.Title = "Parent global category not found"
+					resp.Status = http.StatusNotFound
+					resp.Code = errors.GlobalCategoryNotFound
+					resp.Errors = err
+					return resp.ServerJSON(ctx)
+				}
+
+				resp.Title = "Database query failed"
+				resp.Status = http.StatusInternalServerError
+				resp.Code = errors.DatabaseQueryFailed
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			ancestorIDs, err := au.ListGlobalCategoryAncestorIDs(db, parent.ID)
+			if err != nil {
+				resp.Title = "Database query failed"
+				resp.Status = http.StatusInternalServerError
+				resp.Code = errors.DatabaseQueryFailed
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			for _, ID := range ancestorIDs {
+				if ID == gc.ID {
+					resp.Title = "Global category can't be parent of itself or of its ancestors"
+					resp.Status = http.StatusUnprocessableEntity
+					resp.Code = errors.GlobalCategoryDataInvalid
+					return resp.ServerJSON(ctx)
+				}
+			}
+
+			gc.ParentID = &parent.ID
+		}
+	}
+	if req.Name != nil {
+		gc.Name = *req.Name
+	}
+	if req.Name != nil || req.ParentID != nil {
+		gc.Slug = slug.Make(gc.Name)
+
+		if gc.ParentID != nil {
+			parent, err := au.GetGlobalCategory(db, *gc.ParentID)
+			if err != nil {
+				resp.Title = "Database query failed"
+				resp.Status = http.StatusInternalServerError
+				resp.Code = errors.DatabaseQueryFailed
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+			gc.Slug = slug.Make(parent.Slug + " " + gc.Name)
+		}
+	}
+	if req.Description != nil {
+		gc.Description = *req.Description
+	}
+	if req.Image != nil {
+		gc.Image = *req.Image
+	}
+	if req.IsPublished != nil {
+		gc.IsPublished = *req.IsPublished
+	}
+
+	gc.UpdatedAt = time.Now().UTC()
+
+	if err := au.UpdateGlobalCategory(db, gc); err != nil {
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.GlobalCategoryAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = gc
+	return resp.ServerJSON(ctx)
+}
+
+func deleteGlobalCategory(ctx echo.Context) error {
+	gcID := ctx.Param("gc_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+	au := data.NewMarketplaceRepository()
+	if err := au.DeleteGlobalCategory(db, gcID); err != nil {
+		if errors.IsForeignKeyViolationError(err) {
+			resp.Title = "Global category has sub categories, categories or products"
+			resp.Status = http.StatusConflict
+			resp.Code = errors.GlobalCategoryInUse
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func listGlobalCategories(ctx echo.Context) error {
+	return serveGlobalCategories(ctx, false)
+}
+
+func listGlobalCategoriesForUser(ctx echo.Context) error {
+	return serveGlobalCategories(ctx, true)
+}
+
+func serveGlobalCategories(ctx echo.Context, isPublic bool) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+	parentIDQ := ctx.Request().URL.Query().Get("parent_id")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	var parentID *string
+	if parentIDQ != "" {
+		parentID = &parentIDQ
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+
+	var gcs []models.GlobalCategory
+	if isPublic {
+		gcs, err = au.ListGlobalCategoriesForUser(db, parentID, int(from), int(limit))
+	} else {
+		gcs, err = au.ListGlobalCategories(db, parentID, int(from), int(limit))
+	}
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = gcs
+	return resp.ServerJSON(ctx)
+}
+
+func getGlobalCategory(ctx echo.Context) error {
+	gcID := ctx.Param("gc_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	gc, err := au.GetGlobalCategory(db, gcID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Global category not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.GlobalCategoryNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = gc
+	return resp.ServerJSON(ctx)
+}
+
+func getGlobalCategoryForUser(ctx echo.Context) error {
+	gcID := ctx.Param("gc_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	gc, err := au.GetGlobalCategoryForUser(db, gcID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Global category not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.GlobalCategoryNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = gc
+	return resp.ServerJSON(ctx)
+}
+
+func listProductsByGlobalCategory(ctx echo.Context) error {
+	gcID := ctx.Param("gc_id")
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	gc, err := au.GetGlobalCategoryForUser(db, gcID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Global category not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.GlobalCategoryNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	pu := data.NewProductRepository()
+	products, err := pu.ListByGlobalCategory(db, gc.ID, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = products
+	return resp.ServerJSON(ctx)
+}
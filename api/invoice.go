@@ -0,0 +1,85 @@
+package api
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"net/http"
+)
+
+func downloadOrderInvoice(ctx echo.Context) error {
+	return serveOrderInvoice(ctx, models.InvoiceTypeInvoice)
+}
+
+func downloadOrderCreditNote(ctx echo.Context) error {
+	return serveOrderInvoice(ctx, models.InvoiceTypeCreditNote)
+}
+
+// serveOrderInvoice serves the document to the buyer of the order as well as to the staffs of its store
+func serveOrderInvoice(ctx echo.Context, invoiceType models.InvoiceType) error {
+	orderID := ctx.Param("order_id")
+	userID := utils.GetUserID(ctx)
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	ou := data.NewOrderRepository()
+	o, err := ou.GetDetails(db, orderID)
+	if err != nil {
+		return serveOrderNotFoundOrQueryFailed(ctx, err)
+	}
+
+	if o.UserID != userID {
+		su := data.NewStoreRepository()
+		store, err := su.GetStoreUserProfile(db, userID, o.StoreID)
+		if err != nil && !errors.IsRecordNotFoundError(err) {
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+		if store == nil {
+			return serveOrderNotFoundOrQueryFailed(ctx, gorm.ErrRecordNotFound)
+		}
+	}
+
+	if invoiceType == models.InvoiceTypeCreditNote && o.PaymentStatus != models.PaymentReverted {
+		resp.Title = "Credit note is only available for refunded orders"
+		resp.Status = http.StatusBadRequest
+		resp.Code = errors.CreditNoteNotAvailable
+		return resp.ServerJSON(ctx)
+	}
+
+	inv, b, err := services.GenerateInvoice(o, invoiceType)
+	if err != nil {
+		log.Log().Errorln(err)
+
+		resp.Title = "Failed to generate invoice"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.InvoiceGenerationFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	ctx.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", inv.FileName()))
+	return ctx.Blob(http.StatusOK, services.InvoiceContentType, b)
+}
+
+func serveOrderNotFoundOrQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Order not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.OrderNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	return serveDatabaseQueryFailed(ctx, err)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/jinzhu/gorm"
 	"github.com/labstack/echo/v4"
 	"github.com/shopicano/shopicano-backend/app"
 	"github.com/shopicano/shopicano-backend/core"
@@ -10,6 +11,7 @@ import (
 	"github.com/shopicano/shopicano-backend/middlewares"
 	"github.com/shopicano/shopicano-backend/models"
 	"github.com/shopicano/shopicano-backend/queue"
+	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"github.com/shopicano/shopicano-backend/validators"
 	"github.com/shopicano/shopicano-backend/values"
@@ -19,11 +21,16 @@ import (
 
 func RegisterLegacyRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	publicEndpoints.POST("/login/", login)
-	publicEndpoints.GET("/logout/", logout)
+	publicEndpoints.POST("/login/two-factor/", loginTwoFactor)
+	publicEndpoints.GET("/oidc/providers/", listOIDCProviders)
+	publicEndpoints.POST("/oidc/:provider/authorize/", authorizeOIDC)
+	publicEndpoints.POST("/oidc/:provider/callback/", oidcCallback)
+	publicEndpoints.GET("/logout/", logout, middlewares.JWTAuth())
 	publicEndpoints.GET("/refresh-token/", refreshToken)
 	publicEndpoints.GET("/email-verification/", emailVerification)
 	publicEndpoints.GET("/reset-password/", resetPasswordRequest)
 	publicEndpoints.POST("/reset-password/", resetPasswordUpdate)
+	publicEndpoints.POST("/unlock-account/", unlockAccount)
 
 	func(g echo.Group) {
 		g.Use(middlewares.IsSignUpEnabled)
@@ -45,6 +52,10 @@ func login(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	if err := services.CheckLoginAllowed(pld.Email, ctx.RealIP()); err != nil {
+		return serveThrottled(ctx, err, errors.TooManyLoginAttempts)
+	}
+
 	db := app.DB().Begin()
 
 	uc := data.NewUserRepository()
@@ -55,6 +66,8 @@ func login(ctx echo.Context) error {
 		log.Log().Errorln(err)
 
 		if errors.IsRecordNotFoundError(err) {
+			recordLoginFailure(ctx, pld.Email, nil)
+
 			resp.Title = "User not registered"
 			resp.Status = http.StatusNotFound
 			resp.Code = errors.UserNotFound
@@ -72,6 +85,8 @@ func login(ctx echo.Context) error {
 	if err := utils.CheckPassword(u.Password, pld.Password); err != nil {
 		db.Rollback()
 
+		recordLoginFailure(ctx, pld.Email, u)
+
 		resp.Title = "Invalid login credentials"
 		resp.Status = http.StatusUnauthorized
 		resp.Code = errors.LoginCredentialsInvalid
@@ -89,7 +104,43 @@ func login(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	_, permission, err := uc.GetPermissionByUserID(db, u.ID)
+	return authenticated(ctx, db, u, loginScope(pld.Scope))
+}
+
+// recordLoginFailure counts the failure and sends the unlock email when it locks the account of u.
+// A failure of the limiter doesn't change the response of the login.
+func recordLoginFailure(ctx echo.Context, email string, u *models.User) {
+	lockedUntil, err := services.RecordLoginFailure(email, ctx.RealIP(), ctx.Request().UserAgent(), u)
+	if err != nil {
+		log.Log().Errorln(err)
+		return
+	}
+	if lockedUntil != nil {
+		if err := queue.SendAccountLockedEmail(u.ID, ctx.RealIP(), *lockedUntil); err != nil {
+			log.Log().Errorln(err)
+		}
+	}
+}
+
+func loginScope(v utils.UserScope) utils.UserScope {
+	switch v {
+	case utils.Platform:
+		return utils.Platform
+	case utils.BackStore:
+		return utils.BackStore
+	}
+	return utils.FrontStore
+}
+
+// authenticated logs in a user whose credentials are verified, holding it back for the
+// second factor when enabled. db is committed.
+func authenticated(ctx echo.Context, db *gorm.DB, u *models.User, scope utils.UserScope) error {
+	resp := core.Response{}
+
+	userID := u.ID
+
+	uc := data.NewUserRepository()
+	_, permission, err := uc.GetPermissionByUserID(db, userID)
 	if err != nil {
 		db.Rollback()
 
@@ -102,36 +153,66 @@ func login(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	scope := utils.FrontStore
-	switch pld.Scope {
-	case utils.Platform:
-		scope = utils.Platform
-	case utils.BackStore:
-		scope = utils.BackStore
+	isTwoFactorEnabled, err := services.IsTwoFactorEnabled(db, userID)
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
 	}
 
-	signedToken, err := utils.BuildJWTToken(u.ID, scope)
+	if isTwoFactorEnabled {
+		token, expireOn, err := services.StartTwoFactorChallenge(db, userID, scope)
+		if err != nil {
+			db.Rollback()
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+
+		if err := db.Commit().Error; err != nil {
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+
+		resp.Status = http.StatusOK
+		resp.Data = map[string]interface{}{
+			"two_factor_required": true,
+			"challenge_token":     token,
+			"expire_on":           expireOn,
+		}
+		return resp.ServerJSON(ctx)
+	}
+
+	return completeLogin(ctx, db, u, scope, permission)
+}
+
+// completeLogin creates the session of an authenticated user and commits db, the login failures
+// of the account are cleared only then
+func completeLogin(ctx echo.Context, db *gorm.DB, u *models.User, scope utils.UserScope, permission *models.Permission) error {
+	resp := core.Response{}
+
+	userID := u.ID
+
+	tokens, err := services.CreateSession(db, userID, scope, ctx.Request().UserAgent(), ctx.RealIP())
 	if err != nil {
 		db.Rollback()
 		log.Log().Errorln(err)
 
-		resp.Title = "Failed to sign auth token"
+		resp.Title = "Failed to create session"
 		resp.Status = http.StatusInternalServerError
 		resp.Code = errors.UserLoginFailed
 		resp.Errors = err
 		return resp.ServerJSON(ctx)
 	}
 
-	s := models.Session{
-		ID:           utils.NewUUID(),
-		UserID:       u.ID,
-		AccessToken:  signedToken,
-		RefreshToken: utils.NewToken(),
-		CreatedAt:    time.Now().UTC(),
-		ExpireOn:     time.Now().Add(time.Hour * 48).Unix(),
+	result := map[string]interface{}{
+		"session_id":        tokens.SessionID,
+		"access_token":      tokens.AccessToken,
+		"refresh_token":     tokens.RefreshToken,
+		"expire_on":         tokens.ExpireOn,
+		"refresh_expire_on": tokens.RefreshExpireOn,
+		"permission":        permission,
 	}
 
-	if err := uc.CreateSession(db, &s); err != nil {
+	sc := data.NewStoreRepository()
+	profiles, err := sc.ListStoreUserProfiles(db, userID)
+	if err != nil {
 		db.Rollback()
 
 		resp.Title = "Database query failed"
@@ -141,30 +222,11 @@ func login(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	result := map[string]interface{}{
-		"access_token":  s.AccessToken,
-		"refresh_token": s.RefreshToken,
-		"expire_on":     s.ExpireOn,
-		"permission":    permission,
-	}
-
-	sc := data.NewStoreRepository()
-	profile, err := sc.GetStoreUserProfile(db, u.ID)
-	if err != nil {
-		if !errors.IsRecordNotFoundError(err) {
-			db.Rollback()
-
-			resp.Title = "Database query failed"
-			resp.Status = http.StatusInternalServerError
-			resp.Code = errors.DatabaseQueryFailed
-			resp.Errors = err
-			return resp.ServerJSON(ctx)
-		}
-
-		result["has_store"] = false
-	} else {
-		result["has_store"] = true
-		result["staff"] = profile
+	// staff is the default store, the first one created by the user if any
+	result["has_store"] = len(profiles) > 0
+	result["stores"] = profiles
+	if len(profiles) > 0 {
+		result["staff"] = profiles[0]
 	}
 
 	if err := db.Commit().Error; err != nil {
@@ -175,30 +237,22 @@ func login(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	if err := services.RecordLoginSuccess(u.Email); err != nil {
+		log.Log().Errorln(err)
+	}
+
 	resp.Status = http.StatusOK
 	resp.Data = result
 	return resp.ServerJSON(ctx)
 }
 
 func logout(ctx echo.Context) error {
-	token, err := utils.ParseBearerToken(ctx)
-
 	resp := core.Response{}
 
-	if err != nil {
-		log.Log().Errorln(err)
-
-		resp.Title = "Invalid data"
-		resp.Status = http.StatusBadRequest
-		resp.Code = errors.BearerTokenNotFound
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
-	}
-
 	db := app.DB()
 
-	uc := data.NewUserRepository()
-	if err := uc.Logout(db, token); err != nil {
+	su := data.NewSessionRepository()
+	if err := su.Revoke(db, utils.GetUserID(ctx), utils.GetSessionID(ctx), models.SessionRevokedLogout, time.Now().UTC()); err != nil {
 		log.Log().Errorln(err)
 
 		resp.Title = "Failed to logout"
@@ -228,19 +282,22 @@ func refreshToken(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	db := app.DB()
-
-	uc := data.NewUserRepository()
-	s, err := uc.RefreshToken(db, token)
+	tokens, err := services.RefreshSession(token, ctx.Request().UserAgent(), ctx.RealIP())
 	if err != nil {
 		log.Log().Errorln(err)
 
-		ok := errors.IsRecordNotFoundError(err)
-		if ok {
-			resp.Title = "User already register"
-			resp.Status = http.StatusBadRequest
-			resp.Code = errors.BearerTokenNotFound
-			resp.Errors = errors.NewError("Invalid refresh token")
+		switch err {
+		case services.ErrRefreshTokenInvalid:
+			resp.Title = "Invalid refresh token"
+			resp.Status = http.StatusUnauthorized
+			resp.Code = errors.RefreshTokenInvalid
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		case services.ErrRefreshTokenReused:
+			resp.Title = "Refresh token reused"
+			resp.Status = http.StatusUnauthorized
+			resp.Code = errors.RefreshTokenReused
+			resp.Errors = err
 			return resp.ServerJSON(ctx)
 		}
 
@@ -253,7 +310,7 @@ func refreshToken(ctx echo.Context) error {
 
 	resp.Title = "Refresh token generation successful"
 	resp.Status = http.StatusOK
-	resp.Data = s
+	resp.Data = tokens
 	return resp.ServerJSON(ctx)
 }
 
@@ -388,6 +445,10 @@ func resetPasswordRequest(ctx echo.Context) error {
 
 	email := ctx.QueryParam("email")
 
+	if err := services.CheckResetPasswordAllowed(email, ctx.RealIP(), ctx.Request().UserAgent()); err != nil {
+		return serveThrottled(ctx, err, errors.TooManyResetPasswordRequests)
+	}
+
 	db := app.DB()
 
 	uc := data.NewUserRepository()
@@ -509,6 +570,17 @@ func resetPasswordUpdate(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	su := data.NewSessionRepository()
+	if err := su.RevokeAll(db, u.ID, "", models.SessionRevokedPasswordChange, now); err != nil {
+		db.Rollback()
+
+		resp.Title = "Password reset failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
 	if err := queue.SendPasswordResetConfirmationEmail(u.ID); err != nil {
 		resp.Title = "Task queueing failed"
 		resp.Status = http.StatusInternalServerError
@@ -461,9 +461,18 @@ func listShippingMethodsByLocationForUser(ctx echo.Context) error {
 	locationIDQ := ctx.Param("location_id")
 	locationID, _ := strconv.ParseInt(locationIDQ, 10, 64)
 
+	storeID := ctx.QueryParam("store_id")
+
 	db := app.DB()
 	marketDao := data.NewMarketplaceRepository()
-	m, err := marketDao.ListShippingMethodsByLocationForUser(db, locationID)
+
+	var m []models.ShippingMethod
+	var err error
+	if storeID != "" {
+		m, err = marketDao.ListShippingMethodsByLocationForStore(db, storeID, locationID)
+	} else {
+		m, err = marketDao.ListShippingMethodsByLocationForUser(db, locationID)
+	}
 	if err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+)
+
+func unlockAccount(ctx echo.Context) error {
+	req, err := validators.ValidateAccountUnlock(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.AccountUnlockDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	if err := services.UnlockAccount(db, req.Token, ctx.RealIP(), ctx.Request().UserAgent()); err != nil {
+		db.Rollback()
+
+		if err == services.ErrUnlockTokenInvalid {
+			resp.Title = "Invalid or expired unlock token"
+			resp.Status = http.StatusUnauthorized
+			resp.Code = errors.UnlockTokenInvalid
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Account unlocked"
+	resp.Status = http.StatusOK
+	return resp.ServerJSON(ctx)
+}
+
+func listSecurityAuditLogs(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	lu := data.NewLoginProtectionRepository()
+	logs, err := lu.ListAuditLogs(app.DB(), int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = logs
+	return resp.ServerJSON(ctx)
+}
+
+// serveThrottled refuses the request with the time to wait in the Retry-After header,
+// err is a services.ThrottledError or a failure of the limiter store
+func serveThrottled(ctx echo.Context, err error, code errors.ErrorCode) error {
+	te, ok := err.(*services.ThrottledError)
+	if !ok {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp := core.Response{}
+	resp.Title = "Too many attempts, retry later"
+	resp.Status = http.StatusTooManyRequests
+	resp.Code = code
+	if te.Locked {
+		resp.Title = "Temporarily locked after too many failed attempts"
+		resp.Code = errors.AccountLocked
+	}
+	resp.Errors = err
+
+	ctx.Response().Header().Set("Retry-After", strconv.FormatInt(te.RetryAfterSeconds(), 10))
+	return resp.ServerJSON(ctx)
+}
@@ -38,7 +38,61 @@ func RegisterPlatformRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 		g.GET("/payout-methods/", listPayoutMethods)
 		g.GET("/payout-methods/:pom_id/", getPayoutMethod)
 
+		g.POST("/global-categories/", createGlobalCategory)
+		g.PATCH("/global-categories/:gc_id/", updateGlobalCategory)
+		g.DELETE("/global-categories/:gc_id/", deleteGlobalCategory)
+		g.GET("/global-categories/", listGlobalCategories)
+		g.GET("/global-categories/:gc_id/", getGlobalCategory)
+
+		g.POST("/shipping-zones/", createShippingZone)
+		g.PATCH("/shipping-zones/:zone_id/", updateShippingZone)
+		g.DELETE("/shipping-zones/:zone_id/", deleteShippingZone)
+		g.GET("/shipping-zones/", listShippingZones)
+		g.GET("/shipping-zones/:zone_id/", getShippingZone)
+		g.PUT("/shipping-zones/:zone_id/locations/", setShippingZoneLocations)
+		g.PUT("/shipping-zones/:zone_id/rates/", setShippingZoneRate)
+		g.DELETE("/shipping-zones/:zone_id/rates/:sm_id/", deleteShippingZoneRate)
+
+		g.POST("/tax-classes/", createTaxClass)
+		g.PATCH("/tax-classes/:tc_id/", updateTaxClass)
+		g.DELETE("/tax-classes/:tc_id/", deleteTaxClass)
+		g.GET("/tax-classes/:tc_id/", getTaxClass)
+
+		g.POST("/tax-rates/", createTaxRate)
+		g.PATCH("/tax-rates/:tr_id/", updateTaxRate)
+		g.DELETE("/tax-rates/:tr_id/", deleteTaxRate)
+		g.GET("/tax-rates/", listTaxRates)
+		g.GET("/tax-rates/:tr_id/", getTaxRate)
+
+		g.GET("/tax-exemptions/", listTaxExemptions)
+		g.PATCH("/tax-exemptions/:te_id/", verifyTaxExemption)
+
+		g.POST("/email-templates/", createEmailTemplate)
+		g.POST("/email-templates/preview/", previewEmailTemplate)
+		g.PATCH("/email-templates/:et_id/", updateEmailTemplate)
+		g.DELETE("/email-templates/:et_id/", deleteEmailTemplate)
+		g.GET("/email-templates/", listEmailTemplates)
+		g.GET("/email-templates/:et_id/", getEmailTemplate)
+
+		g.GET("/emails/", searchEmails)
+		g.GET("/emails/:email_id/", getEmail)
+		g.POST("/emails/:email_id/resend/", resendEmail)
+
+		g.POST("/webhooks/", createWebhook)
+		g.GET("/webhooks/", listWebhooks)
+		g.GET("/webhooks/:webhook_id/", getWebhook)
+		g.PATCH("/webhooks/:webhook_id/", updateWebhook)
+		g.DELETE("/webhooks/:webhook_id/", deleteWebhook)
+		g.POST("/webhooks/:webhook_id/ping/", pingWebhook)
+		g.GET("/webhooks/:webhook_id/deliveries/", listWebhookDeliveries)
+		g.POST("/webhooks/:webhook_id/deliveries/:delivery_id/redeliver/", redeliverWebhook)
+
+		g.GET("/reviews/", listReviewsAsAdmin)
+		g.PATCH("/reviews/:review_id/", moderateReview)
+
 		g.GET("/users/", listUsers)
+
+		g.GET("/security-audit-logs/", listSecurityAuditLogs)
 	}(*platformEndpoints)
 
 	func(g echo.Group) {
@@ -46,10 +100,17 @@ func RegisterPlatformRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 		g.PATCH("/settings/", updateSettings)
 	}(*platformEndpoints)
 
+	func(g echo.Group) {
+		g.GET("/global-categories/", listGlobalCategoriesForUser)
+		g.GET("/global-categories/:gc_id/", getGlobalCategoryForUser)
+		g.GET("/global-categories/:gc_id/products/", listProductsByGlobalCategory)
+	}(*publicEndpoints)
+
 	func(g echo.Group) {
 		g.Use(middlewares.JWTAuth())
 		g.GET("/payment-methods/:id/", getPaymentMethodForUser)
 		g.GET("/shipping-methods/:id/", getShippingMethodForUser)
+		g.GET("/tax-classes/", listTaxClasses)
 	}(*publicEndpoints)
 
 	func(g echo.Group) {
@@ -125,6 +186,9 @@ func updateSettings(ctx echo.Context) error {
 	if req.Website != nil {
 		s.Website = *req.Website
 	}
+	if req.IsTwoFactorRequired != nil {
+		s.IsTwoFactorRequired = *req.IsTwoFactorRequired
+	}
 
 	err = au.UpdateSettings(db, s)
 	if err != nil {
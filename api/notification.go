@@ -0,0 +1,155 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"time"
+)
+
+func listNotificationPreferences(ctx echo.Context) error {
+	userID := ctx.Get(utils.UserID).(string)
+
+	resp := core.Response{}
+
+	nps, err := services.GetNotificationPreferences(app.DB(), userID)
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = nps
+	return resp.ServerJSON(ctx)
+}
+
+func updateNotificationPreferences(ctx echo.Context) error {
+	userID := ctx.Get(utils.UserID).(string)
+
+	req, err := validators.ValidateUpdateNotificationPreferences(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.NotificationPreferenceDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	nu := data.NewNotificationRepository()
+	for _, p := range req.Preferences {
+		np := &models.NotificationPreference{
+			UserID:        userID,
+			Event:         p.Event,
+			IsSMSEnabled:  p.IsSMSEnabled,
+			IsPushEnabled: p.IsPushEnabled,
+			UpdatedAt:     time.Now().UTC(),
+		}
+		if err := nu.SavePreference(db, np); err != nil {
+			db.Rollback()
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+	}
+
+	nps, err := services.GetNotificationPreferences(db, userID)
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = nps
+	return resp.ServerJSON(ctx)
+}
+
+func registerPushDevice(ctx echo.Context) error {
+	userID := ctx.Get(utils.UserID).(string)
+
+	req, err := validators.ValidateRegisterPushDevice(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.PushDeviceDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	pd := &models.PushDevice{
+		ID:        utils.NewUUID(),
+		UserID:    userID,
+		Token:     req.Token,
+		Platform:  req.Platform,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	db := app.DB()
+
+	nu := data.NewNotificationRepository()
+	if err := nu.SavePushDevice(db, pd); err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = pd
+	return resp.ServerJSON(ctx)
+}
+
+func listPushDevices(ctx echo.Context) error {
+	userID := ctx.Get(utils.UserID).(string)
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	nu := data.NewNotificationRepository()
+	pds, err := nu.ListPushDevices(db, userID)
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = pds
+	return resp.ServerJSON(ctx)
+}
+
+func deletePushDevice(ctx echo.Context) error {
+	userID := ctx.Get(utils.UserID).(string)
+	deviceID := ctx.Param("device_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	nu := data.NewNotificationRepository()
+	if err := nu.DeletePushDevice(db, userID, deviceID); err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Push device not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.PushDeviceNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+)
+
+func listOIDCProviders(ctx echo.Context) error {
+	resp := core.Response{}
+	resp.Status = http.StatusOK
+	resp.Data = map[string]interface{}{
+		"providers": services.OIDCProviderNames(),
+	}
+	return resp.ServerJSON(ctx)
+}
+
+func authorizeOIDC(ctx echo.Context) error {
+	req, err := validators.ValidateOIDCAuthorize(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.OIDCDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	return startOIDCAuthorization(ctx, loginScope(req.Scope), nil)
+}
+
+func authorizeOIDCLink(ctx echo.Context) error {
+	userID := utils.GetUserID(ctx)
+	return startOIDCAuthorization(ctx, utils.FrontStore, &userID)
+}
+
+func startOIDCAuthorization(ctx echo.Context, scope utils.UserScope, userID *string) error {
+	resp := core.Response{}
+
+	p, err := services.GetOIDCProvider(ctx.Param("provider"))
+	if err != nil {
+		return serveOIDCFailed(ctx, err)
+	}
+
+	uri, expireOn, err := services.StartOIDCAuthorization(app.DB(), p, scope, userID)
+	if err != nil {
+		return serveOIDCFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = map[string]interface{}{
+		"authorization_url": uri,
+		"expire_on":         expireOn,
+	}
+	return resp.ServerJSON(ctx)
+}
+
+func oidcCallback(ctx echo.Context) error {
+	req, err := validators.ValidateOIDCCallback(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.OIDCDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	p, err := services.GetOIDCProvider(ctx.Param("provider"))
+	if err != nil {
+		return serveOIDCFailed(ctx, err)
+	}
+
+	s, id, err := services.CompleteOIDCAuthorization(p, req.State, req.Code)
+	if err != nil {
+		return serveOIDCFailed(ctx, err)
+	}
+
+	db := app.DB().Begin()
+
+	if s.UserID != nil {
+		ui, err := services.LinkOIDCIdentity(db, *s.UserID, p.Name(), id)
+		if err != nil {
+			db.Rollback()
+			return serveOIDCFailed(ctx, err)
+		}
+
+		if err := db.Commit().Error; err != nil {
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+
+		resp.Title = "Identity linked"
+		resp.Status = http.StatusOK
+		resp.Data = ui
+		return resp.ServerJSON(ctx)
+	}
+
+	u, err := services.SignInWithOIDC(db, p.Name(), id)
+	if err != nil {
+		db.Rollback()
+		return serveOIDCFailed(ctx, err)
+	}
+
+	if u.Status != models.UserActive {
+		db.Rollback()
+
+		resp.Title = "User isn't active"
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.UserNotActive
+		return resp.ServerJSON(ctx)
+	}
+
+	return authenticated(ctx, db, u, utils.UserScope(s.Scope))
+}
+
+func listUserIdentities(ctx echo.Context) error {
+	resp := core.Response{}
+
+	iu := data.NewUserIdentityRepository()
+	identities, err := iu.List(app.DB(), utils.GetUserID(ctx))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = identities
+	return resp.ServerJSON(ctx)
+}
+
+func unlinkUserIdentity(ctx echo.Context) error {
+	resp := core.Response{}
+
+	iu := data.NewUserIdentityRepository()
+	if err := iu.Delete(app.DB(), utils.GetUserID(ctx), ctx.Param("identity_id")); err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Identity not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.UserIdentityNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func serveOIDCFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	switch err {
+	case services.ErrOIDCProviderNotFound:
+		resp.Title = "Provider not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.OIDCProviderNotFound
+	case services.ErrOIDCStateInvalid:
+		resp.Title = "Invalid or expired authorization"
+		resp.Status = http.StatusUnauthorized
+		resp.Code = errors.OIDCStateInvalid
+	case services.ErrOIDCTokenInvalid:
+		resp.Title = "Provider authentication failed"
+		resp.Status = http.StatusUnauthorized
+		resp.Code = errors.OIDCTokenInvalid
+	case services.ErrOIDCEmailNotVerified:
+		resp.Title = "Email isn't verified by the provider"
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.OIDCEmailNotVerified
+	case services.ErrOIDCSignUpDisabled:
+		resp.Title = "Sign up disabled in settings"
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.UserSignUpDisabled
+	case services.ErrUserIdentityAlreadyLinked:
+		resp.Title = "Identity is linked to another user"
+		resp.Status = http.StatusConflict
+		resp.Code = errors.UserIdentityAlreadyLinked
+	case services.ErrOIDCProviderFailed:
+		resp.Title = "Provider request failed"
+		resp.Status = http.StatusBadGateway
+		resp.Code = errors.OIDCProviderFailed
+	default:
+		log.Log().Errorln(err)
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
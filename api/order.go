@@ -11,7 +11,6 @@ import (
 	"github.com/shopicano/shopicano-backend/middlewares"
 	"github.com/shopicano/shopicano-backend/models"
 	payment_gateways "github.com/shopicano/shopicano-backend/payment-gateways"
-	"github.com/shopicano/shopicano-backend/queue"
 	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"github.com/shopicano/shopicano-backend/validators"
@@ -28,7 +27,7 @@ func RegisterOrderRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.HasStore())
 		g.Use(middlewares.IsStoreActive())
-		g.Use(middlewares.IsStoreManager())
+		g.Use(middlewares.HasStorePermission(models.StorePermOrdersManage))
 		g.GET("/", listOrdersAsStoreOwner)
 		g.GET("/:order_id/", getOrderAsStoreOwner)
 		g.PATCH("/:order_id/status/", orderUpdateStatus)
@@ -42,6 +41,8 @@ func RegisterOrderRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 		g.POST("/:order_id/nonce/", generatePayNonce)
 		g.POST("/:order_id/review/", createReview)
 		g.GET("/:order_id/products/:product_id/download/", downloadProductAsUser)
+		g.GET("/:order_id/invoice/", downloadOrderInvoice)
+		g.GET("/:order_id/credit-note/", downloadOrderCreditNote)
 		g.GET("/:order_id/nonce/", generatePayNonce)
 	}(*ordersPublicPath)
 
@@ -53,7 +54,7 @@ func RegisterOrderRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.HasStore())
 		g.Use(middlewares.IsStoreActive())
-		g.Use(middlewares.IsStoreAdmin())
+		g.Use(middlewares.HasStorePermission(models.StorePermOrdersRefund))
 		g.POST("/:order_id/refund/", revertOrderPayment)
 		g.PATCH("/:order_id/payment-status/", orderPaymentStatusUpdate)
 	}(*ordersPlatformPath)
@@ -143,6 +144,11 @@ func createNewOrder(ctx echo.Context, pld *validators.ReqOrderCreate) error {
 
 	var storeID *string
 
+	// Volume of the shippable items in cubic centimeters
+	totalVolume := int64(0)
+
+	taxClasses := map[string]*string{}
+
 	for _, v := range pld.Items {
 		orderedItemID := utils.NewUUID()
 
@@ -175,6 +181,8 @@ func createNewOrder(ctx echo.Context, pld *validators.ReqOrderCreate) error {
 			}
 		}
 
+		weight, length, width, height := item.Weight, item.Length, item.Width, item.Height
+
 		for _, a := range v.Attributes {
 			attr, err := pu.GetAttribute(db, v.ID, a)
 			if err != nil {
@@ -191,6 +199,19 @@ func createNewOrder(ctx echo.Context, pld *validators.ReqOrderCreate) error {
 				return serveDatabaseQueryFailed(ctx, err)
 			}
 
+			if attr.Weight != nil {
+				weight = *attr.Weight
+			}
+			if attr.Length != nil {
+				length = *attr.Length
+			}
+			if attr.Width != nil {
+				width = *attr.Width
+			}
+			if attr.Height != nil {
+				height = *attr.Height
+			}
+
 			productAttributes = append(productAttributes, &models.OrderedItemAttribute{
 				OrderedItemID:  orderedItemID,
 				AttributeKey:   attr.Key,
@@ -223,19 +244,46 @@ func createNewOrder(ctx echo.Context, pld *validators.ReqOrderCreate) error {
 			isAllDigitalProduct = item.IsDigital
 		}
 
+		price := item.EffectivePrice(time.Now().UTC())
+
 		oi := &models.OrderedItem{
 			ID:          orderedItemID,
 			OrderID:     o.ID,
 			ProductID:   item.ID,
 			Quantity:    v.Quantity,
-			Price:       item.Price,
+			Price:       price,
 			ProductCost: item.ProductCost,
 		}
-		oi.SubTotal = int64(v.Quantity) * item.Price
+		oi.SubTotal = int64(v.Quantity) * price
 
 		availableItems = append(availableItems, oi)
+		taxClasses[oi.ID] = item.TaxClassID
+
+		if item.IsBundle {
+			bundleItems, err := pu.ListBundleItems(db, item.ID)
+			if err != nil {
+				db.Rollback()
+				return serveDatabaseQueryFailed(ctx, err)
+			}
+
+			// Bundle components are listed as separate lines but priced as part of the bundle
+			for _, bi := range bundleItems {
+				availableItems = append(availableItems, &models.OrderedItem{
+					ID:        utils.NewUUID(),
+					OrderID:   o.ID,
+					ParentID:  &oi.ID,
+					ProductID: bi.ProductID,
+					Quantity:  bi.Quantity * v.Quantity,
+				})
+			}
+		}
 
 		o.SubTotal += oi.SubTotal
+
+		if !item.IsDigital {
+			o.TotalWeight += weight * v.Quantity
+			totalVolume += int64(length) * int64(width) * int64(height) * int64(v.Quantity)
+		}
 	}
 
 	if hasDigitalProducts && hasNonDigitalProducts {
@@ -271,10 +319,65 @@ func createNewOrder(ctx echo.Context, pld *validators.ReqOrderCreate) error {
 	}
 
 	if !isAllDigitalProduct {
-		o.ShippingCharge = sm.CalculateDeliveryCharge(0)
+		ok, err := au.IsShippingMethodOfferedByStore(db, o.StoreID, sm.ID)
+		if err != nil {
+			db.Rollback()
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+		if !ok {
+			db.Rollback()
+
+			resp.Title = "Shipping method isn't offered by the store"
+			resp.Status = http.StatusBadRequest
+			resp.Code = errors.ShippingMethodNotOfferedByStore
+			return resp.ServerJSON(ctx)
+		}
+
+		if o.ShippingAddressID != nil {
+			adu := data.NewAddressRepository()
+			addr, err := adu.GetRawAddressByID(db, *o.ShippingAddressID)
+			if err != nil {
+				db.Rollback()
+
+				if errors.IsRecordNotFoundError(err) {
+					resp.Title = "Shipping address not found"
+					resp.Status = http.StatusNotFound
+					resp.Code = errors.AddressNotFound
+					resp.Errors = err
+					return resp.ServerJSON(ctx)
+				}
+
+				return serveDatabaseQueryFailed(ctx, err)
+			}
+
+			rate, err := au.GetShippingZoneRate(db, sm.ID, addr.LocationIDs())
+			if err != nil && !errors.IsRecordNotFoundError(err) {
+				db.Rollback()
+				return serveDatabaseQueryFailed(ctx, err)
+			}
+			if rate != nil {
+				sm.ApplyZoneRate(rate)
+			}
+		}
+
+		o.ShippingCharge = sm.CalculateDeliveryCharge(o.TotalWeight, totalVolume, o.SubTotal)
 	}
 
-	o.GrandTotal = o.SubTotal + o.ShippingCharge
+	if err := applyOrderTaxes(db, &o, availableItems, taxClasses); err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Address not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.AddressNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	o.GrandTotal = o.SubTotal + o.ShippingCharge + o.TaxAmount - o.IncludedTaxAmount
 	actualEarningsFromOrder := int64(0)
 
 	var couponID *string
@@ -410,8 +513,9 @@ func createNewOrder(ctx echo.Context, pld *validators.ReqOrderCreate) error {
 		return serveDatabaseQueryFailed(ctx, err)
 	}
 
-	o.ActualEarnings = actualEarningsFromOrder
-	o.PlatformEarnings = s.CalculateCommission(o.ActualEarnings)
+	// Taxes are collected by the seller but aren't subject to the platform commission
+	o.ActualEarnings = actualEarningsFromOrder + o.TaxAmount - o.IncludedTaxAmount
+	o.PlatformEarnings = s.CalculateCommission(o.ActualEarnings - o.TaxAmount)
 	o.SellerEarnings = o.ActualEarnings - o.PlatformEarnings
 
 	err = ou.Create(db, &o)
@@ -586,14 +690,9 @@ func createNewOrder(ctx echo.Context, pld *validators.ReqOrderCreate) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	if err := queue.SendOrderDetailsEmail(o.ID, "Thanks for your purchase"); err != nil {
+	if err := services.RecordEvent(db, models.EventOrderCreated, o.ID, &models.StatusChange{To: string(o.Status)}); err != nil {
 		db.Rollback()
-
-		resp.Title = "Failed to queue send order details"
-		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.FailedToEnqueueTask
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
+		return serveDatabaseQueryFailed(ctx, err)
 	}
 
 	if err := db.Commit().Error; err != nil {
@@ -732,6 +831,7 @@ func orderUpdateStatus(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	prevStatus := r.Status
 	r.Status = pld.Status
 
 	if err := ou.UpdateStatus(db, r); err != nil {
@@ -761,12 +861,12 @@ func orderUpdateStatus(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	if err := queue.SendOrderDetailsEmail(r.ID, "Order status updated"); err != nil {
+	if err := services.RecordStatusChange(db, models.EventOrderStatusChanged, r.ID, string(prevStatus), string(r.Status)); err != nil {
 		db.Rollback()
 
-		resp.Title = "Failed to enqueue task"
+		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.FailedToEnqueueTask
+		resp.Code = errors.DatabaseQueryFailed
 		resp.Errors = err
 		return resp.ServerJSON(ctx)
 	}
@@ -820,6 +920,7 @@ func orderPaymentStatusUpdate(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	prevPaymentStatus := r.PaymentStatus
 	r.PaymentStatus = pld.Status
 
 	if err := ou.UpdateStatus(db, r); err != nil {
@@ -849,12 +950,12 @@ func orderPaymentStatusUpdate(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	if err := queue.SendOrderDetailsEmail(r.ID, "Order payment status updated"); err != nil {
+	if err := services.RecordStatusChange(db, models.EventOrderPaymentStatusChanged, r.ID, string(prevPaymentStatus), string(r.PaymentStatus)); err != nil {
 		db.Rollback()
 
-		resp.Title = "Failed to enqueue task"
+		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.FailedToEnqueueTask
+		resp.Code = errors.DatabaseQueryFailed
 		resp.Errors = err
 		return resp.ServerJSON(ctx)
 	}
@@ -11,7 +11,7 @@ import (
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/models"
 	payment_gateways "github.com/shopicano/shopicano-backend/payment-gateways"
-	"github.com/shopicano/shopicano-backend/queue"
+	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"io/ioutil"
 	"net/http"
@@ -156,12 +156,12 @@ func processPayOrderForBrainTree(ctx echo.Context, o *models.OrderDetailsView) e
 	}
 
 	if o.PaymentStatus == models.PaymentCompleted {
-		if err := queue.SendPaymentConfirmationEmail(o.ID); err != nil {
+		if err := services.RecordEvent(db, models.EventPaymentCompleted, o.ID, nil); err != nil {
 			db.Rollback()
 
-			resp.Title = "Failed to enqueue task"
+			resp.Title = "Database query failed"
 			resp.Status = http.StatusInternalServerError
-			resp.Code = errors.FailedToEnqueueTask
+			resp.Code = errors.DatabaseQueryFailed
 			resp.Errors = err
 			return resp.ServerJSON(ctx)
 		}
@@ -235,12 +235,12 @@ func processPayOrderForStripe(ctx echo.Context, o *models.OrderDetailsView) erro
 	}
 
 	if o.PaymentStatus == models.PaymentCompleted {
-		if err := queue.SendPaymentConfirmationEmail(o.ID); err != nil {
+		if err := services.RecordEvent(db, models.EventPaymentCompleted, o.ID, nil); err != nil {
 			db.Rollback()
 
-			resp.Title = "Failed to enqueue task"
+			resp.Title = "Database query failed"
 			resp.Status = http.StatusInternalServerError
-			resp.Code = errors.FailedToEnqueueTask
+			resp.Code = errors.DatabaseQueryFailed
 			resp.Errors = err
 			return resp.ServerJSON(ctx)
 		}
@@ -344,12 +344,12 @@ func processPayOrderFor2Checkout(ctx echo.Context) error {
 	}
 
 	if m.PaymentStatus == models.PaymentCompleted {
-		if err := queue.SendPaymentConfirmationEmail(m.ID); err != nil {
+		if err := services.RecordEvent(db, models.EventPaymentCompleted, m.ID, nil); err != nil {
 			db.Rollback()
 
-			resp.Title = "Failed to enqueue task"
+			resp.Title = "Database query failed"
 			resp.Status = http.StatusInternalServerError
-			resp.Code = errors.FailedToEnqueueTask
+			resp.Code = errors.DatabaseQueryFailed
 			resp.Errors = err
 			return resp.ServerJSON(ctx)
 		}
@@ -423,12 +423,12 @@ func processPayOrderForSSL(ctx echo.Context, m *models.OrderDetailsView) error {
 	}
 
 	if m.PaymentStatus == models.PaymentCompleted {
-		if err := queue.SendPaymentConfirmationEmail(m.ID); err != nil {
+		if err := services.RecordEvent(db, models.EventPaymentCompleted, m.ID, nil); err != nil {
 			db.Rollback()
 
-			resp.Title = "Failed to enqueue task"
+			resp.Title = "Database query failed"
 			resp.Status = http.StatusInternalServerError
-			resp.Code = errors.FailedToEnqueueTask
+			resp.Code = errors.DatabaseQueryFailed
 			resp.Errors = err
 			return resp.ServerJSON(ctx)
 		}
@@ -514,12 +514,12 @@ func processPayOrderForPaddle(ctx echo.Context, m *models.OrderDetailsView) erro
 	}
 
 	if m.PaymentStatus == models.PaymentCompleted {
-		if err := queue.SendPaymentConfirmationEmail(m.ID); err != nil {
+		if err := services.RecordEvent(db, models.EventPaymentCompleted, m.ID, nil); err != nil {
 			db.Rollback()
 
-			resp.Title = "Failed to enqueue task"
+			resp.Title = "Database query failed"
 			resp.Status = http.StatusInternalServerError
-			resp.Code = errors.FailedToEnqueueTask
+			resp.Code = errors.DatabaseQueryFailed
 			resp.Errors = err
 			return resp.ServerJSON(ctx)
 		}
@@ -851,12 +851,12 @@ func revertOrderPaymentForAny(ctx echo.Context, details *models.OrderDetailsView
 		return resp.ServerJSON(ctx)
 	}
 
-	if err := queue.SendPaymentRevertedEmail(details.ID); err != nil {
+	if err := services.RecordEvent(db, models.EventPaymentReverted, details.ID, map[string]string{"reason": body.Reason}); err != nil {
 		db.Rollback()
 
-		resp.Title = "Failed to enqueue task"
+		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.FailedToEnqueueTask
+		resp.Code = errors.DatabaseQueryFailed
 		resp.Errors = err
 		return resp.ServerJSON(ctx)
 	}
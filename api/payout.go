@@ -7,6 +7,7 @@ import (
 	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/errors"
 	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"github.com/shopicano/shopicano-backend/validators"
 	"net/http"
@@ -503,11 +504,13 @@ func updatePayoutEntryByMarketplace(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	db := app.DB()
+	db := app.DB().Begin()
 	au := data.NewMarketplaceRepository()
 
 	entry, err := au.GetPayoutEntry(db, storeID, entryID)
 	if err != nil {
+		db.Rollback()
+
 		if errors.IsRecordNotFoundError(err) {
 			resp.Title = "Payout entry not found"
 			resp.Status = http.StatusNotFound
@@ -523,6 +526,7 @@ func updatePayoutEntryByMarketplace(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	prevStatus := entry.Status
 	if pld.Status != nil {
 		entry.Status = *pld.Status
 	}
@@ -538,6 +542,27 @@ func updatePayoutEntryByMarketplace(ctx echo.Context) error {
 
 	err = au.UpdatePayoutEntry(db, entry)
 	if err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := services.RecordEvent(db, models.EventPayoutUpdated, entry.ID,
+		&models.StatusChange{From: string(prevStatus), To: string(entry.Status)}); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
 		resp.Code = errors.DatabaseQueryFailed
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+)
+
+func requestDataExport(ctx echo.Context) error {
+	resp := core.Response{}
+
+	db := app.DB().Begin()
+
+	de, err := services.RequestDataExport(db, utils.GetUserID(ctx))
+	if err != nil {
+		db.Rollback()
+		return servePersonalDataFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Data export requested"
+	resp.Status = http.StatusAccepted
+	resp.Data = de
+	return resp.ServerJSON(ctx)
+}
+
+func listDataExports(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	pu := data.NewPersonalDataRepository()
+	exports, err := pu.ListExports(app.DB(), utils.GetUserID(ctx), int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = exports
+	return resp.ServerJSON(ctx)
+}
+
+func downloadDataExport(ctx echo.Context) error {
+	de, err := services.GetDownloadableDataExport(app.DB(), utils.GetUserID(ctx), ctx.Param("export_id"))
+	if err != nil {
+		return servePersonalDataFailed(ctx, err)
+	}
+
+	o, err := services.ServeAsStreamFromMinio(*de.Path)
+	if err != nil {
+		resp := core.Response{}
+		resp.Title = "Minio service failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.MinioServiceFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	defer o.Close()
+
+	ctx.Response().Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=\"personal-data-%s.zip\"", de.CreatedAt.Format("2006-01-02")))
+	return ctx.Stream(http.StatusOK, services.DataExportContentType, o)
+}
+
+func getAccountDeletion(ctx echo.Context) error {
+	resp := core.Response{}
+
+	pu := data.NewPersonalDataRepository()
+	ad, err := pu.GetPendingDeletion(app.DB(), utils.GetUserID(ctx))
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return servePersonalDataFailed(ctx, services.ErrAccountDeletionNotRequested)
+		}
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = ad
+	return resp.ServerJSON(ctx)
+}
+
+func requestAccountDeletion(ctx echo.Context) error {
+	req, err := validators.ValidateAccountDeletion(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.AccountDeletionDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	uc := data.NewUserRepository()
+	u, err := uc.Get(db, utils.GetUserID(ctx))
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := utils.CheckPassword(u.Password, req.Password); err != nil {
+		db.Rollback()
+
+		resp.Title = "Invalid login credentials"
+		resp.Status = http.StatusUnauthorized
+		resp.Code = errors.LoginCredentialsInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	ad, err := services.RequestAccountDeletion(db, u.ID)
+	if err != nil {
+		db.Rollback()
+		return servePersonalDataFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Account deletion scheduled"
+	resp.Status = http.StatusAccepted
+	resp.Data = ad
+	return resp.ServerJSON(ctx)
+}
+
+func cancelAccountDeletion(ctx echo.Context) error {
+	resp := core.Response{}
+
+	db := app.DB().Begin()
+
+	if err := services.CancelAccountDeletion(db, utils.GetUserID(ctx)); err != nil {
+		db.Rollback()
+		return servePersonalDataFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func cancelAccountDeletionWithToken(ctx echo.Context) error {
+	req, err := validators.ValidateAccountDeletionCancel(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.AccountDeletionDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	if err := services.CancelAccountDeletionWithToken(db, req.Token); err != nil {
+		db.Rollback()
+		return servePersonalDataFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Account deletion cancelled"
+	resp.Status = http.StatusOK
+	return resp.ServerJSON(ctx)
+}
+
+func servePersonalDataFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Data export not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.DataExportNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	switch err {
+	case services.ErrDataExportInProgress:
+		resp.Title = "A data export is already in progress"
+		resp.Status = http.StatusConflict
+		resp.Code = errors.DataExportInProgress
+	case services.ErrDataExportNotReady:
+		resp.Title = "Data export isn't ready or has expired"
+		resp.Status = http.StatusBadRequest
+		resp.Code = errors.DataExportNotReady
+	case services.ErrAccountDeletionAlreadyRequested:
+		resp.Title = "Account deletion is already requested"
+		resp.Status = http.StatusConflict
+		resp.Code = errors.AccountDeletionAlreadyRequested
+	case services.ErrAccountDeletionNotRequested:
+		resp.Title = "Account deletion isn't requested"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.AccountDeletionNotFound
+	case services.ErrAccountDeletionTokenInvalid:
+		resp.Title = "Invalid or expired account deletion token"
+		resp.Status = http.StatusUnauthorized
+		resp.Code = errors.AccountDeletionTokenInvalid
+	case services.ErrAccountOwnsStore:
+		resp.Title = "Hand over or close your stores before deleting your account"
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.AccountOwnsStore
+	default:
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
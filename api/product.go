@@ -29,12 +29,18 @@ func RegisterProductRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.GET("/", listProducts)
 		g.GET("/:product_id/", getProduct)
+		g.GET("/:product_id/reviews/", listProductReviews)
+	}(*productsPublicPath)
+
+	func(g echo.Group) {
+		g.Use(middlewares.JWTAuth())
+		g.POST("/:product_id/reviews/", createProductReview)
 	}(*productsPublicPath)
 
 	func(g echo.Group) {
 		g.Use(middlewares.HasStore())
 		g.Use(middlewares.IsStoreActive())
-		g.Use(middlewares.IsStoreManager())
+		g.Use(middlewares.HasStorePermission(models.StorePermProductsWrite))
 		g.POST("/", createProduct)
 		g.PATCH("/:product_id/", updateProduct)
 		g.DELETE("/:product_id/", deleteProduct)
@@ -44,6 +50,14 @@ func RegisterProductRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 		g.DELETE("/:product_id/attributes/:attribute_id/", deleteProductAttribute)
 		g.GET("/:product_id/download/", downloadProduct)
 		g.POST("/:product_id/upload/", saveDownloadableProduct)
+		g.GET("/:product_id/reviews/", listProductReviewsAsStoreOwner)
+		g.PUT("/:product_id/reviews/:review_id/reply/", replyProductReview)
+		g.POST("/:product_id/scheduled-prices/", createScheduledPrice)
+		g.GET("/:product_id/scheduled-prices/", listScheduledPrices)
+		g.DELETE("/:product_id/scheduled-prices/:sp_id/", deleteScheduledPrice)
+		g.GET("/:product_id/price-history/", listPriceHistory)
+		g.PUT("/:product_id/bundle-items/", setBundleItems)
+		g.GET("/:product_id/bundle-items/", listBundleItems)
 	}(*productsPlatformPath)
 }
 
@@ -65,19 +79,35 @@ func createProduct(ctx echo.Context) error {
 	if req.CategoryID != nil && *req.CategoryID == "" {
 		req.CategoryID = nil
 	}
+	if req.GlobalCategoryID != nil && *req.GlobalCategoryID == "" {
+		req.GlobalCategoryID = nil
+	}
+	if req.TaxClassID != nil && *req.TaxClassID == "" {
+		req.TaxClassID = nil
+	}
 
 	p := models.Product{
 		ID:               utils.NewUUID(),
 		StoreID:          storeID,
 		Price:            req.Price,
+		SalePrice:        req.SalePrice,
+		SaleStartsAt:     req.SaleStartsAt,
+		SaleEndsAt:       req.SaleEndsAt,
 		ProductCost:      req.ProductCost,
 		Stock:            req.Stock,
 		Name:             req.Name,
 		Slug:             slug.Make(req.Name),
 		IsShippable:      req.IsShippable,
 		CategoryID:       req.CategoryID,
+		GlobalCategoryID: req.GlobalCategoryID,
+		TaxClassID:       req.TaxClassID,
 		IsPublished:      req.IsPublished,
 		IsDigital:        req.IsDigital,
+		IsBundle:         req.IsBundle,
+		Weight:           req.Weight,
+		Length:           req.Length,
+		Width:            req.Width,
+		Height:           req.Height,
 		MaxQuantityCount: req.MaxQuantityCount,
 		SKU:              req.SKU,
 		Unit:             req.Unit,
@@ -126,6 +156,16 @@ func createProduct(ctx echo.Context) error {
 		}
 	}
 
+	if err := services.RecordEvent(db, models.EventProductCreated, p.ID, nil); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
 	if err := db.Commit().Error; err != nil {
 		resp.Title = "Failed to commit data"
 		resp.Status = http.StatusInternalServerError
@@ -179,9 +219,24 @@ func updateProduct(ctx echo.Context) error {
 		p.Name = *req.Name
 		p.Slug = slug.Make(*req.Name)
 	}
+	oldPrice := p.Price
+
 	if req.Price != nil {
 		p.Price = *req.Price
 	}
+	if req.SalePrice != nil {
+		p.SalePrice = *req.SalePrice
+		if p.SalePrice == 0 {
+			p.SaleStartsAt = nil
+			p.SaleEndsAt = nil
+		}
+	}
+	if req.SaleStartsAt != nil {
+		p.SaleStartsAt = req.SaleStartsAt
+	}
+	if req.SaleEndsAt != nil {
+		p.SaleEndsAt = req.SaleEndsAt
+	}
 	if req.ProductCost != nil {
 		p.ProductCost = *req.ProductCost
 	}
@@ -194,12 +249,56 @@ func updateProduct(ctx echo.Context) error {
 	if req.IsPublished != nil {
 		p.IsPublished = *req.IsPublished
 	}
+	if req.IsBundle != nil {
+		if *req.IsBundle && !p.IsBundle {
+			isBundleItem, err := pu.IsBundleItem(db, p.ID)
+			if err != nil {
+				db.Rollback()
+				return serveDatabaseQueryFailed(ctx, err)
+			}
+			if isBundleItem {
+				db.Rollback()
+
+				resp.Title = "Product is a part of bundle, it can't be a bundle"
+				resp.Status = http.StatusBadRequest
+				resp.Code = errors.InvalidBundleItem
+				return resp.ServerJSON(ctx)
+			}
+		}
+		p.IsBundle = *req.IsBundle
+	}
+	if req.Weight != nil {
+		p.Weight = *req.Weight
+	}
+	if req.Length != nil {
+		p.Length = *req.Length
+	}
+	if req.Width != nil {
+		p.Width = *req.Width
+	}
+	if req.Height != nil {
+		p.Height = *req.Height
+	}
 	if req.Stock != nil {
 		p.Stock = *req.Stock
 	}
 	if req.CategoryID != nil {
 		p.CategoryID = req.CategoryID
 	}
+	if req.GlobalCategoryID != nil {
+		if *req.GlobalCategoryID == "" {
+			p.GlobalCategoryID = nil
+		} else {
+			p.GlobalCategoryID = req.GlobalCategoryID
+		}
+	}
+	if req.TaxClassID != nil {
+		if *req.TaxClassID == "" {
+			p.TaxClassID = nil
+		} else {
+			p.TaxClassID = req.TaxClassID
+		}
+	}
 	if req.Image != nil {
 		p.Image = *req.Image
 	}
@@ -247,6 +346,31 @@ func updateProduct(ctx echo.Context) error {
 		p.MaxQuantityCount = *req.MaxQuantityCount
 	}
 
+	if p.SalePrice > 0 && p.SalePrice >= p.Price {
+		db.Rollback()
+
+		ve := errors.ValidationError{}
+		ve.Add("sale_price", "must be less than price")
+
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ProductCreationDataInvalid
+		resp.Errors = &ve
+		return resp.ServerJSON(ctx)
+	}
+	if p.SaleStartsAt != nil && p.SaleEndsAt != nil && !p.SaleEndsAt.After(*p.SaleStartsAt) {
+		db.Rollback()
+
+		ve := errors.ValidationError{}
+		ve.Add("sale_ends_at", "must be after sale_starts_at")
+
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ProductCreationDataInvalid
+		resp.Errors = &ve
+		return resp.ServerJSON(ctx)
+	}
+
 	p.UpdatedAt = time.Now().UTC()
 
 	err = pu.Update(db, p)
@@ -269,6 +393,39 @@ func updateProduct(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	if oldPrice != p.Price {
+		userID := utils.GetUserID(ctx)
+
+		prc := data.NewPriceRepository()
+		if err := prc.CreateHistory(db, &models.PriceHistory{
+			ID:        utils.NewUUID(),
+			ProductID: p.ID,
+			OldPrice:  oldPrice,
+			NewPrice:  p.Price,
+			Source:    models.PriceChangeManual,
+			ChangedBy: &userID,
+			CreatedAt: p.UpdatedAt,
+		}); err != nil {
+			db.Rollback()
+
+			resp.Title = "Database query failed"
+			resp.Status = http.StatusInternalServerError
+			resp.Code = errors.DatabaseQueryFailed
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+	}
+
+	if err := services.RecordEvent(db, models.EventProductUpdated, p.ID, nil); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
 	if err := db.Commit().Error; err != nil {
 		resp.Title = "Failed to commit data"
 		resp.Status = http.StatusInternalServerError
@@ -538,6 +695,10 @@ func addProductAttribute(ctx echo.Context) error {
 		ProductID: p.ID,
 		Key:       req.Key,
 		Value:     req.Value,
+		Weight:    req.Weight,
+		Length:    req.Length,
+		Width:     req.Width,
+		Height:    req.Height,
 	}
 
 	err = pu.AddAttribute(db, &v)
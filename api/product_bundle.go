@@ -0,0 +1,142 @@
+package api
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+)
+
+func setBundleItems(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	productID := ctx.Param("product_id")
+
+	req, err := validators.ValidateSetBundleItems(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.BundleItemsDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	pu := data.NewProductRepository()
+	p, err := pu.GetAsStoreStuff(db, storeID, productID)
+	if err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Product not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ProductNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if !p.IsBundle {
+		db.Rollback()
+
+		resp.Title = "Product is not a bundle"
+		resp.Status = http.StatusBadRequest
+		resp.Code = errors.InvalidBundleItem
+		return resp.ServerJSON(ctx)
+	}
+
+	var items []models.ProductBundleItem
+	for _, v := range req.Items {
+		c, err := pu.GetAsStoreStuff(db, storeID, v.ProductID)
+		if err != nil {
+			db.Rollback()
+
+			if errors.IsRecordNotFoundError(err) {
+				resp.Title = fmt.Sprintf("Product %s not found", v.ProductID)
+				resp.Status = http.StatusNotFound
+				resp.Code = errors.ProductNotFound
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+
+		if c.IsBundle || c.ID == p.ID {
+			db.Rollback()
+
+			resp.Title = fmt.Sprintf("Product %s can't be a part of bundle", c.Name)
+			resp.Status = http.StatusBadRequest
+			resp.Code = errors.InvalidBundleItem
+			return resp.ServerJSON(ctx)
+		}
+
+		items = append(items, models.ProductBundleItem{
+			BundleID:  p.ID,
+			ProductID: c.ID,
+			Quantity:  v.Quantity,
+		})
+	}
+
+	if err := pu.SetBundleItems(db, p.ID, items); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	bundleItems, err := pu.ListBundleItems(db, p.ID)
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = bundleItems
+	return resp.ServerJSON(ctx)
+}
+
+func listBundleItems(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	productID := ctx.Param("product_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	pu := data.NewProductRepository()
+	p, err := pu.GetAsStoreStuff(db, storeID, productID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Product not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ProductNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	bundleItems, err := pu.ListBundleItems(db, p.ID)
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = bundleItems
+	return resp.ServerJSON(ctx)
+}
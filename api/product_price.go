@@ -0,0 +1,197 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func createScheduledPrice(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	productID := ctx.Param("product_id")
+
+	req, err := validators.ValidateCreateScheduledPrice(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ScheduledPriceDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+
+	pu := data.NewProductRepository()
+	p, err := pu.GetAsStoreStuff(db, storeID, productID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Product not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ProductNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	sp := &models.ScheduledPrice{
+		ID:          utils.NewUUID(),
+		ProductID:   p.ID,
+		StoreID:     storeID,
+		Price:       req.Price,
+		EffectiveAt: req.EffectiveAt.UTC(),
+		CreatedBy:   utils.GetUserID(ctx),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	prc := data.NewPriceRepository()
+	if err := prc.CreateScheduledPrice(db, sp); err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = sp
+	return resp.ServerJSON(ctx)
+}
+
+func listScheduledPrices(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	productID := ctx.Param("product_id")
+
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	prc := data.NewPriceRepository()
+	sps, err := prc.ListScheduledPrices(db, storeID, productID, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = sps
+	return resp.ServerJSON(ctx)
+}
+
+func deleteScheduledPrice(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	productID := ctx.Param("product_id")
+	spID := ctx.Param("sp_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	prc := data.NewPriceRepository()
+	if err := prc.DeleteScheduledPrice(db, storeID, productID, spID); err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Scheduled price not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ScheduledPriceNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func listPriceHistory(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	productID := ctx.Param("product_id")
+
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	pu := data.NewProductRepository()
+	p, err := pu.GetAsStoreStuff(db, storeID, productID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Product not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ProductNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	prc := data.NewPriceRepository()
+	phs, err := prc.ListHistory(db, p.ID, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = phs
+	return resp.ServerJSON(ctx)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/shopicano/shopicano-backend/utils"
 	"github.com/shopicano/shopicano-backend/validators"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -68,3 +69,380 @@ func createReview(ctx echo.Context) error {
 	resp.Data = rv
 	return resp.ServerJSON(ctx)
 }
+
+func createProductReview(ctx echo.Context) error {
+	productID := ctx.Param("product_id")
+	userID := utils.GetUserID(ctx)
+
+	resp := core.Response{}
+
+	pld, err := validators.ValidateCreateProductReview(ctx)
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ReviewDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	pu := data.NewProductRepository()
+	p, err := pu.Get(db, productID)
+	if err != nil || !p.IsPublished {
+		db.Rollback()
+
+		if err == nil || errors.IsRecordNotFoundError(err) {
+			resp.Title = "Product not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ProductNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	ru := data.NewReviewRepository()
+	orderID, err := ru.GetPurchasedOrderID(db, userID, p.ID)
+	if err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Only verified purchasers can review the product"
+			resp.Status = http.StatusForbidden
+			resp.Code = errors.ReviewerNotVerifiedPurchaser
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	rv := &models.ProductReview{
+		ID:          utils.NewUUID(),
+		ProductID:   p.ID,
+		StoreID:     p.StoreID,
+		UserID:      userID,
+		OrderID:     orderID,
+		Rating:      pld.Rating,
+		Description: pld.Description,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err := ru.Create(db, rv); err != nil {
+		db.Rollback()
+
+		if _, ok := errors.IsDuplicateKeyError(err); ok {
+			resp.Title = "Product already reviewed"
+			resp.Status = http.StatusConflict
+			resp.Code = errors.ReviewAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	for _, img := range pld.Images {
+		if err := ru.AddImage(db, rv.ID, img); err != nil {
+			db.Rollback()
+
+			resp.Title = "Database query failed"
+			resp.Status = http.StatusInternalServerError
+			resp.Code = errors.DatabaseQueryFailed
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+	}
+
+	if err := ru.UpdateProductRating(db, p.ID); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	r, err := ru.GetDetails(app.DB(), rv.ID)
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = r
+	return resp.ServerJSON(ctx)
+}
+
+func listProductReviews(ctx echo.Context) error {
+	productID := ctx.Param("product_id")
+
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	ru := data.NewReviewRepository()
+	reviews, err := ru.List(db, productID, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = reviews
+	return resp.ServerJSON(ctx)
+}
+
+func listProductReviewsAsStoreOwner(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	productID := ctx.Param("product_id")
+
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	ru := data.NewReviewRepository()
+	reviews, err := ru.ListAsStoreStuff(db, storeID, productID, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = reviews
+	return resp.ServerJSON(ctx)
+}
+
+func replyProductReview(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	productID := ctx.Param("product_id")
+	reviewID := ctx.Param("review_id")
+
+	resp := core.Response{}
+
+	pld, err := validators.ValidateProductReviewReply(ctx)
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ReviewDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+
+	ru := data.NewReviewRepository()
+	rv, err := ru.GetAsStoreStuff(db, storeID, productID, reviewID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Review not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ReviewNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	now := time.Now().UTC()
+	rv.Reply = pld.Reply
+	rv.RepliedAt = &now
+	rv.UpdatedAt = now
+
+	if err := ru.Update(db, rv); err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = rv
+	return resp.ServerJSON(ctx)
+}
+
+func listReviewsAsAdmin(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+	flaggedQ := ctx.Request().URL.Query().Get("flagged")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+	flagged, _ := strconv.ParseBool(flaggedQ)
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	ru := data.NewReviewRepository()
+	reviews, err := ru.ListAsAdmin(db, flagged, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = reviews
+	return resp.ServerJSON(ctx)
+}
+
+func moderateReview(ctx echo.Context) error {
+	reviewID := ctx.Param("review_id")
+
+	resp := core.Response{}
+
+	pld, err := validators.ValidateProductReviewModerate(ctx)
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ReviewDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	ru := data.NewReviewRepository()
+	rv, err := ru.Get(db, reviewID)
+	if err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Review not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ReviewNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if pld.IsHidden != nil {
+		rv.IsHidden = *pld.IsHidden
+	}
+	if pld.IsFlagged != nil {
+		rv.IsFlagged = *pld.IsFlagged
+		if !rv.IsFlagged {
+			rv.FlagReason = ""
+		}
+	}
+	if pld.FlagReason != nil {
+		rv.FlagReason = *pld.FlagReason
+	}
+	rv.UpdatedAt = time.Now().UTC()
+
+	if err := ru.Update(db, rv); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := ru.UpdateProductRating(db, rv.ProductID); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = rv
+	return resp.ServerJSON(ctx)
+}
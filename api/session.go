@@ -0,0 +1,79 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"net/http"
+	"time"
+)
+
+type sessionResponse struct {
+	models.Session
+	IsCurrent bool `json:"is_current"`
+}
+
+func listSessions(ctx echo.Context) error {
+	resp := core.Response{}
+
+	su := data.NewSessionRepository()
+	sessions, err := su.ListActive(app.DB(), utils.GetUserID(ctx), time.Now().UTC())
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	res := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		res = append(res, sessionResponse{
+			Session:   s,
+			IsCurrent: s.ID == utils.GetSessionID(ctx),
+		})
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = res
+	return resp.ServerJSON(ctx)
+}
+
+func revokeSession(ctx echo.Context) error {
+	sessionID := ctx.Param("session_id")
+
+	resp := core.Response{}
+
+	su := data.NewSessionRepository()
+	if err := su.Revoke(app.DB(), utils.GetUserID(ctx), sessionID, models.SessionRevokedByUser, time.Now().UTC()); err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Session not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.SessionNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+// revokeAllSessions signs the user out everywhere, except_current=true keeps the session of the request
+func revokeAllSessions(ctx echo.Context) error {
+	resp := core.Response{}
+
+	exceptID := ""
+	if ctx.QueryParam("except_current") == "true" {
+		exceptID = utils.GetSessionID(ctx)
+	}
+
+	su := data.NewSessionRepository()
+	if err := su.RevokeAll(app.DB(), utils.GetUserID(ctx), exceptID, models.SessionRevokedByUser, time.Now().UTC()); err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
@@ -27,22 +27,29 @@ func createShippingMethod(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	db := app.DB()
+	db := app.DB().Begin()
 
 	m := &models.ShippingMethod{
 		ID:                      utils.NewUUID(),
 		Name:                    req.Name,
 		IsPublished:             req.IsPublished,
 		ApproximateDeliveryTime: req.ApproximateDeliveryTime,
+		HandlingTime:            req.HandlingTime,
 		DeliveryCharge:          req.DeliveryCharge,
 		WeightUnit:              req.WeightUnit,
 		IsFlat:                  req.IsFlat,
+		PerKgRate:               req.PerKgRate,
+		VolumetricDivisor:       req.VolumetricDivisor,
+		FreeShippingThreshold:   req.FreeShippingThreshold,
+		WeightBrackets:          toShippingWeightBrackets(req.WeightBrackets),
 		CreatedAt:               time.Now().UTC(),
 		UpdatedAt:               time.Now().UTC(),
 	}
 
 	au := data.NewMarketplaceRepository()
 	if err := au.CreateShippingMethod(db, m); err != nil {
+		db.Rollback()
+
 		msg, ok := errors.IsDuplicateKeyError(err)
 		if ok {
 			resp.Title = msg
@@ -59,6 +66,24 @@ func createShippingMethod(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	if err := au.SetShippingWeightBrackets(db, m.ID, m.WeightBrackets); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
 	resp.Status = http.StatusCreated
 	resp.Data = m
 	return resp.ServerJSON(ctx)
@@ -79,11 +104,13 @@ func updateShippingMethod(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	db := app.DB()
+	db := app.DB().Begin()
 
 	au := data.NewMarketplaceRepository()
-	m, err := au.GetShippingMethod(db, ID)
+	m, err := au.GetPlatformShippingMethod(db, ID)
 	if err != nil {
+		db.Rollback()
+
 		if errors.IsRecordNotFoundError(err) {
 			resp.Title = "Shipping method not found"
 			resp.Status = http.StatusNotFound
@@ -102,12 +129,37 @@ func updateShippingMethod(ctx echo.Context) error {
 	m.Name = req.Name
 	m.IsPublished = req.IsPublished
 	m.ApproximateDeliveryTime = req.ApproximateDeliveryTime
+	m.HandlingTime = req.HandlingTime
 	m.IsFlat = req.IsFlat
 	m.DeliveryCharge = req.DeliveryCharge
 	m.WeightUnit = req.WeightUnit
+	m.PerKgRate = req.PerKgRate
+	m.VolumetricDivisor = req.VolumetricDivisor
+	m.FreeShippingThreshold = req.FreeShippingThreshold
+	m.WeightBrackets = toShippingWeightBrackets(req.WeightBrackets)
 	m.UpdatedAt = time.Now().UTC()
 
 	if err := au.UpdateShippingMethod(db, m); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := au.SetShippingWeightBrackets(db, m.ID, m.WeightBrackets); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
 		resp.Code = errors.DatabaseQueryFailed
@@ -156,7 +208,7 @@ func getShippingMethod(ctx echo.Context) error {
 	db := app.DB()
 
 	au := data.NewMarketplaceRepository()
-	sm, err := au.GetShippingMethod(db, ID)
+	sm, err := au.GetPlatformShippingMethod(db, ID)
 	if err != nil {
 		if errors.IsRecordNotFoundError(err) {
 			resp.Title = "Shipping method not found"
@@ -243,3 +295,16 @@ func listShippingMethodsAsAdmin(ctx echo.Context) error {
 	resp.Data = v
 	return resp.ServerJSON(ctx)
 }
+
+func toShippingWeightBrackets(req []validators.ReqShippingWeightBracket) []models.ShippingWeightBracket {
+	brackets := []models.ShippingWeightBracket{}
+	for _, b := range req {
+		brackets = append(brackets, models.ShippingWeightBracket{
+			ID:        utils.NewUUID(),
+			MinWeight: b.MinWeight,
+			MaxWeight: b.MaxWeight,
+			Charge:    b.Charge,
+		})
+	}
+	return brackets
+}
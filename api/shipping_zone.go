@@ -0,0 +1,380 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func createShippingZone(ctx echo.Context) error {
+	req, err := validators.ValidateCreateShippingZone(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ShippingZoneDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+	au := data.NewMarketplaceRepository()
+
+	m := &models.ShippingZone{
+		ID:          utils.NewUUID(),
+		Name:        req.Name,
+		Description: req.Description,
+		IsPublished: req.IsPublished,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err := au.CreateShippingZone(db, m); err != nil {
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.ShippingZoneAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = m
+	return resp.ServerJSON(ctx)
+}
+
+func updateShippingZone(ctx echo.Context) error {
+	zoneID := ctx.Param("zone_id")
+
+	req, err := validators.ValidateUpdateShippingZone(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ShippingZoneDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	sz, err := au.GetShippingZone(db, zoneID)
+	if err != nil {
+		return serveShippingZoneQueryFailed(ctx, err)
+	}
+
+	if req.Name != nil {
+		sz.Name = *req.Name
+	}
+	if req.Description != nil {
+		sz.Description = *req.Description
+	}
+	if req.IsPublished != nil {
+		sz.IsPublished = *req.IsPublished
+	}
+
+	sz.UpdatedAt = time.Now().UTC()
+
+	if err := au.UpdateShippingZone(db, sz); err != nil {
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.ShippingZoneAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = sz
+	return resp.ServerJSON(ctx)
+}
+
+func deleteShippingZone(ctx echo.Context) error {
+	zoneID := ctx.Param("zone_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+	au := data.NewMarketplaceRepository()
+	if err := au.DeleteShippingZone(db, zoneID); err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func listShippingZones(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	zones, err := au.ListShippingZones(db, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = zones
+	return resp.ServerJSON(ctx)
+}
+
+func getShippingZone(ctx echo.Context) error {
+	zoneID := ctx.Param("zone_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	sz, err := au.GetShippingZone(db, zoneID)
+	if err != nil {
+		return serveShippingZoneQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = sz
+	return resp.ServerJSON(ctx)
+}
+
+func setShippingZoneLocations(ctx echo.Context) error {
+	zoneID := ctx.Param("zone_id")
+
+	req, err := validators.ValidateSetShippingZoneLocations(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ShippingZoneDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	au := data.NewMarketplaceRepository()
+	if _, err := au.GetShippingZone(db, zoneID); err != nil {
+		db.Rollback()
+		return serveShippingZoneQueryFailed(ctx, err)
+	}
+
+	lu := data.NewLocationRepository()
+	for _, id := range req.LocationIDs {
+		if _, err := lu.FindByID(db, int(id)); err != nil {
+			db.Rollback()
+
+			if errors.IsRecordNotFoundError(err) {
+				resp.Title = "Location not found"
+				resp.Status = http.StatusNotFound
+				resp.Code = errors.LocationNotFound
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			resp.Title = "Database query failed"
+			resp.Status = http.StatusInternalServerError
+			resp.Code = errors.DatabaseQueryFailed
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+	}
+
+	if err := au.SetShippingZoneLocations(db, zoneID, req.LocationIDs); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	sz, err := au.GetShippingZone(db, zoneID)
+	if err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = sz
+	return resp.ServerJSON(ctx)
+}
+
+func setShippingZoneRate(ctx echo.Context) error {
+	zoneID := ctx.Param("zone_id")
+
+	req, err := validators.ValidateSetShippingZoneRate(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ShippingZoneDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	au := data.NewMarketplaceRepository()
+	if _, err := au.GetShippingZone(db, zoneID); err != nil {
+		db.Rollback()
+		return serveShippingZoneQueryFailed(ctx, err)
+	}
+
+	if _, err := au.GetShippingMethod(db, req.ShippingMethodID); err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Shipping method not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.ShippingMethodNotFound
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	r := &models.ShippingZoneRate{
+		ZoneID:                zoneID,
+		ShippingMethodID:      req.ShippingMethodID,
+		DeliveryCharge:        req.DeliveryCharge,
+		PerKgRate:             req.PerKgRate,
+		FreeShippingThreshold: req.FreeShippingThreshold,
+		CreatedAt:             time.Now().UTC(),
+		UpdatedAt:             time.Now().UTC(),
+	}
+
+	if err := au.SetShippingZoneRate(db, r); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = r
+	return resp.ServerJSON(ctx)
+}
+
+func deleteShippingZoneRate(ctx echo.Context) error {
+	zoneID := ctx.Param("zone_id")
+	smID := ctx.Param("sm_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+	au := data.NewMarketplaceRepository()
+	if err := au.DeleteShippingZoneRate(db, zoneID, smID); err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func serveShippingZoneQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Shipping zone not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.ShippingZoneNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Title = "Database query failed"
+	resp.Status = http.StatusInternalServerError
+	resp.Code = errors.DatabaseQueryFailed
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
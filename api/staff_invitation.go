@@ -0,0 +1,297 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+)
+
+func inviteStoreStaff(ctx echo.Context) error {
+	req, err := validators.ValidateStaffInvitation(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.StaffInvitationDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	storeID := utils.GetStoreID(ctx)
+
+	db := app.DB().Begin()
+
+	if req.RoleID != nil {
+		sr := data.NewStoreRoleRepository()
+		if _, err := sr.Get(db, storeID, *req.RoleID); err != nil {
+			db.Rollback()
+			return serveStoreRoleFailed(ctx, err)
+		}
+	}
+
+	if err := services.CheckStaffGrant(db, utils.GetStorePermission(ctx), utils.GetStorePerms(ctx), services.StaffLevel(req.PermissionID), req.RoleID); err != nil {
+		db.Rollback()
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	si, err := services.InviteStaff(db, storeID, utils.GetUserID(ctx), req.Email, req.PermissionID, req.RoleID)
+	if err != nil {
+		db.Rollback()
+		return serveStaffInvitationFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Staff invited"
+	resp.Status = http.StatusCreated
+	resp.Data = si
+	return resp.ServerJSON(ctx)
+}
+
+func listStaffInvitations(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+	statusQ := ctx.Request().URL.Query().Get("status")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	status := models.StaffInvitationPending
+	if statusQ != "" {
+		status = models.StaffInvitationStatus(statusQ)
+		if !status.IsValid() {
+			resp.Title = "Invalid status"
+			resp.Status = http.StatusUnprocessableEntity
+			resp.Code = errors.StaffInvitationDataInvalid
+			return resp.ServerJSON(ctx)
+		}
+	}
+
+	iu := data.NewStaffInvitationRepository()
+	invitations, err := iu.List(app.DB(), utils.GetStoreID(ctx), status, int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = invitations
+	return resp.ServerJSON(ctx)
+}
+
+func revokeStaffInvitation(ctx echo.Context) error {
+	resp := core.Response{}
+
+	db := app.DB().Begin()
+
+	if err := services.RevokeStaffInvitation(db, utils.GetStoreID(ctx), ctx.Param("invitation_id"), utils.GetUserID(ctx)); err != nil {
+		db.Rollback()
+		return serveStaffInvitationFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func listStaffAuditLogs(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	iu := data.NewStaffInvitationRepository()
+	logs, err := iu.ListAuditLogs(app.DB(), utils.GetStoreID(ctx), int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = logs
+	return resp.ServerJSON(ctx)
+}
+
+func getStaffInvitation(ctx echo.Context) error {
+	token := ctx.QueryParam("token")
+
+	resp := core.Response{}
+
+	sid, err := services.GetStaffInvitation(app.DB(), token)
+	if err != nil {
+		return serveStaffInvitationFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = sid
+	return resp.ServerJSON(ctx)
+}
+
+func acceptStaffInvitation(ctx echo.Context) error {
+	req, err := validators.ValidateStaffInvitationToken(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.StaffInvitationDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	si, err := services.AcceptStaffInvitation(db, req.Token, utils.GetUserID(ctx))
+	if err != nil {
+		db.Rollback()
+		return serveStaffInvitationFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Invitation accepted"
+	resp.Status = http.StatusOK
+	resp.Data = si
+	return resp.ServerJSON(ctx)
+}
+
+func declineStaffInvitation(ctx echo.Context) error {
+	req, err := validators.ValidateStaffInvitationToken(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.StaffInvitationDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	if err := services.DeclineStaffInvitation(db, req.Token); err != nil {
+		db.Rollback()
+		return serveStaffInvitationFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Invitation declined"
+	resp.Status = http.StatusOK
+	return resp.ServerJSON(ctx)
+}
+
+func signUpWithStaffInvitation(ctx echo.Context) error {
+	u, token, err := validators.ValidateStaffInvitationSignUp(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.UserSignUpDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	if _, err := services.SignUpWithStaffInvitation(db, token, u); err != nil {
+		db.Rollback()
+
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = "User already register"
+			resp.Status = http.StatusConflict
+			resp.Code = errors.UserAlreadyExists
+			resp.Errors = errors.NewError(msg)
+			return resp.ServerJSON(ctx)
+		}
+		return serveStaffInvitationFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "User registration successful"
+	resp.Status = http.StatusCreated
+	resp.Data = u
+	return resp.ServerJSON(ctx)
+}
+
+func serveStaffInvitationFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Invitation not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.StaffInvitationNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	switch err {
+	case services.ErrStaffInvitationInvalid:
+		resp.Title = "Invalid or expired invitation"
+		resp.Status = http.StatusBadRequest
+		resp.Code = errors.StaffInvitationInvalid
+	case services.ErrStaffInvitationEmailMismatch:
+		resp.Title = "Invitation is for another email"
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.StaffInvitationEmailMismatch
+	case services.ErrStaffInvitationAlreadyExists:
+		resp.Title = "Email already invited"
+		resp.Status = http.StatusConflict
+		resp.Code = errors.StaffInvitationAlreadyExists
+	case services.ErrUserAlreadyStaff:
+		resp.Title = "User already staff"
+		resp.Status = http.StatusConflict
+		resp.Code = errors.UserAlreadyStaff
+	default:
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
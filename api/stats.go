@@ -21,7 +21,7 @@ func RegisterStatsRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.HasStore())
 		g.Use(middlewares.IsStoreActive())
-		g.Use(middlewares.IsStoreManager())
+		g.Use(middlewares.HasStorePermission(models.StorePermStatsRead))
 		g.GET("/products/", productStatsAsStoreOwner)
 		g.GET("/categories/", categoryStatsAsStoreOwner)
 		g.GET("/orders/", orderStats)
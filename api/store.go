@@ -8,6 +8,7 @@ import (
 	"github.com/shopicano/shopicano-backend/errors"
 	"github.com/shopicano/shopicano-backend/middlewares"
 	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"github.com/shopicano/shopicano-backend/validators"
 	"github.com/shopicano/shopicano-backend/values"
@@ -18,6 +19,14 @@ import (
 func RegisterStoreRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	storesPublicPath := publicEndpoints.Group("/stores")
 	storesPlatformPath := platformEndpoints.Group("/stores")
+	staffInvitationsPath := publicEndpoints.Group("/staff-invitations")
+
+	func(g echo.Group) {
+		g.GET("/", getStaffInvitation)
+		g.POST("/decline/", declineStaffInvitation)
+		g.POST("/sign-up/", signUpWithStaffInvitation)
+		g.POST("/accept/", acceptStaffInvitation, middlewares.JWTAuth())
+	}(*staffInvitationsPath)
 
 	func(g echo.Group) {
 		g.GET("/:store_id/", getStore)
@@ -26,7 +35,7 @@ func RegisterStoreRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.JWTAuth())
 		g.Use(middlewares.HasStore())
-		g.Use(middlewares.IsStoreManager())
+		g.Use(middlewares.HasStorePermission(models.StorePermStoreManage))
 		g.GET("/", getStoreForOwner)
 		g.PATCH("/", updateStore)
 	}(*storesPublicPath)
@@ -34,11 +43,21 @@ func RegisterStoreRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 	func(g echo.Group) {
 		g.Use(middlewares.JWTAuth())
 		g.Use(middlewares.HasStore())
-		g.Use(middlewares.IsStoreAdmin())
-		g.POST("/:store_id/staffs/", addStoreStaff)
-		g.PATCH("/:store_id/staffs/:user_id/", updateStoreStaffPermission)
+		g.Use(middlewares.HasStorePermission(models.StorePermStaffManage))
 		g.DELETE("/:store_id/staffs/:user_id/", deleteStoreStaff)
 		g.GET("/:store_id/staffs/", listStaffs)
+		g.GET("/:store_id/staffs/audit-logs/", listStaffAuditLogs)
+		g.POST("/:store_id/invitations/", inviteStoreStaff)
+		g.GET("/:store_id/invitations/", listStaffInvitations)
+		g.DELETE("/:store_id/invitations/:invitation_id/", revokeStaffInvitation)
+		g.GET("/:store_id/roles/", listStoreRoles)
+		g.GET("/:store_id/roles/:role_id/", getStoreRole)
+	}(*storesPublicPath)
+
+	func(g echo.Group) {
+		g.Use(middlewares.JWTAuth())
+		g.Use(middlewares.HasStore())
+		g.Use(middlewares.HasStorePermission(models.StorePermPayoutsRequest))
 		g.POST("/:store_id/payout-settings/", createOrUpdatePayoutSettings)
 		g.GET("/:store_id/payout-settings/", getPayoutSettings)
 		g.POST("/:store_id/payouts/entries/", createPayoutEntry)
@@ -47,12 +66,55 @@ func RegisterStoreRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 		g.GET("/:store_id/payouts/summary/", getStorePayoutSummary)
 	}(*storesPublicPath)
 
+	func(g echo.Group) {
+		g.Use(middlewares.JWTAuth())
+		g.Use(middlewares.HasStore())
+		g.Use(middlewares.IsStoreAdmin())
+		g.PATCH("/:store_id/staffs/:user_id/", updateStoreStaffPermission)
+		g.PUT("/:store_id/staffs/:user_id/role/", setStoreStaffRole)
+		g.POST("/:store_id/roles/", createStoreRole)
+		g.PUT("/:store_id/roles/:role_id/", updateStoreRole)
+		g.DELETE("/:store_id/roles/:role_id/", deleteStoreRole)
+		g.GET("/:store_id/email-branding/", getStoreEmailBranding)
+		g.PUT("/:store_id/email-branding/", updateStoreEmailBranding)
+		g.POST("/:store_id/webhooks/", createWebhook)
+		g.GET("/:store_id/webhooks/", listWebhooks)
+		g.GET("/:store_id/webhooks/:webhook_id/", getWebhook)
+		g.PATCH("/:store_id/webhooks/:webhook_id/", updateWebhook)
+		g.DELETE("/:store_id/webhooks/:webhook_id/", deleteWebhook)
+		g.POST("/:store_id/webhooks/:webhook_id/ping/", pingWebhook)
+		g.GET("/:store_id/webhooks/:webhook_id/deliveries/", listWebhookDeliveries)
+		g.POST("/:store_id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver/", redeliverWebhook)
+		g.GET("/:store_id/api-keys/", listStoreAPIKeys)
+		g.POST("/:store_id/api-keys/", createStoreAPIKey)
+		g.GET("/:store_id/api-keys/:key_id/", getStoreAPIKey)
+		g.DELETE("/:store_id/api-keys/:key_id/", revokeStoreAPIKey)
+	}(*storesPublicPath)
+
+	func(g echo.Group) {
+		g.Use(middlewares.JWTAuth())
+		g.GET("/memberships/", listStoreMemberships)
+	}(*storesPublicPath)
+
 	func(g echo.Group) {
 		g.Use(middlewares.IsStoreCreationEnabled)
 		g.Use(middlewares.JWTAuth())
 		g.POST("/", createStore)
 	}(*storesPublicPath)
 
+	func(g echo.Group) {
+		g.Use(middlewares.HasStore())
+		g.Use(middlewares.IsStoreActive())
+		g.Use(middlewares.IsStoreAdmin())
+		g.POST("/:store_id/shipping-methods/", createStoreShippingMethod)
+		g.PUT("/:store_id/shipping-methods/:id/", updateStoreShippingMethod)
+		g.DELETE("/:store_id/shipping-methods/:id/", deleteStoreShippingMethod)
+		g.GET("/:store_id/shipping-methods/", listStoreShippingMethods)
+		g.GET("/:store_id/shipping-methods/:id/", getStoreShippingMethod)
+		g.PUT("/:store_id/shipping-methods/:id/locations/", setStoreShippingMethodLocations)
+		g.GET("/:store_id/shipping-methods/:id/locations/", listStoreShippingMethodLocations)
+	}(*storesPlatformPath)
+
 	func(g echo.Group) {
 		g.Use(middlewares.IsPlatformManager)
 		g.GET("/", listStores)
@@ -84,25 +146,6 @@ func createStore(ctx echo.Context) error {
 
 	su := data.NewStoreRepository()
 
-	as, err := su.GetStoreUserProfile(db, utils.GetUserID(ctx))
-	if err != nil && !errors.IsRecordNotFoundError(err) {
-		db.Rollback()
-
-		resp.Title = "Database query failed"
-		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.DatabaseQueryFailed
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
-	}
-
-	if as != nil {
-		resp.Title = "User already a store staff"
-		resp.Status = http.StatusConflict
-		resp.Code = errors.UserAlreadyStaff
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
-	}
-
 	au := data.NewMarketplaceRepository()
 	settings, err := au.GetSettings(db)
 	if err != nil {
@@ -227,13 +270,28 @@ func getStore(ctx echo.Context) error {
 	return resp.ServerJSON(ctx)
 }
 
+// listStoreMemberships lists the stores the user is a staff of, to pick the one sent as X-Store-ID
+func listStoreMemberships(ctx echo.Context) error {
+	resp := core.Response{}
+
+	su := data.NewStoreRepository()
+	profiles, err := su.ListStoreUserProfiles(app.DB(), utils.GetUserID(ctx))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = profiles
+	return resp.ServerJSON(ctx)
+}
+
 func getStoreForOwner(ctx echo.Context) error {
 	resp := core.Response{}
 
 	db := app.DB()
 
 	su := data.NewStoreRepository()
-	profile, err := su.GetStoreUserProfile(db, ctx.Get(utils.UserID).(string))
+	profile, err := su.GetStoreUserProfile(db, utils.GetUserID(ctx), utils.GetStoreID(ctx))
 	if err != nil {
 		ok := errors.IsRecordNotFoundError(err)
 		if ok {
@@ -277,8 +335,10 @@ func getStoreForOwner(ctx echo.Context) error {
 	return resp.ServerJSON(ctx)
 }
 
-func addStoreStaff(ctx echo.Context) error {
-	e, p, err := validators.ValidateCreateStoreStaff(ctx)
+func updateStoreStaffPermission(ctx echo.Context) error {
+	uID := ctx.Param("user_id")
+
+	pID, err := validators.ValidateUpdateStoreStaff(ctx)
 
 	resp := core.Response{}
 
@@ -290,12 +350,16 @@ func addStoreStaff(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	if uID == utils.GetUserID(ctx) {
+		return serveStoreRoleFailed(ctx, services.ErrStaffSelfChange)
+	}
+
 	db := app.DB().Begin()
 
 	uu := data.NewUserRepository()
 	su := data.NewStoreRepository()
 
-	u, err := uu.GetByEmail(db, *e)
+	u, err := uu.Get(db, uID)
 	if err != nil {
 		ok := errors.IsRecordNotFoundError(err)
 		if ok {
@@ -316,12 +380,22 @@ func addStoreStaff(ctx echo.Context) error {
 	s := &models.Staff{
 		UserID:       u.ID,
 		StoreID:      utils.GetStoreID(ctx),
-		PermissionID: *p,
+		PermissionID: *pID,
 		IsCreator:    false,
 	}
 
-	exists, err := su.IsAlreadyStaff(db, s.UserID)
+	sp, err := su.GetStoreUserProfile(db, s.UserID, s.StoreID)
 	if err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Staff doesn't exists"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.StaffDoesNotExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
 		resp.Code = errors.DatabaseQueryFailed
@@ -329,15 +403,12 @@ func addStoreStaff(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	if exists {
-		resp.Title = "User already staff"
-		resp.Status = http.StatusConflict
-		resp.Code = errors.UserAlreadyStaff
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
+	if err := services.CheckStaffGrant(db, utils.GetStorePermission(ctx), utils.GetStorePerms(ctx), services.StaffLevel(s.PermissionID), sp.StaffRoleID); err != nil {
+		db.Rollback()
+		return serveStoreRoleFailed(ctx, err)
 	}
 
-	err = su.AddStoreStuff(db, s)
+	err = su.UpdateStoreStuffPermission(db, s)
 	if err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
@@ -346,6 +417,19 @@ func addStoreStaff(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	actorID := utils.GetUserID(ctx)
+	if err := services.RecordStaffAudit(db, &models.StaffAuditLog{
+		StoreID:      s.StoreID,
+		ActorID:      &actorID,
+		Action:       models.StaffAuditPermissionChanged,
+		TargetUserID: &u.ID,
+		TargetEmail:  u.Email,
+		PermissionID: &s.PermissionID,
+	}); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
 	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
@@ -355,82 +439,42 @@ func addStoreStaff(ctx echo.Context) error {
 	}
 
 	resp.Status = http.StatusOK
-	resp.Title = "Staff added to store"
+	resp.Title = "Staff permission updated"
 	return resp.ServerJSON(ctx)
 }
 
-func updateStoreStaffPermission(ctx echo.Context) error {
+func deleteStoreStaff(ctx echo.Context) error {
 	uID := ctx.Param("user_id")
 
-	pID, err := validators.ValidateUpdateStoreStaff(ctx)
-
 	resp := core.Response{}
 
-	if err != nil {
-		resp.Title = "Invalid data"
-		resp.Status = http.StatusUnprocessableEntity
-		resp.Code = errors.AddStoreStaffDataInvalid
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
-	}
-
 	db := app.DB().Begin()
-
-	uu := data.NewUserRepository()
 	su := data.NewStoreRepository()
 
-	u, err := uu.Get(db, uID)
+	sp, err := su.GetStoreUserProfile(db, uID, utils.GetStoreID(ctx))
 	if err != nil {
-		ok := errors.IsRecordNotFoundError(err)
-		if ok {
-			resp.Title = "User not found"
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Staff doesn't exists"
 			resp.Status = http.StatusNotFound
-			resp.Code = errors.UserNotFound
+			resp.Code = errors.StaffDoesNotExists
 			resp.Errors = err
 			return resp.ServerJSON(ctx)
 		}
-
-		resp.Title = "Database query failed"
-		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.DatabaseQueryFailed
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
-	}
-
-	s := &models.Staff{
-		UserID:       u.ID,
-		StoreID:      utils.GetStoreID(ctx),
-		PermissionID: *pID,
-		IsCreator:    false,
-	}
-
-	exists, err := su.IsAlreadyStaff(db, s.UserID)
-	if err != nil {
-		resp.Title = "Database query failed"
-		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.DatabaseQueryFailed
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
+		return serveDatabaseQueryFailed(ctx, err)
 	}
 
-	if !exists {
-		resp.Title = "Staff doesn't exists"
-		resp.Status = http.StatusNotFound
-		resp.Code = errors.StaffDoesNotExists
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
+	// a staff can't remove one holding more than itself
+	if err := services.CheckStaffGrant(db, utils.GetStorePermission(ctx), utils.GetStorePerms(ctx), sp.StaffPermission, sp.StaffRoleID); err != nil {
+		db.Rollback()
+		return serveStoreRoleFailed(ctx, err)
 	}
 
-	err = su.UpdateStoreStuffPermission(db, s)
+	err = su.DeleteStoreStuffPermission(db, utils.GetStoreID(ctx), uID)
 	if err != nil {
-		resp.Title = "Database query failed"
-		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.DatabaseQueryFailed
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
-	}
+		db.Rollback()
 
-	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
 		resp.Code = errors.DatabaseQueryFailed
@@ -438,26 +482,15 @@ func updateStoreStaffPermission(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
-	resp.Status = http.StatusOK
-	resp.Title = "Staff permission updated"
-	return resp.ServerJSON(ctx)
-}
-
-func deleteStoreStaff(ctx echo.Context) error {
-	uID := ctx.Param("user_id")
-
-	resp := core.Response{}
-
-	db := app.DB().Begin()
-	su := data.NewStoreRepository()
-
-	err := su.DeleteStoreStuffPermission(db, utils.GetStoreID(ctx), uID)
-	if err != nil {
-		resp.Title = "Database query failed"
-		resp.Status = http.StatusInternalServerError
-		resp.Code = errors.DatabaseQueryFailed
-		resp.Errors = err
-		return resp.ServerJSON(ctx)
+	actorID := utils.GetUserID(ctx)
+	if err := services.RecordStaffAudit(db, &models.StaffAuditLog{
+		StoreID:      utils.GetStoreID(ctx),
+		ActorID:      &actorID,
+		Action:       models.StaffAuditRemoved,
+		TargetUserID: &uID,
+	}); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
 	}
 
 	if err := db.Commit().Error; err != nil {
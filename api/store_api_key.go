@@ -0,0 +1,116 @@
+package api
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"time"
+)
+
+func listStoreAPIKeys(ctx echo.Context) error {
+	resp := core.Response{}
+
+	ku := data.NewStoreAPIKeyRepository()
+	keys, err := ku.List(app.DB(), utils.GetStoreID(ctx))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = keys
+	return resp.ServerJSON(ctx)
+}
+
+func createStoreAPIKey(ctx echo.Context) error {
+	req, err := validators.ValidateStoreAPIKey(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.StoreAPIKeyDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	for _, s := range req.Scopes {
+		if !utils.HasStorePerm(ctx, s) {
+			resp.Title = fmt.Sprintf("Unauthorized to grant %s permission", s)
+			resp.Status = http.StatusForbidden
+			resp.Code = errors.UnauthorizedStoreAccess
+			return resp.ServerJSON(ctx)
+		}
+	}
+
+	if req.ExpiresAt != nil {
+		expiresAt := req.ExpiresAt.UTC()
+		req.ExpiresAt = &expiresAt
+	}
+
+	db := app.DB().Begin()
+
+	k, key, err := services.CreateStoreAPIKey(db, utils.GetStoreID(ctx), utils.GetUserID(ctx), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "API key created, it won't be shown again"
+	resp.Status = http.StatusCreated
+	resp.Data = map[string]interface{}{
+		"api_key": k,
+		"key":     key,
+	}
+	return resp.ServerJSON(ctx)
+}
+
+func getStoreAPIKey(ctx echo.Context) error {
+	resp := core.Response{}
+
+	ku := data.NewStoreAPIKeyRepository()
+	k, err := ku.Get(app.DB(), utils.GetStoreID(ctx), ctx.Param("key_id"))
+	if err != nil {
+		return serveStoreAPIKeyFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = k
+	return resp.ServerJSON(ctx)
+}
+
+func revokeStoreAPIKey(ctx echo.Context) error {
+	resp := core.Response{}
+
+	ku := data.NewStoreAPIKeyRepository()
+	if err := ku.Revoke(app.DB(), utils.GetStoreID(ctx), ctx.Param("key_id"), time.Now().UTC()); err != nil {
+		return serveStoreAPIKeyFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func serveStoreAPIKeyFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "API key not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.StoreAPIKeyNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	return serveDatabaseQueryFailed(ctx, err)
+}
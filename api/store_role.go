@@ -0,0 +1,272 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"time"
+)
+
+func listStoreRoles(ctx echo.Context) error {
+	resp := core.Response{}
+
+	sr := data.NewStoreRoleRepository()
+	roles, err := sr.List(app.DB(), utils.GetStoreID(ctx))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	var catalogue []map[string]interface{}
+	for _, p := range models.StorePerms {
+		catalogue = append(catalogue, map[string]interface{}{
+			"name":        p,
+			"description": p.Description(),
+		})
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = map[string]interface{}{
+		"roles":       roles,
+		"permissions": catalogue,
+	}
+	return resp.ServerJSON(ctx)
+}
+
+func createStoreRole(ctx echo.Context) error {
+	req, err := validators.ValidateStoreRole(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.StoreRoleDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := services.CheckStorePermsHeld(utils.GetStorePerms(ctx), req.Permissions); err != nil {
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	r := &models.StoreRole{
+		ID:          utils.NewUUID(),
+		StoreID:     utils.GetStoreID(ctx),
+		Name:        req.Name,
+		Permissions: req.Permissions,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	db := app.DB().Begin()
+
+	sr := data.NewStoreRoleRepository()
+	if err := sr.Create(db, r); err != nil {
+		db.Rollback()
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = r
+	return resp.ServerJSON(ctx)
+}
+
+func getStoreRole(ctx echo.Context) error {
+	resp := core.Response{}
+
+	sr := data.NewStoreRoleRepository()
+	r, err := sr.Get(app.DB(), utils.GetStoreID(ctx), ctx.Param("role_id"))
+	if err != nil {
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = r
+	return resp.ServerJSON(ctx)
+}
+
+func updateStoreRole(ctx echo.Context) error {
+	req, err := validators.ValidateStoreRole(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.StoreRoleDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := services.CheckStorePermsHeld(utils.GetStorePerms(ctx), req.Permissions); err != nil {
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	db := app.DB().Begin()
+
+	sr := data.NewStoreRoleRepository()
+	r, err := sr.Get(db, utils.GetStoreID(ctx), ctx.Param("role_id"))
+	if err != nil {
+		db.Rollback()
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	r.Name = req.Name
+	r.Permissions = req.Permissions
+	r.UpdatedAt = time.Now().UTC()
+
+	if err := sr.Update(db, r); err != nil {
+		db.Rollback()
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = r
+	return resp.ServerJSON(ctx)
+}
+
+func deleteStoreRole(ctx echo.Context) error {
+	resp := core.Response{}
+
+	sr := data.NewStoreRoleRepository()
+	if err := sr.Delete(app.DB(), utils.GetStoreID(ctx), ctx.Param("role_id")); err != nil {
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func setStoreStaffRole(ctx echo.Context) error {
+	req, err := validators.ValidateStoreStaffRole(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.StoreRoleDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	storeID := utils.GetStoreID(ctx)
+	userID := ctx.Param("user_id")
+
+	if userID == utils.GetUserID(ctx) {
+		return serveStoreRoleFailed(ctx, services.ErrStaffSelfChange)
+	}
+
+	db := app.DB().Begin()
+
+	if req.RoleID != nil {
+		sr := data.NewStoreRoleRepository()
+		if _, err := sr.Get(db, storeID, *req.RoleID); err != nil {
+			db.Rollback()
+			return serveStoreRoleFailed(ctx, err)
+		}
+	}
+
+	su := data.NewStoreRepository()
+	sp, err := su.GetStoreUserProfile(db, userID, storeID)
+	if err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Staff not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.StaffDoesNotExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := services.CheckStaffGrant(db, utils.GetStorePermission(ctx), utils.GetStorePerms(ctx), sp.StaffPermission, req.RoleID); err != nil {
+		db.Rollback()
+		return serveStoreRoleFailed(ctx, err)
+	}
+
+	if err := su.SetStaffRole(db, storeID, userID, req.RoleID); err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "Staff not found"
+			resp.Status = http.StatusNotFound
+			resp.Code = errors.StaffDoesNotExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	actorID := utils.GetUserID(ctx)
+	if err := services.RecordStaffAudit(db, &models.StaffAuditLog{
+		StoreID:      storeID,
+		ActorID:      &actorID,
+		Action:       models.StaffAuditRoleChanged,
+		TargetUserID: &userID,
+		RoleID:       req.RoleID,
+	}); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Staff role updated"
+	resp.Status = http.StatusOK
+	return resp.ServerJSON(ctx)
+}
+
+func serveStoreRoleFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	switch err {
+	case services.ErrStaffSelfChange:
+		resp.Title = err.Error()
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.StaffSelfChange
+		return resp.ServerJSON(ctx)
+	case services.ErrStaffGrantExceeded:
+		resp.Title = err.Error()
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.StaffGrantExceeded
+		return resp.ServerJSON(ctx)
+	}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Role not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.StoreRoleNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if msg, ok := errors.IsDuplicateKeyError(err); ok {
+		resp.Title = "Role already exists"
+		resp.Status = http.StatusConflict
+		resp.Code = errors.StoreRoleAlreadyExists
+		resp.Errors = errors.NewError(msg)
+		return resp.ServerJSON(ctx)
+	}
+
+	return serveDatabaseQueryFailed(ctx, err)
+}
@@ -0,0 +1,437 @@
+package api
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func createStoreShippingMethod(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+
+	req, err := validators.ValidateCreateStoreShippingMethod(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ShippingMethodCreationDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	au := data.NewMarketplaceRepository()
+	if err := validateShippingCarrier(db, req.CarrierID); err != nil {
+		db.Rollback()
+		return serveShippingCarrierQueryFailed(ctx, err)
+	}
+
+	m := &models.ShippingMethod{
+		ID:                      utils.NewUUID(),
+		StoreID:                 &storeID,
+		CarrierID:               &req.CarrierID,
+		Name:                    req.Name,
+		IsPublished:             req.IsPublished,
+		ApproximateDeliveryTime: req.ApproximateDeliveryTime,
+		HandlingTime:            req.HandlingTime,
+		DeliveryCharge:          req.DeliveryCharge,
+		WeightUnit:              req.WeightUnit,
+		IsFlat:                  req.IsFlat,
+		PerKgRate:               req.PerKgRate,
+		VolumetricDivisor:       req.VolumetricDivisor,
+		FreeShippingThreshold:   req.FreeShippingThreshold,
+		WeightBrackets:          toShippingWeightBrackets(req.WeightBrackets),
+		CreatedAt:               time.Now().UTC(),
+		UpdatedAt:               time.Now().UTC(),
+	}
+
+	if err := au.CreateShippingMethod(db, m); err != nil {
+		db.Rollback()
+
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.ShippingMethodAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := au.SetShippingWeightBrackets(db, m.ID, m.WeightBrackets); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = m
+	return resp.ServerJSON(ctx)
+}
+
+func updateStoreShippingMethod(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	ID := ctx.Param("id")
+
+	req, err := validators.ValidateCreateStoreShippingMethod(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ShippingMethodCreationDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	au := data.NewMarketplaceRepository()
+	m, err := au.GetShippingMethodByStore(db, storeID, ID)
+	if err != nil {
+		db.Rollback()
+		return serveStoreShippingMethodQueryFailed(ctx, err)
+	}
+
+	if err := validateShippingCarrier(db, req.CarrierID); err != nil {
+		db.Rollback()
+		return serveShippingCarrierQueryFailed(ctx, err)
+	}
+
+	m.CarrierID = &req.CarrierID
+	m.Name = req.Name
+	m.IsPublished = req.IsPublished
+	m.ApproximateDeliveryTime = req.ApproximateDeliveryTime
+	m.HandlingTime = req.HandlingTime
+	m.IsFlat = req.IsFlat
+	m.DeliveryCharge = req.DeliveryCharge
+	m.WeightUnit = req.WeightUnit
+	m.PerKgRate = req.PerKgRate
+	m.VolumetricDivisor = req.VolumetricDivisor
+	m.FreeShippingThreshold = req.FreeShippingThreshold
+	m.WeightBrackets = toShippingWeightBrackets(req.WeightBrackets)
+	m.UpdatedAt = time.Now().UTC()
+
+	if err := au.UpdateShippingMethod(db, m); err != nil {
+		db.Rollback()
+
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.ShippingMethodAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := au.SetShippingWeightBrackets(db, m.ID, m.WeightBrackets); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = m
+	return resp.ServerJSON(ctx)
+}
+
+func deleteStoreShippingMethod(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	ID := ctx.Param("id")
+
+	resp := core.Response{}
+
+	db := app.DB().Begin()
+
+	au := data.NewMarketplaceRepository()
+	if err := au.SetShippingMethodLocations(db, ID, nil); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := au.DeleteShippingMethodByStore(db, storeID, ID); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func getStoreShippingMethod(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	ID := ctx.Param("id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	m, err := au.GetShippingMethodByStore(db, storeID, ID)
+	if err != nil {
+		return serveStoreShippingMethodQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = m
+	return resp.ServerJSON(ctx)
+}
+
+func listStoreShippingMethods(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	methods, err := au.ListShippingMethodsByStore(db, storeID, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = methods
+	return resp.ServerJSON(ctx)
+}
+
+func setStoreShippingMethodLocations(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	ID := ctx.Param("id")
+
+	req, err := validators.ValidateSetShippingMethodLocations(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.ShippingMethodCreationDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	au := data.NewMarketplaceRepository()
+	if _, err := au.GetShippingMethodByStore(db, storeID, ID); err != nil {
+		db.Rollback()
+		return serveStoreShippingMethodQueryFailed(ctx, err)
+	}
+
+	lu := data.NewLocationRepository()
+	for _, id := range req.LocationIDs {
+		if _, err := lu.FindByID(db, int(id)); err != nil {
+			db.Rollback()
+
+			if errors.IsRecordNotFoundError(err) {
+				resp.Title = "Location not found"
+				resp.Status = http.StatusNotFound
+				resp.Code = errors.LocationNotFound
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			resp.Title = "Database query failed"
+			resp.Status = http.StatusInternalServerError
+			resp.Code = errors.DatabaseQueryFailed
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+	}
+
+	if err := au.SetShippingMethodLocations(db, ID, req.LocationIDs); err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	locations, err := au.ListShippingMethodLocations(db, ID)
+	if err != nil {
+		db.Rollback()
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = locations
+	return resp.ServerJSON(ctx)
+}
+
+func listStoreShippingMethodLocations(ctx echo.Context) error {
+	storeID := ctx.Get(utils.StoreID).(string)
+	ID := ctx.Param("id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	au := data.NewMarketplaceRepository()
+	if _, err := au.GetShippingMethodByStore(db, storeID, ID); err != nil {
+		return serveStoreShippingMethodQueryFailed(ctx, err)
+	}
+
+	locations, err := au.ListShippingMethodLocations(db, ID)
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = locations
+	return resp.ServerJSON(ctx)
+}
+
+// validateShippingCarrier ensures the carrier is a published shipping method of the platform
+func validateShippingCarrier(db *gorm.DB, carrierID string) error {
+	au := data.NewMarketplaceRepository()
+	carrier, err := au.GetShippingMethodForUser(db, carrierID)
+	if err != nil {
+		return err
+	}
+	if carrier.IsOwnedByStore() {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func serveShippingCarrierQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Shipping carrier not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.ShippingMethodNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Title = "Database query failed"
+	resp.Status = http.StatusInternalServerError
+	resp.Code = errors.DatabaseQueryFailed
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
+
+func serveStoreShippingMethodQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Shipping method not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.ShippingMethodNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Title = "Database query failed"
+	resp.Status = http.StatusInternalServerError
+	resp.Code = errors.DatabaseQueryFailed
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
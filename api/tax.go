@@ -0,0 +1,492 @@
+package api
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func createTaxClass(ctx echo.Context) error {
+	req, err := validators.ValidateTaxClass(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TaxClassDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+
+	tc := &models.TaxClass{
+		ID:          utils.NewUUID(),
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err := tu.CreateClass(db, tc); err != nil {
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.TaxClassAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = tc
+	return resp.ServerJSON(ctx)
+}
+
+func updateTaxClass(ctx echo.Context) error {
+	tcID := ctx.Param("tc_id")
+
+	req, err := validators.ValidateTaxClass(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TaxClassDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+
+	tc, err := tu.GetClass(db, tcID)
+	if err != nil {
+		return serveTaxClassQueryFailed(ctx, err)
+	}
+
+	tc.Name = req.Name
+	tc.Description = req.Description
+	tc.UpdatedAt = time.Now().UTC()
+
+	if err := tu.UpdateClass(db, tc); err != nil {
+		msg, ok := errors.IsDuplicateKeyError(err)
+		if ok {
+			resp.Title = msg
+			resp.Status = http.StatusConflict
+			resp.Code = errors.TaxClassAlreadyExists
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = tc
+	return resp.ServerJSON(ctx)
+}
+
+func deleteTaxClass(ctx echo.Context) error {
+	tcID := ctx.Param("tc_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+	if err := tu.DeleteClass(db, tcID); err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func getTaxClass(ctx echo.Context) error {
+	tcID := ctx.Param("tc_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+
+	tc, err := tu.GetClass(db, tcID)
+	if err != nil {
+		return serveTaxClassQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = tc
+	return resp.ServerJSON(ctx)
+}
+
+func listTaxClasses(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+
+	classes, err := tu.ListClasses(db, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = classes
+	return resp.ServerJSON(ctx)
+}
+
+func createTaxRate(ctx echo.Context) error {
+	req, err := validators.ValidateTaxRate(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TaxRateDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+
+	if err := validateTaxRateReferences(db, req); err != nil {
+		return serveTaxRateReferenceFailed(ctx, err)
+	}
+
+	tr := &models.TaxRate{
+		ID:          utils.NewUUID(),
+		TaxClassID:  req.TaxClassID,
+		LocationID:  req.LocationID,
+		Name:        req.Name,
+		Rate:        req.Rate,
+		Priority:    req.Priority,
+		IsCompound:  req.IsCompound,
+		IsInclusive: req.IsInclusive,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	tu := data.NewTaxRepository()
+	if err := tu.CreateRate(db, tr); err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusCreated
+	resp.Data = tr
+	return resp.ServerJSON(ctx)
+}
+
+func updateTaxRate(ctx echo.Context) error {
+	trID := ctx.Param("tr_id")
+
+	req, err := validators.ValidateTaxRate(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TaxRateDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+
+	tr, err := tu.GetRate(db, trID)
+	if err != nil {
+		return serveTaxRateQueryFailed(ctx, err)
+	}
+
+	if err := validateTaxRateReferences(db, req); err != nil {
+		return serveTaxRateReferenceFailed(ctx, err)
+	}
+
+	tr.TaxClassID = req.TaxClassID
+	tr.LocationID = req.LocationID
+	tr.Name = req.Name
+	tr.Rate = req.Rate
+	tr.Priority = req.Priority
+	tr.IsCompound = req.IsCompound
+	tr.IsInclusive = req.IsInclusive
+	tr.UpdatedAt = time.Now().UTC()
+
+	if err := tu.UpdateRate(db, tr); err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = tr
+	return resp.ServerJSON(ctx)
+}
+
+func deleteTaxRate(ctx echo.Context) error {
+	trID := ctx.Param("tr_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+	if err := tu.DeleteRate(db, trID); err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func getTaxRate(ctx echo.Context) error {
+	trID := ctx.Param("tr_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+
+	tr, err := tu.GetRate(db, trID)
+	if err != nil {
+		return serveTaxRateQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = tr
+	return resp.ServerJSON(ctx)
+}
+
+func listTaxRates(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+	locationIDQ := ctx.Request().URL.Query().Get("location_id")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	var locationID *int64
+	if v, err := strconv.ParseInt(locationIDQ, 10, 64); err == nil {
+		locationID = &v
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+	tu := data.NewTaxRepository()
+
+	rates, err := tu.ListRates(db, locationID, int(from), int(limit))
+	if err != nil {
+		resp.Title = "Database query failed"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = rates
+	return resp.ServerJSON(ctx)
+}
+
+type taxRateReferenceError struct {
+	err  error
+	code errors.ErrorCode
+}
+
+func (e *taxRateReferenceError) Error() string {
+	return e.err.Error()
+}
+
+func validateTaxRateReferences(db *gorm.DB, req *validators.ReqTaxRate) error {
+	if req.TaxClassID != nil {
+		tu := data.NewTaxRepository()
+		if _, err := tu.GetClass(db, *req.TaxClassID); err != nil {
+			return &taxRateReferenceError{err: err, code: errors.TaxClassNotFound}
+		}
+	}
+
+	lu := data.NewLocationRepository()
+	if _, err := lu.FindByID(db, int(req.LocationID)); err != nil {
+		return &taxRateReferenceError{err: err, code: errors.LocationNotFound}
+	}
+	return nil
+}
+
+func serveTaxRateReferenceFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if e, ok := err.(*taxRateReferenceError); ok && errors.IsRecordNotFoundError(e.err) {
+		resp.Title = "Tax class or location not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = e.code
+		resp.Errors = e.err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Title = "Database query failed"
+	resp.Status = http.StatusInternalServerError
+	resp.Code = errors.DatabaseQueryFailed
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
+
+func serveTaxClassQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Tax class not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.TaxClassNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Title = "Database query failed"
+	resp.Status = http.StatusInternalServerError
+	resp.Code = errors.DatabaseQueryFailed
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
+
+func serveTaxRateQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Tax rate not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.TaxRateNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Title = "Database query failed"
+	resp.Status = http.StatusInternalServerError
+	resp.Code = errors.DatabaseQueryFailed
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
+
+// applyOrderTaxes computes the taxes of the ordered items from the rates of the delivery location,
+// falling back to the billing location for orders without shipping. Buyers with a verified VAT number
+// of the country of that location are exempted
+func applyOrderTaxes(db *gorm.DB, o *models.Order, items []*models.OrderedItem, taxClasses map[string]*string) error {
+	tu := data.NewTaxRepository()
+
+	addressID := o.BillingAddressID
+	if o.ShippingAddressID != nil {
+		addressID = *o.ShippingAddressID
+	}
+
+	adu := data.NewAddressRepository()
+	addr, err := adu.GetRawAddressByID(db, addressID)
+	if err != nil {
+		return err
+	}
+
+	te, err := tu.GetExemptionByUser(db, o.UserID)
+	if err != nil && !errors.IsRecordNotFoundError(err) {
+		return err
+	}
+	if te != nil && te.IsVerified && te.CountryID == addr.CountryID {
+		o.TaxExemptVatNumber = &te.VatNumber
+		return nil
+	}
+
+	rates := map[string][]models.TaxRate{}
+
+	for _, oi := range items {
+		// Bundle components are taxed as part of their bundle
+		if oi.ParentID != nil {
+			continue
+		}
+
+		taxClassID := taxClasses[oi.ID]
+
+		key := ""
+		if taxClassID != nil {
+			key = *taxClassID
+		}
+
+		r, ok := rates[key]
+		if !ok {
+			r, err = tu.ListRatesForLocations(db, taxClassID, addr.LocationIDs())
+			if err != nil {
+				return err
+			}
+			rates[key] = r
+		}
+
+		included, excluded := models.CalculateTax(oi.SubTotal, r)
+		oi.TaxAmount = included + excluded
+		oi.IncludedTaxAmount = included
+
+		o.TaxAmount += oi.TaxAmount
+		o.IncludedTaxAmount += oi.IncludedTaxAmount
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func getTaxExemption(ctx echo.Context) error {
+	resp := core.Response{}
+
+	tu := data.NewTaxRepository()
+	te, err := tu.GetExemptionByUser(app.DB(), utils.GetUserID(ctx))
+	if err != nil {
+		return serveTaxExemptionQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = te
+	return resp.ServerJSON(ctx)
+}
+
+// saveTaxExemption submits the VAT number of the user for verification, changing it drops the verification
+func saveTaxExemption(ctx echo.Context) error {
+	req, err := validators.ValidateTaxExemption(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TaxExemptionDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	if err := validateTaxExemptionVatNumber(db, req.CountryID, req.VatNumber); err != nil {
+		db.Rollback()
+		return serveTaxExemptionVatNumberFailed(ctx, err)
+	}
+
+	tu := data.NewTaxRepository()
+
+	te, err := tu.GetExemptionByUser(db, utils.GetUserID(ctx))
+	if err != nil && !errors.IsRecordNotFoundError(err) {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	status := http.StatusOK
+
+	if te == nil {
+		te = &models.TaxExemption{
+			ID:        utils.NewUUID(),
+			UserID:    utils.GetUserID(ctx),
+			CountryID: req.CountryID,
+			VatNumber: req.VatNumber,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+
+		if err := tu.CreateExemption(db, te); err != nil {
+			db.Rollback()
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+		status = http.StatusCreated
+	} else if te.CountryID != req.CountryID || te.VatNumber != req.VatNumber {
+		te.CountryID = req.CountryID
+		te.VatNumber = req.VatNumber
+		te.IsVerified = false
+		te.VerifiedBy = nil
+		te.VerifiedAt = nil
+		te.UpdatedAt = time.Now().UTC()
+
+		if err := tu.UpdateExemption(db, te); err != nil {
+			db.Rollback()
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = status
+	resp.Data = te
+	return resp.ServerJSON(ctx)
+}
+
+func deleteTaxExemption(ctx echo.Context) error {
+	resp := core.Response{}
+
+	tu := data.NewTaxRepository()
+	if err := tu.DeleteExemption(app.DB(), utils.GetUserID(ctx)); err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func listTaxExemptions(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+	isVerifiedQ := ctx.Request().URL.Query().Get("is_verified")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	var isVerified *bool
+	if v, err := strconv.ParseBool(isVerifiedQ); err == nil {
+		isVerified = &v
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	tu := data.NewTaxRepository()
+	exemptions, err := tu.ListExemptions(app.DB(), isVerified, int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = exemptions
+	return resp.ServerJSON(ctx)
+}
+
+// verifyTaxExemption records whether a platform manager checked the VAT number with the issuing authority
+func verifyTaxExemption(ctx echo.Context) error {
+	teID := ctx.Param("te_id")
+
+	req, err := validators.ValidateTaxExemptionVerification(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TaxExemptionDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+	tu := data.NewTaxRepository()
+
+	te, err := tu.GetExemption(db, teID)
+	if err != nil {
+		db.Rollback()
+		return serveTaxExemptionQueryFailed(ctx, err)
+	}
+
+	if req.IsVerified {
+		if err := validateTaxExemptionVatNumber(db, te.CountryID, te.VatNumber); err != nil {
+			db.Rollback()
+			return serveTaxExemptionVatNumberFailed(ctx, err)
+		}
+
+		verifiedBy := utils.GetUserID(ctx)
+		verifiedAt := time.Now().UTC()
+		te.VerifiedBy = &verifiedBy
+		te.VerifiedAt = &verifiedAt
+	} else {
+		te.VerifiedBy = nil
+		te.VerifiedAt = nil
+	}
+	te.IsVerified = req.IsVerified
+	te.UpdatedAt = time.Now().UTC()
+
+	if err := tu.UpdateExemption(db, te); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = te
+	return resp.ServerJSON(ctx)
+}
+
+// validateTaxExemptionVatNumber checks the VAT number is formatted as the ones issued by the country
+func validateTaxExemptionVatNumber(db *gorm.DB, countryID int64, vatNumber string) error {
+	lu := data.NewLocationRepository()
+	l, err := lu.FindByID(db, int(countryID))
+	if err != nil {
+		return err
+	}
+
+	ve := errors.ValidationError{}
+	if l.LocationType != models.LocationTypeCountry || l.ISOName == nil {
+		ve.Add("country_id", "must be a country")
+		return &ve
+	}
+	if !validators.IsValidVatNumber(*l.ISOName, vatNumber) {
+		ve.Add("vat_number", "isn't a valid VAT number of the country")
+		return &ve
+	}
+	return nil
+}
+
+func serveTaxExemptionVatNumberFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if _, ok := err.(*errors.ValidationError); ok || errors.IsRecordNotFoundError(err) {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TaxExemptionDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	return serveDatabaseQueryFailed(ctx, err)
+}
+
+func serveTaxExemptionQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Tax exemption not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.TaxExemptionNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	return serveDatabaseQueryFailed(ctx, err)
+}
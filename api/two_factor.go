@@ -0,0 +1,324 @@
+package api
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+)
+
+func getTwoFactor(ctx echo.Context) error {
+	userID := utils.GetUserID(ctx)
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	isRequired, err := services.IsTwoFactorRequired(db, userID, utils.GetUserPermission(ctx))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	result := map[string]interface{}{
+		"is_enabled":  false,
+		"is_required": isRequired,
+	}
+
+	tu := data.NewTwoFactorRepository()
+	tf, err := tu.Get(db, userID)
+	if err != nil && !errors.IsRecordNotFoundError(err) {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+	if err == nil && tf.IsEnabled {
+		remaining, err := tu.CountRecoveryCodes(db, userID)
+		if err != nil {
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+
+		result["is_enabled"] = true
+		result["enabled_at"] = tf.EnabledAt
+		result["recovery_codes_remaining"] = remaining
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = result
+	return resp.ServerJSON(ctx)
+}
+
+func enrollTwoFactor(ctx echo.Context) error {
+	resp := core.Response{}
+
+	db := app.DB().Begin()
+
+	uc := data.NewUserRepository()
+	u, err := uc.Get(db, utils.GetUserID(ctx))
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	tf, uri, err := services.EnrollTwoFactor(db, u)
+	if err != nil {
+		db.Rollback()
+		return serveTwoFactorFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = map[string]interface{}{
+		"secret":           tf.Secret,
+		"provisioning_uri": uri,
+	}
+	return resp.ServerJSON(ctx)
+}
+
+func confirmTwoFactor(ctx echo.Context) error {
+	req, err := validators.ValidateTwoFactorCode(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TwoFactorDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	codes, err := services.ConfirmTwoFactor(db, utils.GetUserID(ctx), req.Code)
+	if err != nil {
+		db.Rollback()
+		return serveTwoFactorFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Two factor authentication enabled"
+	resp.Status = http.StatusOK
+	resp.Data = map[string]interface{}{
+		"recovery_codes": codes,
+	}
+	return resp.ServerJSON(ctx)
+}
+
+func regenerateTwoFactorRecoveryCodes(ctx echo.Context) error {
+	req, err := validators.ValidateTwoFactorReauth(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TwoFactorDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	if err := reauthenticate(ctx, db, req); err != nil {
+		db.Rollback()
+		return err
+	}
+
+	codes, err := services.RegenerateRecoveryCodes(db, utils.GetUserID(ctx))
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = map[string]interface{}{
+		"recovery_codes": codes,
+	}
+	return resp.ServerJSON(ctx)
+}
+
+func disableTwoFactor(ctx echo.Context) error {
+	req, err := validators.ValidateTwoFactorReauth(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TwoFactorDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	userID := utils.GetUserID(ctx)
+
+	db := app.DB().Begin()
+
+	isRequired, err := services.IsTwoFactorRequired(db, userID, utils.GetUserPermission(ctx))
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+	if isRequired {
+		db.Rollback()
+
+		resp.Title = "Two factor authentication is required for your account"
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.TwoFactorRequired
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := reauthenticate(ctx, db, req); err != nil {
+		db.Rollback()
+		return err
+	}
+
+	tu := data.NewTwoFactorRepository()
+	if err := tu.Delete(db, userID); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Title = "Two factor authentication disabled"
+	resp.Status = http.StatusOK
+	return resp.ServerJSON(ctx)
+}
+
+func loginTwoFactor(ctx echo.Context) error {
+	req, err := validators.ValidateTwoFactorLogin(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.TwoFactorDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	c, err := services.GetTwoFactorChallenge(app.DB(), req.ChallengeToken)
+	if err != nil {
+		return serveTwoFactorFailed(ctx, err)
+	}
+
+	uc := data.NewUserRepository()
+	u, err := uc.Get(app.DB(), c.UserID)
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	// codes are guessed against the same counters as passwords
+	if err := services.CheckLoginAllowed(u.Email, ctx.RealIP()); err != nil {
+		return serveThrottled(ctx, err, errors.TooManyLoginAttempts)
+	}
+
+	c, err = services.CompleteTwoFactorChallenge(req.ChallengeToken, req.Code)
+	if err != nil {
+		if err == services.ErrTwoFactorCodeInvalid {
+			recordLoginFailure(ctx, u.Email, u)
+		}
+		return serveTwoFactorFailed(ctx, err)
+	}
+
+	db := app.DB().Begin()
+
+	_, permission, err := uc.GetPermissionByUserID(db, c.UserID)
+	if err != nil {
+		db.Rollback()
+
+		if errors.IsRecordNotFoundError(err) {
+			resp.Title = "User isn't active"
+			resp.Status = http.StatusForbidden
+			resp.Code = errors.UserNotActive
+			resp.Errors = err
+			return resp.ServerJSON(ctx)
+		}
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	return completeLogin(ctx, db, u, utils.UserScope(c.Scope), permission)
+}
+
+// reauthenticate checks the password and a second factor code of the user for a sensitive change,
+// the error returned is the response already served
+func reauthenticate(ctx echo.Context, db *gorm.DB, req *validators.ReqTwoFactorReauth) error {
+	resp := core.Response{}
+
+	uc := data.NewUserRepository()
+	u, err := uc.Get(db, utils.GetUserID(ctx))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	// a stolen access token mustn't allow guessing the password or the code past the login counters
+	if err := services.CheckLoginAllowed(u.Email, ctx.RealIP()); err != nil {
+		return serveThrottled(ctx, err, errors.TooManyLoginAttempts)
+	}
+
+	if err := utils.CheckPassword(u.Password, req.Password); err != nil {
+		recordLoginFailure(ctx, u.Email, u)
+
+		resp.Title = "Invalid login credentials"
+		resp.Status = http.StatusUnauthorized
+		resp.Code = errors.LoginCredentialsInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := services.VerifyTwoFactor(db, u.ID, req.Code); err != nil {
+		if err == services.ErrTwoFactorCodeInvalid {
+			recordLoginFailure(ctx, u.Email, u)
+		}
+		return serveTwoFactorFailed(ctx, err)
+	}
+	return nil
+}
+
+func serveTwoFactorFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	switch err {
+	case services.ErrTwoFactorAlreadyEnabled:
+		resp.Title = "Two factor authentication is already enabled"
+		resp.Status = http.StatusConflict
+		resp.Code = errors.TwoFactorAlreadyEnabled
+	case services.ErrTwoFactorNotEnabled:
+		resp.Title = "Two factor authentication isn't enabled"
+		resp.Status = http.StatusBadRequest
+		resp.Code = errors.TwoFactorNotEnabled
+	case services.ErrTwoFactorCodeInvalid:
+		resp.Title = "Invalid two factor code"
+		resp.Status = http.StatusUnauthorized
+		resp.Code = errors.TwoFactorCodeInvalid
+	case services.ErrTwoFactorChallengeInvalid:
+		resp.Title = "Invalid or expired two factor challenge"
+		resp.Status = http.StatusUnauthorized
+		resp.Code = errors.TwoFactorChallengeInvalid
+	default:
+		log.Log().Errorln(err)
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Errors = err
+	return resp.ServerJSON(ctx)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/shopicano/shopicano-backend/errors"
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/middlewares"
+	"github.com/shopicano/shopicano-backend/models"
 	"github.com/shopicano/shopicano-backend/utils"
 	"github.com/shopicano/shopicano-backend/validators"
 	"net/http"
@@ -23,8 +24,39 @@ func RegisterUserRoutes(publicEndpoints, platformEndpoints *echo.Group) {
 		g.Use(middlewares.JWTAuth())
 		g.PUT("/", update)
 		g.GET("/", get)
+
+		g.GET("/sessions/", listSessions)
+		g.DELETE("/sessions/", revokeAllSessions)
+		g.DELETE("/sessions/:session_id/", revokeSession)
+		g.GET("/two-factor/", getTwoFactor)
+		g.POST("/two-factor/enroll/", enrollTwoFactor)
+		g.POST("/two-factor/confirm/", confirmTwoFactor)
+		g.POST("/two-factor/recovery-codes/", regenerateTwoFactorRecoveryCodes)
+		g.POST("/two-factor/disable/", disableTwoFactor)
+		g.GET("/identities/", listUserIdentities)
+		g.POST("/identities/:provider/authorize/", authorizeOIDCLink)
+		g.DELETE("/identities/:identity_id/", unlinkUserIdentity)
+
+		g.GET("/notification-preferences/", listNotificationPreferences)
+		g.PUT("/notification-preferences/", updateNotificationPreferences)
+		g.POST("/push-devices/", registerPushDevice)
+		g.GET("/push-devices/", listPushDevices)
+		g.DELETE("/push-devices/:device_id/", deletePushDevice)
+
+		g.POST("/data-exports/", requestDataExport)
+		g.GET("/data-exports/", listDataExports)
+		g.GET("/data-exports/:export_id/download/", downloadDataExport)
+		g.GET("/deletion/", getAccountDeletion)
+		g.POST("/deletion/", requestAccountDeletion)
+		g.DELETE("/deletion/", cancelAccountDeletion)
+
+		g.GET("/tax-exemption/", getTaxExemption)
+		g.PUT("/tax-exemption/", saveTaxExemption)
+		g.DELETE("/tax-exemption/", deleteTaxExemption)
 	}(*usersPublicPath)
 
+	usersPublicPath.POST("/deletion/cancel/", cancelAccountDeletionWithToken)
+
 	func(g echo.Group) {
 		g.Use(middlewares.IsPlatformManager)
 		g.PATCH("/:user_id/status/", updateStatus)
@@ -75,6 +107,9 @@ func update(ctx echo.Context) error {
 	if req.Phone != nil {
 		u.Phone = req.Phone
 	}
+	if req.Locale != nil {
+		u.Locale = *req.Locale
+	}
 
 	if req.NewPassword != nil {
 		if req.CurrentPassword == nil {
@@ -144,6 +179,14 @@ func update(ctx echo.Context) error {
 		return resp.ServerJSON(ctx)
 	}
 
+	if req.NewPassword != nil {
+		su := data.NewSessionRepository()
+		if err := su.RevokeAll(db, u.ID, utils.GetSessionID(ctx), models.SessionRevokedPasswordChange, u.UpdatedAt); err != nil {
+			db.Rollback()
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+	}
+
 	if err := db.Commit().Error; err != nil {
 		resp.Title = "Database query failed"
 		resp.Status = http.StatusInternalServerError
@@ -159,6 +202,7 @@ func update(ctx echo.Context) error {
 		"status":          u.Status,
 		"phone":           u.Phone,
 		"profile_picture": u.ProfilePicture,
+		"locale":          u.Locale,
 		"created_at":      u.CreatedAt,
 		"updated_at":      u.UpdatedAt,
 		"permission":      ctx.Get(utils.UserPermission),
@@ -236,6 +280,7 @@ func updateStatus(ctx echo.Context) error {
 		"status":          u.Status,
 		"phone":           u.Phone,
 		"profile_picture": u.ProfilePicture,
+		"locale":          u.Locale,
 		"created_at":      u.CreatedAt,
 		"updated_at":      u.UpdatedAt,
 		"permission":      ctx.Get(utils.UserPermission),
@@ -313,6 +358,7 @@ func updatePermission(ctx echo.Context) error {
 		"status":          u.Status,
 		"phone":           u.Phone,
 		"profile_picture": u.ProfilePicture,
+		"locale":          u.Locale,
 		"created_at":      u.CreatedAt,
 		"updated_at":      u.UpdatedAt,
 		"permission":      ctx.Get(utils.UserPermission),
@@ -349,6 +395,7 @@ func get(ctx echo.Context) error {
 		"status":          u.Status,
 		"phone":           u.Phone,
 		"profile_picture": u.ProfilePicture,
+		"locale":          u.Locale,
 		"created_at":      u.CreatedAt,
 		"updated_at":      u.UpdatedAt,
 		"permission":      ctx.Get(utils.UserPermission),
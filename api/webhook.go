@@ -0,0 +1,339 @@
+package api
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/queue"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// webhookStoreID returns the store whose webhooks are managed, nil when the platform ones are
+func webhookStoreID(ctx echo.Context) *string {
+	if v, ok := ctx.Get(utils.StoreID).(string); ok {
+		return &v
+	}
+	return nil
+}
+
+func createWebhook(ctx echo.Context) error {
+	req, err := validators.ValidateWebhook(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.WebhookDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	w := &models.Webhook{
+		ID:        utils.NewUUID(),
+		StoreID:   webhookStoreID(ctx),
+		URL:       req.URL,
+		Secret:    fmt.Sprintf("whsec_%s", utils.NewSecret(24)),
+		IsActive:  req.IsActive,
+		CreatedBy: utils.GetUserID(ctx),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Events:    req.Events,
+	}
+
+	db := app.DB().Begin()
+
+	wu := data.NewWebhookRepository()
+	if err := wu.Create(db, w); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := wu.SetEvents(db, w.ID, w.Events); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	// The secret is only shown once, when the webhook is created
+	resp.Status = http.StatusCreated
+	resp.Data = w
+	return resp.ServerJSON(ctx)
+}
+
+func updateWebhook(ctx echo.Context) error {
+	webhookID := ctx.Param("webhook_id")
+
+	req, err := validators.ValidateWebhook(ctx)
+
+	resp := core.Response{}
+
+	if err != nil {
+		resp.Title = "Invalid data"
+		resp.Status = http.StatusUnprocessableEntity
+		resp.Code = errors.WebhookDataInvalid
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	db := app.DB().Begin()
+
+	wu := data.NewWebhookRepository()
+	w, err := wu.Get(db, webhookStoreID(ctx), webhookID)
+	if err != nil {
+		db.Rollback()
+		return serveWebhookQueryFailed(ctx, err)
+	}
+
+	w.URL = req.URL
+	w.IsActive = req.IsActive
+	w.UpdatedAt = time.Now().UTC()
+	w.Events = req.Events
+
+	if err := wu.Update(db, w); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := wu.SetEvents(db, w.ID, w.Events); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	w.Secret = ""
+
+	resp.Status = http.StatusOK
+	resp.Data = w
+	return resp.ServerJSON(ctx)
+}
+
+func deleteWebhook(ctx echo.Context) error {
+	webhookID := ctx.Param("webhook_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	wu := data.NewWebhookRepository()
+	if err := wu.Delete(db, webhookStoreID(ctx), webhookID); err != nil {
+		return serveWebhookQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusNoContent
+	return resp.ServerJSON(ctx)
+}
+
+func getWebhook(ctx echo.Context) error {
+	webhookID := ctx.Param("webhook_id")
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	wu := data.NewWebhookRepository()
+	w, err := wu.Get(db, webhookStoreID(ctx), webhookID)
+	if err != nil {
+		return serveWebhookQueryFailed(ctx, err)
+	}
+
+	w.Events, err = wu.ListEvents(db, w.ID)
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+	w.Secret = ""
+
+	resp.Status = http.StatusOK
+	resp.Data = w
+	return resp.ServerJSON(ctx)
+}
+
+func listWebhooks(ctx echo.Context) error {
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	wu := data.NewWebhookRepository()
+	webhooks, err := wu.List(db, webhookStoreID(ctx), int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	for i := range webhooks {
+		webhooks[i].Events, err = wu.ListEvents(db, webhooks[i].ID)
+		if err != nil {
+			return serveDatabaseQueryFailed(ctx, err)
+		}
+		webhooks[i].Secret = ""
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = webhooks
+	return resp.ServerJSON(ctx)
+}
+
+func listWebhookDeliveries(ctx echo.Context) error {
+	webhookID := ctx.Param("webhook_id")
+	pageQ := ctx.Request().URL.Query().Get("page")
+	limitQ := ctx.Request().URL.Query().Get("limit")
+
+	page, err := strconv.ParseInt(pageQ, 10, 64)
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.ParseInt(limitQ, 10, 64)
+	if err != nil {
+		limit = 10
+	}
+
+	from := (page - 1) * limit
+
+	resp := core.Response{}
+
+	db := app.DB()
+
+	wu := data.NewWebhookRepository()
+	w, err := wu.Get(db, webhookStoreID(ctx), webhookID)
+	if err != nil {
+		return serveWebhookQueryFailed(ctx, err)
+	}
+
+	deliveries, err := wu.ListDeliveries(db, w.ID, int(from), int(limit))
+	if err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	resp.Status = http.StatusOK
+	resp.Data = deliveries
+	return resp.ServerJSON(ctx)
+}
+
+func pingWebhook(ctx echo.Context) error {
+	webhookID := ctx.Param("webhook_id")
+
+	resp := core.Response{}
+
+	db := app.DB().Begin()
+
+	wu := data.NewWebhookRepository()
+	w, err := wu.Get(db, webhookStoreID(ctx), webhookID)
+	if err != nil {
+		db.Rollback()
+		return serveWebhookQueryFailed(ctx, err)
+	}
+
+	wd, err := services.PingWebhook(db, w)
+	if err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := queue.DeliverWebhook(wd.ID); err != nil {
+		resp.Title = "Failed to enqueue task"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.FailedToEnqueueTask
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusAccepted
+	resp.Data = wd
+	return resp.ServerJSON(ctx)
+}
+
+func redeliverWebhook(ctx echo.Context) error {
+	webhookID := ctx.Param("webhook_id")
+	deliveryID := ctx.Param("delivery_id")
+
+	resp := core.Response{}
+
+	db := app.DB().Begin()
+
+	wu := data.NewWebhookRepository()
+	w, err := wu.Get(db, webhookStoreID(ctx), webhookID)
+	if err != nil {
+		db.Rollback()
+		return serveWebhookQueryFailed(ctx, err)
+	}
+
+	wd, err := wu.GetDelivery(db, deliveryID)
+	if err != nil && !errors.IsRecordNotFoundError(err) {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+	if err != nil || wd.WebhookID != w.ID {
+		db.Rollback()
+
+		resp.Title = "Webhook delivery not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.WebhookDeliveryNotFound
+		return resp.ServerJSON(ctx)
+	}
+
+	if err := services.ResetWebhookDelivery(db, wd); err != nil {
+		db.Rollback()
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return serveDatabaseQueryFailed(ctx, err)
+	}
+
+	if err := queue.DeliverWebhook(wd.ID); err != nil {
+		resp.Title = "Failed to enqueue task"
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.FailedToEnqueueTask
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+
+	resp.Status = http.StatusAccepted
+	resp.Data = wd
+	return resp.ServerJSON(ctx)
+}
+
+func serveWebhookQueryFailed(ctx echo.Context, err error) error {
+	resp := core.Response{}
+
+	if errors.IsRecordNotFoundError(err) {
+		resp.Title = "Webhook not found"
+		resp.Status = http.StatusNotFound
+		resp.Code = errors.WebhookNotFound
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	return serveDatabaseQueryFailed(ctx, err)
+}
@@ -3,6 +3,7 @@ package migration
 import (
 	"github.com/shopicano/shopicano-backend/app"
 	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/models"
 	"github.com/spf13/cobra"
@@ -20,9 +21,10 @@ func auto(cmd *cobra.Command, args []string) {
 
 	var tables []core.Table
 	tables = append(tables, &models.Address{})
-	tables = append(tables, &models.UserPermission{}, &models.User{}, &models.Session{})
-	tables = append(tables, &models.StorePermission{}, &models.Store{}, &models.Staff{})
+	tables = append(tables, &models.UserPermission{}, &models.User{}, &models.Session{}, &models.TwoFactor{}, &models.TwoFactorRecoveryCode{}, &models.TwoFactorChallenge{}, &models.UserIdentity{}, &models.OIDCState{}, &models.AccountUnlockToken{}, &models.SecurityAuditLog{}, &models.LimiterCounter{}, &models.DataExport{}, &models.AccountDeletion{})
+	tables = append(tables, &models.StorePermission{}, &models.Store{}, &models.StoreRole{}, &models.StoreRolePermission{}, &models.Staff{}, &models.StaffInvitation{}, &models.StaffAuditLog{}, &models.StoreAPIKey{}, &models.StoreAPIKeyScope{})
 	tables = append(tables, &models.ShippingMethod{}, &models.PaymentMethod{}, &models.Settings{})
+	tables = append(tables, &models.GlobalCategory{}, &models.TaxClass{})
 	tables = append(tables, &models.Category{}, &models.Collection{}, &models.Product{}, &models.CollectionOfProduct{})
 	tables = append(tables, &models.ProductAttribute{}, &models.OrderLog{}, &models.ProductImage{})
 	tables = append(tables, &models.Order{}, &models.OrderedItem{})
@@ -31,6 +33,16 @@ func auto(cmd *cobra.Command, args []string) {
 	tables = append(tables, &models.Location{}, &models.ShippingForLocation{}, &models.PaymentForLocation{})
 	tables = append(tables, &models.BusinessAccountType{}, &models.PayoutMethod{}, &models.PayoutSettings{})
 	tables = append(tables, &models.PayoutSend{})
+	tables = append(tables, &models.ProductReview{}, &models.ProductReviewImage{})
+	tables = append(tables, &models.ScheduledPrice{}, &models.PriceHistory{}, &models.ProductBundleItem{})
+	tables = append(tables, &models.ShippingWeightBracket{})
+	tables = append(tables, &models.ShippingZone{}, &models.ShippingZoneLocation{}, &models.ShippingZoneRate{})
+	tables = append(tables, &models.TaxRate{}, &models.TaxExemption{}, &models.Invoice{})
+	tables = append(tables, &models.EmailTemplate{}, &models.StoreEmailBranding{})
+	tables = append(tables, &models.Email{}, &models.EmailAttachment{}, &models.EmailDeliveryLog{})
+	tables = append(tables, &models.NotificationPreference{}, &models.PushDevice{})
+	tables = append(tables, &models.Webhook{}, &models.WebhookSubscription{}, &models.WebhookDelivery{})
+	tables = append(tables, &models.DomainEvent{})
 
 	for _, t := range tables {
 		if err := tx.AutoMigrate(t).Error; err != nil {
@@ -40,17 +52,58 @@ func auto(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// staffs.user_id was unique until users could be staff of several stores
+	if err := tx.Exec("ALTER TABLE staffs DROP CONSTRAINT IF EXISTS staffs_user_id_key").Error; err != nil {
+		tx.Rollback()
+		log.Log().Errorln(err)
+		return
+	}
+
+	// shipping_methods.name was unique until stores could name their own shipping methods, the composite
+	// index with store_id doesn't cover the platform methods as NULLs are distinct
+	if err := tx.Exec("ALTER TABLE shipping_methods DROP CONSTRAINT IF EXISTS shipping_methods_name_key").Error; err != nil {
+		tx.Rollback()
+		log.Log().Errorln(err)
+		return
+	}
+	if err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS uix_shipping_methods_platform_name ON shipping_methods (name) WHERE store_id IS NULL").Error; err != nil {
+		tx.Rollback()
+		log.Log().Errorln(err)
+		return
+	}
+
+	// products.sold_count and products.search_text are kept up to date by the domain event subscribers from here on
+	pu := data.NewProductRepository()
+	if err := pu.RefreshSoldCount(tx, nil); err != nil {
+		tx.Rollback()
+		log.Log().Errorln(err)
+		return
+	}
+	if err := pu.RefreshSearchText(tx, nil); err != nil {
+		tx.Rollback()
+		log.Log().Errorln(err)
+		return
+	}
+
 	var tForeignKeys []core.Model
-	tForeignKeys = append(tForeignKeys, &models.Address{}, &models.Category{}, &models.Collection{})
+	tForeignKeys = append(tForeignKeys, &models.Address{}, &models.GlobalCategory{}, &models.Category{}, &models.Collection{})
 	tForeignKeys = append(tForeignKeys, &models.Order{}, &models.OrderedItem{})
 	tForeignKeys = append(tForeignKeys, &models.Product{}, &models.CollectionOfProduct{})
 	tForeignKeys = append(tForeignKeys, &models.ProductAttribute{}, &models.OrderLog{}, &models.ProductImage{})
-	tForeignKeys = append(tForeignKeys, &models.Settings{}, &models.Store{}, &models.Staff{})
-	tForeignKeys = append(tForeignKeys, &models.User{}, &models.Session{})
+	tForeignKeys = append(tForeignKeys, &models.Settings{}, &models.Store{}, &models.StoreRole{}, &models.StoreRolePermission{}, &models.Staff{}, &models.StaffInvitation{}, &models.StaffAuditLog{}, &models.StoreAPIKey{}, &models.StoreAPIKeyScope{})
+	tForeignKeys = append(tForeignKeys, &models.User{}, &models.Session{}, &models.TwoFactor{}, &models.TwoFactorRecoveryCode{}, &models.TwoFactorChallenge{}, &models.UserIdentity{}, &models.OIDCState{}, &models.AccountUnlockToken{}, &models.SecurityAuditLog{}, &models.DataExport{}, &models.AccountDeletion{})
 	tForeignKeys = append(tForeignKeys, &models.Coupon{}, &models.Coupon{}, &models.CouponUsage{})
 	tForeignKeys = append(tForeignKeys, &models.Review{}, &models.OrderedItemAttribute{}, &models.ShippingForLocation{})
 	tForeignKeys = append(tForeignKeys, &models.PaymentForLocation{}, &models.PayoutSettings{})
 	tForeignKeys = append(tForeignKeys, &models.PayoutSend{})
+	tForeignKeys = append(tForeignKeys, &models.ProductReview{}, &models.ProductReviewImage{})
+	tForeignKeys = append(tForeignKeys, &models.ScheduledPrice{}, &models.PriceHistory{}, &models.ProductBundleItem{})
+	tForeignKeys = append(tForeignKeys, &models.ShippingWeightBracket{}, &models.ShippingMethod{})
+	tForeignKeys = append(tForeignKeys, &models.ShippingZoneLocation{}, &models.ShippingZoneRate{})
+	tForeignKeys = append(tForeignKeys, &models.TaxRate{}, &models.TaxExemption{}, &models.Invoice{}, &models.StoreEmailBranding{})
+	tForeignKeys = append(tForeignKeys, &models.Email{}, &models.EmailAttachment{}, &models.EmailDeliveryLog{})
+	tForeignKeys = append(tForeignKeys, &models.NotificationPreference{}, &models.PushDevice{})
+	tForeignKeys = append(tForeignKeys, &models.Webhook{}, &models.WebhookSubscription{}, &models.WebhookDelivery{})
 
 	for _, t := range tForeignKeys {
 		for _, fks := range t.ForeignKeys() {
@@ -18,13 +18,23 @@ func drop(cmd *cobra.Command, args []string) {
 	tx := app.DB().Begin()
 
 	var tables []core.Table
+	tables = append(tables, &models.WebhookDelivery{}, &models.WebhookSubscription{}, &models.Webhook{})
+	tables = append(tables, &models.DomainEvent{})
+	tables = append(tables, &models.PushDevice{}, &models.NotificationPreference{})
+	tables = append(tables, &models.EmailDeliveryLog{}, &models.EmailAttachment{}, &models.Email{})
+	tables = append(tables, &models.StoreEmailBranding{}, &models.EmailTemplate{})
+	tables = append(tables, &models.Invoice{}, &models.ProductReviewImage{}, &models.ProductReview{})
+	tables = append(tables, &models.ProductBundleItem{}, &models.PriceHistory{}, &models.ScheduledPrice{})
+	tables = append(tables, &models.ShippingZoneRate{}, &models.ShippingZoneLocation{}, &models.ShippingZone{})
+	tables = append(tables, &models.ShippingWeightBracket{}, &models.TaxExemption{}, &models.TaxRate{})
 	tables = append(tables, &models.CouponUsage{}, &models.CouponFor{}, &models.Coupon{}, &models.Review{}, &models.OrderedItemAttribute{})
 	tables = append(tables, &models.ProductAttribute{}, &models.OrderLog{}, &models.ProductImage{})
 	tables = append(tables, &models.OrderedItem{}, &models.Order{})
 	tables = append(tables, &models.CollectionOfProduct{}, &models.Product{}, &models.Category{}, &models.Collection{})
+	tables = append(tables, &models.GlobalCategory{}, &models.TaxClass{})
 	tables = append(tables, &models.ShippingMethod{}, &models.PaymentMethod{}, &models.Settings{})
-	tables = append(tables, &models.Staff{}, &models.StorePermission{}, &models.Store{})
-	tables = append(tables, &models.Address{}, &models.Session{}, &models.User{}, &models.UserPermission{})
+	tables = append(tables, &models.StoreAPIKeyScope{}, &models.StoreAPIKey{}, &models.StaffAuditLog{}, &models.StaffInvitation{}, &models.Staff{}, &models.StoreRolePermission{}, &models.StoreRole{}, &models.StorePermission{}, &models.Store{})
+	tables = append(tables, &models.Address{}, &models.AccountDeletion{}, &models.DataExport{}, &models.LimiterCounter{}, &models.SecurityAuditLog{}, &models.AccountUnlockToken{}, &models.OIDCState{}, &models.UserIdentity{}, &models.TwoFactorChallenge{}, &models.TwoFactorRecoveryCode{}, &models.TwoFactor{}, &models.Session{}, &models.User{}, &models.UserPermission{})
 	tables = append(tables, &models.Location{}, &models.Log{})
 
 	for _, t := range tables {
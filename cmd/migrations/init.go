@@ -95,6 +95,7 @@ func initCmd(cmd *cobra.Command, args []string) {
 		Password:       password,
 		PermissionID:   upAdmin.ID,
 		Email:          "admin@shopicano.com",
+		Locale:         models.DefaultLocale,
 		CreatedAt:      time.Now().UTC(),
 		UpdatedAt:      time.Now().UTC(),
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/machinery"
 	payment_gateways "github.com/shopicano/shopicano-backend/payment-gateways"
+	"github.com/shopicano/shopicano-backend/queue"
 	"github.com/shopicano/shopicano-backend/server"
 	"github.com/spf13/cobra"
 	"os"
@@ -24,10 +25,16 @@ func preServe(cmd *cobra.Command, args []string) {
 		os.Exit(-1)
 	}
 
-	if err := machinery.NewRabbitMQConnection(); err != nil {
-		log.Log().Errorln("Failed to connect to rabbitmq : ", err)
+	if err := machinery.NewConnection(); err != nil {
+		log.Log().Errorln("Failed to connect to task broker : ", err)
 		os.Exit(-1)
 	}
+	if machinery.IsInProcess() {
+		if err := machinery.RegisterRabbitMQTasks(); err != nil {
+			log.Log().Errorln("Failed to register tasks : ", err)
+			os.Exit(-1)
+		}
+	}
 }
 
 func serve(cmd *cobra.Command, args []string) {
@@ -35,5 +42,11 @@ func serve(cmd *cobra.Command, args []string) {
 		log.Log().Errorln("Failed to setup payment gateway : ", err)
 		os.Exit(-1)
 	}
+
+	if machinery.IsInProcess() {
+		go queue.RunPeriodicTasks()
+		go machinery.RunWorker()
+	}
+
 	server.StartServer()
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/machinery"
 	payment_gateways "github.com/shopicano/shopicano-backend/payment-gateways"
+	"github.com/shopicano/shopicano-backend/queue"
 	"github.com/spf13/cobra"
 	"os"
 )
@@ -22,12 +23,16 @@ func preServeWorker(cmd *cobra.Command, args []string) {
 		log.Log().Errorln("Failed to connect to minio : ", err)
 		os.Exit(-1)
 	}
-	if err := machinery.NewRabbitMQConnection(); err != nil {
-		log.Log().Errorln("Failed to connect to rabbitmq : ", err)
+	if machinery.IsInProcess() {
+		log.Log().Errorln("Tasks are run by serve with the in process task broker")
+		os.Exit(-1)
+	}
+	if err := machinery.NewConnection(); err != nil {
+		log.Log().Errorln("Failed to connect to task broker : ", err)
 		os.Exit(-1)
 	}
 	if err := machinery.RegisterRabbitMQTasks(); err != nil {
-		log.Log().Errorln("Failed to register rabbitmq tasks : ", err)
+		log.Log().Errorln("Failed to register tasks : ", err)
 		os.Exit(-1)
 	}
 }
@@ -38,5 +43,7 @@ func serveWorker(cmd *cobra.Command, args []string) {
 		os.Exit(-1)
 	}
 
-	machinery.RunRabbitMQWorker()
+	go queue.RunPeriodicTasks()
+
+	machinery.RunWorker()
 }
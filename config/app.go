@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/spf13/viper"
+	"time"
 )
 
 type LogLevel string
@@ -22,6 +23,12 @@ type Application struct {
 	FrontStoreUrl string
 	DashboardUrl  string
 	JWTKey        string
+	// AccessTokenTTL is the lifetime of the JWT access tokens, RefreshTokenTTL of the session
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// TrustedProxies are the ips or CIDR ranges of the proxies whose X-Forwarded-For is trusted,
+	// without any the client ip is the address of the connection
+	TrustedProxies []string
 }
 
 // app is the default application configuration
@@ -37,6 +44,9 @@ func LoadApp() {
 	mu.Lock()
 	defer mu.Unlock()
 
+	viper.SetDefault("app.access_token_ttl", "15m")
+	viper.SetDefault("app.refresh_token_ttl", "720h")
+
 	app = Application{
 		Base:          viper.GetString("app.host"),
 		Port:          viper.GetInt("app.port"),
@@ -45,5 +55,9 @@ func LoadApp() {
 		FrontStoreUrl: viper.GetString("app.front_store_url"),
 		DashboardUrl:  viper.GetString("app.dashboard_url"),
 		JWTKey:        viper.GetString("app.jwt_key"),
+
+		AccessTokenTTL:  viper.GetDuration("app.access_token_ttl"),
+		RefreshTokenTTL: viper.GetDuration("app.refresh_token_ttl"),
+		TrustedProxies:  viper.GetStringSlice("app.trusted_proxies"),
 	}
 }
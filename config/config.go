@@ -44,7 +44,13 @@ func LoadConfig() error {
 	LoadMinio()
 	LoadPaymentGateway()
 	LoadRabbitMQ()
+	LoadTaskBroker()
 	LoadEmailService()
+	LoadNotification()
+	LoadOIDC()
+	LoadLoginProtection()
+	LoadRateLimit()
+	LoadPersonalData()
 	LoadPathMapping()
 
 	return nil
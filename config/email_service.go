@@ -2,14 +2,22 @@ package config
 
 import (
 	"github.com/spf13/viper"
+	"time"
 )
 
 type EmailServiceCfg struct {
-	SMTPHost         string
-	SMTPPort         int
-	SMTPUsername     string
-	SMTPPassword     string
-	FromEmailAddress string
+	SMTPHost          string
+	SMTPPort          int
+	SMTPUsername      string
+	SMTPPassword      string
+	SMTPSkipTLSVerify bool
+	FromEmailAddress  string
+	TemplateStorage   string
+	Transport         string
+	FileSinkDir       string
+	MaxAttempts       int
+	RetryBaseDelay    time.Duration
+	RetryMaxDelay     time.Duration
 }
 
 var emailServiceCfg EmailServiceCfg
@@ -18,12 +26,25 @@ func LoadEmailService() {
 	mu.Lock()
 	defer mu.Unlock()
 
+	viper.SetDefault("email_service.transport", "smtp")
+	viper.SetDefault("email_service.file_sink_dir", "emails")
+	viper.SetDefault("email_service.max_attempts", 8)
+	viper.SetDefault("email_service.retry_base_delay", 30)
+	viper.SetDefault("email_service.retry_max_delay", 360)
+
 	emailServiceCfg = EmailServiceCfg{
-		SMTPHost:         viper.GetString("email_service.smtp_host"),
-		SMTPPort:         viper.GetInt("email_service.smtp_port"),
-		SMTPUsername:     viper.GetString("email_service.smtp_username"),
-		SMTPPassword:     viper.GetString("email_service.smtp_password"),
-		FromEmailAddress: viper.GetString("email_service.from_email_address"),
+		SMTPHost:          viper.GetString("email_service.smtp_host"),
+		SMTPPort:          viper.GetInt("email_service.smtp_port"),
+		SMTPUsername:      viper.GetString("email_service.smtp_username"),
+		SMTPPassword:      viper.GetString("email_service.smtp_password"),
+		SMTPSkipTLSVerify: viper.GetBool("email_service.smtp_skip_tls_verify"),
+		FromEmailAddress:  viper.GetString("email_service.from_email_address"),
+		TemplateStorage:   viper.GetString("email_service.template_storage"),
+		Transport:         viper.GetString("email_service.transport"),
+		FileSinkDir:       viper.GetString("email_service.file_sink_dir"),
+		MaxAttempts:       viper.GetInt("email_service.max_attempts"),
+		RetryBaseDelay:    viper.GetDuration("email_service.retry_base_delay") * time.Second,
+		RetryMaxDelay:     viper.GetDuration("email_service.retry_max_delay") * time.Minute,
 	}
 }
 
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"time"
+)
+
+const (
+	LimiterStoreMemory   = "memory"
+	LimiterStoreDatabase = "database"
+)
+
+// LoginProtectionCfg tunes the brute force protection of login and password reset. Failures past
+// FreeAttempts wait BaseDelay doubled on every further failure up to MaxDelay, an account or an ip
+// reaching its lockout attempts within Window is locked for LockoutDuration.
+type LoginProtectionCfg struct {
+	// LimiterStore keeps the counters in memory of the instance or in the database to share them
+	LimiterStore           string
+	Window                 time.Duration
+	FreeAttempts           int
+	BaseDelay              time.Duration
+	MaxDelay               time.Duration
+	AccountLockoutAttempts int
+	IPLockoutAttempts      int
+	LockoutDuration        time.Duration
+	UnlockTokenTTL         time.Duration
+	ResetPasswordWindow    time.Duration
+	ResetPasswordPerEmail  int
+	ResetPasswordPerIP     int
+}
+
+var loginProtectionCfg LoginProtectionCfg
+
+func LoadLoginProtection() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	viper.SetDefault("login_protection.limiter_store", LimiterStoreMemory)
+	viper.SetDefault("login_protection.window", "15m")
+	viper.SetDefault("login_protection.free_attempts", 3)
+	viper.SetDefault("login_protection.base_delay", "1s")
+	viper.SetDefault("login_protection.max_delay", "1m")
+	viper.SetDefault("login_protection.account_lockout_attempts", 10)
+	viper.SetDefault("login_protection.ip_lockout_attempts", 50)
+	viper.SetDefault("login_protection.lockout_duration", "30m")
+	viper.SetDefault("login_protection.unlock_token_ttl", "24h")
+	viper.SetDefault("login_protection.reset_password_window", "1h")
+	viper.SetDefault("login_protection.reset_password_per_email", 3)
+	viper.SetDefault("login_protection.reset_password_per_ip", 10)
+
+	loginProtectionCfg = LoginProtectionCfg{
+		LimiterStore:           viper.GetString("login_protection.limiter_store"),
+		Window:                 viper.GetDuration("login_protection.window"),
+		FreeAttempts:           viper.GetInt("login_protection.free_attempts"),
+		BaseDelay:              viper.GetDuration("login_protection.base_delay"),
+		MaxDelay:               viper.GetDuration("login_protection.max_delay"),
+		AccountLockoutAttempts: viper.GetInt("login_protection.account_lockout_attempts"),
+		IPLockoutAttempts:      viper.GetInt("login_protection.ip_lockout_attempts"),
+		LockoutDuration:        viper.GetDuration("login_protection.lockout_duration"),
+		UnlockTokenTTL:         viper.GetDuration("login_protection.unlock_token_ttl"),
+		ResetPasswordWindow:    viper.GetDuration("login_protection.reset_password_window"),
+		ResetPasswordPerEmail:  viper.GetInt("login_protection.reset_password_per_email"),
+		ResetPasswordPerIP:     viper.GetInt("login_protection.reset_password_per_ip"),
+	}
+}
+
+func LoginProtection() LoginProtectionCfg {
+	return loginProtectionCfg
+}
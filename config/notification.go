@@ -0,0 +1,50 @@
+package config
+
+import "github.com/spf13/viper"
+
+type TwilioCfg struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+}
+
+type FCMCfg struct {
+	ServerKey string
+}
+
+type NotificationCfg struct {
+	SMSProvider  string
+	PushProvider string
+	FileSinkDir  string
+	Twilio       TwilioCfg
+	FCM          FCMCfg
+}
+
+var notificationCfg NotificationCfg
+
+func LoadNotification() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	viper.SetDefault("notification.sms_provider", "none")
+	viper.SetDefault("notification.push_provider", "none")
+	viper.SetDefault("notification.file_sink_dir", "notifications")
+
+	notificationCfg = NotificationCfg{
+		SMSProvider:  viper.GetString("notification.sms_provider"),
+		PushProvider: viper.GetString("notification.push_provider"),
+		FileSinkDir:  viper.GetString("notification.file_sink_dir"),
+		Twilio: TwilioCfg{
+			AccountSID: viper.GetString("notification.twilio.account_sid"),
+			AuthToken:  viper.GetString("notification.twilio.auth_token"),
+			From:       viper.GetString("notification.twilio.from"),
+		},
+		FCM: FCMCfg{
+			ServerKey: viper.GetString("notification.fcm.server_key"),
+		},
+	}
+}
+
+func Notification() NotificationCfg {
+	return notificationCfg
+}
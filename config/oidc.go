@@ -0,0 +1,85 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"sort"
+)
+
+const (
+	OIDCGoogle = "google"
+	OIDCApple  = "apple"
+)
+
+// OIDCProviderCfg configures an OpenID Connect issuer users can sign in with.
+// Apple doesn't issue client secrets, it's signed with the key of TeamID and KeyID instead.
+type OIDCProviderCfg struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	ResponseMode string
+	TeamID       string
+	KeyID        string
+	PrivateKey   string
+}
+
+type OIDCCfg struct {
+	Providers map[string]OIDCProviderCfg
+}
+
+var oidcCfg OIDCCfg
+
+var oidcIssuers = map[string]string{
+	OIDCGoogle: "https://accounts.google.com",
+	OIDCApple:  "https://appleid.apple.com",
+}
+
+// LoadOIDC loads every provider under oidc.providers, the ones without a client id are skipped
+func LoadOIDC() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	oidcCfg = OIDCCfg{
+		Providers: map[string]OIDCProviderCfg{},
+	}
+
+	var names []string
+	for name := range viper.GetStringMap("oidc.providers") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := "oidc.providers." + name
+
+		viper.SetDefault(key+".issuer", oidcIssuers[name])
+		viper.SetDefault(key+".scopes", []string{"openid", "email", "profile"})
+		if name == OIDCApple {
+			viper.SetDefault(key+".scopes", []string{"openid", "email", "name"})
+			viper.SetDefault(key+".response_mode", "form_post")
+		}
+
+		p := OIDCProviderCfg{
+			Name:         name,
+			Issuer:       viper.GetString(key + ".issuer"),
+			ClientID:     viper.GetString(key + ".client_id"),
+			ClientSecret: viper.GetString(key + ".client_secret"),
+			RedirectURL:  viper.GetString(key + ".redirect_url"),
+			Scopes:       viper.GetStringSlice(key + ".scopes"),
+			ResponseMode: viper.GetString(key + ".response_mode"),
+			TeamID:       viper.GetString(key + ".team_id"),
+			KeyID:        viper.GetString(key + ".key_id"),
+			PrivateKey:   viper.GetString(key + ".private_key"),
+		}
+		if p.ClientID == "" || p.Issuer == "" {
+			continue
+		}
+		oidcCfg.Providers[name] = p
+	}
+}
+
+func OIDC() OIDCCfg {
+	return oidcCfg
+}
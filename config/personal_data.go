@@ -0,0 +1,33 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"time"
+)
+
+// PersonalDataCfg tunes the data exports and the account deletions of the users
+type PersonalDataCfg struct {
+	// ExportTTL is how long an export archive stays downloadable
+	ExportTTL time.Duration
+	// DeletionGracePeriod is how long a requested deletion can be cancelled before it's carried out
+	DeletionGracePeriod time.Duration
+}
+
+var personalDataCfg PersonalDataCfg
+
+func LoadPersonalData() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	viper.SetDefault("personal_data.export_ttl", "168h")
+	viper.SetDefault("personal_data.deletion_grace_period", "720h")
+
+	personalDataCfg = PersonalDataCfg{
+		ExportTTL:           viper.GetDuration("personal_data.export_ttl"),
+		DeletionGracePeriod: viper.GetDuration("personal_data.deletion_grace_period"),
+	}
+}
+
+func PersonalData() PersonalDataCfg {
+	return personalDataCfg
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"time"
+)
+
+const (
+	RateLimitDefault  = "default"
+	RateLimitCatalog  = "catalog"
+	RateLimitCheckout = "checkout"
+	RateLimitUploads  = "uploads"
+	RateLimitAdmin    = "admin"
+)
+
+// RateLimitGroupCfg is the token bucket of every client on a group of routes. The bucket holds Burst
+// requests and refills Requests every Period, a group without requests isn't limited. Paths are
+// route prefixes, optionally preceded by a method, e.g. "POST /v1/fs/".
+type RateLimitGroupCfg struct {
+	Name     string
+	Requests int
+	Period   time.Duration
+	Burst    int
+	Paths    []string
+}
+
+// RateLimitCfg limits the requests of each client, an api key, a user or else an ip,
+// the buckets are kept in memory of the instance
+type RateLimitCfg struct {
+	Enabled bool
+	Groups  map[string]RateLimitGroupCfg
+}
+
+var rateLimitCfg RateLimitCfg
+
+var rateLimitDefaults = map[string]RateLimitGroupCfg{
+	RateLimitDefault: {
+		Requests: 120,
+		Period:   time.Minute,
+	},
+	RateLimitCatalog: {
+		Requests: 300,
+		Period:   time.Minute,
+		Paths: []string{"GET /v1/products/", "GET /v1/categories/", "GET /v1/collections/",
+			"GET /v1/stores/", "GET /v1/locations/", "GET /v1/fs/"},
+	},
+	RateLimitCheckout: {
+		Requests: 30,
+		Period:   time.Minute,
+		Paths:    []string{"/v1/orders/", "/v1/payments/", "/v1/coupons/"},
+	},
+	RateLimitUploads: {
+		Requests: 20,
+		Period:   time.Minute,
+		Paths:    []string{"POST /v1/fs/"},
+	},
+	RateLimitAdmin: {
+		Requests: 600,
+		Period:   time.Minute,
+		Paths:    []string{"/v1/marketplace/"},
+	},
+}
+
+// LoadRateLimit loads the known groups along with any other group under rate_limit.groups
+func LoadRateLimit() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	viper.SetDefault("rate_limit.enabled", true)
+
+	rateLimitCfg = RateLimitCfg{
+		Enabled: viper.GetBool("rate_limit.enabled"),
+		Groups:  map[string]RateLimitGroupCfg{},
+	}
+
+	names := map[string]bool{}
+	for name := range rateLimitDefaults {
+		names[name] = true
+	}
+	for name := range viper.GetStringMap("rate_limit.groups") {
+		names[name] = true
+	}
+
+	for name := range names {
+		key := "rate_limit.groups." + name
+
+		d := rateLimitDefaults[name]
+		viper.SetDefault(key+".requests", d.Requests)
+		viper.SetDefault(key+".period", d.Period)
+		viper.SetDefault(key+".paths", d.Paths)
+
+		g := RateLimitGroupCfg{
+			Name:     name,
+			Requests: viper.GetInt(key + ".requests"),
+			Period:   viper.GetDuration(key + ".period"),
+			Burst:    viper.GetInt(key + ".burst"),
+			Paths:    viper.GetStringSlice(key + ".paths"),
+		}
+		if g.Period <= 0 {
+			g.Period = time.Minute
+		}
+		if g.Burst <= 0 {
+			g.Burst = g.Requests
+		}
+		rateLimitCfg.Groups[name] = g
+	}
+}
+
+func RateLimit() RateLimitCfg {
+	return rateLimitCfg
+}
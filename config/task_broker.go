@@ -0,0 +1,56 @@
+package config
+
+import "github.com/spf13/viper"
+
+type RedisBrokerCfg struct {
+	Broker        string
+	DefaultQueue  string
+	ResultBackend string
+	Worker        Worker
+}
+
+type InProcessBrokerCfg struct {
+	Concurrency int
+	QueueSize   int
+}
+
+type TaskBrokerCfg struct {
+	Driver    string
+	Redis     RedisBrokerCfg
+	InProcess InProcessBrokerCfg
+}
+
+var taskBroker TaskBrokerCfg
+
+func LoadTaskBroker() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	viper.SetDefault("task_broker.driver", "rabbitmq")
+	viper.SetDefault("task_broker.redis.default_queue", "shopicano_tasks")
+	viper.SetDefault("task_broker.redis.worker.name", "worker-1")
+	viper.SetDefault("task_broker.redis.worker.count", 5)
+	viper.SetDefault("task_broker.in_process.concurrency", 5)
+	viper.SetDefault("task_broker.in_process.queue_size", 1000)
+
+	taskBroker = TaskBrokerCfg{
+		Driver: viper.GetString("task_broker.driver"),
+		Redis: RedisBrokerCfg{
+			Broker:        viper.GetString("task_broker.redis.broker"),
+			DefaultQueue:  viper.GetString("task_broker.redis.default_queue"),
+			ResultBackend: viper.GetString("task_broker.redis.result_backend"),
+			Worker: Worker{
+				Name:  viper.GetString("task_broker.redis.worker.name"),
+				Count: viper.GetInt("task_broker.redis.worker.count"),
+			},
+		},
+		InProcess: InProcessBrokerCfg{
+			Concurrency: viper.GetInt("task_broker.in_process.concurrency"),
+			QueueSize:   viper.GetInt("task_broker.in_process.queue_size"),
+		},
+	}
+}
+
+func TaskBroker() TaskBrokerCfg {
+	return taskBroker
+}
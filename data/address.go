@@ -3,6 +3,7 @@ package data
 import (
 	"github.com/jinzhu/gorm"
 	"github.com/shopicano/shopicano-backend/models"
+	"time"
 )
 
 type AddressRepository interface {
@@ -13,4 +14,5 @@ type AddressRepository interface {
 	GetRawAddressByID(db *gorm.DB, addressID string) (*models.Address, error)
 	ListAddresses(db *gorm.DB, userID string, from, limit int) ([]models.AddressView, error)
 	DeleteAddress(db *gorm.DB, userID, addressID string) error
+	AnonymizeAddresses(db *gorm.DB, userID string, at time.Time) error
 }
@@ -3,6 +3,7 @@ package data
 import (
 	"github.com/jinzhu/gorm"
 	"github.com/shopicano/shopicano-backend/models"
+	"time"
 )
 
 type AddressRepositoryImpl struct {
@@ -80,3 +81,23 @@ func (au *AddressRepositoryImpl) DeleteAddress(db *gorm.DB, userID, addressID st
 	}
 	return nil
 }
+
+// AnonymizeAddresses clears the contact details of every address of the user, the location is kept
+// as the orders shipped to them are still accounted by their stores
+func (au *AddressRepositoryImpl) AnonymizeAddresses(db *gorm.DB, userID string, at time.Time) error {
+	address := models.Address{}
+	if err := db.Table(address.TableName()).
+		Where("user_id = ?", userID).
+		Select("name, address, postcode, email, phone, updated_at").
+		Updates(map[string]interface{}{
+			"name":       "Deleted user",
+			"address":    "",
+			"postcode":   "",
+			"email":      "",
+			"phone":      "",
+			"updated_at": at,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
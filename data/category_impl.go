@@ -122,13 +122,14 @@ func (cu *CategoryRepositoryImpl) Update(db *gorm.DB, c *models.Category) error
 	col := models.Category{}
 	if err := db.Table(col.TableName()).
 		Where("store_id = ? AND id = ?", c.StoreID, c.ID).
-		Select("name", "description", "image", "is_published", "updated_at").
+		Select("name", "description", "image", "is_published", "global_category_id", "updated_at").
 		Updates(map[string]interface{}{
-			"name":         c.Name,
-			"description":  c.Description,
-			"is_published": c.IsPublished,
-			"image":        c.Image,
-			"updated_at":   c.UpdatedAt,
+			"name":               c.Name,
+			"global_category_id": c.GlobalCategoryID,
+			"description":        c.Description,
+			"is_published":       c.IsPublished,
+			"image":              c.Image,
+			"updated_at":         c.UpdatedAt,
 		}).Error; err != nil {
 		return err
 	}
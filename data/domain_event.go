@@ -0,0 +1,14 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type DomainEventRepository interface {
+	Create(db *gorm.DB, e *models.DomainEvent) error
+	Get(db *gorm.DB, ID string) (*models.DomainEvent, error)
+	ListUnpublished(db *gorm.DB, limit int) ([]models.DomainEvent, error)
+	MarkPublished(db *gorm.DB, ID string, at time.Time) error
+}
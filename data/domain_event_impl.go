@@ -0,0 +1,61 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type DomainEventRepositoryImpl struct {
+}
+
+var domainEventRepository DomainEventRepository
+
+func NewDomainEventRepository() DomainEventRepository {
+	if domainEventRepository == nil {
+		domainEventRepository = &DomainEventRepositoryImpl{}
+	}
+	return domainEventRepository
+}
+
+func (deu *DomainEventRepositoryImpl) Create(db *gorm.DB, e *models.DomainEvent) error {
+	if err := db.Table(e.TableName()).Create(e).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (deu *DomainEventRepositoryImpl) Get(db *gorm.DB, ID string) (*models.DomainEvent, error) {
+	e := models.DomainEvent{}
+	if err := db.Table(e.TableName()).
+		Where("id = ?", ID).
+		First(&e).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ListUnpublished locks the oldest unpublished events, skipping the ones another relay holds
+func (deu *DomainEventRepositoryImpl) ListUnpublished(db *gorm.DB, limit int) ([]models.DomainEvent, error) {
+	e := models.DomainEvent{}
+	var data []models.DomainEvent
+	if err := db.Table(e.TableName()).
+		Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+		Where("published_at IS NULL").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (deu *DomainEventRepositoryImpl) MarkPublished(db *gorm.DB, ID string, at time.Time) error {
+	e := models.DomainEvent{}
+	if err := db.Table(e.TableName()).
+		Where("id = ?", ID).
+		Update("published_at", at).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type EmailRepository interface {
+	Create(db *gorm.DB, e *models.Email) error
+	AddAttachment(db *gorm.DB, a *models.EmailAttachment) error
+	UpdateDelivery(db *gorm.DB, e *models.Email) error
+	CreateDeliveryLog(db *gorm.DB, l *models.EmailDeliveryLog) error
+	Get(db *gorm.DB, ID string) (*models.Email, error)
+	ListAttachments(db *gorm.DB, emailID string) ([]models.EmailAttachment, error)
+	ListDeliveryLogs(db *gorm.DB, emailID string) ([]models.EmailDeliveryLog, error)
+	ListDue(db *gorm.DB, now time.Time, limit int) ([]models.Email, error)
+	Search(db *gorm.DB, filter *EmailFilter, from, limit int) ([]models.Email, error)
+}
+
+type EmailFilter struct {
+	Query   string
+	Status  models.EmailStatus
+	UserID  string
+	OrderID string
+}
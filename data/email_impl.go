@@ -0,0 +1,133 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"strings"
+	"time"
+)
+
+type EmailRepositoryImpl struct {
+}
+
+var emailRepository EmailRepository
+
+func NewEmailRepository() EmailRepository {
+	if emailRepository == nil {
+		emailRepository = &EmailRepositoryImpl{}
+	}
+	return emailRepository
+}
+
+func (eu *EmailRepositoryImpl) Create(db *gorm.DB, e *models.Email) error {
+	if err := db.Table(e.TableName()).Create(e).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (eu *EmailRepositoryImpl) AddAttachment(db *gorm.DB, a *models.EmailAttachment) error {
+	if err := db.Table(a.TableName()).Create(a).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (eu *EmailRepositoryImpl) UpdateDelivery(db *gorm.DB, e *models.Email) error {
+	if err := db.Table(e.TableName()).
+		Where("id = ?", e.ID).
+		Select("status, attempts, last_error, next_attempt_at, sent_at, updated_at").
+		Updates(map[string]interface{}{
+			"status":          e.Status,
+			"attempts":        e.Attempts,
+			"last_error":      e.LastError,
+			"next_attempt_at": e.NextAttemptAt,
+			"sent_at":         e.SentAt,
+			"updated_at":      e.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (eu *EmailRepositoryImpl) CreateDeliveryLog(db *gorm.DB, l *models.EmailDeliveryLog) error {
+	if err := db.Table(l.TableName()).Create(l).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (eu *EmailRepositoryImpl) Get(db *gorm.DB, ID string) (*models.Email, error) {
+	e := models.Email{}
+	if err := db.Table(e.TableName()).
+		Where("id = ?", ID).
+		First(&e).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (eu *EmailRepositoryImpl) ListAttachments(db *gorm.DB, emailID string) ([]models.EmailAttachment, error) {
+	a := models.EmailAttachment{}
+	var data []models.EmailAttachment
+	if err := db.Table(a.TableName()).
+		Where("email_id = ?", emailID).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (eu *EmailRepositoryImpl) ListDeliveryLogs(db *gorm.DB, emailID string) ([]models.EmailDeliveryLog, error) {
+	l := models.EmailDeliveryLog{}
+	var data []models.EmailDeliveryLog
+	if err := db.Table(l.TableName()).
+		Where("email_id = ?", emailID).
+		Order("created_at ASC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (eu *EmailRepositoryImpl) ListDue(db *gorm.DB, now time.Time, limit int) ([]models.Email, error) {
+	e := models.Email{}
+	var data []models.Email
+	if err := db.Table(e.TableName()).
+		Where("status = ? AND next_attempt_at <= ?", models.EmailQueued, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (eu *EmailRepositoryImpl) Search(db *gorm.DB, filter *EmailFilter, from, limit int) ([]models.Email, error) {
+	e := models.Email{}
+	var data []models.Email
+
+	q := db.Table(e.TableName()).
+		Select("id, recipient, subject, template, user_id, order_id, status, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at")
+	if filter.Query != "" {
+		query := "%" + strings.ToLower(filter.Query) + "%"
+		q = q.Where("LOWER(recipient) LIKE ? OR LOWER(subject) LIKE ?", query, query)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.UserID != "" {
+		q = q.Where("user_id = ?", filter.UserID)
+	}
+	if filter.OrderID != "" {
+		q = q.Where("order_id = ?", filter.OrderID)
+	}
+
+	if err := q.Order("created_at DESC").
+		Offset(from).
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
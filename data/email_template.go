@@ -0,0 +1,17 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type EmailTemplateRepository interface {
+	Create(db *gorm.DB, et *models.EmailTemplate) error
+	Update(db *gorm.DB, et *models.EmailTemplate) error
+	Delete(db *gorm.DB, ID string) error
+	Get(db *gorm.DB, ID string) (*models.EmailTemplate, error)
+	GetActive(db *gorm.DB, name models.EmailTemplateName, locale string) (*models.EmailTemplate, error)
+	List(db *gorm.DB, name, locale string, from, limit int) ([]models.EmailTemplate, error)
+	GetStoreBranding(db *gorm.DB, storeID string) (*models.StoreEmailBranding, error)
+	SaveStoreBranding(db *gorm.DB, b *models.StoreEmailBranding) error
+}
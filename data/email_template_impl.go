@@ -0,0 +1,109 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type EmailTemplateRepositoryImpl struct {
+}
+
+var emailTemplateRepository EmailTemplateRepository
+
+func NewEmailTemplateRepository() EmailTemplateRepository {
+	if emailTemplateRepository == nil {
+		emailTemplateRepository = &EmailTemplateRepositoryImpl{}
+	}
+	return emailTemplateRepository
+}
+
+func (etu *EmailTemplateRepositoryImpl) Create(db *gorm.DB, et *models.EmailTemplate) error {
+	if err := db.Table(et.TableName()).Create(et).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (etu *EmailTemplateRepositoryImpl) Update(db *gorm.DB, et *models.EmailTemplate) error {
+	if err := db.Table(et.TableName()).
+		Where("id = ?", et.ID).
+		Select("subject, body, is_active, updated_at").
+		Updates(map[string]interface{}{
+			"subject":    et.Subject,
+			"body":       et.Body,
+			"is_active":  et.IsActive,
+			"updated_at": et.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (etu *EmailTemplateRepositoryImpl) Delete(db *gorm.DB, ID string) error {
+	et := models.EmailTemplate{}
+	if err := db.Table(et.TableName()).
+		Where("id = ?", ID).
+		Delete(&et).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (etu *EmailTemplateRepositoryImpl) Get(db *gorm.DB, ID string) (*models.EmailTemplate, error) {
+	et := models.EmailTemplate{}
+	if err := db.Table(et.TableName()).
+		Where("id = ?", ID).
+		First(&et).Error; err != nil {
+		return nil, err
+	}
+	return &et, nil
+}
+
+func (etu *EmailTemplateRepositoryImpl) GetActive(db *gorm.DB, name models.EmailTemplateName, locale string) (*models.EmailTemplate, error) {
+	et := models.EmailTemplate{}
+	if err := db.Table(et.TableName()).
+		Where("name = ? AND locale = ? AND is_active = ?", name, locale, true).
+		First(&et).Error; err != nil {
+		return nil, err
+	}
+	return &et, nil
+}
+
+func (etu *EmailTemplateRepositoryImpl) List(db *gorm.DB, name, locale string, from, limit int) ([]models.EmailTemplate, error) {
+	et := models.EmailTemplate{}
+	var data []models.EmailTemplate
+
+	q := db.Table(et.TableName()).
+		Select("id, name, locale, subject, is_active, created_at, updated_at")
+	if name != "" {
+		q = q.Where("name = ?", name)
+	}
+	if locale != "" {
+		q = q.Where("locale = ?", locale)
+	}
+
+	if err := q.Order("name ASC, locale ASC").
+		Offset(from).
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (etu *EmailTemplateRepositoryImpl) GetStoreBranding(db *gorm.DB, storeID string) (*models.StoreEmailBranding, error) {
+	b := models.StoreEmailBranding{}
+	if err := db.Table(b.TableName()).
+		Where("store_id = ?", storeID).
+		First(&b).Error; err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (etu *EmailTemplateRepositoryImpl) SaveStoreBranding(db *gorm.DB, b *models.StoreEmailBranding) error {
+	if err := db.Table(b.TableName()).Save(b).Error; err != nil {
+		return err
+	}
+	return nil
+}
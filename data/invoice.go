@@ -0,0 +1,12 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type InvoiceRepository interface {
+	Create(db *gorm.DB, i *models.Invoice) error
+	Get(db *gorm.DB, orderID string, invoiceType models.InvoiceType) (*models.Invoice, error)
+	NextNumber(db *gorm.DB, storeID string, invoiceType models.InvoiceType) (int64, error)
+}
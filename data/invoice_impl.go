@@ -0,0 +1,60 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type InvoiceRepositoryImpl struct {
+}
+
+var invoiceRepository InvoiceRepository
+
+func NewInvoiceRepository() InvoiceRepository {
+	if invoiceRepository == nil {
+		invoiceRepository = &InvoiceRepositoryImpl{}
+	}
+	return invoiceRepository
+}
+
+func (iu *InvoiceRepositoryImpl) Create(db *gorm.DB, i *models.Invoice) error {
+	if err := db.Table(i.TableName()).Create(i).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (iu *InvoiceRepositoryImpl) Get(db *gorm.DB, orderID string, invoiceType models.InvoiceType) (*models.Invoice, error) {
+	i := models.Invoice{}
+	if err := db.Table(i.TableName()).
+		Where("order_id = ? AND type = ?", orderID, invoiceType).
+		First(&i).Error; err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// NextNumber locks the store row so that concurrent callers in other transactions get consecutive numbers
+func (iu *InvoiceRepositoryImpl) NextNumber(db *gorm.DB, storeID string, invoiceType models.InvoiceType) (int64, error) {
+	s := models.Store{}
+	if err := db.Table(s.TableName()).
+		Set("gorm:query_option", "FOR UPDATE").
+		Where("id = ?", storeID).
+		Select("id").
+		First(&s).Error; err != nil {
+		return 0, err
+	}
+
+	i := models.Invoice{}
+
+	var res struct {
+		Number int64
+	}
+	if err := db.Table(i.TableName()).
+		Select("COALESCE(MAX(number), 0) + 1 AS number").
+		Where("store_id = ? AND type = ?", storeID, invoiceType).
+		Scan(&res).Error; err != nil {
+		return 0, err
+	}
+	return res.Number, nil
+}
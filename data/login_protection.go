@@ -0,0 +1,21 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type LoginProtectionRepository interface {
+	GetCounter(db *gorm.DB, name string) (*models.LimiterCounter, error)
+	HitCounter(db *gorm.DB, name string, window time.Duration, now time.Time) (*models.LimiterCounter, error)
+	LockCounter(db *gorm.DB, name string, until time.Time) error
+	DeleteCounter(db *gorm.DB, name string) error
+	DeleteExpiredCounters(db *gorm.DB, now time.Time) error
+	CreateUnlockToken(db *gorm.DB, ut *models.AccountUnlockToken) error
+	GetUnlockToken(db *gorm.DB, tokenHash string) (*models.AccountUnlockToken, error)
+	DeleteUnlockTokens(db *gorm.DB, userID string) error
+	DeleteExpiredUnlockTokens(db *gorm.DB, now time.Time) error
+	CreateAuditLog(db *gorm.DB, l *models.SecurityAuditLog) error
+	ListAuditLogs(db *gorm.DB, from, limit int) ([]models.SecurityAuditLog, error)
+}
@@ -0,0 +1,118 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type LoginProtectionRepositoryImpl struct {
+}
+
+var loginProtectionRepository LoginProtectionRepository
+
+func NewLoginProtectionRepository() LoginProtectionRepository {
+	if loginProtectionRepository == nil {
+		loginProtectionRepository = &LoginProtectionRepositoryImpl{}
+	}
+	return loginProtectionRepository
+}
+
+func (lr *LoginProtectionRepositoryImpl) GetCounter(db *gorm.DB, name string) (*models.LimiterCounter, error) {
+	lc := models.LimiterCounter{}
+	if err := db.Model(&lc).Where("name = ?", name).First(&lc).Error; err != nil {
+		return nil, err
+	}
+	return &lc, nil
+}
+
+// HitCounter counts an attempt in a single statement so concurrent attempts aren't lost,
+// the count starts over once the window has passed
+func (lr *LoginProtectionRepositoryImpl) HitCounter(db *gorm.DB, name string, window time.Duration, now time.Time) (*models.LimiterCounter, error) {
+	lc := models.LimiterCounter{}
+	if err := db.Raw("INSERT INTO limiter_counters (name, count, window_start, last_at, expires_at) VALUES (?, 1, ?, ?, ?)"+
+		" ON CONFLICT (name) DO UPDATE SET"+
+		" count = CASE WHEN limiter_counters.window_start <= ? THEN 1 ELSE limiter_counters.count + 1 END,"+
+		" window_start = CASE WHEN limiter_counters.window_start <= ? THEN EXCLUDED.window_start ELSE limiter_counters.window_start END,"+
+		" last_at = EXCLUDED.last_at,"+
+		" expires_at = GREATEST(EXCLUDED.expires_at, COALESCE(limiter_counters.locked_until, EXCLUDED.expires_at))"+
+		" RETURNING *", name, now, now, now.Add(window), now.Add(-window), now.Add(-window)).
+		Scan(&lc).Error; err != nil {
+		return nil, err
+	}
+	return &lc, nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) LockCounter(db *gorm.DB, name string, until time.Time) error {
+	lc := models.LimiterCounter{}
+	if err := db.Table(lc.TableName()).
+		Where("name = ?", name).
+		Updates(map[string]interface{}{
+			"locked_until": until,
+			"expires_at":   gorm.Expr("GREATEST(expires_at, ?)", until),
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) DeleteCounter(db *gorm.DB, name string) error {
+	if err := db.Where("name = ?", name).Delete(&models.LimiterCounter{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) DeleteExpiredCounters(db *gorm.DB, now time.Time) error {
+	if err := db.Where("expires_at < ?", now).Delete(&models.LimiterCounter{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) CreateUnlockToken(db *gorm.DB, ut *models.AccountUnlockToken) error {
+	if err := db.Create(ut).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) GetUnlockToken(db *gorm.DB, tokenHash string) (*models.AccountUnlockToken, error) {
+	ut := models.AccountUnlockToken{}
+	if err := db.Model(&ut).Where("token_hash = ?", tokenHash).First(&ut).Error; err != nil {
+		return nil, err
+	}
+	return &ut, nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) DeleteUnlockTokens(db *gorm.DB, userID string) error {
+	if err := db.Where("user_id = ?", userID).Delete(&models.AccountUnlockToken{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) DeleteExpiredUnlockTokens(db *gorm.DB, now time.Time) error {
+	if err := db.Where("expires_at < ?", now).Delete(&models.AccountUnlockToken{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) CreateAuditLog(db *gorm.DB, l *models.SecurityAuditLog) error {
+	if err := db.Create(l).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *LoginProtectionRepositoryImpl) ListAuditLogs(db *gorm.DB, from, limit int) ([]models.SecurityAuditLog, error) {
+	var logs []models.SecurityAuditLog
+	if err := db.Model(&models.SecurityAuditLog{}).
+		Order("created_at DESC").
+		Limit(limit).Offset(from).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -14,7 +14,28 @@ type MarketplaceRepository interface {
 	ListShippingMethodsByLocationForUser(db *gorm.DB, locationID int64) ([]models.ShippingMethod, error)
 	DeleteShippingMethod(db *gorm.DB, ID string) error
 	GetShippingMethod(db *gorm.DB, ID string) (*models.ShippingMethod, error)
+	GetPlatformShippingMethod(db *gorm.DB, ID string) (*models.ShippingMethod, error)
 	GetShippingMethodForUser(db *gorm.DB, ID string) (*models.ShippingMethod, error)
+	SetShippingWeightBrackets(db *gorm.DB, shippingMethodID string, brackets []models.ShippingWeightBracket) error
+	ListShippingWeightBrackets(db *gorm.DB, shippingMethodID string) ([]models.ShippingWeightBracket, error)
+
+	ListShippingMethodsByStore(db *gorm.DB, storeID string, from, limit int) ([]models.ShippingMethod, error)
+	GetShippingMethodByStore(db *gorm.DB, storeID, ID string) (*models.ShippingMethod, error)
+	DeleteShippingMethodByStore(db *gorm.DB, storeID, ID string) error
+	SetShippingMethodLocations(db *gorm.DB, shippingMethodID string, locationIDs []int64) error
+	ListShippingMethodLocations(db *gorm.DB, shippingMethodID string) ([]models.Location, error)
+	ListShippingMethodsByLocationForStore(db *gorm.DB, storeID string, locationID int64) ([]models.ShippingMethod, error)
+	IsShippingMethodOfferedByStore(db *gorm.DB, storeID, ID string) (bool, error)
+
+	CreateShippingZone(db *gorm.DB, m *models.ShippingZone) error
+	UpdateShippingZone(db *gorm.DB, m *models.ShippingZone) error
+	DeleteShippingZone(db *gorm.DB, ID string) error
+	ListShippingZones(db *gorm.DB, from, limit int) ([]models.ShippingZone, error)
+	GetShippingZone(db *gorm.DB, ID string) (*models.ShippingZone, error)
+	SetShippingZoneLocations(db *gorm.DB, zoneID string, locationIDs []int64) error
+	SetShippingZoneRate(db *gorm.DB, r *models.ShippingZoneRate) error
+	DeleteShippingZoneRate(db *gorm.DB, zoneID, shippingMethodID string) error
+	GetShippingZoneRate(db *gorm.DB, shippingMethodID string, locationIDs []int64) (*models.ShippingZoneRate, error)
 
 	CreatePaymentMethod(db *gorm.DB, pm *models.PaymentMethod) error
 	UpdatePaymentMethod(db *gorm.DB, pm *models.PaymentMethod) error
@@ -50,9 +71,19 @@ type MarketplaceRepository interface {
 	CreatePayoutEntry(db *gorm.DB, m *models.PayoutSend) error
 	ListPayoutEntries(db *gorm.DB, storeID string, from, limit int) ([]models.PayoutSend, error)
 	GetPayoutEntry(db *gorm.DB, storeID, entryID string) (*models.PayoutSend, error)
+	GetPayoutEntryByID(db *gorm.DB, entryID string) (*models.PayoutSend, error)
 	GetPayoutEntryDetails(db *gorm.DB, storeID, entryID string) (*models.PayoutSendDetails, error)
 	UpdatePayoutEntry(db *gorm.DB, ps *models.PayoutSend) error
 
+	CreateGlobalCategory(db *gorm.DB, m *models.GlobalCategory) error
+	UpdateGlobalCategory(db *gorm.DB, m *models.GlobalCategory) error
+	ListGlobalCategories(db *gorm.DB, parentID *string, from, limit int) ([]models.GlobalCategory, error)
+	ListGlobalCategoriesForUser(db *gorm.DB, parentID *string, from, limit int) ([]models.GlobalCategory, error)
+	DeleteGlobalCategory(db *gorm.DB, ID string) error
+	GetGlobalCategory(db *gorm.DB, ID string) (*models.GlobalCategory, error)
+	GetGlobalCategoryForUser(db *gorm.DB, ID string) (*models.GlobalCategory, error)
+	ListGlobalCategoryAncestorIDs(db *gorm.DB, ID string) ([]string, error)
+
 	GetSettings(db *gorm.DB) (*models.Settings, error)
 	GetSettingsDetails(db *gorm.DB) (*models.SettingsDetails, error)
 	UpdateSettings(db *gorm.DB, s *models.Settings) error
@@ -0,0 +1,118 @@
+package data
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+func (au *MarketplaceRepositoryImpl) CreateGlobalCategory(db *gorm.DB, m *models.GlobalCategory) error {
+	if err := db.Create(m).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) UpdateGlobalCategory(db *gorm.DB, m *models.GlobalCategory) error {
+	if err := db.Table(m.TableName()).
+		Where("id = ?", m.ID).
+		Select("name, slug, parent_id, description, image, is_published, updated_at").
+		Updates(map[string]interface{}{
+			"name":         m.Name,
+			"slug":         m.Slug,
+			"parent_id":    m.ParentID,
+			"description":  m.Description,
+			"image":        m.Image,
+			"is_published": m.IsPublished,
+			"updated_at":   m.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) ListGlobalCategories(db *gorm.DB, parentID *string, from, limit int) ([]models.GlobalCategory, error) {
+	gc := models.GlobalCategory{}
+	var data []models.GlobalCategory
+
+	q := db.Table(gc.TableName())
+	if parentID == nil {
+		q = q.Where("parent_id IS NULL")
+	} else {
+		q = q.Where("parent_id = ?", *parentID)
+	}
+
+	if err := q.Order("name ASC").
+		Offset(from).
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (au *MarketplaceRepositoryImpl) ListGlobalCategoriesForUser(db *gorm.DB, parentID *string, from, limit int) ([]models.GlobalCategory, error) {
+	gc := models.GlobalCategory{}
+	var data []models.GlobalCategory
+
+	q := db.Table(gc.TableName()).Where("is_published = ?", true)
+	if parentID == nil {
+		q = q.Where("parent_id IS NULL")
+	} else {
+		q = q.Where("parent_id = ?", *parentID)
+	}
+
+	if err := q.Order("name ASC").
+		Offset(from).
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (au *MarketplaceRepositoryImpl) DeleteGlobalCategory(db *gorm.DB, ID string) error {
+	gc := models.GlobalCategory{}
+	if err := db.Table(gc.TableName()).Delete(&gc, "id = ?", ID).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) GetGlobalCategory(db *gorm.DB, ID string) (*models.GlobalCategory, error) {
+	gc := models.GlobalCategory{}
+	if err := db.Table(gc.TableName()).First(&gc, "id = ? OR slug = ?", ID, ID).Error; err != nil {
+		return nil, err
+	}
+	return &gc, nil
+}
+
+func (au *MarketplaceRepositoryImpl) GetGlobalCategoryForUser(db *gorm.DB, ID string) (*models.GlobalCategory, error) {
+	gc := models.GlobalCategory{}
+	if err := db.Table(gc.TableName()).First(&gc, "(id = ? OR slug = ?) AND is_published = ?", ID, ID, true).
+		Error; err != nil {
+		return nil, err
+	}
+	return &gc, nil
+}
+
+// ListGlobalCategoryAncestorIDs returns the category and all of its ancestors, UNION ends the walk even on a cycle
+func (au *MarketplaceRepositoryImpl) ListGlobalCategoryAncestorIDs(db *gorm.DB, ID string) ([]string, error) {
+	gc := models.GlobalCategory{}
+
+	var result []struct {
+		ID string
+	}
+	if err := db.Raw(fmt.Sprintf("WITH RECURSIVE t AS (SELECT id, parent_id FROM %s WHERE id = ?"+
+		" UNION SELECT g.id, g.parent_id FROM %s AS g JOIN t ON g.id = t.parent_id) SELECT id FROM t",
+		gc.TableName(), gc.TableName()), ID).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+
+	var IDs []string
+	for _, r := range result {
+		IDs = append(IDs, r.ID)
+	}
+	return IDs, nil
+}
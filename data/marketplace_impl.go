@@ -56,12 +56,17 @@ func (au *MarketplaceRepositoryImpl) ListActiveShippingMethods(db *gorm.DB, from
 	return data, nil
 }
 
+// DeleteShippingMethod deletes a platform shipping method, the ones of the stores are deleted by DeleteShippingMethodByStore
 func (au *MarketplaceRepositoryImpl) DeleteShippingMethod(db *gorm.DB, ID string) error {
 	m := models.ShippingMethod{}
-	if err := db.Table(m.TableName()).
-		Where("id = ?", ID).
-		Delete(&m).Error; err != nil {
-		return err
+	q := db.Table(m.TableName()).
+		Where("id = ? AND store_id IS NULL", ID).
+		Delete(&m)
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
 	}
 	return nil
 }
@@ -73,6 +78,28 @@ func (au *MarketplaceRepositoryImpl) GetShippingMethod(db *gorm.DB, ID string) (
 		First(&m).Error; err != nil {
 		return &m, err
 	}
+
+	brackets, err := au.ListShippingWeightBrackets(db, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.WeightBrackets = brackets
+	return &m, nil
+}
+
+func (au *MarketplaceRepositoryImpl) GetPlatformShippingMethod(db *gorm.DB, ID string) (*models.ShippingMethod, error) {
+	m := models.ShippingMethod{}
+	if err := db.Table(m.TableName()).
+		Where("id = ? AND store_id IS NULL", ID).
+		First(&m).Error; err != nil {
+		return nil, err
+	}
+
+	brackets, err := au.ListShippingWeightBrackets(db, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.WeightBrackets = brackets
 	return &m, nil
 }
 
@@ -83,9 +110,44 @@ func (au *MarketplaceRepositoryImpl) GetShippingMethodForUser(db *gorm.DB, ID st
 		First(&m).Error; err != nil {
 		return &m, err
 	}
+
+	brackets, err := au.ListShippingWeightBrackets(db, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.WeightBrackets = brackets
 	return &m, nil
 }
 
+func (au *MarketplaceRepositoryImpl) SetShippingWeightBrackets(db *gorm.DB, shippingMethodID string, brackets []models.ShippingWeightBracket) error {
+	swb := models.ShippingWeightBracket{}
+	if err := db.Table(swb.TableName()).
+		Where("shipping_method_id = ?", shippingMethodID).
+		Delete(&swb).Error; err != nil {
+		return err
+	}
+
+	for _, b := range brackets {
+		b.ShippingMethodID = shippingMethodID
+		if err := db.Table(swb.TableName()).Create(&b).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) ListShippingWeightBrackets(db *gorm.DB, shippingMethodID string) ([]models.ShippingWeightBracket, error) {
+	brackets := []models.ShippingWeightBracket{}
+	swb := models.ShippingWeightBracket{}
+	if err := db.Table(swb.TableName()).
+		Where("shipping_method_id = ?", shippingMethodID).
+		Order("min_weight ASC").
+		Find(&brackets).Error; err != nil {
+		return nil, err
+	}
+	return brackets, nil
+}
+
 func (au *MarketplaceRepositoryImpl) CreatePaymentMethod(db *gorm.DB, pm *models.PaymentMethod) error {
 	if err := db.Table(pm.TableName()).Create(pm).Error; err != nil {
 		return err
@@ -160,7 +222,7 @@ func (au *MarketplaceRepositoryImpl) GetSettingsDetails(db *gorm.DB) (*models.Se
 	a := models.Address{}
 	if err := db.Table(fmt.Sprintf("%s AS s", settings.TableName())).
 		Where("s.id = ?", "1").
-		Select("s.id AS id, s.name AS name, s.website AS website, s.status AS status, a.address AS address, a.city AS city, a.country AS country, a.postcode AS postcode, a.email AS email, a.phone AS phone, s.is_sign_up_enabled AS is_sign_up_enabled, s.enabled_auto_store_confirmation AS enabled_auto_store_confirmation, s.is_store_creation_enabled AS is_store_creation_enabled, s.default_commission_rate AS default_commission_rate, s.tag_line AS tag_line, s.created_at AS created_at, s.updated_at AS updated_at").
+		Select("s.id AS id, s.name AS name, s.website AS website, s.status AS status, a.address AS address, a.city AS city, a.country AS country, a.postcode AS postcode, a.email AS email, a.phone AS phone, s.is_sign_up_enabled AS is_sign_up_enabled, s.enabled_auto_store_confirmation AS enabled_auto_store_confirmation, s.is_store_creation_enabled AS is_store_creation_enabled, s.default_commission_rate AS default_commission_rate, s.tag_line AS tag_line, s.is_two_factor_required AS is_two_factor_required, s.created_at AS created_at, s.updated_at AS updated_at").
 		Joins(fmt.Sprintf("LEFT JOIN %s AS a ON s.company_address_id = a.id", a.TableName())).
 		Find(&settingsDetails).Error; err != nil {
 		return nil, err
@@ -180,7 +242,7 @@ func (au *MarketplaceRepositoryImpl) GetSettings(db *gorm.DB) (*models.Settings,
 
 func (au *MarketplaceRepositoryImpl) UpdateSettings(db *gorm.DB, s *models.Settings) error {
 	if err := db.Table(s.TableName()).
-		Select("name, status, website, company_address_id, default_commission_rate, enabled_auto_store_confirmation, tag_line, is_sign_up_enabled, is_store_creation_enabled, updated_at").
+		Select("name, status, website, company_address_id, default_commission_rate, enabled_auto_store_confirmation, tag_line, is_sign_up_enabled, is_store_creation_enabled, is_two_factor_required, updated_at").
 		Where("id = ?", "1").
 		Update(map[string]interface{}{
 			"name":                            s.Name,
@@ -192,6 +254,7 @@ func (au *MarketplaceRepositoryImpl) UpdateSettings(db *gorm.DB, s *models.Setti
 			"tag_line":                        s.TagLine,
 			"is_sign_up_enabled":              s.IsSignUpEnabled,
 			"is_store_creation_enabled":       s.IsStoreCreationEnabled,
+			"is_two_factor_required":          s.IsTwoFactorRequired,
 			"updated_at":                      s.UpdatedAt,
 		}).Error; err != nil {
 		return err
@@ -234,7 +297,7 @@ func (au *MarketplaceRepositoryImpl) ListShippingMethodsByLocation(db *gorm.DB,
 	m := models.ShippingMethod{}
 	sol := models.ShippingForLocation{}
 	if err := db.Table(fmt.Sprintf("%s AS sm", m.TableName())).
-		Select("sm.id AS id, sm.name AS name, sm.approximate_delivery_time AS approximate_delivery_time, sm.delivery_charge AS delivery_charge, sm.weight_unit AS weight_unit, sm.is_flat AS is_flat, sm.is_published AS is_published, sm.created_at AS created_at, sm.updated_at AS updated_at").
+		Select("sm.id AS id, sm.store_id AS store_id, sm.carrier_id AS carrier_id, sm.name AS name, sm.approximate_delivery_time AS approximate_delivery_time, sm.handling_time AS handling_time, sm.delivery_charge AS delivery_charge, sm.weight_unit AS weight_unit, sm.is_flat AS is_flat, sm.per_kg_rate AS per_kg_rate, sm.volumetric_divisor AS volumetric_divisor, sm.free_shipping_threshold AS free_shipping_threshold, sm.is_published AS is_published, sm.created_at AS created_at, sm.updated_at AS updated_at").
 		Joins(fmt.Sprintf("JOIN %s AS sol ON sm.id = sol.shipping_method_id AND sol.location_id = %d", sol.TableName(), locationID)).
 		Order("sm.created_at DESC").
 		Find(&data).Error; err != nil {
@@ -249,9 +312,10 @@ func (au *MarketplaceRepositoryImpl) ListShippingMethodsByLocationForUser(db *go
 	sol := models.ShippingForLocation{}
 	loc := models.Location{}
 	if err := db.Table(fmt.Sprintf("%s AS sm", m.TableName())).
-		Select("sm.id AS id, sm.name AS name, sm.approximate_delivery_time AS approximate_delivery_time, sm.delivery_charge AS delivery_charge, sm.weight_unit AS weight_unit, sm.is_flat AS is_flat, sm.is_published AS is_published, sm.created_at AS created_at, sm.updated_at AS updated_at").
+		Select("sm.id AS id, sm.store_id AS store_id, sm.carrier_id AS carrier_id, sm.name AS name, sm.approximate_delivery_time AS approximate_delivery_time, sm.handling_time AS handling_time, sm.delivery_charge AS delivery_charge, sm.weight_unit AS weight_unit, sm.is_flat AS is_flat, sm.per_kg_rate AS per_kg_rate, sm.volumetric_divisor AS volumetric_divisor, sm.free_shipping_threshold AS free_shipping_threshold, sm.is_published AS is_published, sm.created_at AS created_at, sm.updated_at AS updated_at").
 		Joins(fmt.Sprintf("JOIN %s AS sol ON sm.id = sol.shipping_method_id AND sol.location_id = %d AND sm.is_published = %v", sol.TableName(), locationID, true)).
 		Joins(fmt.Sprintf("JOIN %s AS loc ON loc.id = sol.location_id AND loc.is_published = %d", loc.TableName(), 1)).
+		Where("sm.store_id IS NULL").
 		Order("sm.created_at DESC").
 		Find(&data).Error; err != nil {
 		return nil, err
@@ -109,6 +109,14 @@ func (au *MarketplaceRepositoryImpl) GetPayoutEntry(db *gorm.DB, storeID, entryI
 	return &ps, nil
 }
 
+func (au *MarketplaceRepositoryImpl) GetPayoutEntryByID(db *gorm.DB, entryID string) (*models.PayoutSend, error) {
+	ps := models.PayoutSend{}
+	if err := db.Table(ps.TableName()).Where("id = ?", entryID).First(&ps).Error; err != nil {
+		return nil, err
+	}
+	return &ps, nil
+}
+
 func (au *MarketplaceRepositoryImpl) GetPayoutEntryDetails(db *gorm.DB, storeID, entryID string) (*models.PayoutSendDetails, error) {
 	pom := models.PayoutMethod{}
 	ps := models.PayoutSendDetails{}
@@ -0,0 +1,148 @@
+package data
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+func (au *MarketplaceRepositoryImpl) CreateShippingZone(db *gorm.DB, m *models.ShippingZone) error {
+	if err := db.Table(m.TableName()).Create(m).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) UpdateShippingZone(db *gorm.DB, m *models.ShippingZone) error {
+	if err := db.Table(m.TableName()).
+		Where("id = ?", m.ID).
+		Select("name, description, is_published, updated_at").
+		Updates(map[string]interface{}{
+			"name":         m.Name,
+			"description":  m.Description,
+			"is_published": m.IsPublished,
+			"updated_at":   m.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) DeleteShippingZone(db *gorm.DB, ID string) error {
+	m := models.ShippingZone{}
+	if err := db.Table(m.TableName()).
+		Where("id = ?", ID).
+		Delete(&m).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) ListShippingZones(db *gorm.DB, from, limit int) ([]models.ShippingZone, error) {
+	m := models.ShippingZone{}
+	var data []models.ShippingZone
+	if err := db.Table(m.TableName()).
+		Order("name ASC").
+		Offset(from).
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (au *MarketplaceRepositoryImpl) GetShippingZone(db *gorm.DB, ID string) (*models.ShippingZone, error) {
+	m := models.ShippingZone{}
+	if err := db.Table(m.TableName()).
+		Where("id = ?", ID).
+		First(&m).Error; err != nil {
+		return nil, err
+	}
+
+	l := models.Location{}
+	szl := models.ShippingZoneLocation{}
+	if err := db.Table(fmt.Sprintf("%s AS l", l.TableName())).
+		Select("l.*").
+		Joins(fmt.Sprintf("JOIN %s AS szl ON szl.location_id = l.id", szl.TableName())).
+		Where("szl.zone_id = ?", m.ID).
+		Order("l.location_type ASC, l.name ASC").
+		Find(&m.Locations).Error; err != nil {
+		return nil, err
+	}
+
+	szr := models.ShippingZoneRate{}
+	if err := db.Table(szr.TableName()).
+		Where("zone_id = ?", m.ID).
+		Find(&m.Rates).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (au *MarketplaceRepositoryImpl) SetShippingZoneLocations(db *gorm.DB, zoneID string, locationIDs []int64) error {
+	szl := models.ShippingZoneLocation{}
+	if err := db.Table(szl.TableName()).
+		Where("zone_id = ?", zoneID).
+		Delete(&szl).Error; err != nil {
+		return err
+	}
+
+	for _, id := range locationIDs {
+		v := models.ShippingZoneLocation{
+			ZoneID:     zoneID,
+			LocationID: id,
+		}
+		if err := db.Table(szl.TableName()).Create(&v).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) SetShippingZoneRate(db *gorm.DB, r *models.ShippingZoneRate) error {
+	if err := db.Table(r.TableName()).
+		Where("zone_id = ? AND shipping_method_id = ?", r.ZoneID, r.ShippingMethodID).
+		Delete(&models.ShippingZoneRate{}).Error; err != nil {
+		return err
+	}
+
+	if err := db.Table(r.TableName()).Create(r).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) DeleteShippingZoneRate(db *gorm.DB, zoneID, shippingMethodID string) error {
+	r := models.ShippingZoneRate{}
+	if err := db.Table(r.TableName()).
+		Where("zone_id = ? AND shipping_method_id = ?", zoneID, shippingMethodID).
+		Delete(&r).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetShippingZoneRate resolves the rate of the shipping method from the first location having a published zone,
+// locations must be ordered from the most specific to the least
+func (au *MarketplaceRepositoryImpl) GetShippingZoneRate(db *gorm.DB, shippingMethodID string, locationIDs []int64) (*models.ShippingZoneRate, error) {
+	sz := models.ShippingZone{}
+	szl := models.ShippingZoneLocation{}
+	szr := models.ShippingZoneRate{}
+
+	for _, locationID := range locationIDs {
+		var rates []models.ShippingZoneRate
+		if err := db.Table(fmt.Sprintf("%s AS szr", szr.TableName())).
+			Select("szr.*").
+			Joins(fmt.Sprintf("JOIN %s AS sz ON sz.id = szr.zone_id AND sz.is_published = ?", sz.TableName()), true).
+			Joins(fmt.Sprintf("JOIN %s AS szl ON szl.zone_id = szr.zone_id", szl.TableName())).
+			Where("szr.shipping_method_id = ? AND szl.location_id = ?", shippingMethodID, locationID).
+			Limit(1).
+			Find(&rates).Error; err != nil {
+			return nil, err
+		}
+		if len(rates) > 0 {
+			return &rates[0], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
@@ -0,0 +1,113 @@
+package data
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+// storeOfferedShippingMethodCondition matches the shipping methods of a store, or the platform shipping methods
+// when the store hasn't published any of its own
+const storeOfferedShippingMethodCondition = "(sm.store_id = ? OR (sm.store_id IS NULL AND NOT EXISTS (SELECT 1 FROM shipping_methods AS ssm WHERE ssm.store_id = ? AND ssm.is_published = ?)))"
+
+func (au *MarketplaceRepositoryImpl) ListShippingMethodsByStore(db *gorm.DB, storeID string, from, limit int) ([]models.ShippingMethod, error) {
+	var data []models.ShippingMethod
+	m := models.ShippingMethod{}
+	if err := db.Table(m.TableName()).
+		Where("store_id = ?", storeID).
+		Offset(from).Limit(limit).
+		Order("created_at DESC").Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (au *MarketplaceRepositoryImpl) GetShippingMethodByStore(db *gorm.DB, storeID, ID string) (*models.ShippingMethod, error) {
+	m := models.ShippingMethod{}
+	if err := db.Table(m.TableName()).
+		Where("id = ? AND store_id = ?", ID, storeID).
+		First(&m).Error; err != nil {
+		return nil, err
+	}
+
+	brackets, err := au.ListShippingWeightBrackets(db, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.WeightBrackets = brackets
+	return &m, nil
+}
+
+func (au *MarketplaceRepositoryImpl) DeleteShippingMethodByStore(db *gorm.DB, storeID, ID string) error {
+	m := models.ShippingMethod{}
+	if err := db.Table(m.TableName()).
+		Where("id = ? AND store_id = ?", ID, storeID).
+		Delete(&m).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) SetShippingMethodLocations(db *gorm.DB, shippingMethodID string, locationIDs []int64) error {
+	sol := models.ShippingForLocation{}
+	if err := db.Table(sol.TableName()).
+		Where("shipping_method_id = ?", shippingMethodID).
+		Delete(&sol).Error; err != nil {
+		return err
+	}
+
+	for _, id := range locationIDs {
+		v := models.ShippingForLocation{
+			LocationID:       id,
+			ShippingMethodID: shippingMethodID,
+		}
+		if err := db.Table(sol.TableName()).Create(&v).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (au *MarketplaceRepositoryImpl) ListShippingMethodLocations(db *gorm.DB, shippingMethodID string) ([]models.Location, error) {
+	var data []models.Location
+	l := models.Location{}
+	sol := models.ShippingForLocation{}
+	if err := db.Table(fmt.Sprintf("%s AS l", l.TableName())).
+		Select("l.*").
+		Joins(fmt.Sprintf("JOIN %s AS sol ON sol.location_id = l.id", sol.TableName())).
+		Where("sol.shipping_method_id = ?", shippingMethodID).
+		Order("l.name ASC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (au *MarketplaceRepositoryImpl) ListShippingMethodsByLocationForStore(db *gorm.DB, storeID string, locationID int64) ([]models.ShippingMethod, error) {
+	var data []models.ShippingMethod
+	m := models.ShippingMethod{}
+	sol := models.ShippingForLocation{}
+	loc := models.Location{}
+	if err := db.Table(fmt.Sprintf("%s AS sm", m.TableName())).
+		Select("sm.id AS id, sm.store_id AS store_id, sm.carrier_id AS carrier_id, sm.name AS name, sm.approximate_delivery_time AS approximate_delivery_time, sm.handling_time AS handling_time, sm.delivery_charge AS delivery_charge, sm.weight_unit AS weight_unit, sm.is_flat AS is_flat, sm.per_kg_rate AS per_kg_rate, sm.volumetric_divisor AS volumetric_divisor, sm.free_shipping_threshold AS free_shipping_threshold, sm.is_published AS is_published, sm.created_at AS created_at, sm.updated_at AS updated_at").
+		Joins(fmt.Sprintf("JOIN %s AS sol ON sm.id = sol.shipping_method_id AND sol.location_id = %d AND sm.is_published = %v", sol.TableName(), locationID, true)).
+		Joins(fmt.Sprintf("JOIN %s AS loc ON loc.id = sol.location_id AND loc.is_published = %d", loc.TableName(), 1)).
+		Where(storeOfferedShippingMethodCondition, storeID, storeID, true).
+		Order("sm.created_at DESC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (au *MarketplaceRepositoryImpl) IsShippingMethodOfferedByStore(db *gorm.DB, storeID, ID string) (bool, error) {
+	m := models.ShippingMethod{}
+	count := 0
+	if err := db.Table(fmt.Sprintf("%s AS sm", m.TableName())).
+		Where("sm.id = ? AND sm.is_published = ?", ID, true).
+		Where(storeOfferedShippingMethodCondition, storeID, storeID, true).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
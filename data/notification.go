@@ -0,0 +1,16 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type NotificationRepository interface {
+	ListPreferences(db *gorm.DB, userID string) ([]models.NotificationPreference, error)
+	GetPreference(db *gorm.DB, userID string, event models.NotificationEvent) (*models.NotificationPreference, error)
+	SavePreference(db *gorm.DB, np *models.NotificationPreference) error
+	SavePushDevice(db *gorm.DB, pd *models.PushDevice) error
+	ListPushDevices(db *gorm.DB, userID string) ([]models.PushDevice, error)
+	DeletePushDevice(db *gorm.DB, userID, ID string) error
+	DeletePushDeviceByToken(db *gorm.DB, token string) error
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type NotificationRepositoryImpl struct {
+}
+
+var notificationRepository NotificationRepository
+
+func NewNotificationRepository() NotificationRepository {
+	if notificationRepository == nil {
+		notificationRepository = &NotificationRepositoryImpl{}
+	}
+	return notificationRepository
+}
+
+func (nu *NotificationRepositoryImpl) ListPreferences(db *gorm.DB, userID string) ([]models.NotificationPreference, error) {
+	np := models.NotificationPreference{}
+	var nps []models.NotificationPreference
+	if err := db.Table(np.TableName()).
+		Where("user_id = ?", userID).
+		Order("event ASC").
+		Find(&nps).Error; err != nil {
+		return nil, err
+	}
+	return nps, nil
+}
+
+func (nu *NotificationRepositoryImpl) GetPreference(db *gorm.DB, userID string, event models.NotificationEvent) (*models.NotificationPreference, error) {
+	np := models.NotificationPreference{}
+	if err := db.Table(np.TableName()).
+		Where("user_id = ? AND event = ?", userID, event).
+		First(&np).Error; err != nil {
+		return nil, err
+	}
+	return &np, nil
+}
+
+func (nu *NotificationRepositoryImpl) SavePreference(db *gorm.DB, np *models.NotificationPreference) error {
+	if err := db.Table(np.TableName()).Save(np).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// SavePushDevice registers the token to the user, moving it over if another user had it registered
+func (nu *NotificationRepositoryImpl) SavePushDevice(db *gorm.DB, pd *models.PushDevice) error {
+	if err := db.Table(pd.TableName()).
+		Set("gorm:insert_option", "ON CONFLICT (token) DO UPDATE SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, updated_at = EXCLUDED.updated_at").
+		Create(pd).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (nu *NotificationRepositoryImpl) ListPushDevices(db *gorm.DB, userID string) ([]models.PushDevice, error) {
+	pd := models.PushDevice{}
+	var pds []models.PushDevice
+	if err := db.Table(pd.TableName()).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&pds).Error; err != nil {
+		return nil, err
+	}
+	return pds, nil
+}
+
+func (nu *NotificationRepositoryImpl) DeletePushDevice(db *gorm.DB, userID, ID string) error {
+	pd := models.PushDevice{}
+	q := db.Table(pd.TableName()).
+		Where("user_id = ? AND id = ?", userID, ID).
+		Delete(&pd)
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (nu *NotificationRepositoryImpl) DeletePushDeviceByToken(db *gorm.DB, token string) error {
+	pd := models.PushDevice{}
+	if err := db.Table(pd.TableName()).
+		Where("token = ?", token).
+		Delete(&pd).Error; err != nil {
+		return err
+	}
+	return nil
+}
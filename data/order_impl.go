@@ -419,7 +419,7 @@ func (os *OrderRepositoryImpl) StoreSummary(db *gorm.DB, storeID string) (*model
 	if err := db.Table(fmt.Sprintf("%s AS o", o.TableName())).
 		Select("COUNT(o.id) AS total_orders, SUM(oi.price * oi.quantity) AS earnings, SUM(oi.product_cost * oi.quantity) AS expenses,"+
 			"SUM(oi.price * oi.quantity) - SUM(oi.product_cost * oi.quantity) AS profits, SUM(o.discounted_amount) AS discounts,"+
-			"COUNT(DISTINCT (o.user_id)) AS customers").
+			"SUM(oi.tax_amount) AS taxes, COUNT(DISTINCT (o.user_id)) AS customers").
 		Joins(fmt.Sprintf("JOIN %s AS oi ON o.id = oi.order_id", oi.TableName())).
 		Where("o.store_id = ? AND o.status = ? AND o.payment_status = ?", storeID, models.OrderDelivered, models.PaymentCompleted).
 		Find(&sum).Error; err != nil {
@@ -437,7 +437,7 @@ func (os *OrderRepositoryImpl) StoreSummaryAny(db *gorm.DB, storeID string) (*mo
 	if err := db.Table(fmt.Sprintf("%s AS o", o.TableName())).
 		Select("COUNT(o.id) AS total_orders, SUM(oi.price * oi.quantity) AS earnings, SUM(oi.product_cost * oi.quantity) AS expenses,"+
 			"SUM(oi.price * oi.quantity) - SUM(oi.product_cost * oi.quantity) AS profits, SUM(o.discounted_amount) AS discounts,"+
-			"COUNT(DISTINCT (o.user_id)) AS customers").
+			"SUM(oi.tax_amount) AS taxes, COUNT(DISTINCT (o.user_id)) AS customers").
 		Joins(fmt.Sprintf("LEFT JOIN %s AS oi ON o.id = oi.order_id", oi.TableName())).
 		Where("o.store_id = ?", storeID).
 		Find(&sum).Error; err != nil {
@@ -455,7 +455,7 @@ func (os *OrderRepositoryImpl) StoreSummaryByTime(db *gorm.DB, storeID string, f
 	if err := db.Table(fmt.Sprintf("%s AS o", o.TableName())).
 		Select("COUNT(o.id) AS total_orders, SUM(oi.price * oi.quantity) AS earnings, SUM(oi.product_cost * oi.quantity) AS expenses,"+
 			"SUM(oi.price * oi.quantity) - SUM(oi.product_cost * oi.quantity) AS profits, SUM(o.discounted_amount) AS discounts,"+
-			"COUNT(DISTINCT (o.user_id)) AS customers").
+			"SUM(oi.tax_amount) AS taxes, COUNT(DISTINCT (o.user_id)) AS customers").
 		Joins(fmt.Sprintf("JOIN %s AS oi ON o.id = oi.order_id", oi.TableName())).
 		Where("o.store_id = ? AND o.created_at >= ? AND o.created_At <= ? AND o.status = ? AND o.payment_status = ?",
 			storeID, from, end, models.OrderDelivered, models.PaymentCompleted).
@@ -0,0 +1,26 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type PersonalDataRepository interface {
+	CreateExport(db *gorm.DB, de *models.DataExport) error
+	GetExport(db *gorm.DB, userID, ID string) (*models.DataExport, error)
+	GetExportByID(db *gorm.DB, ID string) (*models.DataExport, error)
+	ListExports(db *gorm.DB, userID string, from, limit int) ([]models.DataExport, error)
+	HasPendingExport(db *gorm.DB, userID string) (bool, error)
+	UpdateExport(db *gorm.DB, de *models.DataExport) error
+	ListReadyExports(db *gorm.DB, userID string) ([]models.DataExport, error)
+	ListExpiredExports(db *gorm.DB, now time.Time) ([]models.DataExport, error)
+	CreateDeletion(db *gorm.DB, ad *models.AccountDeletion) error
+	GetDeletionByID(db *gorm.DB, ID string) (*models.AccountDeletion, error)
+	GetPendingDeletion(db *gorm.DB, userID string) (*models.AccountDeletion, error)
+	GetDeletionByCancelTokenHash(db *gorm.DB, tokenHash string) (*models.AccountDeletion, error)
+	SetDeletionCancelTokenHash(db *gorm.DB, ID, tokenHash string) error
+	UpdateDeletionStatus(db *gorm.DB, ID string, status models.AccountDeletionStatus, at time.Time) error
+	ListDueDeletions(db *gorm.DB, now time.Time, limit int) ([]models.AccountDeletion, error)
+	PostponeDeletion(db *gorm.DB, ID string, until time.Time) error
+}
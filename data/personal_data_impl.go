@@ -0,0 +1,202 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type PersonalDataRepositoryImpl struct {
+}
+
+var personalDataRepository PersonalDataRepository
+
+func NewPersonalDataRepository() PersonalDataRepository {
+	if personalDataRepository == nil {
+		personalDataRepository = &PersonalDataRepositoryImpl{}
+	}
+	return personalDataRepository
+}
+
+func (pr *PersonalDataRepositoryImpl) CreateExport(db *gorm.DB, de *models.DataExport) error {
+	if err := db.Create(de).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *PersonalDataRepositoryImpl) GetExport(db *gorm.DB, userID, ID string) (*models.DataExport, error) {
+	de := models.DataExport{}
+	if err := db.Model(&de).Where("user_id = ? AND id = ?", userID, ID).First(&de).Error; err != nil {
+		return nil, err
+	}
+	return &de, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) GetExportByID(db *gorm.DB, ID string) (*models.DataExport, error) {
+	de := models.DataExport{}
+	if err := db.Model(&de).Where("id = ?", ID).First(&de).Error; err != nil {
+		return nil, err
+	}
+	return &de, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) ListExports(db *gorm.DB, userID string, from, limit int) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	if err := db.Model(&models.DataExport{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).Offset(from).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) HasPendingExport(db *gorm.DB, userID string) (bool, error) {
+	count := 0
+	if err := db.Model(&models.DataExport{}).
+		Where("user_id = ? AND status = ?", userID, models.DataExportPending).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) UpdateExport(db *gorm.DB, de *models.DataExport) error {
+	q := db.Table(de.TableName()).
+		Where("id = ?", de.ID).
+		Select("status, path, size, expires_at, completed_at").
+		Updates(map[string]interface{}{
+			"status":       de.Status,
+			"path":         de.Path,
+			"size":         de.Size,
+			"expires_at":   de.ExpiresAt,
+			"completed_at": de.CompletedAt,
+		})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (pr *PersonalDataRepositoryImpl) ListReadyExports(db *gorm.DB, userID string) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	if err := db.Model(&models.DataExport{}).
+		Where("user_id = ? AND status = ?", userID, models.DataExportReady).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) ListExpiredExports(db *gorm.DB, now time.Time) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	if err := db.Model(&models.DataExport{}).
+		Where("status = ? AND expires_at <= ?", models.DataExportReady, now).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) CreateDeletion(db *gorm.DB, ad *models.AccountDeletion) error {
+	if err := db.Create(ad).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *PersonalDataRepositoryImpl) GetDeletionByID(db *gorm.DB, ID string) (*models.AccountDeletion, error) {
+	ad := models.AccountDeletion{}
+	if err := db.Model(&ad).Where("id = ?", ID).First(&ad).Error; err != nil {
+		return nil, err
+	}
+	return &ad, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) GetPendingDeletion(db *gorm.DB, userID string) (*models.AccountDeletion, error) {
+	ad := models.AccountDeletion{}
+	if err := db.Model(&ad).
+		Where("user_id = ? AND status = ?", userID, models.AccountDeletionPending).
+		First(&ad).Error; err != nil {
+		return nil, err
+	}
+	return &ad, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) GetDeletionByCancelTokenHash(db *gorm.DB, tokenHash string) (*models.AccountDeletion, error) {
+	ad := models.AccountDeletion{}
+	if err := db.Model(&ad).Where("cancel_token_hash = ?", tokenHash).First(&ad).Error; err != nil {
+		return nil, err
+	}
+	return &ad, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) SetDeletionCancelTokenHash(db *gorm.DB, ID, tokenHash string) error {
+	ad := models.AccountDeletion{}
+	q := db.Table(ad.TableName()).
+		Where("id = ? AND status = ?", ID, models.AccountDeletionPending).
+		Select("cancel_token_hash").
+		Updates(map[string]interface{}{
+			"cancel_token_hash": tokenHash,
+		})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateDeletionStatus cancels or completes a pending deletion, a deletion that isn't pending anymore is not found
+func (pr *PersonalDataRepositoryImpl) UpdateDeletionStatus(db *gorm.DB, ID string, status models.AccountDeletionStatus, at time.Time) error {
+	ad := models.AccountDeletion{}
+
+	values := map[string]interface{}{
+		"status": status,
+	}
+	if status == models.AccountDeletionCancelled {
+		values["cancelled_at"] = at
+	} else {
+		values["completed_at"] = at
+	}
+
+	q := db.Table(ad.TableName()).
+		Where("id = ? AND status = ?", ID, models.AccountDeletionPending).
+		Updates(values)
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (pr *PersonalDataRepositoryImpl) ListDueDeletions(db *gorm.DB, now time.Time, limit int) ([]models.AccountDeletion, error) {
+	var deletions []models.AccountDeletion
+	if err := db.Model(&models.AccountDeletion{}).
+		Where("status = ? AND scheduled_at <= ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)",
+			models.AccountDeletionPending, now, now).
+		Order("COALESCE(next_attempt_at, scheduled_at) ASC").
+		Limit(limit).
+		Find(&deletions).Error; err != nil {
+		return nil, err
+	}
+	return deletions, nil
+}
+
+func (pr *PersonalDataRepositoryImpl) PostponeDeletion(db *gorm.DB, ID string, until time.Time) error {
+	ad := models.AccountDeletion{}
+	if err := db.Table(ad.TableName()).
+		Where("id = ? AND status = ?", ID, models.AccountDeletionPending).
+		Update("next_attempt_at", until).Error; err != nil {
+		return err
+	}
+	return nil
+}
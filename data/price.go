@@ -0,0 +1,17 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type PriceRepository interface {
+	CreateScheduledPrice(db *gorm.DB, sp *models.ScheduledPrice) error
+	ListScheduledPrices(db *gorm.DB, storeID, productID string, from, limit int) ([]models.ScheduledPrice, error)
+	DeleteScheduledPrice(db *gorm.DB, storeID, productID, scheduledPriceID string) error
+	ListDueScheduledPrices(db *gorm.DB, at time.Time, limit int) ([]models.ScheduledPrice, error)
+	MarkScheduledPriceApplied(db *gorm.DB, scheduledPriceID string, at time.Time) error
+	CreateHistory(db *gorm.DB, ph *models.PriceHistory) error
+	ListHistory(db *gorm.DB, productID string, from, limit int) ([]models.PriceHistory, error)
+}
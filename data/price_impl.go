@@ -0,0 +1,98 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type PriceRepositoryImpl struct {
+}
+
+var priceRepository PriceRepository
+
+func NewPriceRepository() PriceRepository {
+	if priceRepository == nil {
+		priceRepository = &PriceRepositoryImpl{}
+	}
+
+	return priceRepository
+}
+
+func (pr *PriceRepositoryImpl) CreateScheduledPrice(db *gorm.DB, sp *models.ScheduledPrice) error {
+	if err := db.Table(sp.TableName()).Create(sp).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *PriceRepositoryImpl) ListScheduledPrices(db *gorm.DB, storeID, productID string, from, limit int) ([]models.ScheduledPrice, error) {
+	var sps []models.ScheduledPrice
+	sp := models.ScheduledPrice{}
+	if err := db.Table(sp.TableName()).
+		Where("store_id = ? AND product_id = ?", storeID, productID).
+		Offset(from).Limit(limit).
+		Order("effective_at DESC").Find(&sps).Error; err != nil {
+		return nil, err
+	}
+	return sps, nil
+}
+
+func (pr *PriceRepositoryImpl) DeleteScheduledPrice(db *gorm.DB, storeID, productID, scheduledPriceID string) error {
+	sp := models.ScheduledPrice{}
+	q := db.Table(sp.TableName()).
+		Where("id = ? AND store_id = ? AND product_id = ? AND is_applied = ?", scheduledPriceID, storeID, productID, false).
+		Delete(&sp)
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (pr *PriceRepositoryImpl) ListDueScheduledPrices(db *gorm.DB, at time.Time, limit int) ([]models.ScheduledPrice, error) {
+	var sps []models.ScheduledPrice
+	sp := models.ScheduledPrice{}
+	if err := db.Table(sp.TableName()).
+		Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+		Where("is_applied = ? AND effective_at <= ?", false, at).
+		Limit(limit).
+		Order("effective_at ASC").Find(&sps).Error; err != nil {
+		return nil, err
+	}
+	return sps, nil
+}
+
+func (pr *PriceRepositoryImpl) MarkScheduledPriceApplied(db *gorm.DB, scheduledPriceID string, at time.Time) error {
+	sp := models.ScheduledPrice{}
+	if err := db.Table(sp.TableName()).
+		Where("id = ?", scheduledPriceID).
+		Updates(map[string]interface{}{
+			"is_applied": true,
+			"applied_at": at,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *PriceRepositoryImpl) CreateHistory(db *gorm.DB, ph *models.PriceHistory) error {
+	if err := db.Table(ph.TableName()).Create(ph).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *PriceRepositoryImpl) ListHistory(db *gorm.DB, productID string, from, limit int) ([]models.PriceHistory, error) {
+	var phs []models.PriceHistory
+	ph := models.PriceHistory{}
+	if err := db.Table(ph.TableName()).
+		Where("product_id = ?", productID).
+		Offset(from).Limit(limit).
+		Order("created_at DESC").Find(&phs).Error; err != nil {
+		return nil, err
+	}
+	return phs, nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/shopicano/shopicano-backend/helpers"
 	"github.com/shopicano/shopicano-backend/models"
+	"time"
 )
 
 type ProductRepository interface {
@@ -15,8 +16,10 @@ type ProductRepository interface {
 	SearchAsStoreStuff(db *gorm.DB, storeID, query string, from, limit int) ([]models.ProductDetailsInternal, error)
 	ListByCollection(db *gorm.DB, collectionID string, from, limit int) ([]models.ProductDetails, error)
 	ListByCollectionAsStoreStuff(db *gorm.DB, collectionID string, from, limit int) ([]models.ProductDetails, error)
+	ListByGlobalCategory(db *gorm.DB, globalCategoryID string, from, limit int) ([]models.ProductDetails, error)
 	Delete(db *gorm.DB, storeID, productID string) error
 	Get(db *gorm.DB, productID string) (*models.Product, error)
+	UpdatePrice(db *gorm.DB, productID string, price int64, at time.Time) error
 	IncreaseDownloadCounter(db *gorm.DB, pID, sID string) error
 	IncreaseViewCounter(db *gorm.DB, pID, sID string) error
 	GetAsStoreStuff(db *gorm.DB, storeID, productID string) (*models.Product, error)
@@ -25,6 +28,11 @@ type ProductRepository interface {
 	GetForOrder(db *gorm.DB, productID string, quantity int) (*models.Product, error)
 	Stats(db *gorm.DB, offset, limit int) ([]helpers.ProductStats, error)
 	StatsAsStoreStaff(db *gorm.DB, storeID string, offset, limit int) ([]helpers.ProductStats, error)
+	RefreshSearchText(db *gorm.DB, productIDs []string) error
+	RefreshSoldCount(db *gorm.DB, productIDs []string) error
+	ListIDsByOrder(db *gorm.DB, orderID string) ([]string, error)
+	ListIDsByCategory(db *gorm.DB, categoryID string) ([]string, error)
+	ListIDsByCollection(db *gorm.DB, collectionID string) ([]string, error)
 	AddAttribute(db *gorm.DB, v *models.ProductAttribute) error
 	RemoveAttribute(db *gorm.DB, productID, attributeID string) error
 	ListAttributes(db *gorm.DB, productID string) (map[string][]models.ProductKV, error)
@@ -32,4 +40,7 @@ type ProductRepository interface {
 	AddImage(db *gorm.DB, productID, imagePath string) error
 	GetImages(db *gorm.DB, productID string) ([]string, error)
 	RemoveImage(db *gorm.DB, productID string) error
+	SetBundleItems(db *gorm.DB, bundleID string, items []models.ProductBundleItem) error
+	ListBundleItems(db *gorm.DB, bundleID string) ([]models.ProductBundleItemDetails, error)
+	IsBundleItem(db *gorm.DB, productID string) (bool, error)
 }
@@ -6,11 +6,21 @@ import (
 	"github.com/shopicano/shopicano-backend/helpers"
 	"github.com/shopicano/shopicano-backend/models"
 	"strings"
+	"time"
 )
 
 type ProductRepositoryImpl struct {
 }
 
+// activeSaleCondition matches products having a sale running right now
+const activeSaleCondition = "products.sale_price > 0 AND products.sale_price < products.price" +
+	" AND (products.sale_starts_at IS NULL OR products.sale_starts_at <= NOW())" +
+	" AND (products.sale_ends_at IS NULL OR products.sale_ends_at > NOW())"
+
+// activeSaleColumns exposes the sale price and its end only while the sale is running
+const activeSaleColumns = "CASE WHEN " + activeSaleCondition + " THEN products.sale_price ELSE 0 END AS sale_price," +
+	" CASE WHEN " + activeSaleCondition + " THEN products.sale_ends_at END AS sale_ends_at"
+
 var productRepository ProductRepository
 
 func NewProductRepository() ProductRepository {
@@ -30,21 +40,31 @@ func (pu *ProductRepositoryImpl) Create(db *gorm.DB, p *models.Product) error {
 
 func (pu *ProductRepositoryImpl) Update(db *gorm.DB, p *models.Product) error {
 	if err := db.Table(p.TableName()).
-		Select("name, description, is_published, category_id, sku, slug, stock, unit, price, product_cost, max_quantity_count, image, is_shippable, is_digital, digital_download_link, updated_at").
+		Select("name, description, is_published, category_id, global_category_id, tax_class_id, sku, slug, stock, unit, price, sale_price, sale_starts_at, sale_ends_at, product_cost, max_quantity_count, image, is_shippable, is_digital, is_bundle, weight, length, width, height, digital_download_link, updated_at").
 		Where("id = ? AND store_id = ?", p.ID, p.StoreID).
 		Updates(map[string]interface{}{
 			"name":                  p.Name,
 			"description":           p.Description,
 			"is_published":          p.IsPublished,
 			"category_id":           p.CategoryID,
+			"global_category_id":    p.GlobalCategoryID,
+			"tax_class_id":          p.TaxClassID,
 			"sku":                   p.SKU,
 			"slug":                  p.Slug,
 			"stock":                 p.Stock,
 			"unit":                  p.Unit,
 			"price":                 p.Price,
+			"sale_price":            p.SalePrice,
+			"sale_starts_at":        p.SaleStartsAt,
+			"sale_ends_at":          p.SaleEndsAt,
 			"image":                 p.Image,
 			"is_shippable":          p.IsShippable,
 			"is_digital":            p.IsDigital,
+			"is_bundle":             p.IsBundle,
+			"weight":                p.Weight,
+			"length":                p.Length,
+			"width":                 p.Width,
+			"height":                p.Height,
 			"digital_download_link": p.DigitalDownloadLink,
 			"product_cost":          p.ProductCost,
 			"max_quantity_count":    p.MaxQuantityCount,
@@ -55,6 +75,19 @@ func (pu *ProductRepositoryImpl) Update(db *gorm.DB, p *models.Product) error {
 	return nil
 }
 
+func (pu *ProductRepositoryImpl) UpdatePrice(db *gorm.DB, productID string, price int64, at time.Time) error {
+	p := models.Product{}
+	if err := db.Table(p.TableName()).
+		Where("id = ?", productID).
+		Updates(map[string]interface{}{
+			"price":      price,
+			"updated_at": at,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 func (pu *ProductRepositoryImpl) IncreaseDownloadCounter(db *gorm.DB, pID, sID string) error {
 	p := models.Product{}
 	if err := db.Table(p.TableName()).
@@ -79,7 +112,7 @@ func (pu *ProductRepositoryImpl) List(db *gorm.DB, from, limit int) ([]models.Pr
 	var ps []models.ProductDetails
 	p := models.Product{}
 	if err := db.Table(p.TableName()).
-		Select("products.id, products.stock, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, c.id AS category_id, c.name AS category_name, products.image, products.created_at, products.updated_at").
+		Select("products.id, products.stock, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.image, "+activeSaleColumns+", products.rating_average, products.rating_count, products.created_at, products.updated_at").
 		Joins("LEFT JOIN categories AS c ON products.category_id = c.id").
 		Joins("LEFT JOIN stores AS s ON products.store_id = s.id").
 		Where("products.is_published = ?", true).
@@ -94,7 +127,7 @@ func (pu *ProductRepositoryImpl) ListAsStoreStuff(db *gorm.DB, storeID string, f
 	var ps []models.ProductDetailsInternal
 	p := models.Product{}
 	if err := db.Table(p.TableName()).
-		Select("products.id, products.stock, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, c.id AS category_id, c.name AS category_name, products.image, products.created_at, products.updated_at").
+		Select("products.id, products.stock, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.image, products.sale_price, products.sale_starts_at, products.sale_ends_at, products.rating_average, products.rating_count, products.created_at, products.updated_at").
 		Joins("LEFT JOIN categories AS c ON products.category_id = c.id").
 		Joins("LEFT JOIN stores AS s ON products.store_id = s.id").
 		Where("products.store_id = ?", storeID).
@@ -109,13 +142,10 @@ func (pu *ProductRepositoryImpl) Search(db *gorm.DB, query string, from, limit i
 	var ps []models.ProductDetails
 	p := models.Product{}
 	if err := db.Table(p.TableName()).
-		Select("products.id, products.name, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.stock, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, c.id AS category_id, c.name AS category_name, products.image, products.created_at, products.updated_at").
+		Select("products.id, products.name, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.stock, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.image, "+activeSaleColumns+", products.rating_average, products.rating_count, products.created_at, products.updated_at").
 		Joins("LEFT JOIN categories AS c ON products.category_id = c.id").
-		Joins("LEFT JOIN collection_of_products AS cop ON products.id = cop.product_id").
-		Joins("LEFT JOIN collections AS col ON cop.collection_id = col.id").
 		Joins("LEFT JOIN stores AS s ON products.store_id = s.id").
-		Where("products.is_published = ? AND (LOWER(products.name) LIKE ? OR LOWER(c.name) LIKE ? OR LOWER(col.name) LIKE ?)", true, "%"+strings.ToLower(query)+"%", "%"+strings.ToLower(query)+"%", "%"+strings.ToLower(query)+"%").
-		Group("products.id, products.name, products.sku, products.unit, products.store_id, s.name, products.stock, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, c.id, c.name, products.image, products.created_at, products.updated_at").
+		Where("products.is_published = ? AND products.search_text LIKE ?", true, "%"+strings.ToLower(query)+"%").
 		Offset(from).Limit(limit).
 		Order("created_at DESC").Find(&ps).Error; err != nil {
 		return nil, err
@@ -127,10 +157,10 @@ func (pu *ProductRepositoryImpl) SearchAsStoreStuff(db *gorm.DB, storeID, query
 	var ps []models.ProductDetailsInternal
 	p := models.Product{}
 	if err := db.Table(p.TableName()).
-		Select("products.id, products.name, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.stock, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, c.id AS category_id, c.name AS category_name, products.image, products.created_at, products.updated_at").
+		Select("products.id, products.name, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.stock, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.image, products.sale_price, products.sale_starts_at, products.sale_ends_at, products.rating_average, products.rating_count, products.created_at, products.updated_at").
 		Joins("LEFT JOIN categories AS c ON products.category_id = c.id").
 		Joins("LEFT JOIN stores AS s ON products.store_id = s.id").
-		Where("products.store_id = ? AND (LOWER(products.name) LIKE ? OR LOWER(c.name) LIKE ?)", storeID, "%"+strings.ToLower(query)+"%", "%"+strings.ToLower(query)+"%").
+		Where("products.store_id = ? AND products.search_text LIKE ?", storeID, "%"+strings.ToLower(query)+"%").
 		Offset(from).Limit(limit).
 		Order("created_at DESC").Find(&ps).Error; err != nil {
 		return nil, err
@@ -138,6 +168,71 @@ func (pu *ProductRepositoryImpl) SearchAsStoreStuff(db *gorm.DB, storeID, query
 	return ps, nil
 }
 
+// searchTextExpr builds the lower cased text a product is searched by, out of its name and the names of its category and collections
+const searchTextExpr = "LOWER(products.name || ' ' ||" +
+	" COALESCE((SELECT c.name FROM categories AS c WHERE c.id = products.category_id), '') || ' ' ||" +
+	" COALESCE((SELECT STRING_AGG(col.name, ' ') FROM collection_of_products AS cop" +
+	" JOIN collections AS col ON cop.collection_id = col.id WHERE cop.product_id = products.id), ''))"
+
+// RefreshSearchText rebuilds the search text of the given products, of every product when productIDs is nil
+func (pu *ProductRepositoryImpl) RefreshSearchText(db *gorm.DB, productIDs []string) error {
+	p := models.Product{}
+	q := db.Table(p.TableName())
+	if productIDs != nil {
+		q = q.Where("id IN (?)", productIDs)
+	}
+	if err := q.UpdateColumn("search_text", gorm.Expr(searchTextExpr)).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// RefreshSoldCount recounts the ordered quantity of the given products, of every product when productIDs is nil
+func (pu *ProductRepositoryImpl) RefreshSoldCount(db *gorm.DB, productIDs []string) error {
+	p := models.Product{}
+	q := db.Table(p.TableName())
+	if productIDs != nil {
+		q = q.Where("id IN (?)", productIDs)
+	}
+	if err := q.UpdateColumn("sold_count", gorm.Expr("(SELECT COALESCE(SUM(oi.quantity), 0) FROM ordered_items AS oi WHERE oi.product_id = products.id)")).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pu *ProductRepositoryImpl) ListIDsByOrder(db *gorm.DB, orderID string) ([]string, error) {
+	var ids []string
+	oi := models.OrderedItem{}
+	if err := db.Table(oi.TableName()).
+		Where("order_id = ?", orderID).
+		Pluck("DISTINCT product_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (pu *ProductRepositoryImpl) ListIDsByCategory(db *gorm.DB, categoryID string) ([]string, error) {
+	var ids []string
+	p := models.Product{}
+	if err := db.Table(p.TableName()).
+		Where("category_id = ?", categoryID).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (pu *ProductRepositoryImpl) ListIDsByCollection(db *gorm.DB, collectionID string) ([]string, error) {
+	var ids []string
+	cop := models.CollectionOfProduct{}
+	if err := db.Table(cop.TableName()).
+		Where("collection_id = ?", collectionID).
+		Pluck("product_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 func (pu *ProductRepositoryImpl) Delete(db *gorm.DB, storeID, productID string) error {
 	p := models.Product{}
 	if err := db.Table(p.TableName()).
@@ -175,7 +270,7 @@ func (pu *ProductRepositoryImpl) GetDetails(db *gorm.DB, productID string) (*mod
 	store := models.Store{}
 
 	if err := db.Table(fmt.Sprintf("%s", p.TableName())).
-		Select("products.id, s.id AS store_id, s.name AS store_name, products.max_quantity_count AS max_quantity_count, products.digital_download_link, products.price, products.product_cost, products.unit, products.stock, products.sku, products.name, products.slug, products.description, products.is_published, products.is_shippable, products.is_digital, c.id AS category_id, c.name AS category_name, products.image, products.created_at, products.updated_at").
+		Select("products.id, s.id AS store_id, s.name AS store_name, products.max_quantity_count AS max_quantity_count, products.digital_download_link, products.price, products.product_cost, products.unit, products.stock, products.sku, products.name, products.slug, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.tax_class_id, products.image, "+activeSaleColumns+", products.rating_average, products.rating_count, products.created_at, products.updated_at").
 		Joins(fmt.Sprintf("LEFT JOIN %s AS c ON products.category_id = c.id", cat.TableName())).
 		Joins(fmt.Sprintf("LEFT JOIN %s AS s ON products.store_id = s.id", store.TableName())).
 		Where("(products.id = ? OR products.slug = ?) AND products.is_published = ?", productID, productID, true).
@@ -207,6 +302,14 @@ func (pu *ProductRepositoryImpl) GetDetails(db *gorm.DB, productID string) (*mod
 	}
 	ps.AdditionalImages = additionalImages
 
+	if ps.IsBundle {
+		bundleItems, err := pu.ListBundleItems(db, ps.ID)
+		if err != nil {
+			return nil, err
+		}
+		ps.BundleItems = bundleItems
+	}
+
 	return &ps, nil
 }
 
@@ -217,7 +320,7 @@ func (pu *ProductRepositoryImpl) GetDetailsAsStoreStuff(db *gorm.DB, storeID, pr
 	store := models.Store{}
 
 	if err := db.Table(fmt.Sprintf("%s", p.TableName())).
-		Select("products.id, s.id AS store_id, s.name AS store_name, products.max_quantity_count AS max_quantity_count, products.digital_download_link, products.price, products.product_cost, products.unit, products.stock, products.sku, products.name, products.slug, products.description, products.is_published, products.is_shippable, products.is_digital, c.id AS category_id, c.name AS category_name, products.image, products.created_at, products.updated_at").
+		Select("products.id, s.id AS store_id, s.name AS store_name, products.max_quantity_count AS max_quantity_count, products.digital_download_link, products.price, products.product_cost, products.unit, products.stock, products.sku, products.name, products.slug, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.tax_class_id, products.image, products.sale_price, products.sale_starts_at, products.sale_ends_at, products.rating_average, products.rating_count, products.created_at, products.updated_at").
 		Joins(fmt.Sprintf("LEFT JOIN %s AS c ON products.category_id = c.id", cat.TableName())).
 		Joins(fmt.Sprintf("LEFT JOIN %s AS s ON products.store_id = s.id", store.TableName())).
 		Where("(products.id = ? OR products.slug = ?) AND products.store_id = ?", productID, productID, storeID).
@@ -250,18 +353,53 @@ func (pu *ProductRepositoryImpl) GetDetailsAsStoreStuff(db *gorm.DB, storeID, pr
 	}
 	ps.AdditionalImages = additionalImages
 
+	if ps.IsBundle {
+		bundleItems, err := pu.ListBundleItems(db, ps.ID)
+		if err != nil {
+			return nil, err
+		}
+		ps.BundleItems = bundleItems
+	}
+
 	return &ps, nil
 }
 
 func (pu *ProductRepositoryImpl) GetForOrder(db *gorm.DB, productID string, quantity int) (*models.Product, error) {
+	return pu.getForOrder(db, productID, quantity, false)
+}
+
+// getForOrder reserves the stock of the product, or of the components of a bundle. Bundles can't be
+// components, a nested one left by bad data makes the product unavailable instead of recursing on.
+func (pu *ProductRepositoryImpl) getForOrder(db *gorm.DB, productID string, quantity int, isComponent bool) (*models.Product, error) {
 	p := models.Product{}
 
 	if err := db.Table(p.TableName()).
-		Where("id = ? AND (stock - ? >= 0 OR is_digital)", productID, quantity).
+		Where("id = ? AND (stock - ? >= 0 OR is_digital OR is_bundle)", productID, quantity).
 		Find(&p).Error; err != nil {
 		return nil, err
 	}
 
+	if p.IsBundle {
+		if isComponent {
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		bundleItems, err := pu.ListBundleItems(db, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(bundleItems) == 0 {
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		for _, bi := range bundleItems {
+			if _, err := pu.getForOrder(db, bi.ProductID, bi.Quantity*quantity, true); err != nil {
+				return nil, err
+			}
+		}
+		return &p, nil
+	}
+
 	if p.IsDigital {
 		return &p, nil
 	}
@@ -279,13 +417,10 @@ func (pu *ProductRepositoryImpl) Stats(db *gorm.DB, from, limit int) ([]helpers.
 	var stats []helpers.ProductStats
 
 	p := models.Product{}
-	oi := models.OrderedItem{}
 
 	if err := db.Table(fmt.Sprintf("%s AS p", p.TableName())).
-		Select("p.id AS id, p.name AS name, p.stock AS stock, p.price AS price, p.image AS image, p.description AS description, COALESCE(SUM(oi.quantity), 0) AS count_skip").
-		Joins(fmt.Sprintf("LEFT JOIN %s AS oi ON p.id = oi.product_id", oi.TableName())).
-		Group("p.id, p.name, p.stock, p.price, p.image, p.description").
-		Order("count_skip DESC").
+		Select("p.id AS id, p.name AS name, p.stock AS stock, p.price AS price, p.image AS image, p.description AS description, p.sold_count AS count_skip").
+		Order("p.sold_count DESC").
 		Offset(from).
 		Limit(limit).
 		Find(&stats).Error; err != nil {
@@ -303,13 +438,10 @@ func (pu *ProductRepositoryImpl) StatsAsStoreStaff(db *gorm.DB, storeID string,
 	var stats []helpers.ProductStats
 
 	p := models.Product{}
-	oi := models.OrderedItem{}
 
 	if err := db.Table(fmt.Sprintf("%s AS p", p.TableName())).
-		Select("p.id AS id, p.name AS name, p.stock AS stock, p.price AS price, p.image AS image, p.description AS description, COALESCE(SUM(oi.quantity), 0) AS count").
-		Joins(fmt.Sprintf("LEFT JOIN %s AS oi ON p.id = oi.product_id", oi.TableName())).
-		Group("p.id, p.name, p.stock, p.price, p.image, p.description").
-		Order("count DESC").
+		Select("p.id AS id, p.name AS name, p.stock AS stock, p.price AS price, p.image AS image, p.description AS description, p.sold_count AS count").
+		Order("p.sold_count DESC").
 		Offset(from).
 		Limit(limit).
 		Find(&stats, "p.store_id = ?", storeID).Error; err != nil {
@@ -352,16 +484,24 @@ func (pu *ProductRepositoryImpl) ListAttributes(db *gorm.DB, productID string) (
 	for _, a := range attributes {
 		if _, ok := sortedAttributes[a.Key]; ok {
 			sortedAttributes[a.Key] = append(sortedAttributes[a.Key], models.ProductKV{
-				ID:    a.ID,
-				Value: a.Value,
-				Image: a.Image,
+				ID:     a.ID,
+				Value:  a.Value,
+				Image:  a.Image,
+				Weight: a.Weight,
+				Length: a.Length,
+				Width:  a.Width,
+				Height: a.Height,
 			})
 		} else {
 			sortedAttributes[a.Key] = []models.ProductKV{
 				{
-					ID:    a.ID,
-					Value: a.Value,
-					Image: a.Image,
+					ID:     a.ID,
+					Value:  a.Value,
+					Image:  a.Image,
+					Weight: a.Weight,
+					Length: a.Length,
+					Width:  a.Width,
+					Height: a.Height,
 				},
 			}
 		}
@@ -384,7 +524,7 @@ func (pu *ProductRepositoryImpl) ListByCollection(db *gorm.DB, collectionID stri
 	var ps []models.ProductDetails
 	p := models.Product{}
 	if err := db.Table(p.TableName()).
-		Select("products.id, products.stock, products.sku, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, c.id AS category_id, c.name AS category_name, products.image, products.created_at, products.updated_at").
+		Select("products.id, products.stock, products.sku, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.image, "+activeSaleColumns+", products.rating_average, products.rating_count, products.created_at, products.updated_at").
 		Joins("LEFT JOIN categories AS c ON products.category_id = c.id").
 		Joins("LEFT JOIN stores AS s ON products.store_id = s.id").
 		Joins("LEFT JOIN collection_of_products AS cop ON cop.product_id = products.id").
@@ -400,7 +540,7 @@ func (pu *ProductRepositoryImpl) ListByCollectionAsStoreStuff(db *gorm.DB, colle
 	var ps []models.ProductDetails
 	p := models.Product{}
 	if err := db.Table(p.TableName()).
-		Select("products.id, products.stock, products.sku, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, c.id AS category_id, c.name AS category_name, products.image, products.created_at, products.updated_at").
+		Select("products.id, products.stock, products.sku, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.image, "+activeSaleColumns+", products.rating_average, products.rating_count, products.created_at, products.updated_at").
 		Joins("LEFT JOIN categories AS c ON products.category_id = c.id").
 		Joins("LEFT JOIN stores AS s ON products.store_id = s.id").
 		Joins("LEFT JOIN collection_of_products AS cop ON cop.product_id = products.id").
@@ -412,6 +552,28 @@ func (pu *ProductRepositoryImpl) ListByCollectionAsStoreStuff(db *gorm.DB, colle
 	return ps, nil
 }
 
+// ListByGlobalCategory lists published products of every store mapped onto the global category
+// or any of its descendants. A product level mapping takes precedence over its store category's one.
+func (pu *ProductRepositoryImpl) ListByGlobalCategory(db *gorm.DB, globalCategoryID string, from, limit int) ([]models.ProductDetails, error) {
+	var ps []models.ProductDetails
+	p := models.Product{}
+	gc := models.GlobalCategory{}
+
+	subTree := fmt.Sprintf("WITH RECURSIVE t AS (SELECT id FROM %s WHERE id = ?"+
+		" UNION SELECT g.id FROM %s AS g JOIN t ON g.parent_id = t.id) SELECT id FROM t", gc.TableName(), gc.TableName())
+
+	if err := db.Table(p.TableName()).
+		Select("products.id, products.stock, products.sku, products.slug, products.unit, products.store_id, s.name AS store_name, products.name, products.price, products.description, products.is_published, products.is_shippable, products.is_digital, products.is_bundle, products.weight, products.length, products.width, products.height, c.id AS category_id, c.name AS category_name, products.image, "+activeSaleColumns+", products.rating_average, products.rating_count, products.created_at, products.updated_at").
+		Joins("LEFT JOIN categories AS c ON products.category_id = c.id").
+		Joins("LEFT JOIN stores AS s ON products.store_id = s.id").
+		Where(fmt.Sprintf("products.is_published = ? AND COALESCE(products.global_category_id, c.global_category_id) IN (%s)", subTree), true, globalCategoryID).
+		Offset(from).Limit(limit).
+		Order("products.created_at DESC").Find(&ps).Error; err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
 func (pu *ProductRepositoryImpl) AddImage(db *gorm.DB, productID, imagePath string) error {
 	pi := models.ProductImage{
 		ProductID: productID,
@@ -445,3 +607,46 @@ func (pu *ProductRepositoryImpl) RemoveImage(db *gorm.DB, productID string) erro
 	}
 	return nil
 }
+
+func (pu *ProductRepositoryImpl) SetBundleItems(db *gorm.DB, bundleID string, items []models.ProductBundleItem) error {
+	pbi := models.ProductBundleItem{}
+	if err := db.Table(pbi.TableName()).Where("bundle_id = ?", bundleID).Delete(&pbi).Error; err != nil {
+		return err
+	}
+
+	for _, v := range items {
+		v.BundleID = bundleID
+		if err := db.Table(pbi.TableName()).Create(&v).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pu *ProductRepositoryImpl) ListBundleItems(db *gorm.DB, bundleID string) ([]models.ProductBundleItemDetails, error) {
+	p := models.Product{}
+	pbi := models.ProductBundleItem{}
+
+	var items []models.ProductBundleItemDetails
+	if err := db.Table(fmt.Sprintf("%s AS pbi", pbi.TableName())).
+		Select("pbi.product_id, p.name, p.sku, p.image, p.is_digital, pbi.quantity").
+		Joins(fmt.Sprintf("JOIN %s AS p ON pbi.product_id = p.id", p.TableName())).
+		Where("pbi.bundle_id = ?", bundleID).
+		Order("p.name ASC").
+		Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (pu *ProductRepositoryImpl) IsBundleItem(db *gorm.DB, productID string) (bool, error) {
+	pbi := models.ProductBundleItem{}
+
+	count := 0
+	if err := db.Table(pbi.TableName()).
+		Where("product_id = ?", productID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,24 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type ReviewRepository interface {
+	Create(db *gorm.DB, r *models.ProductReview) error
+	Update(db *gorm.DB, r *models.ProductReview) error
+	Get(db *gorm.DB, reviewID string) (*models.ProductReview, error)
+	GetAsStoreStuff(db *gorm.DB, storeID, productID, reviewID string) (*models.ProductReview, error)
+	GetDetails(db *gorm.DB, reviewID string) (*models.ProductReviewDetails, error)
+	List(db *gorm.DB, productID string, from, limit int) ([]models.ProductReviewDetails, error)
+	ListAsStoreStuff(db *gorm.DB, storeID, productID string, from, limit int) ([]models.ProductReviewDetails, error)
+	ListAsAdmin(db *gorm.DB, flaggedOnly bool, from, limit int) ([]models.ProductReviewDetails, error)
+	AddImage(db *gorm.DB, reviewID, imagePath string) error
+	GetImages(db *gorm.DB, reviewID string) ([]string, error)
+	GetPurchasedOrderID(db *gorm.DB, userID, productID string) (string, error)
+	UpdateProductRating(db *gorm.DB, productID string) error
+	ListByUser(db *gorm.DB, userID string) ([]models.ProductReview, error)
+	AnonymizeByUser(db *gorm.DB, userID string, at time.Time) error
+}
@@ -0,0 +1,239 @@
+package data
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type ReviewRepositoryImpl struct {
+}
+
+var reviewRepository ReviewRepository
+
+func NewReviewRepository() ReviewRepository {
+	if reviewRepository == nil {
+		reviewRepository = &ReviewRepositoryImpl{}
+	}
+
+	return reviewRepository
+}
+
+func (ru *ReviewRepositoryImpl) Create(db *gorm.DB, r *models.ProductReview) error {
+	if err := db.Table(r.TableName()).Create(r).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ru *ReviewRepositoryImpl) Update(db *gorm.DB, r *models.ProductReview) error {
+	if err := db.Table(r.TableName()).
+		Where("id = ?", r.ID).
+		Select("reply, replied_at, is_hidden, is_flagged, flag_reason, updated_at").
+		Updates(map[string]interface{}{
+			"reply":       r.Reply,
+			"replied_at":  r.RepliedAt,
+			"is_hidden":   r.IsHidden,
+			"is_flagged":  r.IsFlagged,
+			"flag_reason": r.FlagReason,
+			"updated_at":  r.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ru *ReviewRepositoryImpl) Get(db *gorm.DB, reviewID string) (*models.ProductReview, error) {
+	r := models.ProductReview{}
+	if err := db.Table(r.TableName()).Where("id = ?", reviewID).First(&r).Error; err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (ru *ReviewRepositoryImpl) GetAsStoreStuff(db *gorm.DB, storeID, productID, reviewID string) (*models.ProductReview, error) {
+	r := models.ProductReview{}
+	if err := db.Table(r.TableName()).
+		Where("id = ? AND store_id = ? AND product_id = ?", reviewID, storeID, productID).
+		First(&r).Error; err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (ru *ReviewRepositoryImpl) details(db *gorm.DB) *gorm.DB {
+	r := models.ProductReview{}
+	p := models.Product{}
+	u := models.User{}
+
+	return db.Table(fmt.Sprintf("%s AS r", r.TableName())).
+		Select("r.id, r.product_id, p.name AS product_name, r.store_id, r.user_id, u.name AS user_name, r.rating, r.description, r.reply, r.replied_at, r.is_hidden, r.is_flagged, r.flag_reason, r.created_at, r.updated_at").
+		Joins(fmt.Sprintf("JOIN %s AS p ON r.product_id = p.id", p.TableName())).
+		Joins(fmt.Sprintf("JOIN %s AS u ON r.user_id = u.id", u.TableName()))
+}
+
+func (ru *ReviewRepositoryImpl) withImages(db *gorm.DB, reviews []models.ProductReviewDetails) ([]models.ProductReviewDetails, error) {
+	for i, v := range reviews {
+		images, err := ru.GetImages(db, v.ID)
+		if err != nil {
+			return nil, err
+		}
+		reviews[i].Images = images
+	}
+	return reviews, nil
+}
+
+func (ru *ReviewRepositoryImpl) GetDetails(db *gorm.DB, reviewID string) (*models.ProductReviewDetails, error) {
+	var reviews []models.ProductReviewDetails
+	if err := ru.details(db).
+		Where("r.id = ?", reviewID).
+		Limit(1).Scan(&reviews).Error; err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	reviews, err := ru.withImages(db, reviews)
+	if err != nil {
+		return nil, err
+	}
+	return &reviews[0], nil
+}
+
+func (ru *ReviewRepositoryImpl) List(db *gorm.DB, productID string, from, limit int) ([]models.ProductReviewDetails, error) {
+	var reviews []models.ProductReviewDetails
+	if err := ru.details(db).
+		Where("(p.id = ? OR p.slug = ?) AND p.is_published = ? AND r.is_hidden = ?", productID, productID, true, false).
+		Offset(from).Limit(limit).
+		Order("r.created_at DESC").Scan(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return ru.withImages(db, reviews)
+}
+
+func (ru *ReviewRepositoryImpl) ListAsStoreStuff(db *gorm.DB, storeID, productID string, from, limit int) ([]models.ProductReviewDetails, error) {
+	var reviews []models.ProductReviewDetails
+	if err := ru.details(db).
+		Where("(p.id = ? OR p.slug = ?) AND r.store_id = ?", productID, productID, storeID).
+		Offset(from).Limit(limit).
+		Order("r.created_at DESC").Scan(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return ru.withImages(db, reviews)
+}
+
+func (ru *ReviewRepositoryImpl) ListAsAdmin(db *gorm.DB, flaggedOnly bool, from, limit int) ([]models.ProductReviewDetails, error) {
+	var reviews []models.ProductReviewDetails
+	q := ru.details(db)
+	if flaggedOnly {
+		q = q.Where("r.is_flagged = ?", true)
+	}
+	if err := q.Offset(from).Limit(limit).
+		Order("r.created_at DESC").Scan(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return ru.withImages(db, reviews)
+}
+
+func (ru *ReviewRepositoryImpl) AddImage(db *gorm.DB, reviewID, imagePath string) error {
+	ri := models.ProductReviewImage{
+		ReviewID:  reviewID,
+		ImagePath: imagePath,
+	}
+
+	if err := db.Table(ri.TableName()).Create(&ri).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ru *ReviewRepositoryImpl) GetImages(db *gorm.DB, reviewID string) ([]string, error) {
+	var images []models.ProductReviewImage
+	ri := models.ProductReviewImage{}
+	if err := db.Table(ri.TableName()).Where("review_id = ?", reviewID).Find(&images).Error; err != nil {
+		return nil, err
+	}
+
+	filteredImages := []string{}
+	for _, v := range images {
+		filteredImages = append(filteredImages, v.ImagePath)
+	}
+	return filteredImages, nil
+}
+
+// GetPurchasedOrderID returns the latest paid order of the user containing the product,
+// only verified purchasers are allowed to review a product
+func (ru *ReviewRepositoryImpl) GetPurchasedOrderID(db *gorm.DB, userID, productID string) (string, error) {
+	o := models.Order{}
+	oi := models.OrderedItem{}
+
+	var orders []models.Order
+	if err := db.Table(fmt.Sprintf("%s AS o", o.TableName())).
+		Select("o.id").
+		Joins(fmt.Sprintf("JOIN %s AS oi ON oi.order_id = o.id", oi.TableName())).
+		Where("o.user_id = ? AND oi.product_id = ? AND o.payment_status = ?", userID, productID, models.PaymentCompleted).
+		Order("o.created_at DESC").
+		Limit(1).Scan(&orders).Error; err != nil {
+		return "", err
+	}
+	if len(orders) == 0 {
+		return "", gorm.ErrRecordNotFound
+	}
+	return orders[0].ID, nil
+}
+
+// UpdateProductRating recalculates the rating aggregates of the product from its visible reviews
+func (ru *ReviewRepositoryImpl) UpdateProductRating(db *gorm.DB, productID string) error {
+	p := models.Product{}
+	r := models.ProductReview{}
+
+	if err := db.Exec(fmt.Sprintf("UPDATE %s SET"+
+		" rating_average = (SELECT COALESCE(AVG(r.rating), 0) FROM %s AS r WHERE r.product_id = ? AND r.is_hidden = ?),"+
+		" rating_count = (SELECT COUNT(r.id) FROM %s AS r WHERE r.product_id = ? AND r.is_hidden = ?)"+
+		" WHERE id = ?", p.TableName(), r.TableName(), r.TableName()),
+		productID, false, productID, false, productID).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ru *ReviewRepositoryImpl) ListByUser(db *gorm.DB, userID string) ([]models.ProductReview, error) {
+	var reviews []models.ProductReview
+	if err := db.Model(&models.ProductReview{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// AnonymizeByUser clears the text and the images of the reviews of the user, including the ones left on
+// its orders. The ratings are kept so the ratings of the products don't change.
+func (ru *ReviewRepositoryImpl) AnonymizeByUser(db *gorm.DB, userID string, at time.Time) error {
+	r := models.ProductReview{}
+	ri := models.ProductReviewImage{}
+	or := models.Review{}
+	o := models.Order{}
+
+	if err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE review_id IN (SELECT id FROM %s WHERE user_id = ?)",
+		ri.TableName(), r.TableName()), userID).Error; err != nil {
+		return err
+	}
+	if err := db.Table(r.TableName()).
+		Where("user_id = ?", userID).
+		Select("description, updated_at").
+		Updates(map[string]interface{}{
+			"description": "",
+			"updated_at":  at,
+		}).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(fmt.Sprintf("UPDATE %s SET description = '' WHERE order_id IN (SELECT id FROM %s WHERE user_id = ?)",
+		or.TableName(), o.TableName()), userID).Error; err != nil {
+		return err
+	}
+	return nil
+}
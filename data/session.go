@@ -0,0 +1,20 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type SessionRepository interface {
+	Create(db *gorm.DB, s *models.Session) error
+	Get(db *gorm.DB, ID string) (*models.Session, error)
+	ListActive(db *gorm.DB, userID string, now time.Time) ([]models.Session, error)
+	Rotate(db *gorm.DB, s *models.Session) error
+	Touch(db *gorm.DB, ID string, at time.Time, userAgent, ip string) error
+	Revoke(db *gorm.DB, userID, ID, reason string, at time.Time) error
+	RevokeAll(db *gorm.DB, userID, exceptID, reason string, at time.Time) error
+	DeleteInactive(db *gorm.DB, before time.Time) error
+	List(db *gorm.DB, userID string) ([]models.Session, error)
+	DeleteByUser(db *gorm.DB, userID string) error
+}
@@ -0,0 +1,144 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type SessionRepositoryImpl struct {
+}
+
+var sessionRepository SessionRepository
+
+func NewSessionRepository() SessionRepository {
+	if sessionRepository == nil {
+		sessionRepository = &SessionRepositoryImpl{}
+	}
+	return sessionRepository
+}
+
+func (sr *SessionRepositoryImpl) Create(db *gorm.DB, s *models.Session) error {
+	if err := db.Model(s).Create(s).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *SessionRepositoryImpl) Get(db *gorm.DB, ID string) (*models.Session, error) {
+	s := models.Session{}
+	if err := db.Model(&s).Where("id = ?", ID).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (sr *SessionRepositoryImpl) ListActive(db *gorm.DB, userID string, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+
+	s := models.Session{}
+	if err := db.Table(s.TableName()).
+		Where("user_id = ? AND revoked_at IS NULL AND expire_on > ?", userID, now.Unix()).
+		Order("last_seen_at DESC NULLS LAST, created_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (sr *SessionRepositoryImpl) Rotate(db *gorm.DB, s *models.Session) error {
+	if err := db.Table(s.TableName()).
+		Where("id = ?", s.ID).
+		Select("access_token, refresh_token, expire_on, user_agent, ip, last_seen_at").
+		Updates(map[string]interface{}{
+			"access_token":  s.AccessToken,
+			"refresh_token": s.RefreshToken,
+			"expire_on":     s.ExpireOn,
+			"user_agent":    s.UserAgent,
+			"ip":            s.IP,
+			"last_seen_at":  s.LastSeenAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *SessionRepositoryImpl) Touch(db *gorm.DB, ID string, at time.Time, userAgent, ip string) error {
+	s := models.Session{}
+	if err := db.Table(s.TableName()).
+		Where("id = ?", ID).
+		Select("last_seen_at, user_agent, ip").
+		Updates(map[string]interface{}{
+			"last_seen_at": at,
+			"user_agent":   userAgent,
+			"ip":           ip,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *SessionRepositoryImpl) Revoke(db *gorm.DB, userID, ID, reason string, at time.Time) error {
+	s := models.Session{}
+	q := db.Table(s.TableName()).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", ID, userID).
+		Select("revoked_at, revoked_reason").
+		Updates(map[string]interface{}{
+			"revoked_at":     at,
+			"revoked_reason": reason,
+		})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (sr *SessionRepositoryImpl) RevokeAll(db *gorm.DB, userID, exceptID, reason string, at time.Time) error {
+	s := models.Session{}
+	if err := db.Table(s.TableName()).
+		Where("user_id = ? AND id != ? AND revoked_at IS NULL", userID, exceptID).
+		Select("revoked_at, revoked_reason").
+		Updates(map[string]interface{}{
+			"revoked_at":     at,
+			"revoked_reason": reason,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *SessionRepositoryImpl) DeleteInactive(db *gorm.DB, before time.Time) error {
+	s := models.Session{}
+	if err := db.Table(s.TableName()).
+		Where("expire_on < ? OR revoked_at < ?", before.Unix(), before).
+		Delete(&s).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *SessionRepositoryImpl) List(db *gorm.DB, userID string) ([]models.Session, error) {
+	var sessions []models.Session
+
+	s := models.Session{}
+	if err := db.Table(s.TableName()).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (sr *SessionRepositoryImpl) DeleteByUser(db *gorm.DB, userID string) error {
+	s := models.Session{}
+	if err := db.Table(s.TableName()).
+		Where("user_id = ?", userID).
+		Delete(&s).Error; err != nil {
+		return err
+	}
+	return nil
+}
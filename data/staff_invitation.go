@@ -0,0 +1,22 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type StaffInvitationRepository interface {
+	Create(db *gorm.DB, si *models.StaffInvitation) error
+	Get(db *gorm.DB, storeID, ID string) (*models.StaffInvitation, error)
+	GetByID(db *gorm.DB, ID string) (*models.StaffInvitation, error)
+	GetByTokenHash(db *gorm.DB, tokenHash string) (*models.StaffInvitation, error)
+	GetDetails(db *gorm.DB, ID string) (*models.StaffInvitationDetails, error)
+	HasPending(db *gorm.DB, storeID, email string, now time.Time) (bool, error)
+	List(db *gorm.DB, storeID string, status models.StaffInvitationStatus, from, limit int) ([]models.StaffInvitation, error)
+	SetTokenHash(db *gorm.DB, ID, tokenHash string, at time.Time) error
+	Respond(db *gorm.DB, ID string, status models.StaffInvitationStatus, at time.Time) error
+	ExpirePending(db *gorm.DB, now time.Time) error
+	CreateAuditLog(db *gorm.DB, l *models.StaffAuditLog) error
+	ListAuditLogs(db *gorm.DB, storeID string, from, limit int) ([]models.StaffAuditLogDetails, error)
+}
@@ -0,0 +1,165 @@
+package data
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type StaffInvitationRepositoryImpl struct {
+}
+
+var staffInvitationRepository StaffInvitationRepository
+
+func NewStaffInvitationRepository() StaffInvitationRepository {
+	if staffInvitationRepository == nil {
+		staffInvitationRepository = &StaffInvitationRepositoryImpl{}
+	}
+	return staffInvitationRepository
+}
+
+func (sr *StaffInvitationRepositoryImpl) Create(db *gorm.DB, si *models.StaffInvitation) error {
+	if err := db.Create(si).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) Get(db *gorm.DB, storeID, ID string) (*models.StaffInvitation, error) {
+	si := models.StaffInvitation{}
+	if err := db.Model(&si).Where("store_id = ? AND id = ?", storeID, ID).First(&si).Error; err != nil {
+		return nil, err
+	}
+	return &si, nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) GetByID(db *gorm.DB, ID string) (*models.StaffInvitation, error) {
+	si := models.StaffInvitation{}
+	if err := db.Model(&si).Where("id = ?", ID).First(&si).Error; err != nil {
+		return nil, err
+	}
+	return &si, nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) GetByTokenHash(db *gorm.DB, tokenHash string) (*models.StaffInvitation, error) {
+	si := models.StaffInvitation{}
+	if err := db.Model(&si).Where("token_hash = ?", tokenHash).First(&si).Error; err != nil {
+		return nil, err
+	}
+	return &si, nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) GetDetails(db *gorm.DB, ID string) (*models.StaffInvitationDetails, error) {
+	sid := models.StaffInvitationDetails{}
+	si := models.StaffInvitation{}
+	if err := db.Table(fmt.Sprintf("%s AS si", si.TableName())).
+		Select("si.id AS id, si.store_id AS store_id, s.name AS store_name, si.email AS email, si.permission_id AS permission_id,"+
+			" u.name AS inviter_name, si.status AS status, si.expires_at AS expires_at").
+		Joins("LEFT JOIN stores AS s ON si.store_id = s.id").
+		Joins("LEFT JOIN users AS u ON si.invited_by = u.id").
+		Where("si.id = ?", ID).
+		Scan(&sid).Error; err != nil {
+		return nil, err
+	}
+	return &sid, nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) HasPending(db *gorm.DB, storeID, email string, now time.Time) (bool, error) {
+	count := 0
+	if err := db.Model(&models.StaffInvitation{}).
+		Where("store_id = ? AND LOWER(email) = LOWER(?) AND status = ? AND expires_at > ?", storeID, email, models.StaffInvitationPending, now).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) List(db *gorm.DB, storeID string, status models.StaffInvitationStatus, from, limit int) ([]models.StaffInvitation, error) {
+	var invitations []models.StaffInvitation
+	q := db.Model(&models.StaffInvitation{}).Where("store_id = ?", storeID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("created_at DESC").
+		Limit(limit).Offset(from).
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) SetTokenHash(db *gorm.DB, ID, tokenHash string, at time.Time) error {
+	si := models.StaffInvitation{}
+	q := db.Table(si.TableName()).
+		Where("id = ? AND status = ?", ID, models.StaffInvitationPending).
+		Select("token_hash, updated_at").
+		Updates(map[string]interface{}{
+			"token_hash": tokenHash,
+			"updated_at": at,
+		})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Respond moves a pending invitation to the given status, an invitation that isn't pending anymore is not found
+func (sr *StaffInvitationRepositoryImpl) Respond(db *gorm.DB, ID string, status models.StaffInvitationStatus, at time.Time) error {
+	si := models.StaffInvitation{}
+	q := db.Table(si.TableName()).
+		Where("id = ? AND status = ?", ID, models.StaffInvitationPending).
+		Select("status, responded_at, updated_at").
+		Updates(map[string]interface{}{
+			"status":       status,
+			"responded_at": at,
+			"updated_at":   at,
+		})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) ExpirePending(db *gorm.DB, now time.Time) error {
+	si := models.StaffInvitation{}
+	if err := db.Table(si.TableName()).
+		Where("status = ? AND expires_at <= ?", models.StaffInvitationPending, now).
+		Select("status, updated_at").
+		Updates(map[string]interface{}{
+			"status":     models.StaffInvitationExpired,
+			"updated_at": now,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) CreateAuditLog(db *gorm.DB, l *models.StaffAuditLog) error {
+	if err := db.Create(l).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *StaffInvitationRepositoryImpl) ListAuditLogs(db *gorm.DB, storeID string, from, limit int) ([]models.StaffAuditLogDetails, error) {
+	var logs []models.StaffAuditLogDetails
+	sal := models.StaffAuditLog{}
+	if err := db.Table(fmt.Sprintf("%s AS sal", sal.TableName())).
+		Select("sal.*, a.name AS actor_name, a.email AS actor_email, t.name AS target_user_name").
+		Joins("LEFT JOIN users AS a ON sal.actor_id = a.id").
+		Joins("LEFT JOIN users AS t ON sal.target_user_id = t.id").
+		Where("sal.store_id = ?", storeID).
+		Order("sal.created_at DESC").
+		Limit(limit).Offset(from).
+		Scan(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -6,7 +6,8 @@ import (
 )
 
 type StoreRepository interface {
-	GetStoreUserProfile(db *gorm.DB, userID string) (*models.StaffProfile, error)
+	GetStoreUserProfile(db *gorm.DB, userID, storeID string) (*models.StaffProfile, error)
+	ListStoreUserProfiles(db *gorm.DB, userID string) ([]models.StaffProfile, error)
 	CreateStore(db *gorm.DB, s *models.Store) error
 	FindStoreByID(db *gorm.DB, ID string) (*models.Store, error)
 	FindByID(db *gorm.DB, ID string) (*models.StoreView, error)
@@ -15,7 +16,9 @@ type StoreRepository interface {
 	SearchStaffs(db *gorm.DB, storeID, query string, from, limit int) ([]models.StaffProfile, error)
 	UpdateStoreStuffPermission(db *gorm.DB, staff *models.Staff) error
 	DeleteStoreStuffPermission(db *gorm.DB, storeID, userID string) error
-	IsAlreadyStaff(db *gorm.DB, userID string) (bool, error)
+	IsAlreadyStaff(db *gorm.DB, storeID, userID string) (bool, error)
+	GetCreator(db *gorm.DB, storeID string) (*models.Staff, error)
+	SetStaffRole(db *gorm.DB, storeID, userID string, roleID *string) error
 	List(db *gorm.DB, from, limit int) ([]models.Store, error)
 	Search(db *gorm.DB, query string, from, limit int) ([]models.Store, error)
 	UpdateStoreStatus(db *gorm.DB, s *models.Store) error
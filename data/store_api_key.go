@@ -0,0 +1,16 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type StoreAPIKeyRepository interface {
+	Create(db *gorm.DB, k *models.StoreAPIKey) error
+	Get(db *gorm.DB, storeID, ID string) (*models.StoreAPIKey, error)
+	GetByHash(db *gorm.DB, keyHash string) (*models.StoreAPIKey, error)
+	List(db *gorm.DB, storeID string) ([]models.StoreAPIKey, error)
+	Revoke(db *gorm.DB, storeID, ID string, at time.Time) error
+	Touch(db *gorm.DB, ID, ip string, at time.Time) error
+}
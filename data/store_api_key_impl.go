@@ -0,0 +1,115 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type StoreAPIKeyRepositoryImpl struct {
+}
+
+var storeAPIKeyRepository StoreAPIKeyRepository
+
+func NewStoreAPIKeyRepository() StoreAPIKeyRepository {
+	if storeAPIKeyRepository == nil {
+		storeAPIKeyRepository = &StoreAPIKeyRepositoryImpl{}
+	}
+	return storeAPIKeyRepository
+}
+
+func (kr *StoreAPIKeyRepositoryImpl) Create(db *gorm.DB, k *models.StoreAPIKey) error {
+	if err := db.Create(k).Error; err != nil {
+		return err
+	}
+	for _, p := range k.Scopes {
+		if err := db.Create(&models.StoreAPIKeyScope{
+			APIKeyID:   k.ID,
+			Permission: p,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kr *StoreAPIKeyRepositoryImpl) Get(db *gorm.DB, storeID, ID string) (*models.StoreAPIKey, error) {
+	k := models.StoreAPIKey{}
+	if err := db.Model(&k).Where("id = ? AND store_id = ?", ID, storeID).First(&k).Error; err != nil {
+		return nil, err
+	}
+	return kr.withScopes(db, &k)
+}
+
+func (kr *StoreAPIKeyRepositoryImpl) GetByHash(db *gorm.DB, keyHash string) (*models.StoreAPIKey, error) {
+	k := models.StoreAPIKey{}
+	if err := db.Model(&k).Where("key_hash = ?", keyHash).First(&k).Error; err != nil {
+		return nil, err
+	}
+	return kr.withScopes(db, &k)
+}
+
+func (kr *StoreAPIKeyRepositoryImpl) List(db *gorm.DB, storeID string) ([]models.StoreAPIKey, error) {
+	var keys []models.StoreAPIKey
+	if err := db.Model(&models.StoreAPIKey{}).
+		Where("store_id = ?", storeID).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range keys {
+		if _, err := kr.withScopes(db, &keys[i]); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+func (kr *StoreAPIKeyRepositoryImpl) Revoke(db *gorm.DB, storeID, ID string, at time.Time) error {
+	k := models.StoreAPIKey{}
+	q := db.Table(k.TableName()).
+		Where("id = ? AND store_id = ? AND revoked_at IS NULL", ID, storeID).
+		Select("revoked_at, updated_at").
+		Updates(map[string]interface{}{
+			"revoked_at": at,
+			"updated_at": at,
+		})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (kr *StoreAPIKeyRepositoryImpl) Touch(db *gorm.DB, ID, ip string, at time.Time) error {
+	k := models.StoreAPIKey{}
+	if err := db.Table(k.TableName()).
+		Where("id = ?", ID).
+		Select("last_used_at, last_used_ip").
+		Updates(map[string]interface{}{
+			"last_used_at": at,
+			"last_used_ip": ip,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (kr *StoreAPIKeyRepositoryImpl) withScopes(db *gorm.DB, k *models.StoreAPIKey) (*models.StoreAPIKey, error) {
+	var kss []models.StoreAPIKeyScope
+	if err := db.Model(&models.StoreAPIKeyScope{}).
+		Where("api_key_id = ?", k.ID).
+		Order("permission ASC").
+		Find(&kss).Error; err != nil {
+		return nil, err
+	}
+
+	k.Scopes = []models.StorePerm{}
+	for _, ks := range kss {
+		k.Scopes = append(k.Scopes, ks.Permission)
+	}
+	return k, nil
+}
@@ -18,23 +18,43 @@ func NewStoreRepository() StoreRepository {
 	return storeRepository
 }
 
-func (su *StoreRepositoryImpl) GetStoreUserProfile(db *gorm.DB, userID string) (*models.StaffProfile, error) {
+func (su *StoreRepositoryImpl) GetStoreUserProfile(db *gorm.DB, userID, storeID string) (*models.StaffProfile, error) {
 	sup := models.StaffProfile{}
 	st := models.Staff{}
 	if err := db.Table(fmt.Sprintf("%s AS st", st.TableName())).
 		Select("st.user_id AS staff_id, st.store_id AS store_id, s.name AS store_name, s.status AS store_status, st.is_creator AS is_creator,"+
-			" sp.permission AS staff_permission, u.name AS staff_name, u.email AS staff_email, u.phone AS staff_phone,"+
+			" sp.permission AS staff_permission, st.role_id AS staff_role_id, sr.name AS staff_role_name, u.name AS staff_name, u.email AS staff_email, u.phone AS staff_phone,"+
 			" u.profile_picture AS staff_picture, u.status AS staff_status").
 		Joins("LEFT JOIN store_permissions AS sp ON st.permission_id = sp.id").
+		Joins("LEFT JOIN store_roles AS sr ON st.role_id = sr.id").
 		Joins("LEFT JOIN stores AS s ON st.store_id = s.id").
 		Joins("LEFT JOIN users AS u ON st.user_id = u.id").
-		Where("st.user_id = ?", userID).
+		Where("st.user_id = ? AND st.store_id = ?", userID, storeID).
 		Find(&sup).Error; err != nil {
 		return nil, err
 	}
 	return &sup, nil
 }
 
+func (su *StoreRepositoryImpl) ListStoreUserProfiles(db *gorm.DB, userID string) ([]models.StaffProfile, error) {
+	var sup []models.StaffProfile
+	st := models.Staff{}
+	if err := db.Table(fmt.Sprintf("%s AS st", st.TableName())).
+		Select("st.user_id AS staff_id, st.store_id AS store_id, s.name AS store_name, s.status AS store_status, st.is_creator AS is_creator,"+
+			" sp.permission AS staff_permission, st.role_id AS staff_role_id, sr.name AS staff_role_name, u.name AS staff_name, u.email AS staff_email, u.phone AS staff_phone,"+
+			" u.profile_picture AS staff_picture, u.status AS staff_status").
+		Joins("LEFT JOIN store_permissions AS sp ON st.permission_id = sp.id").
+		Joins("LEFT JOIN store_roles AS sr ON st.role_id = sr.id").
+		Joins("LEFT JOIN stores AS s ON st.store_id = s.id").
+		Joins("LEFT JOIN users AS u ON st.user_id = u.id").
+		Where("st.user_id = ?", userID).
+		Order("st.is_creator DESC, s.name ASC").
+		Find(&sup).Error; err != nil {
+		return nil, err
+	}
+	return sup, nil
+}
+
 func (su *StoreRepositoryImpl) CreateStore(db *gorm.DB, s *models.Store) error {
 	if err := db.Table(s.TableName()).Create(s).Error; err != nil {
 		return err
@@ -87,27 +107,38 @@ func (su *StoreRepositoryImpl) FindByID(db *gorm.DB, ID string) (*models.StoreVi
 	return &s, nil
 }
 
-func (su *StoreRepositoryImpl) IsAlreadyStaff(db *gorm.DB, userID string) (bool, error) {
+func (su *StoreRepositoryImpl) IsAlreadyStaff(db *gorm.DB, storeID, userID string) (bool, error) {
 	staff := models.Staff{}
 
 	var count int
 
 	if err := db.Table(staff.TableName()).
-		Where("user_id = ?", userID).
+		Where("store_id = ? AND user_id = ?", storeID, userID).
 		Count(&count).Error; err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
+func (su *StoreRepositoryImpl) GetCreator(db *gorm.DB, storeID string) (*models.Staff, error) {
+	staff := models.Staff{}
+	if err := db.Table(staff.TableName()).
+		Where("store_id = ? AND is_creator = ?", storeID, true).
+		First(&staff).Error; err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
 func (su *StoreRepositoryImpl) ListStaffs(db *gorm.DB, storeID string, from, limit int) ([]models.StaffProfile, error) {
 	var sup []models.StaffProfile
 	st := models.Staff{}
 	if err := db.Table(fmt.Sprintf("%s AS st", st.TableName())).
 		Select("st.user_id AS staff_id, st.store_id AS store_id, s.name AS store_name, s.status AS store_status, st.is_creator AS is_creator,"+
-			" sp.permission AS staff_permission, u.name AS staff_name, u.email AS staff_email, u.phone AS staff_phone,"+
+			" sp.permission AS staff_permission, st.role_id AS staff_role_id, sr.name AS staff_role_name, u.name AS staff_name, u.email AS staff_email, u.phone AS staff_phone,"+
 			" u.profile_picture AS staff_picture, u.status AS staff_status").
 		Joins("LEFT JOIN store_permissions AS sp ON st.permission_id = sp.id").
+		Joins("LEFT JOIN store_roles AS sr ON st.role_id = sr.id").
 		Joins("LEFT JOIN stores AS s ON st.store_id = s.id").
 		Joins("LEFT JOIN users AS u ON st.user_id = u.id").
 		Where("st.store_id = ?", storeID).
@@ -123,9 +154,10 @@ func (su *StoreRepositoryImpl) SearchStaffs(db *gorm.DB, storeID, query string,
 	st := models.Staff{}
 	if err := db.Table(fmt.Sprintf("%s AS st", st.TableName())).
 		Select("st.user_id AS staff_id, st.store_id AS store_id, s.name AS store_name, s.status AS store_status, st.is_creator AS is_creator,"+
-			" sp.permission AS staff_permission, u.name AS staff_name, u.email AS staff_email, u.phone AS staff_phone,"+
+			" sp.permission AS staff_permission, st.role_id AS staff_role_id, sr.name AS staff_role_name, u.name AS staff_name, u.email AS staff_email, u.phone AS staff_phone,"+
 			" u.profile_picture AS staff_picture, u.status AS staff_status").
 		Joins("LEFT JOIN store_permissions AS sp ON st.permission_id = sp.id").
+		Joins("LEFT JOIN store_roles AS sr ON st.role_id = sr.id").
 		Joins("LEFT JOIN users AS u ON st.user_id = u.id").
 		Joins("LEFT JOIN stores AS s ON st.store_id = s.id").
 		Where("id = ? AND (u.email LIKE ? OR u.phone LIKE ?)", storeID, "%"+query+"%", "%"+query+"%").
@@ -209,3 +241,18 @@ func (su *StoreRepositoryImpl) GetStorePayoutSummary(db *gorm.DB, storeID string
 	}
 	return &m, nil
 }
+
+func (su *StoreRepositoryImpl) SetStaffRole(db *gorm.DB, storeID, userID string, roleID *string) error {
+	st := models.Staff{}
+
+	q := db.Table(st.TableName()).
+		Where("store_id = ? AND user_id = ?", storeID, userID).
+		Update("role_id", roleID)
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type StoreRoleRepository interface {
+	Create(db *gorm.DB, r *models.StoreRole) error
+	Update(db *gorm.DB, r *models.StoreRole) error
+	Delete(db *gorm.DB, storeID, ID string) error
+	Get(db *gorm.DB, storeID, ID string) (*models.StoreRole, error)
+	List(db *gorm.DB, storeID string) ([]models.StoreRole, error)
+	ListPermissions(db *gorm.DB, roleID string) ([]models.StorePerm, error)
+}
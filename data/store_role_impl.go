@@ -0,0 +1,117 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type StoreRoleRepositoryImpl struct {
+}
+
+var storeRoleRepository StoreRoleRepository
+
+func NewStoreRoleRepository() StoreRoleRepository {
+	if storeRoleRepository == nil {
+		storeRoleRepository = &StoreRoleRepositoryImpl{}
+	}
+	return storeRoleRepository
+}
+
+func (sr *StoreRoleRepositoryImpl) Create(db *gorm.DB, r *models.StoreRole) error {
+	if err := db.Create(r).Error; err != nil {
+		return err
+	}
+	return sr.replacePermissions(db, r)
+}
+
+func (sr *StoreRoleRepositoryImpl) Update(db *gorm.DB, r *models.StoreRole) error {
+	q := db.Table(r.TableName()).
+		Where("id = ? AND store_id = ?", r.ID, r.StoreID).
+		Select("name, updated_at").
+		Updates(map[string]interface{}{
+			"name":       r.Name,
+			"updated_at": r.UpdatedAt,
+		})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return sr.replacePermissions(db, r)
+}
+
+func (sr *StoreRoleRepositoryImpl) replacePermissions(db *gorm.DB, r *models.StoreRole) error {
+	if err := db.Where("role_id = ?", r.ID).Delete(&models.StoreRolePermission{}).Error; err != nil {
+		return err
+	}
+	for _, p := range r.Permissions {
+		if err := db.Create(&models.StoreRolePermission{
+			RoleID:     r.ID,
+			Permission: p,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sr *StoreRoleRepositoryImpl) Delete(db *gorm.DB, storeID, ID string) error {
+	q := db.Where("id = ? AND store_id = ?", ID, storeID).Delete(&models.StoreRole{})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (sr *StoreRoleRepositoryImpl) Get(db *gorm.DB, storeID, ID string) (*models.StoreRole, error) {
+	r := models.StoreRole{}
+	if err := db.Model(&r).Where("id = ? AND store_id = ?", ID, storeID).First(&r).Error; err != nil {
+		return nil, err
+	}
+
+	perms, err := sr.ListPermissions(db, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	r.Permissions = perms
+	return &r, nil
+}
+
+func (sr *StoreRoleRepositoryImpl) List(db *gorm.DB, storeID string) ([]models.StoreRole, error) {
+	var roles []models.StoreRole
+	if err := db.Model(&models.StoreRole{}).
+		Where("store_id = ?", storeID).
+		Order("name ASC").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range roles {
+		perms, err := sr.ListPermissions(db, roles[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		roles[i].Permissions = perms
+	}
+	return roles, nil
+}
+
+func (sr *StoreRoleRepositoryImpl) ListPermissions(db *gorm.DB, roleID string) ([]models.StorePerm, error) {
+	var rps []models.StoreRolePermission
+	if err := db.Model(&models.StoreRolePermission{}).
+		Where("role_id = ?", roleID).
+		Order("permission ASC").
+		Find(&rps).Error; err != nil {
+		return nil, err
+	}
+
+	perms := []models.StorePerm{}
+	for _, rp := range rps {
+		perms = append(perms, rp.Permission)
+	}
+	return perms, nil
+}
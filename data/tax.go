@@ -0,0 +1,26 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type TaxRepository interface {
+	CreateClass(db *gorm.DB, tc *models.TaxClass) error
+	UpdateClass(db *gorm.DB, tc *models.TaxClass) error
+	DeleteClass(db *gorm.DB, ID string) error
+	GetClass(db *gorm.DB, ID string) (*models.TaxClass, error)
+	ListClasses(db *gorm.DB, from, limit int) ([]models.TaxClass, error)
+	CreateRate(db *gorm.DB, tr *models.TaxRate) error
+	UpdateRate(db *gorm.DB, tr *models.TaxRate) error
+	DeleteRate(db *gorm.DB, ID string) error
+	GetRate(db *gorm.DB, ID string) (*models.TaxRate, error)
+	ListRates(db *gorm.DB, locationID *int64, from, limit int) ([]models.TaxRate, error)
+	ListRatesForLocations(db *gorm.DB, taxClassID *string, locationIDs []int64) ([]models.TaxRate, error)
+	CreateExemption(db *gorm.DB, te *models.TaxExemption) error
+	UpdateExemption(db *gorm.DB, te *models.TaxExemption) error
+	DeleteExemption(db *gorm.DB, userID string) error
+	GetExemption(db *gorm.DB, ID string) (*models.TaxExemption, error)
+	GetExemptionByUser(db *gorm.DB, userID string) (*models.TaxExemption, error)
+	ListExemptions(db *gorm.DB, isVerified *bool, from, limit int) ([]models.TaxExemption, error)
+}
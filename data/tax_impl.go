@@ -0,0 +1,231 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type TaxRepositoryImpl struct {
+}
+
+var taxRepository TaxRepository
+
+func NewTaxRepository() TaxRepository {
+	if taxRepository == nil {
+		taxRepository = &TaxRepositoryImpl{}
+	}
+	return taxRepository
+}
+
+func (tu *TaxRepositoryImpl) CreateClass(db *gorm.DB, tc *models.TaxClass) error {
+	if err := db.Table(tc.TableName()).Create(tc).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) UpdateClass(db *gorm.DB, tc *models.TaxClass) error {
+	if err := db.Table(tc.TableName()).
+		Where("id = ?", tc.ID).
+		Select("name, description, updated_at").
+		Updates(map[string]interface{}{
+			"name":        tc.Name,
+			"description": tc.Description,
+			"updated_at":  tc.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) DeleteClass(db *gorm.DB, ID string) error {
+	tc := models.TaxClass{}
+	if err := db.Table(tc.TableName()).
+		Where("id = ?", ID).
+		Delete(&tc).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) GetClass(db *gorm.DB, ID string) (*models.TaxClass, error) {
+	tc := models.TaxClass{}
+	if err := db.Table(tc.TableName()).
+		Where("id = ?", ID).
+		First(&tc).Error; err != nil {
+		return nil, err
+	}
+	return &tc, nil
+}
+
+func (tu *TaxRepositoryImpl) ListClasses(db *gorm.DB, from, limit int) ([]models.TaxClass, error) {
+	tc := models.TaxClass{}
+	var data []models.TaxClass
+	if err := db.Table(tc.TableName()).
+		Order("name ASC").
+		Offset(from).
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (tu *TaxRepositoryImpl) CreateRate(db *gorm.DB, tr *models.TaxRate) error {
+	if err := db.Table(tr.TableName()).Create(tr).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) UpdateRate(db *gorm.DB, tr *models.TaxRate) error {
+	if err := db.Table(tr.TableName()).
+		Where("id = ?", tr.ID).
+		Select("tax_class_id, location_id, name, rate, priority, is_compound, is_inclusive, updated_at").
+		Updates(map[string]interface{}{
+			"tax_class_id": tr.TaxClassID,
+			"location_id":  tr.LocationID,
+			"name":         tr.Name,
+			"rate":         tr.Rate,
+			"priority":     tr.Priority,
+			"is_compound":  tr.IsCompound,
+			"is_inclusive": tr.IsInclusive,
+			"updated_at":   tr.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) DeleteRate(db *gorm.DB, ID string) error {
+	tr := models.TaxRate{}
+	if err := db.Table(tr.TableName()).
+		Where("id = ?", ID).
+		Delete(&tr).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) GetRate(db *gorm.DB, ID string) (*models.TaxRate, error) {
+	tr := models.TaxRate{}
+	if err := db.Table(tr.TableName()).
+		Where("id = ?", ID).
+		First(&tr).Error; err != nil {
+		return nil, err
+	}
+	return &tr, nil
+}
+
+func (tu *TaxRepositoryImpl) ListRates(db *gorm.DB, locationID *int64, from, limit int) ([]models.TaxRate, error) {
+	tr := models.TaxRate{}
+	var data []models.TaxRate
+
+	q := db.Table(tr.TableName())
+	if locationID != nil {
+		q = q.Where("location_id = ?", *locationID)
+	}
+
+	if err := q.Order("location_id ASC, priority ASC").
+		Offset(from).
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (tu *TaxRepositoryImpl) ListRatesForLocations(db *gorm.DB, taxClassID *string, locationIDs []int64) ([]models.TaxRate, error) {
+	tr := models.TaxRate{}
+	var data []models.TaxRate
+
+	if len(locationIDs) == 0 {
+		return data, nil
+	}
+
+	q := db.Table(tr.TableName()).
+		Where("location_id IN (?)", locationIDs)
+	if taxClassID != nil {
+		q = q.Where("tax_class_id = ?", *taxClassID)
+	} else {
+		q = q.Where("tax_class_id IS NULL")
+	}
+
+	if err := q.Order("priority ASC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (tu *TaxRepositoryImpl) CreateExemption(db *gorm.DB, te *models.TaxExemption) error {
+	if err := db.Table(te.TableName()).Create(te).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) UpdateExemption(db *gorm.DB, te *models.TaxExemption) error {
+	if err := db.Table(te.TableName()).
+		Where("id = ?", te.ID).
+		Select("country_id, vat_number, is_verified, verified_by, verified_at, updated_at").
+		Updates(map[string]interface{}{
+			"country_id":  te.CountryID,
+			"vat_number":  te.VatNumber,
+			"is_verified": te.IsVerified,
+			"verified_by": te.VerifiedBy,
+			"verified_at": te.VerifiedAt,
+			"updated_at":  te.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) DeleteExemption(db *gorm.DB, userID string) error {
+	te := models.TaxExemption{}
+	if err := db.Table(te.TableName()).
+		Where("user_id = ?", userID).
+		Delete(&te).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tu *TaxRepositoryImpl) GetExemption(db *gorm.DB, ID string) (*models.TaxExemption, error) {
+	te := models.TaxExemption{}
+	if err := db.Table(te.TableName()).
+		Where("id = ?", ID).
+		First(&te).Error; err != nil {
+		return nil, err
+	}
+	return &te, nil
+}
+
+func (tu *TaxRepositoryImpl) GetExemptionByUser(db *gorm.DB, userID string) (*models.TaxExemption, error) {
+	te := models.TaxExemption{}
+	if err := db.Table(te.TableName()).
+		Where("user_id = ?", userID).
+		First(&te).Error; err != nil {
+		return nil, err
+	}
+	return &te, nil
+}
+
+func (tu *TaxRepositoryImpl) ListExemptions(db *gorm.DB, isVerified *bool, from, limit int) ([]models.TaxExemption, error) {
+	te := models.TaxExemption{}
+	var data []models.TaxExemption
+
+	q := db.Table(te.TableName())
+	if isVerified != nil {
+		q = q.Where("is_verified = ?", *isVerified)
+	}
+
+	if err := q.Order("updated_at DESC").
+		Offset(from).
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,21 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type TwoFactorRepository interface {
+	Get(db *gorm.DB, userID string) (*models.TwoFactor, error)
+	Save(db *gorm.DB, tf *models.TwoFactor) error
+	Delete(db *gorm.DB, userID string) error
+	ReplaceRecoveryCodes(db *gorm.DB, userID string, codes []models.TwoFactorRecoveryCode) error
+	UseRecoveryCode(db *gorm.DB, userID, codeHash string, at time.Time) error
+	CountRecoveryCodes(db *gorm.DB, userID string) (int, error)
+	CreateChallenge(db *gorm.DB, c *models.TwoFactorChallenge) error
+	GetChallenge(db *gorm.DB, tokenHash string) (*models.TwoFactorChallenge, error)
+	IncreaseChallengeAttempts(db *gorm.DB, ID string) error
+	DeleteChallenge(db *gorm.DB, ID string) error
+	DeleteExpiredChallenges(db *gorm.DB, before time.Time) error
+}
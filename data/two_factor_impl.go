@@ -0,0 +1,121 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type TwoFactorRepositoryImpl struct {
+}
+
+var twoFactorRepository TwoFactorRepository
+
+func NewTwoFactorRepository() TwoFactorRepository {
+	if twoFactorRepository == nil {
+		twoFactorRepository = &TwoFactorRepositoryImpl{}
+	}
+	return twoFactorRepository
+}
+
+func (tr *TwoFactorRepositoryImpl) Get(db *gorm.DB, userID string) (*models.TwoFactor, error) {
+	tf := models.TwoFactor{}
+	if err := db.Model(&tf).Where("user_id = ?", userID).First(&tf).Error; err != nil {
+		return nil, err
+	}
+	return &tf, nil
+}
+
+func (tr *TwoFactorRepositoryImpl) Save(db *gorm.DB, tf *models.TwoFactor) error {
+	if err := db.Save(tf).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *TwoFactorRepositoryImpl) Delete(db *gorm.DB, userID string) error {
+	if err := db.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("user_id = ?", userID).Delete(&models.TwoFactor{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *TwoFactorRepositoryImpl) ReplaceRecoveryCodes(db *gorm.DB, userID string, codes []models.TwoFactorRecoveryCode) error {
+	if err := db.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	for i := range codes {
+		if err := db.Create(&codes[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tr *TwoFactorRepositoryImpl) UseRecoveryCode(db *gorm.DB, userID, codeHash string, at time.Time) error {
+	rc := models.TwoFactorRecoveryCode{}
+	q := db.Table(rc.TableName()).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", at)
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (tr *TwoFactorRepositoryImpl) CountRecoveryCodes(db *gorm.DB, userID string) (int, error) {
+	count := 0
+
+	rc := models.TwoFactorRecoveryCode{}
+	if err := db.Table(rc.TableName()).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (tr *TwoFactorRepositoryImpl) CreateChallenge(db *gorm.DB, c *models.TwoFactorChallenge) error {
+	if err := db.Create(c).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *TwoFactorRepositoryImpl) GetChallenge(db *gorm.DB, tokenHash string) (*models.TwoFactorChallenge, error) {
+	c := models.TwoFactorChallenge{}
+	if err := db.Model(&c).Where("token_hash = ?", tokenHash).First(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (tr *TwoFactorRepositoryImpl) IncreaseChallengeAttempts(db *gorm.DB, ID string) error {
+	c := models.TwoFactorChallenge{}
+	if err := db.Table(c.TableName()).
+		Where("id = ?", ID).
+		Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *TwoFactorRepositoryImpl) DeleteChallenge(db *gorm.DB, ID string) error {
+	if err := db.Where("id = ?", ID).Delete(&models.TwoFactorChallenge{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *TwoFactorRepositoryImpl) DeleteExpiredChallenges(db *gorm.DB, before time.Time) error {
+	if err := db.Where("expires_at < ?", before).Delete(&models.TwoFactorChallenge{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
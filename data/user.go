@@ -3,16 +3,13 @@ package data
 import (
 	"github.com/jinzhu/gorm"
 	"github.com/shopicano/shopicano-backend/models"
+	"time"
 )
 
 type UserRepository interface {
 	Register(db *gorm.DB, u *models.User) error
 	Login(db *gorm.DB, email string) (*models.User, error)
-	CreateSession(db *gorm.DB, s *models.Session) error
-	Logout(db *gorm.DB, token string) error
-	RefreshToken(db *gorm.DB, token string) (*models.Session, error)
 	Update(db *gorm.DB, u *models.User) error
-	GetPermission(db *gorm.DB, token string) (string, *models.Permission, error)
 	GetPermissionByUserID(db *gorm.DB, userID string) (string, *models.Permission, error)
 	Get(db *gorm.DB, userID string) (*models.User, error)
 	IsSignUpEnabled(db *gorm.DB) (bool, error)
@@ -20,4 +17,5 @@ type UserRepository interface {
 	GetByEmail(db *gorm.DB, email string) (*models.User, error)
 	List(db *gorm.DB, from, limit int) ([]models.User, error)
 	Search(db *gorm.DB, query string, from, limit int) ([]models.User, error)
+	Anonymize(db *gorm.DB, userID string, at time.Time) error
 }
@@ -0,0 +1,19 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type UserIdentityRepository interface {
+	Create(db *gorm.DB, ui *models.UserIdentity) error
+	GetBySubject(db *gorm.DB, provider, subject string) (*models.UserIdentity, error)
+	List(db *gorm.DB, userID string) ([]models.UserIdentity, error)
+	Touch(db *gorm.DB, ID, email string, isEmailVerified bool, at time.Time) error
+	Delete(db *gorm.DB, userID, ID string) error
+	CreateState(db *gorm.DB, s *models.OIDCState) error
+	GetState(db *gorm.DB, stateHash string) (*models.OIDCState, error)
+	DeleteState(db *gorm.DB, ID string) error
+	DeleteExpiredStates(db *gorm.DB, before time.Time) error
+}
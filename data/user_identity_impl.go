@@ -0,0 +1,101 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type UserIdentityRepositoryImpl struct {
+}
+
+var userIdentityRepository UserIdentityRepository
+
+func NewUserIdentityRepository() UserIdentityRepository {
+	if userIdentityRepository == nil {
+		userIdentityRepository = &UserIdentityRepositoryImpl{}
+	}
+	return userIdentityRepository
+}
+
+func (ur *UserIdentityRepositoryImpl) Create(db *gorm.DB, ui *models.UserIdentity) error {
+	if err := db.Create(ui).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *UserIdentityRepositoryImpl) GetBySubject(db *gorm.DB, provider, subject string) (*models.UserIdentity, error) {
+	ui := models.UserIdentity{}
+	if err := db.Model(&ui).Where("provider = ? AND subject = ?", provider, subject).First(&ui).Error; err != nil {
+		return nil, err
+	}
+	return &ui, nil
+}
+
+func (ur *UserIdentityRepositoryImpl) List(db *gorm.DB, userID string) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	if err := db.Model(&models.UserIdentity{}).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (ur *UserIdentityRepositoryImpl) Touch(db *gorm.DB, ID, email string, isEmailVerified bool, at time.Time) error {
+	ui := models.UserIdentity{}
+	if err := db.Table(ui.TableName()).
+		Where("id = ?", ID).
+		Select("email, is_email_verified, last_login_at, updated_at").
+		Updates(map[string]interface{}{
+			"email":             email,
+			"is_email_verified": isEmailVerified,
+			"last_login_at":     at,
+			"updated_at":        at,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *UserIdentityRepositoryImpl) Delete(db *gorm.DB, userID, ID string) error {
+	q := db.Where("user_id = ? AND id = ?", userID, ID).Delete(&models.UserIdentity{})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (ur *UserIdentityRepositoryImpl) CreateState(db *gorm.DB, s *models.OIDCState) error {
+	if err := db.Create(s).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *UserIdentityRepositoryImpl) GetState(db *gorm.DB, stateHash string) (*models.OIDCState, error) {
+	s := models.OIDCState{}
+	if err := db.Model(&s).Where("state_hash = ?", stateHash).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (ur *UserIdentityRepositoryImpl) DeleteState(db *gorm.DB, ID string) error {
+	if err := db.Where("id = ?", ID).Delete(&models.OIDCState{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *UserIdentityRepositoryImpl) DeleteExpiredStates(db *gorm.DB, before time.Time) error {
+	if err := db.Where("expires_at < ?", before).Delete(&models.OIDCState{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
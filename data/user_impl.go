@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/jinzhu/gorm"
 	"github.com/shopicano/shopicano-backend/models"
-	"github.com/shopicano/shopicano-backend/utils"
 	"time"
 )
 
@@ -37,57 +36,10 @@ func (uu *UserRepositoryImpl) Login(db *gorm.DB, email string) (*models.User, er
 	return &u, nil
 }
 
-func (uu *UserRepositoryImpl) CreateSession(db *gorm.DB, s *models.Session) error {
-	if err := db.Model(&s).Create(&s).Error; err != nil {
-		return err
-	}
-	return nil
-}
-
-func (uu *UserRepositoryImpl) Logout(db *gorm.DB, token string) error {
-	s := models.Session{}
-
-	if err := db.Model(&s).Where("access_token = ?", token).Delete(&s).Error; err != nil {
-		return err
-	}
-	return nil
-}
-
-func (uu *UserRepositoryImpl) RefreshToken(db *gorm.DB, token string) (*models.Session, error) {
-	os := models.Session{}
-
-	if err := db.Model(&os).Where("refresh_token = ?", token).First(&os).Error; err != nil {
-		return nil, err
-	}
-
-	s := models.Session{
-		ID:           utils.NewUUID(),
-		UserID:       os.UserID,
-		AccessToken:  utils.NewToken(),
-		RefreshToken: utils.NewToken(),
-		CreatedAt:    time.Now().UTC(),
-		ExpireOn:     time.Now().Add(time.Hour * 48).Unix(),
-	}
-
-	if err := db.Model(&s).Create(&s).Error; err != nil {
-		return nil, err
-	}
-
-	if err := db.Model(&os).Where("refresh_token = ?", token).Delete(&os).Error; err != nil {
-		return nil, err
-	}
-
-	if err := db.Commit().Error; err != nil {
-		return nil, err
-	}
-
-	return &s, nil
-}
-
 func (uu *UserRepositoryImpl) Update(db *gorm.DB, u *models.User) error {
 	if err := db.Table(u.TableName()).
 		Where("id = ?", u.ID).
-		Select("name, profile_picture, phone, password, reset_password_token, reset_password_token_generated_at, verification_token, is_email_verified, status, permission_id, updated_at").
+		Select("name, profile_picture, phone, password, reset_password_token, reset_password_token_generated_at, verification_token, is_email_verified, status, permission_id, locale, updated_at").
 		Updates(map[string]interface{}{
 			"name":                              u.Name,
 			"profile_picture":                   u.ProfilePicture,
@@ -99,6 +51,7 @@ func (uu *UserRepositoryImpl) Update(db *gorm.DB, u *models.User) error {
 			"is_email_verified":                 u.IsEmailVerified,
 			"status":                            u.Status,
 			"permission_id":                     u.PermissionID,
+			"locale":                            u.Locale,
 			"updated_at":                        u.UpdatedAt,
 		}).Error; err != nil {
 		return err
@@ -106,26 +59,6 @@ func (uu *UserRepositoryImpl) Update(db *gorm.DB, u *models.User) error {
 	return nil
 }
 
-func (uu *UserRepositoryImpl) GetPermission(db *gorm.DB, token string) (string, *models.Permission, error) {
-	s := models.Session{}
-	u := models.User{}
-	up := models.UserPermission{}
-
-	result := struct {
-		ID         string             `json:"id"`
-		Permission *models.Permission `json:"permission"`
-	}{}
-
-	if err := db.Table(fmt.Sprintf("%s AS s", s.TableName())).
-		Select("u.id, up.permission").
-		Joins(fmt.Sprintf("JOIN %s AS u ON u.id = s.user_id", u.TableName())).
-		Joins(fmt.Sprintf("JOIN %s AS up ON u.permission_id = up.id", up.TableName())).
-		Where("s.access_token = ? AND u.status = ?", token, models.UserActive).Scan(&result).Error; err != nil {
-		return "", nil, err
-	}
-	return result.ID, result.Permission, nil
-}
-
 func (uu *UserRepositoryImpl) GetPermissionByUserID(db *gorm.DB, userID string) (string, *models.Permission, error) {
 	u := models.User{}
 	up := models.UserPermission{}
@@ -201,3 +134,30 @@ func (uu *UserRepositoryImpl) Search(db *gorm.DB, query string, from, limit int)
 	}
 	return users, nil
 }
+
+// Anonymize replaces the personal data of the user, the row is kept for the orders referring to it
+func (uu *UserRepositoryImpl) Anonymize(db *gorm.DB, userID string, at time.Time) error {
+	u := models.User{}
+	q := db.Table(u.TableName()).
+		Where("id = ?", userID).
+		Select("name, email, profile_picture, phone, password, verification_token, reset_password_token, reset_password_token_generated_at, status, updated_at").
+		Updates(map[string]interface{}{
+			"name":                              "Deleted user",
+			"email":                             fmt.Sprintf("deleted-%s@deleted.invalid", userID),
+			"profile_picture":                   nil,
+			"phone":                             nil,
+			"password":                          "",
+			"verification_token":                nil,
+			"reset_password_token":              nil,
+			"reset_password_token_generated_at": nil,
+			"status":                            models.UserDeleted,
+			"updated_at":                        at,
+		})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+// WebhookRepository scopes webhooks by store, a nil store ID stands for the platform webhooks
+type WebhookRepository interface {
+	Create(db *gorm.DB, w *models.Webhook) error
+	Update(db *gorm.DB, w *models.Webhook) error
+	Delete(db *gorm.DB, storeID *string, ID string) error
+	Get(db *gorm.DB, storeID *string, ID string) (*models.Webhook, error)
+	GetByID(db *gorm.DB, ID string) (*models.Webhook, error)
+	List(db *gorm.DB, storeID *string, from, limit int) ([]models.Webhook, error)
+	SetEvents(db *gorm.DB, webhookID string, events []models.WebhookEvent) error
+	ListEvents(db *gorm.DB, webhookID string) ([]models.WebhookEvent, error)
+	ListSubscribed(db *gorm.DB, storeID *string, event models.WebhookEvent) ([]models.Webhook, error)
+
+	CreateDelivery(db *gorm.DB, wd *models.WebhookDelivery) error
+	UpdateDelivery(db *gorm.DB, wd *models.WebhookDelivery) error
+	GetDelivery(db *gorm.DB, ID string) (*models.WebhookDelivery, error)
+	ListDeliveries(db *gorm.DB, webhookID string, from, limit int) ([]models.WebhookDelivery, error)
+	ListDueDeliveries(db *gorm.DB, now time.Time, limit int) ([]models.WebhookDelivery, error)
+}
@@ -0,0 +1,209 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type WebhookRepositoryImpl struct {
+}
+
+var webhookRepository WebhookRepository
+
+func NewWebhookRepository() WebhookRepository {
+	if webhookRepository == nil {
+		webhookRepository = &WebhookRepositoryImpl{}
+	}
+	return webhookRepository
+}
+
+func scopeWebhookStore(db *gorm.DB, storeID *string) *gorm.DB {
+	if storeID == nil {
+		return db.Where("store_id IS NULL")
+	}
+	return db.Where("store_id = ?", *storeID)
+}
+
+func (wu *WebhookRepositoryImpl) Create(db *gorm.DB, w *models.Webhook) error {
+	if err := db.Table(w.TableName()).Create(w).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wu *WebhookRepositoryImpl) Update(db *gorm.DB, w *models.Webhook) error {
+	if err := db.Table(w.TableName()).
+		Where("id = ?", w.ID).
+		Select("url, is_active, updated_at").
+		Updates(map[string]interface{}{
+			"url":        w.URL,
+			"is_active":  w.IsActive,
+			"updated_at": w.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wu *WebhookRepositoryImpl) Delete(db *gorm.DB, storeID *string, ID string) error {
+	w := models.Webhook{}
+	q := scopeWebhookStore(db.Table(w.TableName()), storeID).
+		Where("id = ?", ID).
+		Delete(&w)
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (wu *WebhookRepositoryImpl) Get(db *gorm.DB, storeID *string, ID string) (*models.Webhook, error) {
+	w := models.Webhook{}
+	if err := scopeWebhookStore(db.Table(w.TableName()), storeID).
+		Where("id = ?", ID).
+		First(&w).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (wu *WebhookRepositoryImpl) GetByID(db *gorm.DB, ID string) (*models.Webhook, error) {
+	w := models.Webhook{}
+	if err := db.Table(w.TableName()).
+		Where("id = ?", ID).
+		First(&w).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (wu *WebhookRepositoryImpl) List(db *gorm.DB, storeID *string, from, limit int) ([]models.Webhook, error) {
+	w := models.Webhook{}
+	var data []models.Webhook
+	if err := scopeWebhookStore(db.Table(w.TableName()), storeID).
+		Order("created_at DESC").
+		Offset(from).Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (wu *WebhookRepositoryImpl) SetEvents(db *gorm.DB, webhookID string, events []models.WebhookEvent) error {
+	ws := models.WebhookSubscription{}
+	if err := db.Table(ws.TableName()).
+		Where("webhook_id = ?", webhookID).
+		Delete(&ws).Error; err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		s := models.WebhookSubscription{
+			WebhookID: webhookID,
+			Event:     e,
+		}
+		if err := db.Table(ws.TableName()).Create(&s).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (wu *WebhookRepositoryImpl) ListEvents(db *gorm.DB, webhookID string) ([]models.WebhookEvent, error) {
+	ws := models.WebhookSubscription{}
+	var events []models.WebhookEvent
+	if err := db.Table(ws.TableName()).
+		Where("webhook_id = ?", webhookID).
+		Order("event ASC").
+		Pluck("event", &events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListSubscribed returns the active webhooks of the store and of the platform subscribed to the event
+func (wu *WebhookRepositoryImpl) ListSubscribed(db *gorm.DB, storeID *string, event models.WebhookEvent) ([]models.Webhook, error) {
+	w := models.Webhook{}
+	ws := models.WebhookSubscription{}
+
+	q := db.Table(w.TableName()+" AS w").
+		Select("w.*").
+		Joins("JOIN "+ws.TableName()+" AS ws ON ws.webhook_id = w.id").
+		Where("w.is_active = ? AND ws.event = ?", true, event)
+	if storeID == nil {
+		q = q.Where("w.store_id IS NULL")
+	} else {
+		q = q.Where("w.store_id IS NULL OR w.store_id = ?", *storeID)
+	}
+
+	var data []models.Webhook
+	if err := q.Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (wu *WebhookRepositoryImpl) CreateDelivery(db *gorm.DB, wd *models.WebhookDelivery) error {
+	if err := db.Table(wd.TableName()).Create(wd).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wu *WebhookRepositoryImpl) UpdateDelivery(db *gorm.DB, wd *models.WebhookDelivery) error {
+	if err := db.Table(wd.TableName()).
+		Where("id = ?", wd.ID).
+		Select("status, attempts, response_status, error, duration, next_attempt_at, delivered_at, updated_at").
+		Updates(map[string]interface{}{
+			"status":          wd.Status,
+			"attempts":        wd.Attempts,
+			"response_status": wd.ResponseStatus,
+			"error":           wd.Error,
+			"duration":        wd.Duration,
+			"next_attempt_at": wd.NextAttemptAt,
+			"delivered_at":    wd.DeliveredAt,
+			"updated_at":      wd.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wu *WebhookRepositoryImpl) GetDelivery(db *gorm.DB, ID string) (*models.WebhookDelivery, error) {
+	wd := models.WebhookDelivery{}
+	if err := db.Table(wd.TableName()).
+		Where("id = ?", ID).
+		First(&wd).Error; err != nil {
+		return nil, err
+	}
+	return &wd, nil
+}
+
+func (wu *WebhookRepositoryImpl) ListDeliveries(db *gorm.DB, webhookID string, from, limit int) ([]models.WebhookDelivery, error) {
+	wd := models.WebhookDelivery{}
+	var data []models.WebhookDelivery
+	if err := db.Table(wd.TableName()).
+		Where("webhook_id = ?", webhookID).
+		Order("created_at DESC").
+		Offset(from).Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (wu *WebhookRepositoryImpl) ListDueDeliveries(db *gorm.DB, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	wd := models.WebhookDelivery{}
+	var data []models.WebhookDelivery
+	if err := db.Table(wd.TableName()).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -16,6 +16,13 @@ const (
 	ExceedMaxProductQuantity                      ErrorCode = "400012"
 	PaymentMethodMustBeOnlineForDigitalProducts   ErrorCode = "400013"
 	PayoutAmountInvalid                           ErrorCode = "400014"
+	InvalidBundleItem                             ErrorCode = "400015"
+	TwoFactorNotEnabled                           ErrorCode = "400018"
+	ShippingMethodNotOfferedByStore               ErrorCode = "400016"
+	CreditNoteNotAvailable                        ErrorCode = "400017"
+	StoreNotSelected                              ErrorCode = "400019"
+	StaffInvitationInvalid                        ErrorCode = "400020"
+	DataExportNotReady                            ErrorCode = "400021"
 	StoreCreationDataInvalid                      ErrorCode = "422001"
 	UserLoginDataInvalid                          ErrorCode = "422002"
 	UserSignUpDataInvalid                         ErrorCode = "422003"
@@ -39,6 +46,25 @@ const (
 	PayoutMethodDataInvalid                       ErrorCode = "422021"
 	PayoutSettingsDataInvalid                     ErrorCode = "422022"
 	PayoutEntryDataInvalid                        ErrorCode = "422023"
+	GlobalCategoryDataInvalid                     ErrorCode = "422024"
+	ScheduledPriceDataInvalid                     ErrorCode = "422025"
+	BundleItemsDataInvalid                        ErrorCode = "422026"
+	ShippingZoneDataInvalid                       ErrorCode = "422027"
+	TaxClassDataInvalid                           ErrorCode = "422028"
+	TaxRateDataInvalid                            ErrorCode = "422029"
+	EmailTemplateDataInvalid                      ErrorCode = "422030"
+	EmailBrandingDataInvalid                      ErrorCode = "422031"
+	NotificationPreferenceDataInvalid             ErrorCode = "422032"
+	PushDeviceDataInvalid                         ErrorCode = "422033"
+	WebhookDataInvalid                            ErrorCode = "422034"
+	TwoFactorDataInvalid                          ErrorCode = "422035"
+	OIDCDataInvalid                               ErrorCode = "422036"
+	StoreRoleDataInvalid                          ErrorCode = "422037"
+	StaffInvitationDataInvalid                    ErrorCode = "422038"
+	StoreAPIKeyDataInvalid                        ErrorCode = "422039"
+	AccountUnlockDataInvalid                      ErrorCode = "422040"
+	AccountDeletionDataInvalid                    ErrorCode = "422041"
+	TaxExemptionDataInvalid                       ErrorCode = "422042"
 	StoreCreationQueryFailed                      ErrorCode = "500001"
 	DatabaseQueryFailed                           ErrorCode = "500002"
 	PasswordEncryptionFailed                      ErrorCode = "500003"
@@ -50,6 +76,10 @@ const (
 	PaymentGatewayFailed                          ErrorCode = "500009"
 	PaymentProcessingFailed                       ErrorCode = "500009"
 	FailedToEnqueueTask                           ErrorCode = "500010"
+	InvoiceGenerationFailed                       ErrorCode = "500011"
+	EmailTemplateRenderFailed                     ErrorCode = "500012"
+	EmailDeliveryFailed                           ErrorCode = "500013"
+	OIDCProviderFailed                            ErrorCode = "500014"
 	StoreAlreadyExists                            ErrorCode = "409001"
 	StoreMemberAlreadyExists                      ErrorCode = "409002"
 	CategoryAlreadyExists                         ErrorCode = "409003"
@@ -67,6 +97,17 @@ const (
 	UserAlreadyStaff                              ErrorCode = "409015"
 	BusinessAccountTypeAlreadyExists              ErrorCode = "409016"
 	PayoutMethodAlreadyExists                     ErrorCode = "409017"
+	GlobalCategoryAlreadyExists                   ErrorCode = "409018"
+	ShippingZoneAlreadyExists                     ErrorCode = "409019"
+	TaxClassAlreadyExists                         ErrorCode = "409020"
+	EmailTemplateAlreadyExists                    ErrorCode = "409021"
+	TwoFactorAlreadyEnabled                       ErrorCode = "409022"
+	UserIdentityAlreadyLinked                     ErrorCode = "409023"
+	StoreRoleAlreadyExists                        ErrorCode = "409024"
+	StaffInvitationAlreadyExists                  ErrorCode = "409025"
+	DataExportInProgress                          ErrorCode = "409026"
+	AccountDeletionAlreadyRequested               ErrorCode = "409027"
+	GlobalCategoryInUse                           ErrorCode = "409028"
 	UserHasAStore                                 ErrorCode = "403001"
 	UserSignUpDisabled                            ErrorCode = "403002"
 	StoreCreationDisabled                         ErrorCode = "403003"
@@ -80,6 +121,13 @@ const (
 	StoreNotActive                                ErrorCode = "403012"
 	UserNotActive                                 ErrorCode = "403013"
 	UnauthorizedStoreAccess                       ErrorCode = "403014"
+	ReviewerNotVerifiedPurchaser                  ErrorCode = "403015"
+	TwoFactorRequired                             ErrorCode = "403016"
+	OIDCEmailNotVerified                          ErrorCode = "403017"
+	StaffInvitationEmailMismatch                  ErrorCode = "403018"
+	AccountOwnsStore                              ErrorCode = "403019"
+	StaffSelfChange                               ErrorCode = "403020"
+	StaffGrantExceeded                            ErrorCode = "403021"
 	StoreNotFound                                 ErrorCode = "404001"
 	SettingsNotFound                              ErrorCode = "404002"
 	UserNotFound                                  ErrorCode = "404003"
@@ -102,8 +150,42 @@ const (
 	PayoutMethodNotFound                          ErrorCode = "404020"
 	PayoutSettingsNotFound                        ErrorCode = "404021"
 	PayoutEntryNotFound                           ErrorCode = "404022"
+	GlobalCategoryNotFound                        ErrorCode = "404023"
+	ReviewNotFound                                ErrorCode = "404024"
+	ScheduledPriceNotFound                        ErrorCode = "404025"
+	ShippingZoneNotFound                          ErrorCode = "404026"
+	TaxClassNotFound                              ErrorCode = "404027"
+	TaxRateNotFound                               ErrorCode = "404028"
+	EmailTemplateNotFound                         ErrorCode = "404029"
+	EmailNotFound                                 ErrorCode = "404030"
+	PushDeviceNotFound                            ErrorCode = "404031"
+	WebhookNotFound                               ErrorCode = "404032"
+	WebhookDeliveryNotFound                       ErrorCode = "404033"
+	SessionNotFound                               ErrorCode = "404034"
+	OIDCProviderNotFound                          ErrorCode = "404035"
+	UserIdentityNotFound                          ErrorCode = "404036"
+	StoreRoleNotFound                             ErrorCode = "404037"
+	StaffInvitationNotFound                       ErrorCode = "404038"
+	StoreAPIKeyNotFound                           ErrorCode = "404039"
+	DataExportNotFound                            ErrorCode = "404040"
+	AccountDeletionNotFound                       ErrorCode = "404041"
+	TaxExemptionNotFound                          ErrorCode = "404042"
 	LoginCredentialsInvalid                       ErrorCode = "401001"
 	VerificationTokenIsInvalid                    ErrorCode = "401002"
 	UnauthorizedRequest                           ErrorCode = "401004"
 	InvalidAuthorizationToken                     ErrorCode = "401005"
+	SessionRevoked                                ErrorCode = "401006"
+	RefreshTokenInvalid                           ErrorCode = "401007"
+	RefreshTokenReused                            ErrorCode = "401008"
+	TwoFactorCodeInvalid                          ErrorCode = "401009"
+	TwoFactorChallengeInvalid                     ErrorCode = "401010"
+	OIDCStateInvalid                              ErrorCode = "401011"
+	OIDCTokenInvalid                              ErrorCode = "401012"
+	StoreAPIKeyInvalid                            ErrorCode = "401013"
+	UnlockTokenInvalid                            ErrorCode = "401014"
+	AccountDeletionTokenInvalid                   ErrorCode = "401015"
+	TooManyLoginAttempts                          ErrorCode = "429001"
+	AccountLocked                                 ErrorCode = "429002"
+	TooManyResetPasswordRequests                  ErrorCode = "429003"
+	RateLimitExceeded                             ErrorCode = "429004"
 )
@@ -9,6 +9,10 @@ func IsRecordNotFoundError(err error) bool {
 	return strings.Contains(err.Error(), "record not found")
 }
 
+func IsForeignKeyViolationError(err error) bool {
+	return strings.Contains(err.Error(), "violates foreign key constraint")
+}
+
 func IsDuplicateKeyError(err error) (string, bool) {
 	ok := strings.Contains(err.Error(), "duplicate key")
 
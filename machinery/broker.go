@@ -0,0 +1,53 @@
+package machinery
+
+import (
+	"github.com/RichardKnop/machinery/v1"
+	cfg "github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/log"
+	"os"
+)
+
+const (
+	BrokerRabbitMQ  = "rabbitmq"
+	BrokerRedis     = "redis"
+	BrokerInProcess = "in_process"
+)
+
+// NewConnection sets the task server up on the broker selected in config.
+// RabbitMQConnection returns it whatever the broker is.
+func NewConnection() error {
+	switch cfg.TaskBroker().Driver {
+	case BrokerRedis:
+		return NewRedisConnection()
+	case BrokerInProcess:
+		return NewInProcessConnection()
+	}
+	return NewRabbitMQConnection()
+}
+
+// IsInProcess tells whether the tasks are run by serve instead of a separate worker
+func IsInProcess() bool {
+	return cfg.TaskBroker().Driver == BrokerInProcess
+}
+
+// RunWorker consumes the tasks of the configured broker, it blocks until the worker quits
+func RunWorker() {
+	var w *machinery.Worker
+
+	switch cfg.TaskBroker().Driver {
+	case BrokerRedis:
+		cnf := cfg.TaskBroker().Redis.Worker
+		w = RabbitMQConnection().NewWorker(cnf.Name, cnf.Count)
+	case BrokerInProcess:
+		w = RabbitMQConnection().NewWorker("in-process", cfg.TaskBroker().InProcess.Concurrency)
+	default:
+		RunRabbitMQWorker()
+		return
+	}
+
+	worker = w
+	if err := worker.Launch(); err != nil {
+		log.Log().Errorln("Couldn't launch worker", err)
+		os.Exit(-1)
+	}
+}
@@ -0,0 +1,108 @@
+package machinery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/RichardKnop/machinery/v1"
+	nullbackend "github.com/RichardKnop/machinery/v1/backends/null"
+	"github.com/RichardKnop/machinery/v1/brokers/iface"
+	"github.com/RichardKnop/machinery/v1/common"
+	"github.com/RichardKnop/machinery/v1/config"
+	"github.com/RichardKnop/machinery/v1/tasks"
+	cfg "github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/log"
+	"sync"
+	"time"
+)
+
+// inProcessBroker keeps the tasks in memory and runs them on goroutines of the same process.
+// Queued tasks are lost when the process stops, it's meant for development and single node setups.
+type inProcessBroker struct {
+	common.Broker
+	queue chan *tasks.Signature
+}
+
+func newInProcessBroker(cnf *config.Config, size int) *inProcessBroker {
+	return &inProcessBroker{
+		Broker: common.NewBroker(cnf),
+		queue:  make(chan *tasks.Signature, size),
+	}
+}
+
+func (b *inProcessBroker) StartConsuming(consumerTag string, concurrency int, p iface.TaskProcessor) (bool, error) {
+	b.Broker.StartConsuming(consumerTag, concurrency, p)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-b.GetStopChan():
+					return
+				case s := <-b.queue:
+					if err := p.Process(s); err != nil {
+						log.Log().Errorln("Failed to process task", s.Name, ":", err)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return false, nil
+}
+
+// Publish queues a copy of the task, the same way a remote broker would hand it over.
+// Delayed tasks are queued once their ETA is reached.
+func (b *inProcessBroker) Publish(ctx context.Context, signature *tasks.Signature) error {
+	msg, err := json.Marshal(signature)
+	if err != nil {
+		return err
+	}
+
+	s := &tasks.Signature{}
+	decoder := json.NewDecoder(bytes.NewReader(msg))
+	decoder.UseNumber()
+	if err := decoder.Decode(s); err != nil {
+		return err
+	}
+
+	if s.ETA != nil {
+		if d := time.Until(*s.ETA); d > 0 {
+			time.AfterFunc(d, func() {
+				b.queue <- s
+			})
+			return nil
+		}
+	}
+
+	select {
+	case b.queue <- s:
+		return nil
+	default:
+		return errors.New("in process task queue is full")
+	}
+}
+
+func NewInProcessConnection() error {
+	cnf := &config.Config{
+		Broker:        BrokerInProcess,
+		DefaultQueue:  BrokerInProcess,
+		ResultBackend: "null",
+		// serve handles the signals itself
+		NoUnixSignals: true,
+	}
+
+	machineryServer = machinery.NewServerWithBrokerBackend(cnf,
+		newInProcessBroker(cnf, cfg.TaskBroker().InProcess.QueueSize), nullbackend.New())
+	return nil
+}
@@ -54,6 +54,39 @@ func RegisterRabbitMQTasks() error {
 	if err := machineryServer.RegisterTask(tasks.SendResetPasswordConfirmationEmailTaskName, tasks.SendResetPasswordConfirmationEmailFn); err != nil {
 		return err
 	}
+	if err := machineryServer.RegisterTask(tasks.ApplyScheduledPricesTaskName, tasks.ApplyScheduledPricesFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.RetryOutboxEmailsTaskName, tasks.RetryOutboxEmailsFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.DeliverWebhookTaskName, tasks.DeliverWebhookFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.RetryWebhookDeliveriesTaskName, tasks.RetryWebhookDeliveriesFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.HandleDomainEventTaskName, tasks.HandleDomainEventFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.CleanupTaskName, tasks.CleanupFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.SendStaffInvitationEmailTaskName, tasks.SendStaffInvitationEmailFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.SendAccountLockedEmailTaskName, tasks.SendAccountLockedEmailFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.SendAccountDeletionEmailTaskName, tasks.SendAccountDeletionEmailFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.BuildDataExportTaskName, tasks.BuildDataExportFn); err != nil {
+		return err
+	}
+	if err := machineryServer.RegisterTask(tasks.ProcessAccountDeletionsTaskName, tasks.ProcessAccountDeletionsFn); err != nil {
+		return err
+	}
 	return nil
 }
 
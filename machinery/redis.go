@@ -0,0 +1,29 @@
+package machinery
+
+import (
+	"github.com/RichardKnop/machinery/v1"
+	"github.com/RichardKnop/machinery/v1/config"
+	cfg "github.com/shopicano/shopicano-backend/config"
+)
+
+func NewRedisConnection() error {
+	machineryServer, msErr = machinery.NewServer(&config.Config{
+		Broker:        cfg.TaskBroker().Redis.Broker,
+		DefaultQueue:  cfg.TaskBroker().Redis.DefaultQueue,
+		ResultBackend: cfg.TaskBroker().Redis.ResultBackend,
+		Redis: &config.RedisConfig{
+			MaxIdle:                3,
+			IdleTimeout:            240,
+			ReadTimeout:            15,
+			WriteTimeout:           15,
+			ConnectTimeout:         15,
+			NormalTasksPollPeriod:  1000,
+			DelayedTasksPollPeriod: 500,
+		},
+		ResultsExpireIn: 3600,
+	})
+	if msErr != nil {
+		return msErr
+	}
+	return nil
+}
@@ -19,7 +19,7 @@ func IsPlatformAdmin(next echo.HandlerFunc) echo.HandlerFunc {
 			resp.Title = "Unauthorized to access platform as admin"
 			return resp.ServerJSON(ctx)
 		}
-		return next(ctx)
+		return requireTwoFactor(ctx, next)
 	}
 }
 
@@ -33,6 +33,6 @@ func IsPlatformManager(next echo.HandlerFunc) echo.HandlerFunc {
 			resp.Title = "Unauthorized to access platform as manager"
 			return resp.ServerJSON(ctx)
 		}
-		return next(ctx)
+		return requireTwoFactor(ctx, next)
 	}
 }
@@ -8,9 +8,13 @@ import (
 	"github.com/shopicano/shopicano-backend/core"
 	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"net/http"
 	"strings"
+	"time"
 )
 
 func JWTAuth() echo.MiddlewareFunc {
@@ -18,7 +22,7 @@ func JWTAuth() echo.MiddlewareFunc {
 		return func(ctx echo.Context) error {
 			resp := core.Response{}
 
-			claims, token, err := extractAndValidateToken(ctx)
+			claims, _, err := extractAndValidateToken(ctx)
 			if err != nil {
 				resp.Status = http.StatusUnauthorized
 				resp.Code = errors.InvalidAuthorizationToken
@@ -47,12 +51,13 @@ func JWTAuth() echo.MiddlewareFunc {
 				return resp.ServerJSON(ctx)
 			}
 
-			_, permission, err := userDao.GetPermission(db, token.Raw)
+			su := data.NewSessionRepository()
+			session, err := su.Get(db, claims.SessionID)
 			if err != nil {
 				if errors.IsRecordNotFoundError(err) {
 					resp.Status = http.StatusUnauthorized
 					resp.Code = errors.InvalidAuthorizationToken
-					resp.Title = "Token not found"
+					resp.Title = "Session not found"
 					resp.Errors = err
 					return resp.ServerJSON(ctx)
 				}
@@ -64,7 +69,37 @@ func JWTAuth() echo.MiddlewareFunc {
 				return resp.ServerJSON(ctx)
 			}
 
+			if session.UserID != claims.UserID || !session.IsActive(time.Now()) {
+				resp.Status = http.StatusUnauthorized
+				resp.Code = errors.SessionRevoked
+				resp.Title = "Session is revoked or expired"
+				resp.Errors = errors.NewError("Session is no longer active")
+				return resp.ServerJSON(ctx)
+			}
+
+			_, permission, err := userDao.GetPermissionByUserID(db, claims.UserID)
+			if err != nil {
+				if errors.IsRecordNotFoundError(err) {
+					resp.Status = http.StatusUnauthorized
+					resp.Code = errors.InvalidAuthorizationToken
+					resp.Title = "User not found"
+					resp.Errors = err
+					return resp.ServerJSON(ctx)
+				}
+
+				resp.Status = http.StatusInternalServerError
+				resp.Code = errors.DatabaseQueryFailed
+				resp.Title = "Database query failed"
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			if err := services.TouchSession(db, session, ctx.Request().UserAgent(), ctx.RealIP()); err != nil {
+				log.Log().Errorln(err)
+			}
+
 			ctx.Set(utils.UserID, claims.UserID)
+			ctx.Set(utils.SessionID, claims.SessionID)
 			ctx.Set(utils.Scope, utils.UserScope(claims.Audience))
 			ctx.Set(utils.UserPermission, *permission)
 			ctx.Set(utils.UserStatus, u.Status)
@@ -73,6 +108,82 @@ func JWTAuth() echo.MiddlewareFunc {
 	}
 }
 
+// JWTOrAPIKeyAuth authenticates like JWTAuth and also accepts the api key of a store in the Authorization
+// header, never in the url where it would end up in logs. A key acts as the staff who created it while
+// active but only on its store, with no platform permission, see HasStore.
+func JWTOrAPIKeyAuth() echo.MiddlewareFunc {
+	jwtAuth := JWTAuth()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withJWT := jwtAuth(next)
+
+		return func(ctx echo.Context) error {
+			key := extractTokenFromHeader(ctx)
+			if !services.IsStoreAPIKey(key) {
+				return withJWT(ctx)
+			}
+
+			resp := core.Response{}
+
+			db := app.DB()
+
+			k, err := services.AuthenticateStoreAPIKey(db, key)
+			if err != nil {
+				if err == services.ErrStoreAPIKeyInvalid {
+					resp.Status = http.StatusUnauthorized
+					resp.Code = errors.StoreAPIKeyInvalid
+					resp.Title = "Unauthorized request"
+					resp.Errors = err
+					return resp.ServerJSON(ctx)
+				}
+
+				resp.Status = http.StatusInternalServerError
+				resp.Code = errors.DatabaseQueryFailed
+				resp.Title = "Database query failed"
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			userDao := data.NewUserRepository()
+			u, err := userDao.Get(db, k.CreatedBy)
+			if err != nil {
+				if errors.IsRecordNotFoundError(err) {
+					resp.Status = http.StatusUnauthorized
+					resp.Code = errors.UserNotFound
+					resp.Title = "User not found"
+					resp.Errors = err
+					return resp.ServerJSON(ctx)
+				}
+
+				resp.Status = http.StatusInternalServerError
+				resp.Code = errors.DatabaseQueryFailed
+				resp.Title = "Database query failed"
+				resp.Errors = err
+				return resp.ServerJSON(ctx)
+			}
+
+			if u.Status != models.UserActive {
+				resp.Status = http.StatusForbidden
+				resp.Code = errors.UserNotActive
+				resp.Title = "Creator of the api key isn't active"
+				return resp.ServerJSON(ctx)
+			}
+
+			if err := services.TouchStoreAPIKey(db, k, ctx.RealIP()); err != nil {
+				log.Log().Errorln(err)
+			}
+
+			ctx.Set(utils.UserID, u.ID)
+			ctx.Set(utils.SessionID, "")
+			ctx.Set(utils.Scope, utils.BackStore)
+			ctx.Set(utils.UserPermission, models.UserPerm)
+			ctx.Set(utils.UserStatus, u.Status)
+			ctx.Set(utils.APIKey, k)
+			return next(ctx)
+		}
+	}
+}
+
 func extractTokenFromHeader(ctx echo.Context) string {
 	tokenWithBearer := ctx.Request().Header.Get("Authorization")
 	token := strings.Replace(tokenWithBearer, "Bearer", "", -1)
@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/log"
+	"net"
+	"strings"
+)
+
+// ClientIPExtractor trusts X-Forwarded-For only from the given proxies, the client ip is the nearest
+// untrusted address of the chain. Without proxies the address of the connection is used, so the
+// ip a client sends can't pick its rate limit bucket or login lockout.
+func ClientIPExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, p := range trustedProxies {
+		if !strings.Contains(p, "/") {
+			if strings.Contains(p, ":") {
+				p += "/128"
+			} else {
+				p += "/32"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(p)
+		if err != nil {
+			log.Log().Errorln("Invalid trusted proxy : ", err)
+			continue
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
@@ -0,0 +1,122 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/services"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+)
+
+type rateLimitRoute struct {
+	method string
+	prefix string
+	group  config.RateLimitGroupCfg
+}
+
+// RateLimit spends a token of the bucket of the client on the group of the route and refuses the
+// request once it's empty. The limits are sent back in the RateLimit-* headers.
+func RateLimit() echo.MiddlewareFunc {
+	cfg := config.RateLimit()
+
+	var routes []rateLimitRoute
+	for _, g := range cfg.Groups {
+		for _, p := range g.Paths {
+			r := rateLimitRoute{prefix: p, group: g}
+			if i := strings.Index(p, " "); i > 0 {
+				r.method = strings.ToUpper(p[:i])
+				r.prefix = strings.TrimSpace(p[i+1:])
+			}
+			routes = append(routes, r)
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			// The health check isn't limited
+			if !cfg.Enabled || ctx.Path() == "/" {
+				return next(ctx)
+			}
+
+			g := rateLimitGroup(ctx, routes, cfg.Groups[config.RateLimitDefault])
+			if g.Requests <= 0 {
+				return next(ctx)
+			}
+
+			r := services.GetRateLimiter().Take(g, rateLimitClient(ctx), time.Now())
+
+			h := ctx.Response().Header()
+			h.Set(RateLimitLimitHeader, strconv.Itoa(r.Limit))
+			h.Set(RateLimitRemainingHeader, strconv.Itoa(r.Remaining))
+			h.Set(RateLimitResetHeader, strconv.FormatInt(ceilSeconds(r.Reset), 10))
+
+			if !r.Allowed {
+				h.Set("Retry-After", strconv.FormatInt(ceilSeconds(r.RetryAfter), 10))
+
+				resp := core.Response{}
+				resp.Status = http.StatusTooManyRequests
+				resp.Code = errors.RateLimitExceeded
+				resp.Title = "Too many requests"
+				resp.Errors = errors.NewError("Rate limit exceeded, retry later")
+				return resp.ServerJSON(ctx)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// rateLimitGroup returns the group of the longest path matching the route, or the default group
+func rateLimitGroup(ctx echo.Context, routes []rateLimitRoute, def config.RateLimitGroupCfg) config.RateLimitGroupCfg {
+	path := ctx.Path()
+	method := ctx.Request().Method
+
+	g := def
+	longest := -1
+	for _, r := range routes {
+		if r.method != "" && r.method != method {
+			continue
+		}
+		if strings.HasPrefix(path, r.prefix) && len(r.prefix) > longest {
+			g = r.group
+			longest = len(r.prefix)
+		}
+	}
+	return g
+}
+
+// rateLimitClient identifies the client by its api key or user when the token is valid, otherwise by ip
+func rateLimitClient(ctx echo.Context) string {
+	token := extractToken(ctx)
+
+	if services.IsStoreAPIKey(token) {
+		k, err := services.AuthenticateStoreAPIKey(app.DB(), token)
+		if err == nil {
+			return "api_key:" + k.ID
+		}
+		if err != services.ErrStoreAPIKeyInvalid {
+			log.Log().Errorln(err)
+		}
+	} else if token != "" {
+		if claims, _, err := extractAndValidateToken(ctx); err == nil {
+			return "user:" + claims.UserID
+		}
+	}
+	return "ip:" + ctx.RealIP()
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateLimitClientIgnoresSpoofedForwardedFor(t *testing.T) {
+	cases := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   []string
+		client         string
+	}{
+		{
+			name:         "no trusted proxy",
+			remoteAddr:   "203.0.113.7:4321",
+			forwardedFor: []string{"", "198.51.100.1", "198.51.100.2, 198.51.100.3"},
+			client:       "ip:203.0.113.7",
+		},
+		{
+			name:           "untrusted connection",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "203.0.113.7:4321",
+			forwardedFor:   []string{"", "198.51.100.1", "10.0.0.2"},
+			client:         "ip:203.0.113.7",
+		},
+		{
+			name:           "trusted proxy",
+			trustedProxies: []string{"10.0.0.0/8", "192.0.2.1"},
+			remoteAddr:     "10.0.0.5:4321",
+			forwardedFor:   []string{"203.0.113.7, 192.0.2.1", "198.51.100.1, 203.0.113.7", "10.1.1.1, 203.0.113.7, 192.0.2.1"},
+			client:         "ip:203.0.113.7",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := echo.New()
+			e.IPExtractor = ClientIPExtractor(c.trustedProxies)
+
+			for _, xff := range c.forwardedFor {
+				req := httptest.NewRequest(http.MethodGet, "/v1/products/", nil)
+				req.RemoteAddr = c.remoteAddr
+				if xff != "" {
+					req.Header.Set(echo.HeaderXForwardedFor, xff)
+				}
+				req.Header.Set(echo.HeaderXRealIP, "198.51.100.9")
+
+				ctx := e.NewContext(req, httptest.NewRecorder())
+				if got := rateLimitClient(ctx); got != c.client {
+					t.Errorf("X-Forwarded-For %q: got client %s, want %s", xff, got, c.client)
+				}
+			}
+		})
+	}
+}
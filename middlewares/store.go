@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/shopicano/shopicano-backend/app"
 	"github.com/shopicano/shopicano-backend/core"
@@ -8,10 +9,14 @@ import (
 	"github.com/shopicano/shopicano-backend/errors"
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
 	"github.com/shopicano/shopicano-backend/utils"
 	"net/http"
 )
 
+// HasStore resolves the store the request acts on among the ones the user is a staff of, from the
+// store id in the path or the X-Store-ID header. A user with a single store may leave it out.
+// An api key is bound to its store and acts below the manager level, only through the permissions of its scopes.
 func HasStore() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
@@ -19,17 +24,75 @@ func HasStore() echo.MiddlewareFunc {
 
 			db := app.DB()
 
+			userID := ctx.Get(utils.UserID).(string)
+
+			storeID := ctx.Param("store_id")
+			if storeID == "" {
+				storeID = ctx.Request().Header.Get(utils.StoreIDHeader)
+			}
+
+			k := utils.GetAPIKey(ctx)
+			if k != nil {
+				if storeID == "" {
+					storeID = k.StoreID
+				}
+				if storeID != k.StoreID {
+					resp.Status = http.StatusForbidden
+					resp.Code = errors.UnauthorizedStoreAccess
+					resp.Title = "Unauthorized request"
+					return resp.ServerJSON(ctx)
+				}
+			}
+
 			su := data.NewStoreRepository()
-			store, err := su.GetStoreUserProfile(db, ctx.Get(utils.UserID).(string))
-			if err != nil {
-				log.Log().Errorln(err)
-				if errors.IsRecordNotFoundError(err) {
+
+			var store *models.StaffProfile
+			if storeID == "" {
+				stores, err := su.ListStoreUserProfiles(db, userID)
+				if err != nil {
+					log.Log().Errorln(err)
+
+					resp.Status = http.StatusInternalServerError
+					resp.Code = errors.DatabaseQueryFailed
+					resp.Title = "Database query failed"
+					resp.Errors = err
+					return resp.ServerJSON(ctx)
+				}
+				if len(stores) == 0 {
 					resp.Status = http.StatusNotFound
 					resp.Code = errors.StoreNotFound
 					resp.Title = "Store not found"
 					return resp.ServerJSON(ctx)
 				}
+				if len(stores) > 1 {
+					resp.Status = http.StatusBadRequest
+					resp.Code = errors.StoreNotSelected
+					resp.Title = fmt.Sprintf("Staff of multiple stores, select one with the %s header", utils.StoreIDHeader)
+					return resp.ServerJSON(ctx)
+				}
+				store = &stores[0]
+			} else {
+				var err error
+				store, err = su.GetStoreUserProfile(db, userID, storeID)
+				if err != nil {
+					log.Log().Errorln(err)
+					if errors.IsRecordNotFoundError(err) {
+						resp.Status = http.StatusForbidden
+						resp.Code = errors.UnauthorizedStoreAccess
+						resp.Title = "Unauthorized request"
+						return resp.ServerJSON(ctx)
+					}
+
+					resp.Status = http.StatusInternalServerError
+					resp.Code = errors.DatabaseQueryFailed
+					resp.Title = "Database query failed"
+					resp.Errors = err
+					return resp.ServerJSON(ctx)
+				}
+			}
 
+			perms, err := services.GetStaffPermissions(db, store.StaffPermission, store.StaffRoleID)
+			if err != nil {
 				resp.Status = http.StatusInternalServerError
 				resp.Code = errors.DatabaseQueryFailed
 				resp.Title = "Database query failed"
@@ -37,19 +100,15 @@ func HasStore() echo.MiddlewareFunc {
 				return resp.ServerJSON(ctx)
 			}
 
-			storeID := ctx.Param("store_id")
-			if storeID != "" && storeID != store.StoreID {
-				resp.Status = http.StatusForbidden
-				resp.Code = errors.UnauthorizedStoreAccess
-				resp.Title = "Unauthorized request"
-				return resp.ServerJSON(ctx)
+			level := store.StaffPermission
+			if k != nil {
+				perms = services.StoreAPIKeyPermissions(perms, k)
+				level = models.UserPerm
 			}
 
-			log.Log().Infoln(store.StaffPermission)
-			log.Log().Infoln(store.StoreStatus)
-
 			ctx.Set(utils.StoreID, store.StoreID)
-			ctx.Set(utils.StorePermission, store.StaffPermission)
+			ctx.Set(utils.StorePermission, level)
+			ctx.Set(utils.StorePerms, perms)
 			ctx.Set(utils.StoreStatus, store.StoreStatus)
 			return next(ctx)
 		}
@@ -101,6 +160,27 @@ func IsStoreAdmin() echo.MiddlewareFunc {
 				resp.Title = "Unauthorized to access store as admin"
 				return resp.ServerJSON(ctx)
 			}
+			return requireTwoFactor(ctx, next)
+		}
+	}
+}
+
+// HasStorePermission lets the staff through when granted the permission, by its level or its role.
+// Store admins and the sensitive permissions go through the two factor requirement.
+func HasStorePermission(perm models.StorePerm) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			resp := core.Response{}
+
+			if !utils.HasStorePerm(ctx, perm) {
+				resp.Status = http.StatusForbidden
+				resp.Code = errors.UnauthorizedStoreAccess
+				resp.Title = fmt.Sprintf("Unauthorized to access store without %s permission", perm)
+				return resp.ServerJSON(ctx)
+			}
+			if utils.GetStorePermission(ctx) == models.AdminPerm || perm.RequiresTwoFactor() {
+				return requireTwoFactor(ctx, next)
+			}
 			return next(ctx)
 		}
 	}
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/core"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"net/http"
+)
+
+// requireTwoFactor lets a privileged user through only with two factor authentication enabled,
+// when the platform requires it. Enrolling stays possible as it's not behind a privileged route.
+func requireTwoFactor(ctx echo.Context, next echo.HandlerFunc) error {
+	resp := core.Response{}
+
+	db := app.DB()
+
+	pu := data.NewMarketplaceRepository()
+	settings, err := pu.GetSettings(db)
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Title = "Database query failed"
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	if !settings.IsTwoFactorRequired {
+		return next(ctx)
+	}
+
+	ok, err := services.IsTwoFactorEnabled(db, utils.GetUserID(ctx))
+	if err != nil {
+		resp.Status = http.StatusInternalServerError
+		resp.Code = errors.DatabaseQueryFailed
+		resp.Title = "Database query failed"
+		resp.Errors = err
+		return resp.ServerJSON(ctx)
+	}
+	if !ok {
+		resp.Status = http.StatusForbidden
+		resp.Code = errors.TwoFactorRequired
+		resp.Title = "Two factor authentication must be enabled"
+		return resp.ServerJSON(ctx)
+	}
+	return next(ctx)
+}
@@ -13,6 +13,8 @@ type Address struct {
 	State     string    `json:"state" gorm:"column:state"`
 	City      string    `json:"city" gorm:"column:city;not null"`
 	CountryID int64     `json:"country_id" gorm:"column:country_id;not null"`
+	StateID   *int64    `json:"state_id,omitempty" gorm:"column:state_id"`
+	CityID    *int64    `json:"city_id,omitempty" gorm:"column:city_id"`
 	Postcode  string    `json:"postcode" gorm:"column:postcode;not null"`
 	Email     string    `json:"email,omitempty" gorm:"column:email"`
 	Phone     string    `json:"phone,omitempty" gorm:"column:phone"`
@@ -31,5 +33,19 @@ func (a *Address) ForeignKeys() []string {
 	return []string{
 		fmt.Sprintf("user_id;%s(id);RESTRICT;RESTRICT", u.TableName()),
 		fmt.Sprintf("country_id;%s(id);RESTRICT;RESTRICT", l.TableName()),
+		fmt.Sprintf("state_id;%s(id);RESTRICT;RESTRICT", l.TableName()),
+		fmt.Sprintf("city_id;%s(id);RESTRICT;RESTRICT", l.TableName()),
 	}
 }
+
+// LocationIDs returns the locations of the address from the most specific to the least
+func (a *Address) LocationIDs() []int64 {
+	var ids []int64
+	if a.CityID != nil {
+		ids = append(ids, *a.CityID)
+	}
+	if a.StateID != nil {
+		ids = append(ids, *a.StateID)
+	}
+	return append(ids, a.CountryID)
+}
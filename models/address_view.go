@@ -17,6 +17,8 @@ type AddressView struct {
 	Postcode  string    `json:"postcode,omitempty"`
 	Email     string    `json:"email,omitempty"`
 	Phone     string    `json:"phone,omitempty"`
+	StateID   *int64    `json:"state_id,omitempty"`
+	CityID    *int64    `json:"city_id,omitempty"`
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
 }
@@ -28,7 +30,7 @@ func (av *AddressView) TableName() string {
 func (av *AddressView) CreateView(tx *gorm.DB) error {
 	sql := fmt.Sprintf("CREATE OR REPLACE VIEW %s AS SELECT a.id AS id, a.user_id AS user_id, a.name AS name, a.address AS address, a.city AS city,"+
 		" loc.name AS country, a.state AS state, a.postcode AS postcode, a.email AS email, a.phone AS phone, a.created_at AS created_at,"+
-		" a.updated_at AS updated_at"+
+		" a.updated_at AS updated_at, a.state_id AS state_id, a.city_id AS city_id"+
 		" FROM addresses AS a"+
 		" LEFT JOIN locations AS loc ON a.country_id = loc.id", av.TableName())
 	if err := tx.Exec(sql).Error; err != nil {
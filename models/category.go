@@ -6,14 +6,15 @@ import (
 )
 
 type Category struct {
-	ID          string    `json:"id" gorm:"column:id;unique;not null"`
-	Name        string    `json:"name" gorm:"column:name;primary_key"`
-	StoreID     string    `json:"-" gorm:"column:store_id;primary_key"`
-	Description string    `json:"description" gorm:"column:description;not null"`
-	Image       string    `json:"image" gorm:"column:image;not null"`
-	IsPublished bool      `json:"is_published" gorm:"column:is_published;index;not null"`
-	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at;index;not null"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at"`
+	ID               string    `json:"id" gorm:"column:id;unique;not null"`
+	Name             string    `json:"name" gorm:"column:name;primary_key"`
+	StoreID          string    `json:"-" gorm:"column:store_id;primary_key"`
+	GlobalCategoryID *string   `json:"global_category_id,omitempty" gorm:"column:global_category_id;index"`
+	Description      string    `json:"description" gorm:"column:description;not null"`
+	Image            string    `json:"image" gorm:"column:image;not null"`
+	IsPublished      bool      `json:"is_published" gorm:"column:is_published;index;not null"`
+	CreatedAt        time.Time `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"column:updated_at"`
 }
 
 func (c *Category) TableName() string {
@@ -22,9 +23,11 @@ func (c *Category) TableName() string {
 
 func (c *Category) ForeignKeys() []string {
 	s := Store{}
+	gc := GlobalCategory{}
 
 	return []string{
 		fmt.Sprintf("store_id;%s(id);RESTRICT;RESTRICT", s.TableName()),
+		fmt.Sprintf("global_category_id;%s(id);RESTRICT;RESTRICT", gc.TableName()),
 	}
 }
 
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	EventOrderCreated              DomainEventType = "order.created"
+	EventOrderStatusChanged        DomainEventType = "order.status_changed"
+	EventOrderPaymentStatusChanged DomainEventType = "order.payment_status_changed"
+	EventPaymentCompleted          DomainEventType = "payment.completed"
+	EventPaymentReverted           DomainEventType = "payment.reverted"
+	EventProductCreated            DomainEventType = "product.created"
+	EventProductUpdated            DomainEventType = "product.updated"
+	EventCategoryRenamed           DomainEventType = "category.renamed"
+	EventCollectionProductsChanged DomainEventType = "collection.products_changed"
+	EventPayoutUpdated             DomainEventType = "payout.updated"
+	EventStaffInvited              DomainEventType = "staff_invitation.created"
+	EventDataExportRequested       DomainEventType = "data_export.requested"
+	EventAccountDeletionRequested  DomainEventType = "account_deletion.requested"
+)
+
+type DomainEventType string
+
+// DomainEvent is written in the transaction of the change it describes and published to the
+// subscribers by the relay afterwards, so side effects never run for a rolled back change
+type DomainEvent struct {
+	ID          string          `json:"id" gorm:"column:id;primary_key"`
+	Type        DomainEventType `json:"type" gorm:"column:type;index;not null"`
+	AggregateID string          `json:"aggregate_id" gorm:"column:aggregate_id;index;not null"`
+	Payload     string          `json:"payload" gorm:"column:payload;type:text;not null"`
+	PublishedAt *time.Time      `json:"published_at,omitempty" gorm:"column:published_at;index"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"column:created_at;index;not null"`
+}
+
+func (de *DomainEvent) TableName() string {
+	return "domain_events"
+}
+
+// StatusChange is the payload of the events of a status moving from one value to another
+type StatusChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ProductsChange is the payload of the events touching several products at once
+type ProductsChange struct {
+	ProductIDs []string `json:"product_ids"`
+}
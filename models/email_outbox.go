@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	EmailQueued EmailStatus = "queued"
+	EmailSent   EmailStatus = "sent"
+	EmailFailed EmailStatus = "failed"
+)
+
+type EmailStatus string
+
+func (s EmailStatus) IsValid() bool {
+	for _, v := range []EmailStatus{EmailQueued, EmailSent, EmailFailed} {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Email is an outbox entry of a transactional email, it's kept queued until delivered or out of attempts
+type Email struct {
+	ID            string      `json:"id" gorm:"column:id;primary_key"`
+	Recipient     string      `json:"recipient" gorm:"column:recipient;index;not null"`
+	Subject       string      `json:"subject" gorm:"column:subject;not null"`
+	Body          string      `json:"body,omitempty" gorm:"column:body;type:text;not null"`
+	Template      string      `json:"template" gorm:"column:template;index"`
+	UserID        *string     `json:"user_id,omitempty" gorm:"column:user_id;index"`
+	OrderID       *string     `json:"order_id,omitempty" gorm:"column:order_id;index"`
+	Status        EmailStatus `json:"status" gorm:"column:status;index;not null"`
+	Attempts      int         `json:"attempts" gorm:"column:attempts;not null;default:0"`
+	LastError     string      `json:"last_error,omitempty" gorm:"column:last_error"`
+	NextAttemptAt *time.Time  `json:"next_attempt_at,omitempty" gorm:"column:next_attempt_at;index"`
+	SentAt        *time.Time  `json:"sent_at,omitempty" gorm:"column:sent_at"`
+	CreatedAt     time.Time   `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt     time.Time   `json:"updated_at" gorm:"column:updated_at;not null"`
+
+	Attachments []EmailAttachment  `json:"attachments,omitempty" gorm:"-"`
+	Deliveries  []EmailDeliveryLog `json:"deliveries,omitempty" gorm:"-"`
+}
+
+func (e *Email) TableName() string {
+	return "emails"
+}
+
+func (e *Email) ForeignKeys() []string {
+	u := User{}
+	o := Order{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);SET NULL;RESTRICT", u.TableName()),
+		fmt.Sprintf("order_id;%s(id);SET NULL;RESTRICT", o.TableName()),
+	}
+}
+
+// EmailAttachment refers to a file in minio so that the email can be delivered again later
+type EmailAttachment struct {
+	ID          string `json:"id" gorm:"column:id;primary_key"`
+	EmailID     string `json:"email_id" gorm:"column:email_id;index;not null"`
+	Name        string `json:"name" gorm:"column:name;not null"`
+	ContentType string `json:"content_type" gorm:"column:content_type;not null"`
+	Path        string `json:"-" gorm:"column:path;not null"`
+}
+
+func (ea *EmailAttachment) TableName() string {
+	return "email_attachments"
+}
+
+func (ea *EmailAttachment) ForeignKeys() []string {
+	e := Email{}
+
+	return []string{
+		fmt.Sprintf("email_id;%s(id);CASCADE;RESTRICT", e.TableName()),
+	}
+}
+
+type EmailDeliveryLog struct {
+	ID        string    `json:"id" gorm:"column:id;primary_key"`
+	EmailID   string    `json:"email_id" gorm:"column:email_id;index;not null"`
+	Attempt   int       `json:"attempt" gorm:"column:attempt;not null"`
+	Transport string    `json:"transport" gorm:"column:transport;not null"`
+	IsSuccess bool      `json:"is_success" gorm:"column:is_success;not null"`
+	Error     string    `json:"error,omitempty" gorm:"column:error"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;index;not null"`
+}
+
+func (edl *EmailDeliveryLog) TableName() string {
+	return "email_delivery_logs"
+}
+
+func (edl *EmailDeliveryLog) ForeignKeys() []string {
+	e := Email{}
+
+	return []string{
+		fmt.Sprintf("email_id;%s(id);CASCADE;RESTRICT", e.TableName()),
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	EmailTemplateVerifyEmail               EmailTemplateName = "verify_email"
+	EmailTemplateResetPassword             EmailTemplateName = "reset_password"
+	EmailTemplateResetPasswordConfirmation EmailTemplateName = "reset_password_confirmation"
+	EmailTemplateInvoice                   EmailTemplateName = "invoice"
+	EmailTemplateStaffInvitation           EmailTemplateName = "staff_invitation"
+	EmailTemplateAccountLocked             EmailTemplateName = "account_locked"
+	EmailTemplateAccountDeletionRequested  EmailTemplateName = "account_deletion_requested"
+
+	DefaultLocale = "en"
+)
+
+type EmailTemplateName string
+
+func (n EmailTemplateName) IsValid() bool {
+	for _, v := range []EmailTemplateName{EmailTemplateVerifyEmail, EmailTemplateResetPassword,
+		EmailTemplateResetPasswordConfirmation, EmailTemplateInvoice, EmailTemplateStaffInvitation,
+		EmailTemplateAccountLocked, EmailTemplateAccountDeletionRequested} {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailTemplate overrides the built in template of the given name for a locale,
+// the body is kept in the row or in minio depending on the configured template storage
+type EmailTemplate struct {
+	ID        string            `json:"id" gorm:"column:id;primary_key"`
+	Name      EmailTemplateName `json:"name" gorm:"column:name;not null;unique_index:uix_email_templates_name_locale"`
+	Locale    string            `json:"locale" gorm:"column:locale;not null;unique_index:uix_email_templates_name_locale"`
+	Subject   string            `json:"subject" gorm:"column:subject"`
+	Body      string            `json:"body,omitempty" gorm:"column:body;type:text"`
+	IsActive  bool              `json:"is_active" gorm:"column:is_active;index"`
+	CreatedAt time.Time         `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt time.Time         `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (et *EmailTemplate) TableName() string {
+	return "email_templates"
+}
+
+func (et *EmailTemplate) ObjectPath() string {
+	return fmt.Sprintf("email-templates/%s/%s.html", et.Name, et.Locale)
+}
+
+type StoreEmailBranding struct {
+	StoreID      string    `json:"store_id" gorm:"column:store_id;primary_key"`
+	LogoUrl      string    `json:"logo_url" gorm:"column:logo_url"`
+	PrimaryColor string    `json:"primary_color" gorm:"column:primary_color"`
+	AccentColor  string    `json:"accent_color" gorm:"column:accent_color"`
+	FooterText   string    `json:"footer_text" gorm:"column:footer_text"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (seb *StoreEmailBranding) TableName() string {
+	return "store_email_brandings"
+}
+
+func (seb *StoreEmailBranding) ForeignKeys() []string {
+	s := Store{}
+
+	return []string{
+		fmt.Sprintf("store_id;%s(id);CASCADE;RESTRICT", s.TableName()),
+	}
+}
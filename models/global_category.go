@@ -0,0 +1,28 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type GlobalCategory struct {
+	ID          string    `json:"id" gorm:"column:id;primary_key"`
+	Name        string    `json:"name" gorm:"column:name;not null;index"`
+	Slug        string    `json:"slug" gorm:"column:slug;unique_index;not null"`
+	ParentID    *string   `json:"parent_id,omitempty" gorm:"column:parent_id;index"`
+	Description string    `json:"description" gorm:"column:description"`
+	Image       string    `json:"image" gorm:"column:image"`
+	IsPublished bool      `json:"is_published" gorm:"column:is_published;index;not null;default:false"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (gc *GlobalCategory) TableName() string {
+	return "global_categories"
+}
+
+func (gc *GlobalCategory) ForeignKeys() []string {
+	return []string{
+		fmt.Sprintf("parent_id;%s(id);RESTRICT;RESTRICT", gc.TableName()),
+	}
+}
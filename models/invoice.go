@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	InvoiceTypeInvoice    InvoiceType = "invoice"
+	InvoiceTypeCreditNote InvoiceType = "credit_note"
+)
+
+type InvoiceType string
+
+// Invoice is a rendered PDF document of an order, numbered sequentially per store and type
+type Invoice struct {
+	ID        string      `json:"id" gorm:"column:id;primary_key"`
+	StoreID   string      `json:"store_id" gorm:"column:store_id;not null;unique_index:uix_invoices_store_id_type_number"`
+	OrderID   string      `json:"order_id" gorm:"column:order_id;not null;unique_index:uix_invoices_order_id_type"`
+	Type      InvoiceType `json:"type" gorm:"column:type;not null;unique_index:uix_invoices_store_id_type_number,uix_invoices_order_id_type"`
+	Number    int64       `json:"number" gorm:"column:number;not null;unique_index:uix_invoices_store_id_type_number"`
+	Amount    int64       `json:"amount" gorm:"column:amount;not null"`
+	Path      string      `json:"-" gorm:"column:path;not null"`
+	CreatedAt time.Time   `json:"created_at" gorm:"column:created_at;index;not null"`
+}
+
+func (i *Invoice) TableName() string {
+	return "invoices"
+}
+
+func (i *Invoice) ForeignKeys() []string {
+	s := Store{}
+	o := Order{}
+
+	return []string{
+		fmt.Sprintf("store_id;%s(id);RESTRICT;RESTRICT", s.TableName()),
+		fmt.Sprintf("order_id;%s(id);RESTRICT;RESTRICT", o.TableName()),
+	}
+}
+
+func (i *Invoice) DisplayNumber() string {
+	if i.Type == InvoiceTypeCreditNote {
+		return fmt.Sprintf("CN-%06d", i.Number)
+	}
+	return fmt.Sprintf("INV-%06d", i.Number)
+}
+
+func (i *Invoice) FileName() string {
+	return fmt.Sprintf("%s.pdf", i.DisplayNumber())
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// LimiterCounter counts the attempts made under a key within the window started at WindowStart.
+// It is kept by the database limiter store and can be dropped after ExpiresAt.
+type LimiterCounter struct {
+	Name        string     `json:"name" gorm:"column:name;primary_key"`
+	Count       int        `json:"count" gorm:"column:count;not null"`
+	WindowStart time.Time  `json:"window_start" gorm:"column:window_start;not null"`
+	LastAt      time.Time  `json:"last_at" gorm:"column:last_at;not null"`
+	LockedUntil *time.Time `json:"locked_until" gorm:"column:locked_until"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"column:expires_at;index;not null"`
+}
+
+func (lc *LimiterCounter) TableName() string {
+	return "limiter_counters"
+}
+
+func (lc *LimiterCounter) IsLocked(now time.Time) bool {
+	return lc.LockedUntil != nil && lc.LockedUntil.After(now)
+}
+
+// AccountUnlockToken unlocks an account locked out after too many failed logins, only its hash is kept
+type AccountUnlockToken struct {
+	ID        string    `json:"-" gorm:"column:id;primary_key"`
+	UserID    string    `json:"-" gorm:"column:user_id;index;not null"`
+	TokenHash string    `json:"-" gorm:"column:token_hash;unique;not null"`
+	ExpiresAt time.Time `json:"-" gorm:"column:expires_at;index;not null"`
+	CreatedAt time.Time `json:"-" gorm:"column:created_at;not null"`
+}
+
+func (ut *AccountUnlockToken) TableName() string {
+	return "account_unlock_tokens"
+}
+
+func (ut *AccountUnlockToken) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
+
+type SecurityEvent string
+
+const (
+	SecurityEventAccountLocked          SecurityEvent = "account_locked"
+	SecurityEventIPLocked               SecurityEvent = "ip_locked"
+	SecurityEventAccountUnlocked        SecurityEvent = "account_unlocked"
+	SecurityEventResetPasswordThrottled SecurityEvent = "reset_password_throttled"
+)
+
+// SecurityAuditLog records a lockout or an unlock, UserID is empty when the email isn't registered
+type SecurityAuditLog struct {
+	ID          string        `json:"id" gorm:"column:id;primary_key"`
+	Event       SecurityEvent `json:"event" gorm:"column:event;index;not null"`
+	UserID      *string       `json:"user_id" gorm:"column:user_id;index"`
+	Email       string        `json:"email" gorm:"column:email;index"`
+	IP          string        `json:"ip" gorm:"column:ip;index"`
+	UserAgent   string        `json:"user_agent" gorm:"column:user_agent"`
+	LockedUntil *time.Time    `json:"locked_until" gorm:"column:locked_until"`
+	CreatedAt   time.Time     `json:"created_at" gorm:"column:created_at;index;not null"`
+}
+
+func (sal *SecurityAuditLog) TableName() string {
+	return "security_audit_logs"
+}
+
+func (sal *SecurityAuditLog) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);SET NULL;CASCADE", u.TableName()),
+	}
+}
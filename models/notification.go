@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	NotificationOrderConfirmed  NotificationEvent = "order_confirmed"
+	NotificationOrderShipped    NotificationEvent = "order_shipped"
+	NotificationPaymentReceived NotificationEvent = "payment_received"
+	NotificationPayoutCompleted NotificationEvent = "payout_completed"
+
+	PushPlatformAndroid PushPlatform = "android"
+	PushPlatformIOS     PushPlatform = "ios"
+	PushPlatformWeb     PushPlatform = "web"
+)
+
+type NotificationEvent string
+
+func (ne NotificationEvent) IsValid() bool {
+	for _, v := range NotificationEvents() {
+		if v == ne {
+			return true
+		}
+	}
+	return false
+}
+
+func NotificationEvents() []NotificationEvent {
+	return []NotificationEvent{NotificationOrderConfirmed, NotificationOrderShipped,
+		NotificationPaymentReceived, NotificationPayoutCompleted}
+}
+
+// NotificationEventOfOrderStatus returns the event the buyer is notified by when the order moves to the status
+func NotificationEventOfOrderStatus(status OrderStatus) (NotificationEvent, bool) {
+	switch status {
+	case OrderConfirmed:
+		return NotificationOrderConfirmed, true
+	case OrderShipping:
+		return NotificationOrderShipped, true
+	}
+	return "", false
+}
+
+type PushPlatform string
+
+func (pp PushPlatform) IsValid() bool {
+	for _, v := range []PushPlatform{PushPlatformAndroid, PushPlatformIOS, PushPlatformWeb} {
+		if v == pp {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationPreference keeps the channels a user wants to be notified through on an event,
+// transactional emails are sent regardless of it
+type NotificationPreference struct {
+	UserID        string            `json:"-" gorm:"column:user_id;primary_key"`
+	Event         NotificationEvent `json:"event" gorm:"column:event;primary_key"`
+	IsSMSEnabled  bool              `json:"is_sms_enabled" gorm:"column:is_sms_enabled;not null"`
+	IsPushEnabled bool              `json:"is_push_enabled" gorm:"column:is_push_enabled;not null"`
+	UpdatedAt     time.Time         `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (np *NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+func (np *NotificationPreference) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;RESTRICT", u.TableName()),
+	}
+}
+
+// PushDevice is a device registered by a user to receive push notifications on
+type PushDevice struct {
+	ID        string       `json:"id" gorm:"column:id;primary_key"`
+	UserID    string       `json:"user_id" gorm:"column:user_id;index;not null"`
+	Token     string       `json:"token" gorm:"column:token;unique;not null"`
+	Platform  PushPlatform `json:"platform" gorm:"column:platform;not null"`
+	CreatedAt time.Time    `json:"created_at" gorm:"column:created_at;not null"`
+	UpdatedAt time.Time    `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (pd *PushDevice) TableName() string {
+	return "push_devices"
+}
+
+func (pd *PushDevice) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;RESTRICT", u.TableName()),
+	}
+}
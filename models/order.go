@@ -51,6 +51,7 @@ type Order struct {
 	ShippingCharge       int64         `json:"shipping_charge" gomr:"column:shipping_charge"`
 	PaymentProcessingFee int64         `json:"payment_processing_fee" gorm:"column:payment_processing_fee"`
 	SubTotal             int64         `json:"sub_total" gorm:"column:sub_total"`
+	TotalWeight          int           `json:"total_weight" gorm:"column:total_weight;not null;default:0"`
 	IsAllDigitalProducts bool          `json:"is_all_digital_products" gorm:"column:is_all_digital_products;index"`
 	PaymentGateway       *string       `json:"payment_gateway" gorm:"column:payment_gateway"`
 	Nonce                *string       `json:"nonce" gomr:"column:nonce"`
@@ -61,6 +62,9 @@ type Order struct {
 	ActualEarnings       int64         `json:"actual_earnings" gorm:"actual_earnings;index;not null;default:0"`
 	GrandTotal           int64         `json:"grand_total" gorm:"column:grand_total;not nul;default:0"`
 	DiscountedAmount     int64         `json:"discounted_amount" gorm:"column:discounted_amount"`
+	TaxAmount            int64         `json:"tax_amount" gorm:"column:tax_amount;not null;default:0"`
+	IncludedTaxAmount    int64         `json:"included_tax_amount" gorm:"column:included_tax_amount;not null;default:0"`
+	TaxExemptVatNumber   *string       `json:"tax_exempt_vat_number,omitempty" gorm:"column:tax_exempt_vat_number"`
 	Status               OrderStatus   `json:"status" gorm:"column:status"`
 	PaymentStatus        PaymentStatus `json:"payment_status" gorm:"column:payment_status"`
 	CreatedAt            time.Time     `json:"created_at" gorm:"column:created_at;index;not null"`
@@ -65,6 +65,9 @@ type OrderDetailsView struct {
 	SellerEarnings          int64             `json:"seller_earnings"`
 	PlatformEarnings        int64             `json:"platform_earnings"`
 	ActualEarnings          int64             `json:"actual_earnings"`
+	TaxAmount               int64             `json:"tax_amount"`
+	IncludedTaxAmount       int64             `json:"included_tax_amount"`
+	TaxExemptVatNumber      *string           `json:"tax_exempt_vat_number,omitempty"`
 }
 
 func (odv *OrderDetailsView) TableName() string {
@@ -86,7 +89,8 @@ func (odv *OrderDetailsView) CreateView(tx *gorm.DB) error {
 		" sm.id AS shipping_method_id, sm.name AS shipping_method_name, sm.approximate_delivery_time AS approximate_delivery_time,"+
 		" pm.id AS payment_method_id, pm.name AS payment_method_name, pm.is_offline_payment AS payment_method_is_offline,"+
 		" rv.rating AS review_rating, rv.description AS review_description, o.seller_earnings AS seller_earnings,"+
-		" o.platform_earnings AS platform_earnings, o.actual_earnings AS actual_earnings"+
+		" o.platform_earnings AS platform_earnings, o.actual_earnings AS actual_earnings,"+
+		" o.tax_amount AS tax_amount, o.included_tax_amount AS included_tax_amount, o.tax_exempt_vat_number AS tax_exempt_vat_number"+
 		" FROM orders AS o"+
 		" LEFT JOIN addresses_view AS sa ON o.shipping_address_id = sa.id"+
 		" LEFT JOIN addresses_view AS ba ON o.billing_address_id = ba.id"+
@@ -58,6 +58,9 @@ type OrderDetailsViewExternal struct {
 	UserPicture             *string                   `json:"user_picture,omitempty"`
 	ReviewRating            int                       `json:"review_rating"`
 	ReviewDescription       string                    `json:"review_description"`
+	TaxAmount               int64                     `json:"tax_amount"`
+	IncludedTaxAmount       int64                     `json:"included_tax_amount"`
+	TaxExemptVatNumber      *string                   `json:"tax_exempt_vat_number,omitempty"`
 }
 
 func (odi *OrderDetailsViewExternal) TableName() string {
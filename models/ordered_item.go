@@ -3,13 +3,16 @@ package models
 import "fmt"
 
 type OrderedItem struct {
-	ID          string `json:"id" gorm:"column:id;primary_key;not null"`
-	OrderID     string `json:"order_id" gorm:"column:order_id"`
-	ProductID   string `json:"product_id" gorm:"column:product_id"`
-	Quantity    int    `json:"quantity" gorm:"column:quantity"`
-	Price       int64  `json:"price" gorm:"column:price"`
-	ProductCost int64  `json:"product_cost" gorm:"column:product_cost"`
-	SubTotal    int64  `json:"sub_total" gorm:"column:sub_total"`
+	ID                string  `json:"id" gorm:"column:id;primary_key;not null"`
+	OrderID           string  `json:"order_id" gorm:"column:order_id"`
+	ParentID          *string `json:"parent_id,omitempty" gorm:"column:parent_id;index"`
+	ProductID         string  `json:"product_id" gorm:"column:product_id"`
+	Quantity          int     `json:"quantity" gorm:"column:quantity"`
+	Price             int64   `json:"price" gorm:"column:price"`
+	ProductCost       int64   `json:"product_cost" gorm:"column:product_cost"`
+	SubTotal          int64   `json:"sub_total" gorm:"column:sub_total"`
+	TaxAmount         int64   `json:"tax_amount" gorm:"column:tax_amount;not null;default:0"`
+	IncludedTaxAmount int64   `json:"included_tax_amount" gorm:"column:included_tax_amount;not null;default:0"`
 }
 
 func (op *OrderedItem) TableName() string {
@@ -23,5 +26,6 @@ func (op *OrderedItem) ForeignKeys() []string {
 	return []string{
 		fmt.Sprintf("order_id;%s(id);RESTRICT;RESTRICT", o.TableName()),
 		fmt.Sprintf("product_id;%s(id);RESTRICT;RESTRICT", p.TableName()),
+		fmt.Sprintf("parent_id;%s(id);RESTRICT;RESTRICT", op.TableName()),
 	}
 }
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+	DataExportExpired DataExportStatus = "expired"
+)
+
+// DataExport is an archive of the personal data of a user, built by the queue and kept in minio until ExpiresAt
+type DataExport struct {
+	ID          string           `json:"id" gorm:"column:id;primary_key"`
+	UserID      string           `json:"-" gorm:"column:user_id;index;not null"`
+	Status      DataExportStatus `json:"status" gorm:"column:status;index;not null"`
+	Path        *string          `json:"-" gorm:"column:path"`
+	Size        int64            `json:"size" gorm:"column:size;not null;default:0"`
+	ExpiresAt   *time.Time       `json:"expires_at" gorm:"column:expires_at;index"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"column:created_at;index;not null"`
+	CompletedAt *time.Time       `json:"completed_at" gorm:"column:completed_at"`
+}
+
+func (de *DataExport) TableName() string {
+	return "data_exports"
+}
+
+func (de *DataExport) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
+
+// IsDownloadable tells whether the archive is built and not expired yet
+func (de *DataExport) IsDownloadable(now time.Time) bool {
+	return de.Status == DataExportReady && de.Path != nil && de.ExpiresAt != nil && de.ExpiresAt.After(now)
+}
+
+type AccountDeletionStatus string
+
+const (
+	AccountDeletionPending   AccountDeletionStatus = "pending"
+	AccountDeletionCancelled AccountDeletionStatus = "cancelled"
+	AccountDeletionCompleted AccountDeletionStatus = "completed"
+)
+
+// AccountDeletion schedules the anonymisation of a user after the grace period, until then it can be
+// cancelled by the user or with the token of the confirmation email. A deletion that couldn't be carried
+// out, e.g. of a store owner, is attempted again after NextAttemptAt
+type AccountDeletion struct {
+	ID              string                `json:"id" gorm:"column:id;primary_key"`
+	UserID          string                `json:"-" gorm:"column:user_id;index;not null"`
+	Status          AccountDeletionStatus `json:"status" gorm:"column:status;index;not null"`
+	CancelTokenHash *string               `json:"-" gorm:"column:cancel_token_hash;unique"`
+	ScheduledAt     time.Time             `json:"scheduled_at" gorm:"column:scheduled_at;index;not null"`
+	NextAttemptAt   *time.Time            `json:"next_attempt_at,omitempty" gorm:"column:next_attempt_at;index"`
+	CreatedAt       time.Time             `json:"created_at" gorm:"column:created_at;not null"`
+	CancelledAt     *time.Time            `json:"cancelled_at" gorm:"column:cancelled_at"`
+	CompletedAt     *time.Time            `json:"completed_at" gorm:"column:completed_at"`
+}
+
+func (ad *AccountDeletion) TableName() string {
+	return "account_deletions"
+}
+
+func (ad *AccountDeletion) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
+
+// PersonalData is the content of a data export
+type PersonalData struct {
+	Profile     *User                      `json:"profile"`
+	Addresses   []AddressView              `json:"addresses"`
+	Orders      []OrderDetailsViewExternal `json:"orders"`
+	Reviews     []ProductReview            `json:"reviews"`
+	Sessions    []Session                  `json:"sessions"`
+	Identities  []UserIdentity             `json:"identities"`
+	GeneratedAt time.Time                  `json:"generated_at"`
+}
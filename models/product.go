@@ -6,27 +6,41 @@ import (
 )
 
 type Product struct {
-	ID                  string    `json:"id" gorm:"column:id;unique"`
-	Name                string    `json:"name" gorm:"column:name;primary_key"`
-	Slug                string    `json:"slug" gorm:"column:slug;index"`
-	Description         string    `json:"description" gorm:"column:description"`
-	IsPublished         bool      `json:"is_published" gorm:"column:is_published;index"`
-	StoreID             string    `json:"store_id" gorm:"column:store_id;primary_key"`
-	CategoryID          *string   `json:"category_id,omitempty" gorm:"column:category_id;index"`
-	SKU                 string    `json:"sku" gorm:"column:sku;unique"`
-	Stock               int       `json:"stock" gorm:"column:stock;index"`
-	MaxQuantityCount    int       `json:"max_quantity_count" gorm:"column:max_quantity_count;not null;default:10"`
-	Unit                string    `json:"unit" gorm:"column:unit"`
-	Price               int64     `json:"price" gorm:"column:price;index"`
-	ProductCost         int64     `json:"product_cost" gorm:"column:product_cost;index"`
-	Image               string    `json:"image,omitempty" gorm:"column:image"`
-	IsShippable         bool      `json:"is_shippable" gorm:"column:is_shippable;index"`
-	IsDigital           bool      `json:"is_digital" gorm:"column:is_digital;index"`
-	DigitalDownloadLink string    `json:"-" gorm:"column:digital_download_link"`
-	DownloadCounter     int       `json:"download_counter" gorm:"column:download_counter;default:0;index"`
-	Views               int       `json:"views" gorm:"column:views;default:0;index"`
-	CreatedAt           time.Time `json:"created_at" gorm:"column:created_at;index"`
-	UpdatedAt           time.Time `json:"updated_at" gorm:"column:updated_at;index"`
+	ID                  string     `json:"id" gorm:"column:id;unique"`
+	Name                string     `json:"name" gorm:"column:name;primary_key"`
+	Slug                string     `json:"slug" gorm:"column:slug;index"`
+	Description         string     `json:"description" gorm:"column:description"`
+	IsPublished         bool       `json:"is_published" gorm:"column:is_published;index"`
+	StoreID             string     `json:"store_id" gorm:"column:store_id;primary_key"`
+	CategoryID          *string    `json:"category_id,omitempty" gorm:"column:category_id;index"`
+	GlobalCategoryID    *string    `json:"global_category_id,omitempty" gorm:"column:global_category_id;index"`
+	TaxClassID          *string    `json:"tax_class_id,omitempty" gorm:"column:tax_class_id;index"`
+	SKU                 string     `json:"sku" gorm:"column:sku;unique"`
+	Stock               int        `json:"stock" gorm:"column:stock;index"`
+	MaxQuantityCount    int        `json:"max_quantity_count" gorm:"column:max_quantity_count;not null;default:10"`
+	Unit                string     `json:"unit" gorm:"column:unit"`
+	Price               int64      `json:"price" gorm:"column:price;index"`
+	SalePrice           int64      `json:"sale_price" gorm:"column:sale_price;not null;default:0"`
+	SaleStartsAt        *time.Time `json:"sale_starts_at,omitempty" gorm:"column:sale_starts_at"`
+	SaleEndsAt          *time.Time `json:"sale_ends_at,omitempty" gorm:"column:sale_ends_at"`
+	ProductCost         int64      `json:"product_cost" gorm:"column:product_cost;index"`
+	Image               string     `json:"image,omitempty" gorm:"column:image"`
+	IsShippable         bool       `json:"is_shippable" gorm:"column:is_shippable;index"`
+	IsDigital           bool       `json:"is_digital" gorm:"column:is_digital;index"`
+	IsBundle            bool       `json:"is_bundle" gorm:"column:is_bundle;not null;default:false;index"`
+	Weight              int        `json:"weight" gorm:"column:weight;not null;default:0"`
+	Length              int        `json:"length" gorm:"column:length;not null;default:0"`
+	Width               int        `json:"width" gorm:"column:width;not null;default:0"`
+	Height              int        `json:"height" gorm:"column:height;not null;default:0"`
+	DigitalDownloadLink string     `json:"-" gorm:"column:digital_download_link"`
+	DownloadCounter     int        `json:"download_counter" gorm:"column:download_counter;default:0;index"`
+	Views               int        `json:"views" gorm:"column:views;default:0;index"`
+	RatingAverage       float64    `json:"rating_average" gorm:"column:rating_average;default:0;index"`
+	RatingCount         int        `json:"rating_count" gorm:"column:rating_count;default:0"`
+	SoldCount           int        `json:"sold_count" gorm:"column:sold_count;not null;default:0;index"`
+	SearchText          string     `json:"-" gorm:"column:search_text;type:text;not null;default:''"`
+	CreatedAt           time.Time  `json:"created_at" gorm:"column:created_at;index"`
+	UpdatedAt           time.Time  `json:"updated_at" gorm:"column:updated_at;index"`
 }
 
 func (p *Product) TableName() string {
@@ -36,9 +50,35 @@ func (p *Product) TableName() string {
 func (p *Product) ForeignKeys() []string {
 	s := Store{}
 	c := Category{}
+	gc := GlobalCategory{}
+	tc := TaxClass{}
 
 	return []string{
 		fmt.Sprintf("store_id;%s(id);RESTRICT;RESTRICT", s.TableName()),
 		fmt.Sprintf("category_id;%s(id);RESTRICT;RESTRICT", c.TableName()),
+		fmt.Sprintf("global_category_id;%s(id);RESTRICT;RESTRICT", gc.TableName()),
+		fmt.Sprintf("tax_class_id;%s(id);RESTRICT;RESTRICT", tc.TableName()),
 	}
 }
+
+// IsOnSale reports whether the sale price of the product is active at the given time
+func (p *Product) IsOnSale(at time.Time) bool {
+	if p.SalePrice <= 0 || p.SalePrice >= p.Price {
+		return false
+	}
+	if p.SaleStartsAt != nil && at.Before(*p.SaleStartsAt) {
+		return false
+	}
+	if p.SaleEndsAt != nil && !at.Before(*p.SaleEndsAt) {
+		return false
+	}
+	return true
+}
+
+// EffectivePrice returns the price that has to be charged at the given time
+func (p *Product) EffectivePrice(at time.Time) int64 {
+	if p.IsOnSale(at) {
+		return p.SalePrice
+	}
+	return p.Price
+}
@@ -3,9 +3,13 @@ package models
 import "fmt"
 
 type ProductKV struct {
-	ID    string `json:"id"`
-	Value string `json:"value"`
-	Image string `json:"image"`
+	ID     string `json:"id"`
+	Value  string `json:"value"`
+	Image  string `json:"image"`
+	Weight *int   `json:"weight,omitempty"`
+	Length *int   `json:"length,omitempty"`
+	Width  *int   `json:"width,omitempty"`
+	Height *int   `json:"height,omitempty"`
 }
 
 type OrderItemAttributeKV struct {
@@ -19,6 +23,10 @@ type ProductAttribute struct {
 	Key       string `json:"key" gorm:"column:key;primary_key"`
 	Value     string `json:"value" gorm:"column:value;primary_key"`
 	Image     string `json:"image" gorm:"column:image"`
+	Weight    *int   `json:"weight,omitempty" gorm:"column:weight"`
+	Length    *int   `json:"length,omitempty" gorm:"column:length"`
+	Width     *int   `json:"width,omitempty" gorm:"column:width"`
+	Height    *int   `json:"height,omitempty" gorm:"column:height"`
 }
 
 func (pa *ProductAttribute) TableName() string {
@@ -0,0 +1,31 @@
+package models
+
+import "fmt"
+
+type ProductBundleItem struct {
+	BundleID  string `json:"bundle_id" gorm:"column:bundle_id;primary_key"`
+	ProductID string `json:"product_id" gorm:"column:product_id;primary_key"`
+	Quantity  int    `json:"quantity" gorm:"column:quantity;not null;default:1"`
+}
+
+func (pbi *ProductBundleItem) TableName() string {
+	return "product_bundle_items"
+}
+
+func (pbi *ProductBundleItem) ForeignKeys() []string {
+	p := Product{}
+
+	return []string{
+		fmt.Sprintf("bundle_id;%s(id);CASCADE;RESTRICT", p.TableName()),
+		fmt.Sprintf("product_id;%s(id);RESTRICT;RESTRICT", p.TableName()),
+	}
+}
+
+type ProductBundleItemDetails struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	SKU       string `json:"sku"`
+	Image     string `json:"image,omitempty"`
+	IsDigital bool   `json:"is_digital"`
+	Quantity  int    `json:"quantity"`
+}
@@ -3,53 +3,76 @@ package models
 import "time"
 
 type ProductDetails struct {
-	ID               string                 `json:"id"`
-	Name             string                 `json:"name"`
-	StoreID          string                 `json:"store_id"`
-	StoreName        string                 `json:"store_name"`
-	Slug             string                 `json:"slug"`
-	Description      string                 `json:"description"`
-	IsPublished      bool                   `json:"is_published"`
-	CategoryID       string                 `json:"category_id,omitempty"`
-	CategoryName     string                 `json:"category_name,omitempty"`
-	Image            string                 `json:"image,omitempty"`
-	IsShippable      bool                   `json:"is_shippable"`
-	IsDigital        bool                   `json:"is_digital"`
-	Price            int                    `json:"price"`
-	MaxQuantityCount int                    `json:"max_quantity_count"`
-	SKU              string                 `json:"sku"`
-	Stock            int                    `json:"stock"`
-	Unit             string                 `json:"unit"`
-	AdditionalImages []string               `json:"additional_images"`
-	CreatedAt        time.Time              `json:"created_at"`
-	UpdatedAt        time.Time              `json:"updated_at"`
-	Collections      []Collection           `json:"collections,omitempty"`
-	Attributes       map[string][]ProductKV `json:"attributes,omitempty"`
+	ID               string                     `json:"id"`
+	Name             string                     `json:"name"`
+	StoreID          string                     `json:"store_id"`
+	StoreName        string                     `json:"store_name"`
+	Slug             string                     `json:"slug"`
+	Description      string                     `json:"description"`
+	IsPublished      bool                       `json:"is_published"`
+	CategoryID       string                     `json:"category_id,omitempty"`
+	CategoryName     string                     `json:"category_name,omitempty"`
+	TaxClassID       *string                    `json:"tax_class_id,omitempty"`
+	Image            string                     `json:"image,omitempty"`
+	IsShippable      bool                       `json:"is_shippable"`
+	IsDigital        bool                       `json:"is_digital"`
+	IsBundle         bool                       `json:"is_bundle"`
+	Weight           int                        `json:"weight"`
+	Length           int                        `json:"length"`
+	Width            int                        `json:"width"`
+	Height           int                        `json:"height"`
+	Price            int                        `json:"price"`
+	SalePrice        int                        `json:"sale_price,omitempty"`
+	SaleEndsAt       *time.Time                 `json:"sale_ends_at,omitempty"`
+	MaxQuantityCount int                        `json:"max_quantity_count"`
+	SKU              string                     `json:"sku"`
+	Stock            int                        `json:"stock"`
+	Unit             string                     `json:"unit"`
+	AdditionalImages []string                   `json:"additional_images"`
+	RatingAverage    float64                    `json:"rating_average"`
+	RatingCount      int                        `json:"rating_count"`
+	CreatedAt        time.Time                  `json:"created_at"`
+	UpdatedAt        time.Time                  `json:"updated_at"`
+	Collections      []Collection               `json:"collections,omitempty"`
+	Attributes       map[string][]ProductKV     `json:"attributes,omitempty"`
+	BundleItems      []ProductBundleItemDetails `json:"bundle_items,omitempty"`
 }
 
 type ProductDetailsInternal struct {
-	ID                  string                 `json:"id"`
-	Name                string                 `json:"name"`
-	StoreID             string                 `json:"store_id"`
-	StoreName           string                 `json:"store_name"`
-	Slug                string                 `json:"slug"`
-	Description         string                 `json:"description"`
-	IsPublished         bool                   `json:"is_published"`
-	CategoryID          string                 `json:"category_id,omitempty"`
-	CategoryName        string                 `json:"category_name,omitempty"`
-	Image               string                 `json:"image,omitempty"`
-	IsShippable         bool                   `json:"is_shippable"`
-	IsDigital           bool                   `json:"is_digital"`
-	Price               int                    `json:"price"`
-	ProductCost         int                    `json:"product_cost"`
-	MaxQuantityCount    int                    `json:"max_quantity_count"`
-	SKU                 string                 `json:"sku"`
-	Stock               int                    `json:"stock"`
-	Unit                string                 `json:"unit"`
-	AdditionalImages    []string               `json:"additional_images"`
-	DigitalDownloadLink string                 `json:"digital_download_link"`
-	CreatedAt           time.Time              `json:"created_at"`
-	UpdatedAt           time.Time              `json:"updated_at"`
-	Collections         []Collection           `json:"collections,omitempty"`
-	Attributes          map[string][]ProductKV `json:"attributes,omitempty"`
+	ID                  string                     `json:"id"`
+	Name                string                     `json:"name"`
+	StoreID             string                     `json:"store_id"`
+	StoreName           string                     `json:"store_name"`
+	Slug                string                     `json:"slug"`
+	Description         string                     `json:"description"`
+	IsPublished         bool                       `json:"is_published"`
+	CategoryID          string                     `json:"category_id,omitempty"`
+	CategoryName        string                     `json:"category_name,omitempty"`
+	TaxClassID          *string                    `json:"tax_class_id,omitempty"`
+	Image               string                     `json:"image,omitempty"`
+	IsShippable         bool                       `json:"is_shippable"`
+	IsDigital           bool                       `json:"is_digital"`
+	IsBundle            bool                       `json:"is_bundle"`
+	Weight              int                        `json:"weight"`
+	Length              int                        `json:"length"`
+	Width               int                        `json:"width"`
+	Height              int                        `json:"height"`
+	Price               int                        `json:"price"`
+	SalePrice           int                        `json:"sale_price"`
+	SaleStartsAt        *time.Time                 `json:"sale_starts_at,omitempty"`
+	SaleEndsAt          *time.Time                 `json:"sale_ends_at,omitempty"`
+	ProductCost         int                        `json:"product_cost"`
+	MaxQuantityCount    int                        `json:"max_quantity_count"`
+	SKU                 string                     `json:"sku"`
+	Stock               int                        `json:"stock"`
+	Unit                string                     `json:"unit"`
+	AdditionalImages    []string                   `json:"additional_images"`
+	DigitalDownloadLink string                     `json:"digital_download_link"`
+	RatingAverage       float64                    `json:"rating_average"`
+	RatingCount         int                        `json:"rating_count"`
+	CreatedAt           time.Time                  `json:"created_at"`
+	UpdatedAt           time.Time                  `json:"updated_at"`
+	Collections         []Collection               `json:"collections,omitempty"`
+	Attributes          map[string][]ProductKV     `json:"attributes,omitempty"`
+	BundleItems         []ProductBundleItemDetails `json:"bundle_items,omitempty"`
 }
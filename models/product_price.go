@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	PriceChangeManual    PriceChangeSource = "manual"
+	PriceChangeScheduled PriceChangeSource = "scheduled"
+)
+
+type PriceChangeSource string
+
+type ScheduledPrice struct {
+	ID          string     `json:"id" gorm:"column:id;primary_key"`
+	ProductID   string     `json:"product_id" gorm:"column:product_id;index;not null"`
+	StoreID     string     `json:"store_id" gorm:"column:store_id;index;not null"`
+	Price       int64      `json:"price" gorm:"column:price;not null"`
+	EffectiveAt time.Time  `json:"effective_at" gorm:"column:effective_at;index;not null"`
+	IsApplied   bool       `json:"is_applied" gorm:"column:is_applied;index;not null;default:false"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty" gorm:"column:applied_at"`
+	CreatedBy   string     `json:"created_by" gorm:"column:created_by;not null"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at;index;not null"`
+}
+
+func (sp *ScheduledPrice) TableName() string {
+	return "scheduled_prices"
+}
+
+func (sp *ScheduledPrice) ForeignKeys() []string {
+	p := Product{}
+	s := Store{}
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("product_id;%s(id);CASCADE;RESTRICT", p.TableName()),
+		fmt.Sprintf("store_id;%s(id);RESTRICT;RESTRICT", s.TableName()),
+		fmt.Sprintf("created_by;%s(id);RESTRICT;RESTRICT", u.TableName()),
+	}
+}
+
+type PriceHistory struct {
+	ID        string            `json:"id" gorm:"column:id;primary_key"`
+	ProductID string            `json:"product_id" gorm:"column:product_id;index;not null"`
+	OldPrice  int64             `json:"old_price" gorm:"column:old_price;not null"`
+	NewPrice  int64             `json:"new_price" gorm:"column:new_price;not null"`
+	Source    PriceChangeSource `json:"source" gorm:"column:source;index;not null"`
+	ChangedBy *string           `json:"changed_by,omitempty" gorm:"column:changed_by"`
+	CreatedAt time.Time         `json:"created_at" gorm:"column:created_at;index;not null"`
+}
+
+func (ph *PriceHistory) TableName() string {
+	return "price_histories"
+}
+
+func (ph *PriceHistory) ForeignKeys() []string {
+	p := Product{}
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("product_id;%s(id);CASCADE;RESTRICT", p.TableName()),
+		fmt.Sprintf("changed_by;%s(id);RESTRICT;RESTRICT", u.TableName()),
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type ProductReview struct {
+	ID          string     `json:"id" gorm:"column:id;primary_key"`
+	ProductID   string     `json:"product_id" gorm:"column:product_id;unique_index:uix_product_reviews_product_id_user_id;not null"`
+	StoreID     string     `json:"store_id" gorm:"column:store_id;index;not null"`
+	UserID      string     `json:"user_id" gorm:"column:user_id;unique_index:uix_product_reviews_product_id_user_id;not null"`
+	OrderID     string     `json:"order_id" gorm:"column:order_id;index;not null"`
+	Rating      int        `json:"rating" gorm:"column:rating;index;not null"`
+	Description string     `json:"description" gorm:"column:description"`
+	Reply       string     `json:"reply,omitempty" gorm:"column:reply"`
+	RepliedAt   *time.Time `json:"replied_at,omitempty" gorm:"column:replied_at"`
+	IsHidden    bool       `json:"is_hidden" gorm:"column:is_hidden;index;not null;default:false"`
+	IsFlagged   bool       `json:"is_flagged" gorm:"column:is_flagged;index;not null;default:false"`
+	FlagReason  string     `json:"flag_reason,omitempty" gorm:"column:flag_reason"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (pr *ProductReview) TableName() string {
+	return "product_reviews"
+}
+
+func (pr *ProductReview) ForeignKeys() []string {
+	p := Product{}
+	s := Store{}
+	u := User{}
+	o := Order{}
+
+	return []string{
+		fmt.Sprintf("product_id;%s(id);RESTRICT;RESTRICT", p.TableName()),
+		fmt.Sprintf("store_id;%s(id);RESTRICT;RESTRICT", s.TableName()),
+		fmt.Sprintf("user_id;%s(id);RESTRICT;RESTRICT", u.TableName()),
+		fmt.Sprintf("order_id;%s(id);RESTRICT;RESTRICT", o.TableName()),
+	}
+}
+
+type ProductReviewImage struct {
+	ReviewID  string `json:"review_id" gorm:"column:review_id;primary_key"`
+	ImagePath string `json:"image_path" gorm:"column:image_path;primary_key"`
+}
+
+func (pri *ProductReviewImage) TableName() string {
+	return "product_review_images"
+}
+
+func (pri *ProductReviewImage) ForeignKeys() []string {
+	pr := ProductReview{}
+
+	return []string{
+		fmt.Sprintf("review_id;%s(id);CASCADE;RESTRICT", pr.TableName()),
+	}
+}
+
+type ProductReviewDetails struct {
+	ID          string     `json:"id"`
+	ProductID   string     `json:"product_id"`
+	ProductName string     `json:"product_name"`
+	StoreID     string     `json:"store_id"`
+	UserID      string     `json:"user_id"`
+	UserName    string     `json:"user_name"`
+	Rating      int        `json:"rating"`
+	Description string     `json:"description"`
+	Reply       string     `json:"reply,omitempty"`
+	RepliedAt   *time.Time `json:"replied_at,omitempty"`
+	IsHidden    bool       `json:"is_hidden"`
+	IsFlagged   bool       `json:"is_flagged"`
+	FlagReason  string     `json:"flag_reason,omitempty"`
+	Images      []string   `json:"images" gorm:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
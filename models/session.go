@@ -5,13 +5,28 @@ import (
 	"time"
 )
 
+const (
+	SessionRevokedLogout         = "logout"
+	SessionRevokedByUser         = "revoked"
+	SessionRevokedRefreshReused  = "refresh_token_reused"
+	SessionRevokedPasswordChange = "password_changed"
+)
+
+// Session is a login of a user on a device. The access token is a short lived JWT bound to the session,
+// the refresh token is rotated on every use and only its hash is stored.
 type Session struct {
-	ID           string    `json:"-" gorm:"column:id;primary_key"`
-	UserID       string    `json:"-" gorm:"column:user_id;index;not null"`
-	AccessToken  string    `json:"access_token" gorm:"column:access_token;unique;not null"`
-	RefreshToken string    `json:"refresh_token" gorm:"column:refresh_token;unique;not null"`
-	CreatedAt    time.Time `json:"-" gorm:"column:created_at;index;not null"`
-	ExpireOn     int64     `json:"expire_on" gorm:"column:expire_on;index;not null"`
+	ID            string     `json:"id" gorm:"column:id;primary_key"`
+	UserID        string     `json:"-" gorm:"column:user_id;index;not null"`
+	Scope         string     `json:"scope" gorm:"column:scope"`
+	AccessToken   string     `json:"-" gorm:"column:access_token;unique;not null"`
+	RefreshToken  string     `json:"-" gorm:"column:refresh_token;unique;not null"`
+	UserAgent     string     `json:"user_agent" gorm:"column:user_agent"`
+	IP            string     `json:"ip" gorm:"column:ip"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"column:created_at;index;not null"`
+	LastSeenAt    *time.Time `json:"last_seen_at" gorm:"column:last_seen_at"`
+	ExpireOn      int64      `json:"expire_on" gorm:"column:expire_on;index;not null"`
+	RevokedAt     *time.Time `json:"-" gorm:"column:revoked_at;index"`
+	RevokedReason string     `json:"-" gorm:"column:revoked_reason"`
 }
 
 func (s *Session) TableName() string {
@@ -25,3 +40,7 @@ func (s *Session) ForeignKeys() []string {
 		fmt.Sprintf("user_id;%s(id);RESTRICT;RESTRICT", u.TableName()),
 	}
 }
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && s.ExpireOn > now.Unix()
+}
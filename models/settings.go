@@ -23,6 +23,7 @@ type Settings struct {
 	DefaultCommissionRate        int64          `json:"default_commission_rate" gorm:"column:default_commission_rate;not null;default:0"`
 	EnabledAutoStoreConfirmation bool           `json:"enabled_auto_store_confirmation" gorm:"column:enabled_auto_store_confirmation"`
 	TagLine                      string         `json:"tag_line" gorm:"column:tag_line;not null"`
+	IsTwoFactorRequired          bool           `json:"is_two_factor_required" gorm:"column:is_two_factor_required;not null;default:false"`
 	CreatedAt                    time.Time      `json:"created_at" gorm:"column:created_at;not null"`
 	UpdatedAt                    time.Time      `json:"updated_at" gorm:"column:updated_at;not null"`
 }
@@ -18,6 +18,7 @@ type SettingsDetails struct {
 	EnabledAutoStoreConfirmation bool           `json:"enabled_auto_store_confirmation"`
 	DefaultCommissionRate        int64          `json:"default_commission_rate"`
 	TagLine                      string         `json:"tag_line"`
+	IsTwoFactorRequired          bool           `json:"is_two_factor_required"`
 	CreatedAt                    time.Time      `json:"created_at"`
 	UpdatedAt                    time.Time      `json:"updated_at"`
 }
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 const (
 	Ounce WeightUnit = "ounce"
@@ -18,23 +22,119 @@ func (wu WeightUnit) IsValid() bool {
 	return false
 }
 
+func (wu WeightUnit) FromGrams(grams int) int {
+	if wu == Ounce {
+		return int(math.Ceil(float64(grams) / 28.3495))
+	}
+	return grams
+}
+
 type ShippingMethod struct {
 	ID                      string     `json:"id" sql:"id" gorm:"primary_key"`
-	Name                    string     `json:"name" sql:"name" gorm:"unique;not null"`
+	StoreID                 *string    `json:"store_id,omitempty" gorm:"column:store_id;unique_index:uix_shipping_methods_store_id_name"`
+	CarrierID               *string    `json:"carrier_id,omitempty" gorm:"column:carrier_id;index"`
+	Name                    string     `json:"name" sql:"name" gorm:"unique_index:uix_shipping_methods_store_id_name;not null"`
 	ApproximateDeliveryTime int        `json:"approximate_delivery_time" gorm:"approximate_delivery_time" gorm:"index"`
+	HandlingTime            int        `json:"handling_time" gorm:"column:handling_time;not null;default:0"`
 	DeliveryCharge          int64      `json:"delivery_charge" sql:"delivery_charge" gorm:"index"`
 	WeightUnit              WeightUnit `json:"weight_unit" sql:"weight_unit"`
 	IsFlat                  bool       `json:"is_flat" gorm:"column:is_flat"`
+	PerKgRate               int64      `json:"per_kg_rate" gorm:"column:per_kg_rate;not null;default:0"`
+	VolumetricDivisor       int        `json:"volumetric_divisor" gorm:"column:volumetric_divisor;not null;default:0"`
+	FreeShippingThreshold   int64      `json:"free_shipping_threshold" gorm:"column:free_shipping_threshold;not null;default:0"`
 	IsPublished             bool       `json:"is_published" sql:"is_published" gorm:"index"`
 	CreatedAt               time.Time  `json:"created_at" sql:"created_at" gorm:"not null;index"`
 	UpdatedAt               time.Time  `json:"updated_at" sql:"updated_at" gorm:"not null"`
+
+	WeightBrackets []ShippingWeightBracket `json:"weight_brackets" gorm:"-"`
 }
 
 func (sm *ShippingMethod) TableName() string {
 	return "shipping_methods"
 }
 
-func (sm *ShippingMethod) CalculateDeliveryCharge(weight int) int64 {
-	// TODO : Currently weight doesn't have any impact on charge
-	return sm.DeliveryCharge
+func (sm *ShippingMethod) ForeignKeys() []string {
+	s := Store{}
+
+	return []string{
+		fmt.Sprintf("store_id;%s(id);CASCADE;RESTRICT", s.TableName()),
+		fmt.Sprintf("carrier_id;%s(id);RESTRICT;RESTRICT", sm.TableName()),
+	}
+}
+
+// IsOwnedByStore returns true if the shipping method is defined by a store instead of the platform
+func (sm *ShippingMethod) IsOwnedByStore() bool {
+	return sm.StoreID != nil
+}
+
+// ApplyZoneRate replaces the default rates of the shipping method by the rates of a shipping zone,
+// the weight brackets of the method are its default rates too and don't apply in the zone
+func (sm *ShippingMethod) ApplyZoneRate(r *ShippingZoneRate) {
+	sm.WeightBrackets = nil
+	sm.DeliveryCharge = r.DeliveryCharge
+	sm.PerKgRate = r.PerKgRate
+	sm.FreeShippingThreshold = r.FreeShippingThreshold
+}
+
+// ChargeableWeight returns the greater of the actual weight and the volumetric weight in grams,
+// volume is in cubic centimeters and the volumetric divisor in cubic centimeters per kg
+func (sm *ShippingMethod) ChargeableWeight(weight int, volume int64) int {
+	if sm.VolumetricDivisor <= 0 {
+		return weight
+	}
+
+	volumetricWeight := int(math.Ceil(float64(volume) * 1000 / float64(sm.VolumetricDivisor)))
+	if volumetricWeight > weight {
+		return volumetricWeight
+	}
+	return weight
+}
+
+// CalculateDeliveryCharge calculates the charge of an order having the given weight in grams,
+// volume in cubic centimeters and sub total
+func (sm *ShippingMethod) CalculateDeliveryCharge(weight int, volume int64, subTotal int64) int64 {
+	if sm.FreeShippingThreshold > 0 && subTotal >= sm.FreeShippingThreshold {
+		return 0
+	}
+	if sm.IsFlat {
+		return sm.DeliveryCharge
+	}
+
+	chargeableWeight := sm.ChargeableWeight(weight, volume)
+
+	inUnit := sm.WeightUnit.FromGrams(chargeableWeight)
+	for _, b := range sm.WeightBrackets {
+		if b.Matches(inUnit) {
+			return b.Charge
+		}
+	}
+
+	kgs := int64(math.Ceil(float64(chargeableWeight) / 1000))
+	return sm.DeliveryCharge + kgs*sm.PerKgRate
+}
+
+type ShippingWeightBracket struct {
+	ID               string `json:"id" gorm:"column:id;primary_key"`
+	ShippingMethodID string `json:"-" gorm:"column:shipping_method_id;index;not null"`
+	MinWeight        int    `json:"min_weight" gorm:"column:min_weight;not null"`
+	MaxWeight        int    `json:"max_weight" gorm:"column:max_weight;not null;default:0"`
+	Charge           int64  `json:"charge" gorm:"column:charge;not null"`
+}
+
+func (swb *ShippingWeightBracket) TableName() string {
+	return "shipping_weight_brackets"
+}
+
+func (swb *ShippingWeightBracket) ForeignKeys() []string {
+	sm := ShippingMethod{}
+
+	return []string{
+		fmt.Sprintf("shipping_method_id;%s(id);CASCADE;RESTRICT", sm.TableName()),
+	}
+}
+
+// Matches reports whether the weight, in the weight unit of the shipping method, falls into the bracket,
+// max weight 0 means the bracket has no upper bound
+func (swb *ShippingWeightBracket) Matches(weight int) bool {
+	return weight >= swb.MinWeight && (swb.MaxWeight == 0 || weight < swb.MaxWeight)
 }
@@ -0,0 +1,61 @@
+package models
+
+import "testing"
+
+func TestCalculateDeliveryChargeWithZoneRate(t *testing.T) {
+	newMethod := func() *ShippingMethod {
+		return &ShippingMethod{
+			WeightUnit:     Gram,
+			DeliveryCharge: 500,
+			PerKgRate:      100,
+			WeightBrackets: []ShippingWeightBracket{
+				{MinWeight: 0, MaxWeight: 1000, Charge: 300},
+				{MinWeight: 1000, Charge: 900},
+			},
+		}
+	}
+
+	cases := []struct {
+		name     string
+		rate     *ShippingZoneRate
+		weight   int
+		subTotal int64
+		charge   int64
+	}{
+		{name: "bracket without zone rate", weight: 500, subTotal: 1000, charge: 300},
+		{name: "upper bracket without zone rate", weight: 2500, subTotal: 1000, charge: 900},
+		{
+			name:     "zone rate overrides brackets",
+			rate:     &ShippingZoneRate{DeliveryCharge: 2000, PerKgRate: 400},
+			weight:   500,
+			subTotal: 1000,
+			charge:   2400,
+		},
+		{
+			name:     "zone rate charged per kg",
+			rate:     &ShippingZoneRate{DeliveryCharge: 2000, PerKgRate: 400},
+			weight:   2500,
+			subTotal: 1000,
+			charge:   3200,
+		},
+		{
+			name:     "zone free shipping threshold",
+			rate:     &ShippingZoneRate{DeliveryCharge: 2000, FreeShippingThreshold: 1000},
+			weight:   500,
+			subTotal: 1000,
+			charge:   0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sm := newMethod()
+			if c.rate != nil {
+				sm.ApplyZoneRate(c.rate)
+			}
+			if got := sm.CalculateDeliveryCharge(c.weight, 0, c.subTotal); got != c.charge {
+				t.Errorf("got charge %d, want %d", got, c.charge)
+			}
+		})
+	}
+}
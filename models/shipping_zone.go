@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type ShippingZone struct {
+	ID          string    `json:"id" gorm:"column:id;primary_key"`
+	Name        string    `json:"name" gorm:"column:name;unique;not null"`
+	Description string    `json:"description" gorm:"column:description"`
+	IsPublished bool      `json:"is_published" gorm:"column:is_published;index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at;not null"`
+
+	Locations []Location         `json:"locations,omitempty" gorm:"-"`
+	Rates     []ShippingZoneRate `json:"rates,omitempty" gorm:"-"`
+}
+
+func (sz *ShippingZone) TableName() string {
+	return "shipping_zones"
+}
+
+type ShippingZoneLocation struct {
+	ZoneID     string `json:"zone_id" gorm:"column:zone_id;primary_key"`
+	LocationID int64  `json:"location_id" gorm:"column:location_id;primary_key"`
+}
+
+func (szl *ShippingZoneLocation) TableName() string {
+	return "shipping_zone_locations"
+}
+
+func (szl *ShippingZoneLocation) ForeignKeys() []string {
+	sz := ShippingZone{}
+	l := Location{}
+
+	return []string{
+		fmt.Sprintf("zone_id;%s(id);CASCADE;RESTRICT", sz.TableName()),
+		fmt.Sprintf("location_id;%s(id);RESTRICT;RESTRICT", l.TableName()),
+	}
+}
+
+type ShippingZoneRate struct {
+	ZoneID                string    `json:"zone_id" gorm:"column:zone_id;primary_key"`
+	ShippingMethodID      string    `json:"shipping_method_id" gorm:"column:shipping_method_id;primary_key"`
+	DeliveryCharge        int64     `json:"delivery_charge" gorm:"column:delivery_charge;not null"`
+	PerKgRate             int64     `json:"per_kg_rate" gorm:"column:per_kg_rate;not null;default:0"`
+	FreeShippingThreshold int64     `json:"free_shipping_threshold" gorm:"column:free_shipping_threshold;not null;default:0"`
+	CreatedAt             time.Time `json:"created_at" gorm:"column:created_at;not null"`
+	UpdatedAt             time.Time `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (szr *ShippingZoneRate) TableName() string {
+	return "shipping_zone_rates"
+}
+
+func (szr *ShippingZoneRate) ForeignKeys() []string {
+	sz := ShippingZone{}
+	sm := ShippingMethod{}
+
+	return []string{
+		fmt.Sprintf("zone_id;%s(id);CASCADE;RESTRICT", sz.TableName()),
+		fmt.Sprintf("shipping_method_id;%s(id);CASCADE;RESTRICT", sm.TableName()),
+	}
+}
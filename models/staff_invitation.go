@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type StaffInvitationStatus string
+
+const (
+	StaffInvitationPending  StaffInvitationStatus = "pending"
+	StaffInvitationAccepted StaffInvitationStatus = "accepted"
+	StaffInvitationDeclined StaffInvitationStatus = "declined"
+	StaffInvitationRevoked  StaffInvitationStatus = "revoked"
+	StaffInvitationExpired  StaffInvitationStatus = "expired"
+)
+
+func (s StaffInvitationStatus) IsValid() bool {
+	for _, v := range []StaffInvitationStatus{StaffInvitationPending, StaffInvitationAccepted,
+		StaffInvitationDeclined, StaffInvitationRevoked, StaffInvitationExpired} {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// StaffInvitation invites an email to join the staffs of a store. TokenHash is set when the
+// invitation email is sent, so the token itself is only known to the recipient.
+type StaffInvitation struct {
+	ID           string                `json:"id" gorm:"column:id;primary_key"`
+	StoreID      string                `json:"store_id" gorm:"column:store_id;index;not null"`
+	Email        string                `json:"email" gorm:"column:email;index;not null"`
+	PermissionID string                `json:"permission_id" gorm:"column:permission_id;not null"`
+	RoleID       *string               `json:"role_id" gorm:"column:role_id;index"`
+	TokenHash    *string               `json:"-" gorm:"column:token_hash;unique"`
+	Status       StaffInvitationStatus `json:"status" gorm:"column:status;index;not null"`
+	InvitedBy    string                `json:"invited_by" gorm:"column:invited_by;index;not null"`
+	ExpiresAt    time.Time             `json:"expires_at" gorm:"column:expires_at;index;not null"`
+	RespondedAt  *time.Time            `json:"responded_at" gorm:"column:responded_at"`
+	CreatedAt    time.Time             `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt    time.Time             `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (si *StaffInvitation) TableName() string {
+	return "staff_invitations"
+}
+
+func (si *StaffInvitation) ForeignKeys() []string {
+	u := User{}
+	s := Store{}
+	sp := StorePermission{}
+	sr := StoreRole{}
+
+	return []string{
+		fmt.Sprintf("store_id;%s(id);CASCADE;CASCADE", s.TableName()),
+		fmt.Sprintf("permission_id;%s(id);RESTRICT;RESTRICT", sp.TableName()),
+		fmt.Sprintf("role_id;%s(id);SET NULL;CASCADE", sr.TableName()),
+		fmt.Sprintf("invited_by;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
+
+// StaffInvitationDetails is an invitation as shown to the recipient
+type StaffInvitationDetails struct {
+	ID           string                `json:"id"`
+	StoreID      string                `json:"store_id"`
+	StoreName    string                `json:"store_name"`
+	Email        string                `json:"email"`
+	PermissionID string                `json:"permission_id"`
+	InviterName  string                `json:"inviter_name"`
+	Status       StaffInvitationStatus `json:"status"`
+	ExpiresAt    time.Time             `json:"expires_at"`
+	// IsRegistered tells whether the recipient has to sign up before accepting
+	IsRegistered bool `json:"is_registered"`
+}
+
+type StaffAuditAction string
+
+const (
+	StaffAuditInvited            StaffAuditAction = "invited"
+	StaffAuditInvitationRevoked  StaffAuditAction = "invitation_revoked"
+	StaffAuditInvitationAccepted StaffAuditAction = "invitation_accepted"
+	StaffAuditInvitationDeclined StaffAuditAction = "invitation_declined"
+	StaffAuditPermissionChanged  StaffAuditAction = "permission_changed"
+	StaffAuditRoleChanged        StaffAuditAction = "role_changed"
+	StaffAuditRemoved            StaffAuditAction = "removed"
+)
+
+// StaffAuditLog records a change to the staffs of a store. ActorID is empty when the recipient
+// of an invitation acted without signing in.
+type StaffAuditLog struct {
+	ID           string           `json:"id" gorm:"column:id;primary_key"`
+	StoreID      string           `json:"store_id" gorm:"column:store_id;index;not null"`
+	ActorID      *string          `json:"actor_id" gorm:"column:actor_id;index"`
+	Action       StaffAuditAction `json:"action" gorm:"column:action;index;not null"`
+	TargetUserID *string          `json:"target_user_id" gorm:"column:target_user_id;index"`
+	TargetEmail  string           `json:"target_email" gorm:"column:target_email"`
+	InvitationID *string          `json:"invitation_id" gorm:"column:invitation_id;index"`
+	PermissionID *string          `json:"permission_id" gorm:"column:permission_id"`
+	RoleID       *string          `json:"role_id" gorm:"column:role_id"`
+	CreatedAt    time.Time        `json:"created_at" gorm:"column:created_at;index;not null"`
+}
+
+func (sal *StaffAuditLog) TableName() string {
+	return "staff_audit_logs"
+}
+
+func (sal *StaffAuditLog) ForeignKeys() []string {
+	s := Store{}
+
+	return []string{
+		fmt.Sprintf("store_id;%s(id);CASCADE;CASCADE", s.TableName()),
+	}
+}
+
+// StaffAuditLogDetails is an audit log entry with the names of the actor and the target
+type StaffAuditLogDetails struct {
+	StaffAuditLog
+	ActorName      *string `json:"actor_name"`
+	ActorEmail     *string `json:"actor_email"`
+	TargetUserName *string `json:"target_user_name"`
+}
@@ -63,10 +63,12 @@ func (s *Store) CalculateCommission(value int64) int64 {
 }
 
 type Staff struct {
-	UserID       string `json:"user_id" gorm:"column:user_id;primary_key;unique"`
-	StoreID      string `json:"store_id" gorm:"column:store_id;primary_key"`
+	UserID       string `json:"user_id" gorm:"column:user_id;primary_key;unique_index:uix_staffs_user_id_store_id"`
+	StoreID      string `json:"store_id" gorm:"column:store_id;primary_key;unique_index:uix_staffs_user_id_store_id"`
 	PermissionID string `json:"permission_id" gorm:"column:permission_id;primary_key"`
 	IsCreator    bool   `json:"is_creator" gorm:"column:is_creator;index"`
+	// RoleID replaces the default permissions of a manager with the ones of the role
+	RoleID *string `json:"role_id" gorm:"column:role_id;index"`
 }
 
 func (sf *Staff) TableName() string {
@@ -77,11 +79,13 @@ func (sf *Staff) ForeignKeys() []string {
 	u := User{}
 	s := Store{}
 	sp := StorePermission{}
+	sr := StoreRole{}
 
 	return []string{
 		fmt.Sprintf("user_id;%s(id);RESTRICT;RESTRICT", u.TableName()),
 		fmt.Sprintf("store_id;%s(id);RESTRICT;RESTRICT", s.TableName()),
 		fmt.Sprintf("permission_id;%s(id);RESTRICT;RESTRICT", sp.TableName()),
+		fmt.Sprintf("role_id;%s(id);SET NULL;CASCADE", sr.TableName()),
 	}
 }
 
@@ -93,6 +97,8 @@ type StaffProfile struct {
 	StaffPhone      string      `json:"staff_phone"`
 	StaffPicture    string      `json:"staff_picture"`
 	StaffPermission Permission  `json:"staff_permission"`
+	StaffRoleID     *string     `json:"staff_role_id"`
+	StaffRoleName   *string     `json:"staff_role_name"`
 	StaffStatus     UserStatus  `json:"staff_status"`
 	IsCreator       bool        `json:"is_creator"`
 	StoreStatus     StoreStatus `json:"store_status"`
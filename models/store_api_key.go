@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// StoreAPIKeyPrefix starts every store api key, telling it apart from a user access token
+const StoreAPIKeyPrefix = "sk_"
+
+// StoreAPIKey lets an integration act on a store on behalf of the staff who created it, limited to
+// the scopes of the key. Only the hash of the key is kept, Prefix identifies it afterwards.
+type StoreAPIKey struct {
+	ID         string      `json:"id" gorm:"column:id;primary_key"`
+	StoreID    string      `json:"store_id" gorm:"column:store_id;index;not null"`
+	Name       string      `json:"name" gorm:"column:name;not null"`
+	Prefix     string      `json:"prefix" gorm:"column:prefix;not null"`
+	KeyHash    string      `json:"-" gorm:"column:key_hash;unique;not null"`
+	Scopes     []StorePerm `json:"scopes" gorm:"-"`
+	CreatedBy  string      `json:"created_by" gorm:"column:created_by;index;not null"`
+	ExpiresAt  *time.Time  `json:"expires_at" gorm:"column:expires_at"`
+	LastUsedAt *time.Time  `json:"last_used_at" gorm:"column:last_used_at"`
+	LastUsedIP string      `json:"last_used_ip" gorm:"column:last_used_ip"`
+	RevokedAt  *time.Time  `json:"revoked_at" gorm:"column:revoked_at"`
+	CreatedAt  time.Time   `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt  time.Time   `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (k *StoreAPIKey) TableName() string {
+	return "store_api_keys"
+}
+
+func (k *StoreAPIKey) ForeignKeys() []string {
+	s := Store{}
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("store_id;%s(id);CASCADE;CASCADE", s.TableName()),
+		fmt.Sprintf("created_by;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
+
+func (k *StoreAPIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
+
+type StoreAPIKeyScope struct {
+	APIKeyID   string    `json:"-" gorm:"column:api_key_id;primary_key"`
+	Permission StorePerm `json:"-" gorm:"column:permission;primary_key"`
+}
+
+func (ks *StoreAPIKeyScope) TableName() string {
+	return "store_api_key_scopes"
+}
+
+func (ks *StoreAPIKeyScope) ForeignKeys() []string {
+	k := StoreAPIKey{}
+
+	return []string{
+		fmt.Sprintf("api_key_id;%s(id);CASCADE;CASCADE", k.TableName()),
+	}
+}
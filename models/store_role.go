@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	StorePermProductsWrite  StorePerm = "products:write"
+	StorePermCatalogManage  StorePerm = "catalog:manage"
+	StorePermOrdersManage   StorePerm = "orders:manage"
+	StorePermOrdersRefund   StorePerm = "orders:refund"
+	StorePermCustomersRead  StorePerm = "customers:read"
+	StorePermCouponsManage  StorePerm = "coupons:manage"
+	StorePermPayoutsRequest StorePerm = "payouts:request"
+	StorePermStaffManage    StorePerm = "staff:manage"
+	StorePermStatsRead      StorePerm = "stats:read"
+	StorePermStoreManage    StorePerm = "store:manage"
+)
+
+// StorePerm is a granular permission of store staff, granted through a role
+type StorePerm string
+
+var storePermDescriptions = map[StorePerm]string{
+	StorePermProductsWrite:  "Create, update and delete products",
+	StorePermCatalogManage:  "Create, update and delete categories and collections",
+	StorePermOrdersManage:   "List orders and update their status",
+	StorePermOrdersRefund:   "Refund orders and update their payment status",
+	StorePermCustomersRead:  "List the customers",
+	StorePermCouponsManage:  "Create, update and delete coupons",
+	StorePermPayoutsRequest: "Manage payout settings and request payouts",
+	StorePermStaffManage:    "Add and remove staff, manage roles",
+	StorePermStatsRead:      "Read the store statistics",
+	StorePermStoreManage:    "Read and update the store details",
+}
+
+// StorePerms is the permission catalogue
+var StorePerms = []StorePerm{
+	StorePermProductsWrite,
+	StorePermCatalogManage,
+	StorePermOrdersManage,
+	StorePermOrdersRefund,
+	StorePermCustomersRead,
+	StorePermCouponsManage,
+	StorePermPayoutsRequest,
+	StorePermStaffManage,
+	StorePermStatsRead,
+	StorePermStoreManage,
+}
+
+func (sp StorePerm) IsValid() bool {
+	_, ok := storePermDescriptions[sp]
+	return ok
+}
+
+func (sp StorePerm) Description() string {
+	return storePermDescriptions[sp]
+}
+
+// twoFactorStorePerms move money or grant access, exercising them requires two factor authentication whatever the staff level is
+var twoFactorStorePerms = map[StorePerm]bool{
+	StorePermOrdersRefund:   true,
+	StorePermPayoutsRequest: true,
+	StorePermStaffManage:    true,
+}
+
+func (sp StorePerm) RequiresTwoFactor() bool {
+	return twoFactorStorePerms[sp]
+}
+
+// DefaultStorePerms are the permissions of a staff without a role. Store admins have every
+// permission whatever their role is.
+func DefaultStorePerms(level Permission) []StorePerm {
+	switch level {
+	case AdminPerm:
+		return StorePerms
+	case ManagerPerm:
+		return []StorePerm{StorePermProductsWrite, StorePermCatalogManage, StorePermOrdersManage, StorePermCustomersRead,
+			StorePermCouponsManage, StorePermStatsRead, StorePermStoreManage}
+	}
+	return []StorePerm{}
+}
+
+type StoreRole struct {
+	ID          string      `json:"id" gorm:"column:id;primary_key"`
+	StoreID     string      `json:"store_id" gorm:"column:store_id;not null;unique_index:uix_store_roles_store_id_name"`
+	Name        string      `json:"name" gorm:"column:name;not null;unique_index:uix_store_roles_store_id_name"`
+	Permissions []StorePerm `json:"permissions" gorm:"-"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"column:created_at;not null"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (sr *StoreRole) TableName() string {
+	return "store_roles"
+}
+
+func (sr *StoreRole) ForeignKeys() []string {
+	s := Store{}
+
+	return []string{
+		fmt.Sprintf("store_id;%s(id);CASCADE;CASCADE", s.TableName()),
+	}
+}
+
+type StoreRolePermission struct {
+	RoleID     string    `json:"-" gorm:"column:role_id;primary_key"`
+	Permission StorePerm `json:"-" gorm:"column:permission;primary_key"`
+}
+
+func (srp *StoreRolePermission) TableName() string {
+	return "store_role_permissions"
+}
+
+func (srp *StoreRolePermission) ForeignKeys() []string {
+	sr := StoreRole{}
+
+	return []string{
+		fmt.Sprintf("role_id;%s(id);CASCADE;CASCADE", sr.TableName()),
+	}
+}
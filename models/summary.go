@@ -7,5 +7,6 @@ type Summary struct {
 	Expenses    int    `json:"expenses"`
 	Profits     int    `json:"profits"`
 	Discounts   int    `json:"discounts"`
+	Taxes       int    `json:"taxes"`
 	Customers   int    `json:"customers"`
 }
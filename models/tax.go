@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+type TaxClass struct {
+	ID          string    `json:"id" gorm:"column:id;primary_key"`
+	Name        string    `json:"name" gorm:"column:name;unique;not null"`
+	Description string    `json:"description" gorm:"column:description"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (tc *TaxClass) TableName() string {
+	return "tax_classes"
+}
+
+// TaxRate is applied to the products of the tax class, or to the products without
+// any tax class when TaxClassID is nil, delivered or billed to the location
+type TaxRate struct {
+	ID          string    `json:"id" gorm:"column:id;primary_key"`
+	TaxClassID  *string   `json:"tax_class_id" gorm:"column:tax_class_id;index"`
+	LocationID  int64     `json:"location_id" gorm:"column:location_id;index;not null"`
+	Name        string    `json:"name" gorm:"column:name;not null"`
+	Rate        float64   `json:"rate" gorm:"column:rate;not null"`
+	Priority    int       `json:"priority" gorm:"column:priority;not null;default:0"`
+	IsCompound  bool      `json:"is_compound" gorm:"column:is_compound;not null;default:false"`
+	IsInclusive bool      `json:"is_inclusive" gorm:"column:is_inclusive;not null;default:false"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (tr *TaxRate) TableName() string {
+	return "tax_rates"
+}
+
+func (tr *TaxRate) ForeignKeys() []string {
+	tc := TaxClass{}
+	l := Location{}
+
+	return []string{
+		fmt.Sprintf("tax_class_id;%s(id);CASCADE;RESTRICT", tc.TableName()),
+		fmt.Sprintf("location_id;%s(id);RESTRICT;RESTRICT", l.TableName()),
+	}
+}
+
+// CalculateTax returns the taxes already included in the amount and the taxes to be added on top of it.
+// Rates are applied by priority, a compound rate is applied on the amount plus the taxes applied before it.
+func CalculateTax(amount int64, rates []TaxRate) (included int64, excluded int64) {
+	if amount == 0 || len(rates) == 0 {
+		return 0, 0
+	}
+
+	sorted := make([]TaxRate, len(rates))
+	copy(sorted, rates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+
+	apply := func(base float64, inclusive bool, taxed float64) float64 {
+		for _, r := range sorted {
+			if r.IsInclusive != inclusive {
+				continue
+			}
+			if r.IsCompound {
+				taxed += (base + taxed) * r.Rate / 100
+			} else {
+				taxed += base * r.Rate / 100
+			}
+		}
+		return taxed
+	}
+
+	// Net amount is resolved from the ratio of the inclusive taxes over a unit amount
+	factor := 1 + apply(1, true, 0)
+	net := float64(amount) / factor
+	includedTaxes := float64(amount) - net
+
+	excludedTaxes := apply(net, false, includedTaxes) - includedTaxes
+
+	return int64(math.Round(includedTaxes)), int64(math.Round(excludedTaxes))
+}
+
+// TaxExemption is the VAT registration a business buyer claims, orders are exempted from taxes
+// only once a platform manager verified it and only when delivered to its country
+type TaxExemption struct {
+	ID         string     `json:"id" gorm:"column:id;primary_key"`
+	UserID     string     `json:"user_id" gorm:"column:user_id;unique_index;not null"`
+	CountryID  int64      `json:"country_id" gorm:"column:country_id;not null"`
+	VatNumber  string     `json:"vat_number" gorm:"column:vat_number;not null"`
+	IsVerified bool       `json:"is_verified" gorm:"column:is_verified;not null;default:false;index"`
+	VerifiedBy *string    `json:"verified_by,omitempty" gorm:"column:verified_by"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty" gorm:"column:verified_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (te *TaxExemption) TableName() string {
+	return "tax_exemptions"
+}
+
+func (te *TaxExemption) ForeignKeys() []string {
+	u := User{}
+	l := Location{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);RESTRICT;RESTRICT", u.TableName()),
+		fmt.Sprintf("country_id;%s(id);RESTRICT;RESTRICT", l.TableName()),
+		fmt.Sprintf("verified_by;%s(id);RESTRICT;RESTRICT", u.TableName()),
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// TwoFactor is the TOTP enrollment of a user, it's enabled once the first code is verified
+type TwoFactor struct {
+	UserID       string     `json:"-" gorm:"column:user_id;primary_key"`
+	Secret       string     `json:"-" gorm:"column:secret;not null"`
+	IsEnabled    bool       `json:"is_enabled" gorm:"column:is_enabled;not null"`
+	LastUsedStep int64      `json:"-" gorm:"column:last_used_step;not null;default:0"`
+	EnabledAt    *time.Time `json:"enabled_at" gorm:"column:enabled_at"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"column:created_at;not null"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (tf *TwoFactor) TableName() string {
+	return "two_factors"
+}
+
+func (tf *TwoFactor) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
+
+// TwoFactorRecoveryCode can be used once in place of a TOTP code, only its hash is stored
+type TwoFactorRecoveryCode struct {
+	ID        string     `json:"-" gorm:"column:id;primary_key"`
+	UserID    string     `json:"-" gorm:"column:user_id;index;not null"`
+	CodeHash  string     `json:"-" gorm:"column:code_hash;not null"`
+	UsedAt    *time.Time `json:"-" gorm:"column:used_at"`
+	CreatedAt time.Time  `json:"-" gorm:"column:created_at;not null"`
+}
+
+func (rc *TwoFactorRecoveryCode) TableName() string {
+	return "two_factor_recovery_codes"
+}
+
+func (rc *TwoFactorRecoveryCode) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
+
+// TwoFactorChallenge is the pending second step of a login whose password has been verified
+type TwoFactorChallenge struct {
+	ID        string    `json:"-" gorm:"column:id;primary_key"`
+	UserID    string    `json:"-" gorm:"column:user_id;index;not null"`
+	TokenHash string    `json:"-" gorm:"column:token_hash;unique;not null"`
+	Scope     string    `json:"-" gorm:"column:scope;not null"`
+	Attempts  int       `json:"-" gorm:"column:attempts;not null;default:0"`
+	ExpiresAt time.Time `json:"-" gorm:"column:expires_at;index;not null"`
+	CreatedAt time.Time `json:"-" gorm:"column:created_at;not null"`
+}
+
+func (c *TwoFactorChallenge) TableName() string {
+	return "two_factor_challenges"
+}
+
+func (c *TwoFactorChallenge) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
@@ -10,6 +10,8 @@ const (
 	UserActive     UserStatus = "active"
 	UserBanned     UserStatus = "banned"
 	UserSuspended  UserStatus = "suspended"
+	// UserDeleted is set once the personal data is anonymised, it can't be set by the admins
+	UserDeleted UserStatus = "deleted"
 
 	AdminPerm   Permission = "admin"
 	ManagerPerm Permission = "manager"
@@ -41,6 +43,7 @@ type User struct {
 	Status                        UserStatus `json:"status" gorm:"column:status;index;not null"`
 	IsEmailVerified               bool       `json:"is_email_verified" gorm:"column:is_email_verified"`
 	PermissionID                  string     `json:"-" gorm:"column:permission_id;index;not null"`
+	Locale                        string     `json:"locale" gorm:"column:locale;not null;default:'en'"`
 	CreatedAt                     time.Time  `json:"created_at" gorm:"column:created_at;index"`
 	UpdatedAt                     time.Time  `json:"updated_at" gorm:"column:updated_at"`
 }
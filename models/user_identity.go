@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// UserIdentity links a user to the subject of an OpenID Connect provider
+type UserIdentity struct {
+	ID              string     `json:"id" gorm:"column:id;primary_key"`
+	UserID          string     `json:"-" gorm:"column:user_id;index;not null"`
+	Provider        string     `json:"provider" gorm:"column:provider;not null;unique_index:uix_user_identities_provider_subject"`
+	Subject         string     `json:"-" gorm:"column:subject;not null;unique_index:uix_user_identities_provider_subject"`
+	Email           string     `json:"email" gorm:"column:email"`
+	IsEmailVerified bool       `json:"is_email_verified" gorm:"column:is_email_verified;not null"`
+	LastLoginAt     *time.Time `json:"last_login_at" gorm:"column:last_login_at"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"column:created_at;not null"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (ui *UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+func (ui *UserIdentity) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
+
+// OIDCState is a pending authorization with a provider. UserID is set when an identity
+// is being linked to a signed in user rather than signing in.
+type OIDCState struct {
+	ID           string    `json:"-" gorm:"column:id;primary_key"`
+	StateHash    string    `json:"-" gorm:"column:state_hash;unique;not null"`
+	Provider     string    `json:"-" gorm:"column:provider;not null"`
+	Nonce        string    `json:"-" gorm:"column:nonce;not null"`
+	CodeVerifier string    `json:"-" gorm:"column:code_verifier;not null"`
+	Scope        string    `json:"-" gorm:"column:scope;not null"`
+	UserID       *string   `json:"-" gorm:"column:user_id;index"`
+	ExpiresAt    time.Time `json:"-" gorm:"column:expires_at;index;not null"`
+	CreatedAt    time.Time `json:"-" gorm:"column:created_at;not null"`
+}
+
+func (s *OIDCState) TableName() string {
+	return "oidc_states"
+}
+
+func (s *OIDCState) ForeignKeys() []string {
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("user_id;%s(id);CASCADE;CASCADE", u.TableName()),
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	WebhookOrderCreated       WebhookEvent = "order.created"
+	WebhookOrderStatusChanged WebhookEvent = "order.status_changed"
+	WebhookPaymentCompleted   WebhookEvent = "payment.completed"
+	WebhookPaymentReverted    WebhookEvent = "payment.reverted"
+	WebhookProductUpdated     WebhookEvent = "product.updated"
+	WebhookPayoutUpdated      WebhookEvent = "payout.updated"
+	WebhookPing               WebhookEvent = "ping"
+
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookEvent string
+
+// IsValid reports whether a webhook can subscribe to the event, ping is sent on request only
+func (we WebhookEvent) IsValid() bool {
+	for _, v := range WebhookEvents() {
+		if v == we {
+			return true
+		}
+	}
+	return false
+}
+
+func WebhookEvents() []WebhookEvent {
+	return []WebhookEvent{WebhookOrderCreated, WebhookOrderStatusChanged, WebhookPaymentCompleted,
+		WebhookPaymentReverted, WebhookProductUpdated, WebhookPayoutUpdated}
+}
+
+type WebhookDeliveryStatus string
+
+// Webhook is an endpoint registered by a store, or by the platform when StoreID is nil, to receive events on.
+// Platform webhooks receive the events of every store.
+type Webhook struct {
+	ID        string    `json:"id" gorm:"column:id;primary_key"`
+	StoreID   *string   `json:"store_id,omitempty" gorm:"column:store_id;index"`
+	URL       string    `json:"url" gorm:"column:url;not null"`
+	Secret    string    `json:"secret,omitempty" gorm:"column:secret;not null"`
+	IsActive  bool      `json:"is_active" gorm:"column:is_active;not null"`
+	CreatedBy string    `json:"created_by" gorm:"column:created_by;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;not null"`
+
+	Events []WebhookEvent `json:"events" gorm:"-"`
+}
+
+func (w *Webhook) TableName() string {
+	return "webhooks"
+}
+
+func (w *Webhook) ForeignKeys() []string {
+	s := Store{}
+	u := User{}
+
+	return []string{
+		fmt.Sprintf("store_id;%s(id);CASCADE;RESTRICT", s.TableName()),
+		fmt.Sprintf("created_by;%s(id);RESTRICT;RESTRICT", u.TableName()),
+	}
+}
+
+type WebhookSubscription struct {
+	WebhookID string       `json:"webhook_id" gorm:"column:webhook_id;primary_key"`
+	Event     WebhookEvent `json:"event" gorm:"column:event;primary_key"`
+}
+
+func (ws *WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+func (ws *WebhookSubscription) ForeignKeys() []string {
+	w := Webhook{}
+
+	return []string{
+		fmt.Sprintf("webhook_id;%s(id);CASCADE;RESTRICT", w.TableName()),
+	}
+}
+
+// WebhookDelivery is the log of an event sent to a webhook, keeping the response status of the last attempt
+type WebhookDelivery struct {
+	ID             string                `json:"id" gorm:"column:id;primary_key"`
+	WebhookID      string                `json:"webhook_id" gorm:"column:webhook_id;index;not null"`
+	Event          WebhookEvent          `json:"event" gorm:"column:event;index;not null"`
+	Payload        string                `json:"payload" gorm:"column:payload;type:text;not null"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"column:status;index;not null"`
+	Attempts       int                   `json:"attempts" gorm:"column:attempts;not null;default:0"`
+	ResponseStatus int                   `json:"response_status" gorm:"column:response_status"`
+	Error          string                `json:"error,omitempty" gorm:"column:error"`
+	Duration       int64                 `json:"duration" gorm:"column:duration"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" gorm:"column:next_attempt_at;index"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" gorm:"column:delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" gorm:"column:created_at;index;not null"`
+	UpdatedAt      time.Time             `json:"updated_at" gorm:"column:updated_at;not null"`
+}
+
+func (wd *WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (wd *WebhookDelivery) ForeignKeys() []string {
+	w := Webhook{}
+
+	return []string{
+		fmt.Sprintf("webhook_id;%s(id);CASCADE;RESTRICT", w.TableName()),
+	}
+}
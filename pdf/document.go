@@ -0,0 +1,138 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Document is a minimal single column PDF writer using the standard Helvetica fonts,
+// so no font has to be embedded into the generated file
+type Document struct {
+	pages  []*bytes.Buffer
+	margin float64
+}
+
+func NewDocument(margin float64) *Document {
+	d := &Document{margin: margin}
+	d.AddPage()
+	return d
+}
+
+func (d *Document) Margin() float64 {
+	return d.margin
+}
+
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) current() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Text writes s with its baseline at y, measured from the top of the page
+func (d *Document) Text(x, y float64, font Font, size float64, align Align, s string) {
+	switch align {
+	case AlignRight:
+		x -= TextWidth(font, size, s)
+	case AlignCenter:
+		x -= TextWidth(font, size, s) / 2
+	}
+
+	name := "F1"
+	if font == Bold {
+		name = "F2"
+	}
+
+	fmt.Fprintf(d.current(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		name, size, x, PageHeight-y, escape(encode(s)))
+}
+
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+		width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// FillRect paints a rectangle in the given gray level, 0 being black and 1 being white
+func (d *Document) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.current(), "q %.2f g %.2f %.2f %.2f %.2f re f Q\n",
+		gray, x, PageHeight-y-h, w, h)
+}
+
+func (d *Document) Bytes() []byte {
+	buf := &bytes.Buffer{}
+	var offsets []int
+
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Fixed objects are catalog, page tree and the two fonts, followed by a page and content pair per page
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+i*2))
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 6+i*2))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.Len(), p.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// encode converts s to Latin-1, which WinAnsiEncoding matches for printable characters
+func encode(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r < 32 || (r > 126 && r < 160) || r > 255 {
+			b = append(b, '?')
+			continue
+		}
+		b = append(b, byte(r))
+	}
+	return string(b)
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+	return r.Replace(s)
+}
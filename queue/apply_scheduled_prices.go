@@ -0,0 +1,18 @@
+package queue
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/machinery"
+	tasks2 "github.com/shopicano/shopicano-backend/tasks"
+)
+
+func ApplyScheduledPrices() error {
+	sig := &tasks.Signature{
+		Name: tasks2.ApplyScheduledPricesTaskName,
+	}
+	_, err := machinery.RabbitMQConnection().SendTask(sig)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package queue
+
+import (
+	"github.com/shopicano/shopicano-backend/log"
+	"time"
+)
+
+type periodicTask struct {
+	name     string
+	interval time.Duration
+	send     func() error
+}
+
+var periodicTasks = []periodicTask{
+	{
+		name:     "apply scheduled prices",
+		interval: time.Minute,
+		send:     ApplyScheduledPrices,
+	},
+	{
+		name:     "retry outbox emails",
+		interval: time.Minute,
+		send:     RetryOutboxEmails,
+	},
+	{
+		name:     "retry webhook deliveries",
+		interval: time.Minute,
+		send:     RetryWebhookDeliveries,
+	},
+	{
+		name:     "relay domain events",
+		interval: time.Second * 5,
+		send:     RelayDomainEvents,
+	},
+	{
+		name:     "cleanup",
+		interval: time.Hour,
+		send:     Cleanup,
+	},
+	{
+		name:     "process account deletions",
+		interval: time.Hour,
+		send:     ProcessAccountDeletions,
+	},
+}
+
+// RunPeriodicTasks enqueues every periodic task on its own interval, it blocks forever
+func RunPeriodicTasks() {
+	for _, pt := range periodicTasks {
+		go func(pt periodicTask) {
+			ticker := time.NewTicker(pt.interval)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				if err := pt.send(); err != nil {
+					log.Log().Errorln("Failed to enqueue periodic task", pt.name, ":", err)
+				}
+			}
+		}(pt)
+	}
+
+	select {}
+}
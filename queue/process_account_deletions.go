@@ -0,0 +1,18 @@
+package queue
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/machinery"
+	tasks2 "github.com/shopicano/shopicano-backend/tasks"
+)
+
+func ProcessAccountDeletions() error {
+	sig := &tasks.Signature{
+		Name: tasks2.ProcessAccountDeletionsTaskName,
+	}
+	_, err := machinery.RabbitMQConnection().SendTask(sig)
+	if err != nil {
+		return err
+	}
+	return nil
+}
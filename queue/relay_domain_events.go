@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/machinery"
+	tasks2 "github.com/shopicano/shopicano-backend/tasks"
+	"time"
+)
+
+func HandleDomainEvent(eventID, subscriber string) error {
+	sig := &tasks.Signature{
+		Name: tasks2.HandleDomainEventTaskName,
+		Args: []tasks.Arg{
+			{
+				Type:  "string",
+				Value: eventID,
+				Name:  "eventID",
+			},
+			{
+				Type:  "string",
+				Value: subscriber,
+				Name:  "subscriber",
+			},
+		},
+		RetryCount:   tasks2.DomainEventRetries,
+		RetryTimeout: tasks2.DomainEventRetryTimeout,
+	}
+	_, err := machinery.RabbitMQConnection().SendTask(sig)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RelayDomainEvents enqueues a task per subscriber of each recorded event and marks the event published.
+// Events are relayed at least once, an event failing half way is sent again to all of its subscribers.
+func RelayDomainEvents() error {
+	db := app.DB().Begin()
+
+	deu := data.NewDomainEventRepository()
+	events, err := deu.ListUnpublished(db, 100)
+	if err != nil {
+		db.Rollback()
+		return err
+	}
+
+	for _, e := range events {
+		for _, s := range tasks2.DomainEventSubscribers(e.Type) {
+			if err := HandleDomainEvent(e.ID, s); err != nil {
+				if err := db.Commit().Error; err != nil {
+					log.Log().Errorln(err)
+				}
+				return err
+			}
+		}
+
+		if err := deu.MarkPublished(db, e.ID, time.Now().UTC()); err != nil {
+			db.Rollback()
+			return err
+		}
+	}
+
+	return db.Commit().Error
+}
@@ -7,24 +7,26 @@ import (
 	"time"
 )
 
-func SendOrderDetailsEmail(orderID, subject string) error {
-	now := time.Now().Add(time.Second * 10)
-
+func SendAccountLockedEmail(userID, ip string, lockedUntil time.Time) error {
 	sig := &tasks.Signature{
-		Name: tasks2.SendOrderDetailsEmailTaskName,
+		Name: tasks2.SendAccountLockedEmailTaskName,
 		Args: []tasks.Arg{
 			{
 				Type:  "string",
-				Value: orderID,
-				Name:  "orderID",
+				Value: userID,
+				Name:  "userID",
 			},
 			{
 				Type:  "string",
-				Value: subject,
-				Name:  "subject",
+				Value: ip,
+				Name:  "ip",
+			},
+			{
+				Type:  "int64",
+				Value: lockedUntil.Unix(),
+				Name:  "lockedUntil",
 			},
 		},
-		ETA: &now,
 	}
 	_, err := machinery.RabbitMQConnection().SendTask(sig)
 	if err != nil {
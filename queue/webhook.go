@@ -4,22 +4,18 @@ import (
 	"github.com/RichardKnop/machinery/v1/tasks"
 	"github.com/shopicano/shopicano-backend/machinery"
 	tasks2 "github.com/shopicano/shopicano-backend/tasks"
-	"time"
 )
 
-func SendPaymentConfirmationEmail(orderID string) error {
-	now := time.Now().Add(time.Second * 10)
-
+func DeliverWebhook(deliveryID string) error {
 	sig := &tasks.Signature{
-		Name: tasks2.SendPaymentConfirmationEmailTaskName,
+		Name: tasks2.DeliverWebhookTaskName,
 		Args: []tasks.Arg{
 			{
 				Type:  "string",
-				Value: orderID,
-				Name:  "orderID",
+				Value: deliveryID,
+				Name:  "deliveryID",
 			},
 		},
-		ETA: &now,
 	}
 	_, err := machinery.RabbitMQConnection().SendTask(sig)
 	if err != nil {
@@ -28,19 +24,9 @@ func SendPaymentConfirmationEmail(orderID string) error {
 	return nil
 }
 
-func SendPaymentRevertedEmail(orderID string) error {
-	now := time.Now().Add(time.Second * 10)
-
+func RetryWebhookDeliveries() error {
 	sig := &tasks.Signature{
-		Name: tasks2.SendPaymentRevertedEmailTaskName,
-		Args: []tasks.Arg{
-			{
-				Type:  "string",
-				Value: orderID,
-				Name:  "orderID",
-			},
-		},
-		ETA: &now,
+		Name: tasks2.RetryWebhookDeliveriesTaskName,
 	}
 	_, err := machinery.RabbitMQConnection().SendTask(sig)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/shopicano/shopicano-backend/api"
+	"github.com/shopicano/shopicano-backend/config"
 	"github.com/shopicano/shopicano-backend/middlewares"
 	"net/http"
 	"strings"
@@ -13,6 +14,8 @@ var router = echo.New()
 
 // GetRouter returns the api router
 func GetRouter() http.Handler {
+	router.IPExtractor = middlewares.ClientIPExtractor(config.App().TrustedProxies)
+
 	router.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: 6,
 		Skipper: func(ctx echo.Context) bool {
@@ -29,8 +32,12 @@ func GetRouter() http.Handler {
 		AllowOrigins: []string{"*"},
 		AllowHeaders: []string{"*"},
 		AllowMethods: []string{"*"},
+		ExposeHeaders: []string{middlewares.RateLimitLimitHeader, middlewares.RateLimitRemainingHeader,
+			middlewares.RateLimitResetHeader, "Retry-After"},
 	}))
 
+	router.Use(middlewares.RateLimit())
+
 	router.GET("/", func(ctx echo.Context) error {
 		return ctx.String(http.StatusOK, "OK")
 	})
@@ -44,7 +51,7 @@ func registerV1Routes() {
 	publicEndpoints := v1
 	platformEndpoints := v1.Group("/marketplace")
 
-	platformEndpoints.Use(middlewares.JWTAuth())
+	platformEndpoints.Use(middlewares.JWTOrAPIKeyAuth())
 
 	api.RegisterLegacyRoutes(publicEndpoints, platformEndpoints)
 	api.RegisterPlatformRoutes(publicEndpoints, platformEndpoints)
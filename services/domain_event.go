@@ -0,0 +1,39 @@
+package services
+
+import (
+	"encoding/json"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"time"
+)
+
+// RecordEvent writes the event in the transaction of db, the relay publishes it once the transaction commits
+func RecordEvent(db *gorm.DB, t models.DomainEventType, aggregateID string, payload interface{}) error {
+	b := []byte("{}")
+	if payload != nil {
+		var err error
+		b, err = json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	deu := data.NewDomainEventRepository()
+	return deu.Create(db, &models.DomainEvent{
+		ID:          utils.NewUUID(),
+		Type:        t,
+		AggregateID: aggregateID,
+		Payload:     string(b),
+		CreatedAt:   time.Now().UTC(),
+	})
+}
+
+// RecordStatusChange records the event when the status actually changed
+func RecordStatusChange(db *gorm.DB, t models.DomainEventType, aggregateID string, from, to string) error {
+	if from == to {
+		return nil
+	}
+	return RecordEvent(db, t, aggregateID, &models.StatusChange{From: from, To: to})
+}
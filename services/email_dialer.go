@@ -9,6 +9,6 @@ import (
 func EmailDialer() *gomail.Dialer {
 	cfg := config.EmailService()
 	d := gomail.NewDialer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword)
-	d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	d.TLSConfig = &tls.Config{ServerName: cfg.SMTPHost, InsecureSkipVerify: cfg.SMTPSkipTLSVerify}
 	return d
 }
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/go-gomail/gomail"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/values"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// EmailAttachment is attached from Data when set, otherwise it's read from Path in minio.
+// Attachments without a Path are stored in minio so that the email can be resent.
+type EmailAttachment struct {
+	Name        string
+	ContentType string
+	Path        string
+	Data        []byte
+}
+
+type OutgoingEmail struct {
+	Recipient   string
+	Subject     string
+	Body        string
+	Template    models.EmailTemplateName
+	UserID      *string
+	OrderID     *string
+	Attachments []EmailAttachment
+}
+
+// SendEmail records the email in the outbox and makes the first delivery attempt.
+// Failed deliveries are retried by DeliverDueEmails, so only failing to record the email is returned.
+func SendEmail(oe *OutgoingEmail) error {
+	now := time.Now().UTC()
+
+	e := &models.Email{
+		ID:            utils.NewUUID(),
+		Recipient:     oe.Recipient,
+		Subject:       oe.Subject,
+		Body:          oe.Body,
+		Template:      string(oe.Template),
+		UserID:        oe.UserID,
+		OrderID:       oe.OrderID,
+		Status:        models.EmailQueued,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	// Content of the attachments by path, to avoid reading them back from minio on the first attempt
+	contents := map[string][]byte{}
+
+	db := app.DB().Begin()
+
+	eu := data.NewEmailRepository()
+	if err := eu.Create(db, e); err != nil {
+		db.Rollback()
+		return err
+	}
+
+	for _, a := range oe.Attachments {
+		path := a.Path
+		if path == "" {
+			path = fmt.Sprintf("%s/emails/%s/%s", values.ReservedBucketName, e.ID, a.Name)
+			if err := UploadToMinio(path, a.ContentType, bytes.NewReader(a.Data), int64(len(a.Data))); err != nil {
+				db.Rollback()
+				return err
+			}
+		}
+		if a.Data != nil {
+			contents[path] = a.Data
+		}
+
+		ea := models.EmailAttachment{
+			ID:          utils.NewUUID(),
+			EmailID:     e.ID,
+			Name:        a.Name,
+			ContentType: a.ContentType,
+			Path:        path,
+		}
+		if err := eu.AddAttachment(db, &ea); err != nil {
+			db.Rollback()
+			return err
+		}
+		e.Attachments = append(e.Attachments, ea)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+
+	if err := deliverEmail(app.DB(), e, contents); err != nil {
+		log.Log().Errorln("Failed to deliver email", e.ID, ":", err)
+	}
+	return nil
+}
+
+// ResendEmail makes a delivery attempt of the email right away, whatever its status is
+func ResendEmail(emailID string) (*models.Email, error) {
+	db := app.DB()
+
+	eu := data.NewEmailRepository()
+	e, err := eu.Get(db, emailID)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Attachments, err = eu.ListAttachments(db, e.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, deliverEmail(db, e, nil)
+}
+
+// DeliverDueEmails retries the queued emails whose backoff has elapsed
+func DeliverDueEmails(limit int) error {
+	db := app.DB().Begin()
+
+	eu := data.NewEmailRepository()
+	emails, err := eu.ListDue(db.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED"), time.Now().UTC(), limit)
+	if err != nil {
+		db.Rollback()
+		return err
+	}
+
+	for i := range emails {
+		e := &emails[i]
+
+		e.Attachments, err = eu.ListAttachments(db, e.ID)
+		if err != nil {
+			db.Rollback()
+			return err
+		}
+
+		if err := deliverEmail(db, e, nil); err != nil {
+			log.Log().Errorln("Failed to deliver email", e.ID, ":", err)
+		}
+	}
+
+	return db.Commit().Error
+}
+
+// EmailRetryDelay grows exponentially with the attempts made, up to the configured maximum
+func EmailRetryDelay(attempts int) time.Duration {
+	cfg := config.EmailService()
+
+	delay := cfg.RetryBaseDelay
+	for i := 1; i < attempts && delay < cfg.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > cfg.RetryMaxDelay {
+		delay = cfg.RetryMaxDelay
+	}
+	return delay
+}
+
+// deliverEmail sends the email through the configured transport and records the outcome of the attempt.
+// The delivery error is returned after the attempt is recorded.
+func deliverEmail(db *gorm.DB, e *models.Email, contents map[string][]byte) error {
+	transport := NewEmailTransport()
+
+	sendErr := func() error {
+		m, err := composeEmail(e, contents)
+		if err != nil {
+			return err
+		}
+		return transport.Send(m)
+	}()
+
+	now := time.Now().UTC()
+
+	e.Attempts++
+	e.UpdatedAt = now
+
+	l := &models.EmailDeliveryLog{
+		ID:        utils.NewUUID(),
+		EmailID:   e.ID,
+		Attempt:   e.Attempts,
+		Transport: transport.Name(),
+		IsSuccess: sendErr == nil,
+		CreatedAt: now,
+	}
+
+	switch {
+	case sendErr == nil:
+		e.Status = models.EmailSent
+		e.LastError = ""
+		e.NextAttemptAt = nil
+		e.SentAt = &now
+	case e.Attempts >= config.EmailService().MaxAttempts:
+		e.Status = models.EmailFailed
+		e.LastError = sendErr.Error()
+		e.NextAttemptAt = nil
+		l.Error = sendErr.Error()
+	default:
+		next := now.Add(EmailRetryDelay(e.Attempts))
+		e.Status = models.EmailQueued
+		e.LastError = sendErr.Error()
+		e.NextAttemptAt = &next
+		l.Error = sendErr.Error()
+	}
+
+	eu := data.NewEmailRepository()
+	if err := eu.UpdateDelivery(db, e); err != nil {
+		return err
+	}
+	if err := eu.CreateDeliveryLog(db, l); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+func composeEmail(e *models.Email, contents map[string][]byte) (*gomail.Message, error) {
+	m := gomail.NewMessage()
+	m.SetHeader("From", config.EmailService().FromEmailAddress)
+	m.SetHeader("To", e.Recipient)
+	m.SetHeader("Subject", e.Subject)
+	m.SetBody("text/html", e.Body)
+
+	for _, a := range e.Attachments {
+		content, ok := contents[a.Path]
+		if !ok {
+			o, err := ServeAsStreamFromMinio(a.Path)
+			if err != nil {
+				return nil, err
+			}
+			content, err = ioutil.ReadAll(o)
+			o.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		m.Attach(a.Name,
+			gomail.SetHeader(map[string][]string{"Content-Type": {a.ContentType}}),
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(content)
+				return err
+			}))
+	}
+	return m, nil
+}
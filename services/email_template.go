@@ -0,0 +1,269 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/templates"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/values"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+const (
+	EmailTemplateStorageDatabase = "database"
+	EmailTemplateStorageMinio    = "minio"
+
+	defaultPrimaryColor = "#3f71f4"
+	defaultAccentColor  = "#ff8124"
+)
+
+// EmailTemplateStorage keeps the bodies of the customized email templates
+type EmailTemplateStorage interface {
+	Read(et *models.EmailTemplate) (string, error)
+	Write(et *models.EmailTemplate, body string) error
+	Delete(et *models.EmailTemplate) error
+}
+
+type databaseTemplateStorage struct {
+}
+
+func (s *databaseTemplateStorage) Read(et *models.EmailTemplate) (string, error) {
+	return et.Body, nil
+}
+
+func (s *databaseTemplateStorage) Write(et *models.EmailTemplate, body string) error {
+	et.Body = body
+	return nil
+}
+
+func (s *databaseTemplateStorage) Delete(et *models.EmailTemplate) error {
+	return nil
+}
+
+type minioTemplateStorage struct {
+}
+
+func (s *minioTemplateStorage) Read(et *models.EmailTemplate) (string, error) {
+	o, err := ServeAsStreamFromMinio(fmt.Sprintf("%s/%s", values.ReservedBucketName, et.ObjectPath()))
+	if err != nil {
+		return "", err
+	}
+	defer o.Close()
+
+	b, err := ioutil.ReadAll(o)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (s *minioTemplateStorage) Write(et *models.EmailTemplate, body string) error {
+	et.Body = ""
+	return UploadToMinio(fmt.Sprintf("%s/%s", values.ReservedBucketName, et.ObjectPath()),
+		"text/html", bytes.NewReader([]byte(body)), int64(len(body)))
+}
+
+func (s *minioTemplateStorage) Delete(et *models.EmailTemplate) error {
+	cfg := config.Minio()
+	return app.Minio().RemoveObject(cfg.Bucket, fmt.Sprintf("%s/%s", values.ReservedBucketName, et.ObjectPath()))
+}
+
+func NewEmailTemplateStorage() EmailTemplateStorage {
+	if config.EmailService().TemplateStorage == EmailTemplateStorageMinio {
+		return &minioTemplateStorage{}
+	}
+	return &databaseTemplateStorage{}
+}
+
+// localeFallbacks returns the locales to look a template up by, from the most specific one
+func localeFallbacks(locale string) []string {
+	var locales []string
+	if locale != "" {
+		locales = append(locales, locale)
+		if i := strings.Index(locale, "-"); i > 0 {
+			locales = append(locales, locale[:i])
+		}
+	}
+	if locale != models.DefaultLocale {
+		locales = append(locales, models.DefaultLocale)
+	}
+	return locales
+}
+
+// applyBranding fills the platform and branding params in, letting the store override the platform look
+func applyBranding(db *gorm.DB, storeID *string, params map[string]interface{}) (map[string]interface{}, error) {
+	pu := data.NewMarketplaceRepository()
+	settings, err := pu.GetSettings(db)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]interface{}{
+		"platformName":    settings.Name,
+		"platformWebsite": settings.Website,
+		"siteUrl":         settings.Website,
+		"assetsUrl":       fmt.Sprintf("%s/assets/", settings.Website),
+	}
+	for k, v := range params {
+		res[k] = v
+	}
+
+	res["logoUrl"] = fmt.Sprintf("%sgroup-26@3x.png", res["assetsUrl"])
+	res["primaryColor"] = defaultPrimaryColor
+	res["accentColor"] = defaultAccentColor
+	res["footerText"] = fmt.Sprintf("© %d %s. All rights reserved.", time.Now().Year(), res["platformName"])
+
+	if storeID == nil {
+		return res, nil
+	}
+
+	etu := data.NewEmailTemplateRepository()
+	b, err := etu.GetStoreBranding(db, *storeID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	if b.LogoUrl != "" {
+		res["logoUrl"] = b.LogoUrl
+	}
+	if b.PrimaryColor != "" {
+		res["primaryColor"] = b.PrimaryColor
+	}
+	if b.AccentColor != "" {
+		res["accentColor"] = b.AccentColor
+	}
+	if b.FooterText != "" {
+		res["footerText"] = b.FooterText
+	}
+	return res, nil
+}
+
+// RenderEmail resolves the named template for the locale, falling back to the language and then to the
+// default locale, and renders it with the branding of the store. Built in templates are used when
+// no customized one is active. The given subject is used unless the template defines its own.
+func RenderEmail(name models.EmailTemplateName, locale string, storeID *string, subject string, params map[string]interface{}) (string, string, error) {
+	db := app.DB()
+
+	body, ok := templates.DefaultEmailTemplate(string(name))
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %s", name)
+	}
+
+	etu := data.NewEmailTemplateRepository()
+	for _, l := range localeFallbacks(locale) {
+		et, err := etu.GetActive(db, name, l)
+		if err != nil {
+			if errors.IsRecordNotFoundError(err) {
+				continue
+			}
+			return "", "", err
+		}
+
+		body, err = NewEmailTemplateStorage().Read(et)
+		if err != nil {
+			return "", "", err
+		}
+		if et.Subject != "" {
+			subject = et.Subject
+		}
+		break
+	}
+
+	return renderEmail(db, string(name), body, storeID, subject, params)
+}
+
+func renderEmail(db *gorm.DB, name, body string, storeID *string, subject string, params map[string]interface{}) (string, string, error) {
+	params, err := applyBranding(db, storeID, params)
+	if err != nil {
+		return "", "", err
+	}
+
+	subject, err = templates.GenerateEmailSubject(subject, params)
+	if err != nil {
+		return "", "", err
+	}
+
+	html, err := templates.GenerateEmailHTML(name, body, params)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, html, nil
+}
+
+// PreviewEmail renders the body, or the built in template if body is empty, with sample data
+func PreviewEmail(name models.EmailTemplateName, storeID *string, subject, body string) (string, string, error) {
+	if body == "" {
+		b, ok := templates.DefaultEmailTemplate(string(name))
+		if !ok {
+			return "", "", fmt.Errorf("unknown email template %s", name)
+		}
+		body = b
+	}
+	return renderEmail(app.DB(), string(name), body, storeID, subject, emailTemplateSampleParams(name))
+}
+
+func emailTemplateSampleParams(name models.EmailTemplateName) map[string]interface{} {
+	params := map[string]interface{}{
+		"userName": "Jane Doe",
+	}
+
+	switch name {
+	case models.EmailTemplateVerifyEmail:
+		params["verificationUrl"] = fmt.Sprintf("%s?email=jane@example.com&token=sample", config.App().FrontStoreUrl)
+	case models.EmailTemplateResetPassword:
+		params["resetPasswordUrl"] = fmt.Sprintf("%s?token=sample&email=jane@example.com", config.App().FrontStoreUrl)
+	case models.EmailTemplateStaffInvitation:
+		params["storeName"] = "Sample Store"
+		params["inviterName"] = "John Doe"
+		params["invitationUrl"] = fmt.Sprintf("%s?token=sample&email=jane@example.com&signup=false", config.App().FrontStoreUrl)
+		params["expireOn"] = time.Now().Add(time.Hour * 24 * 7).Format(utils.DateTimeFormatForDistribution)
+		params["isRegistered"] = true
+	case models.EmailTemplateAccountLocked:
+		params["unlockUrl"] = fmt.Sprintf("%s?token=sample", config.App().FrontStoreUrl)
+		params["lockedUntil"] = time.Now().Add(time.Minute * 30).Format(utils.DateTimeFormatForDistribution)
+		params["ip"] = "203.0.113.7"
+	case models.EmailTemplateAccountDeletionRequested:
+		params["cancelUrl"] = fmt.Sprintf("%s?token=sample", config.App().FrontStoreUrl)
+		params["deletionDate"] = time.Now().Add(time.Hour * 24 * 30).Format(utils.DateTimeFormatForDistribution)
+	case models.EmailTemplateInvoice:
+		params["greetings"] = "Hi Jane Doe,"
+		params["intros"] = "Your order has been placed."
+		params["orderHash"] = "SAMPLE1"
+		params["orderUrl"] = config.App().FrontStoreUrl
+		params["orderDate"] = time.Now().Format(utils.DateTimeFormatForDistribution)
+		params["buyerName"] = "Jane Doe"
+		params["billingAddress"] = "House 1, Road 2, Dhaka, Bangladesh - 1212"
+		params["shippingAddress"] = "House 1, Road 2, Dhaka, Bangladesh - 1212"
+		params["isShippable"] = true
+		params["isDigitalPayment"] = true
+		params["isCouponApplied"] = true
+		params["couponCode"] = "WELCOME"
+		params["discount"] = "5.00"
+		params["isTaxApplied"] = true
+		params["tax"] = "2.50"
+		params["subTotal"] = "50.00"
+		params["shippingCharge"] = "5.00"
+		params["paymentProcessingFee"] = "1.50"
+		params["grandTotal"] = "54.00"
+		params["paymentGateway"] = "Stripe"
+		params["status"] = "Pending"
+		params["paymentStatus"] = "Pending"
+		params["orderedItems"] = []map[string]interface{}{
+			{"name": "Sample Product", "quantity": 2, "price": "20.00", "subTotal": "40.00", "isComponent": false},
+			{"name": "Sample Bundle", "quantity": 1, "price": "10.00", "subTotal": "10.00", "isComponent": false},
+			{"name": "Bundled Item", "quantity": 1, "isComponent": true},
+		}
+	}
+	return params
+}
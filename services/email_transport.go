@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"github.com/go-gomail/gomail"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	EmailTransportSMTP = "smtp"
+	EmailTransportFile = "file"
+	EmailTransportLog  = "log"
+)
+
+// EmailTransport delivers a composed message, the file and log sinks are meant for development and tests
+type EmailTransport interface {
+	Name() string
+	Send(m *gomail.Message) error
+}
+
+type smtpTransport struct {
+}
+
+func (t *smtpTransport) Name() string {
+	return EmailTransportSMTP
+}
+
+func (t *smtpTransport) Send(m *gomail.Message) error {
+	return EmailDialer().DialAndSend(m)
+}
+
+type fileTransport struct {
+	dir string
+}
+
+func (t *fileTransport) Name() string {
+	return EmailTransportFile
+}
+
+// Send writes the message as an .eml file named after the recipient and the time it was sent
+func (t *fileTransport) Send(m *gomail.Message) error {
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return err
+	}
+
+	recipient := strings.Join(m.GetHeader("To"), "_")
+	name := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), strings.NewReplacer("/", "_", "@", "_at_").Replace(recipient))
+
+	f, err := os.Create(filepath.Join(t.dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = m.WriteTo(f)
+	return err
+}
+
+type logTransport struct {
+}
+
+func (t *logTransport) Name() string {
+	return EmailTransportLog
+}
+
+func (t *logTransport) Send(m *gomail.Message) error {
+	log.Log().Infoln("Email to", m.GetHeader("To"), "with subject", m.GetHeader("Subject"))
+	return nil
+}
+
+func NewEmailTransport() EmailTransport {
+	cfg := config.EmailService()
+	switch cfg.Transport {
+	case EmailTransportFile:
+		return &fileTransport{dir: cfg.FileSinkDir}
+	case EmailTransportLog:
+		return &logTransport{}
+	}
+	return &smtpTransport{}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/templates"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/values"
+	"io/ioutil"
+	"time"
+)
+
+const (
+	InvoiceContentType = "application/pdf"
+)
+
+// GenerateInvoice returns the invoice or credit note of the order along with its PDF,
+// rendering and storing it in minio the first time it's requested
+func GenerateInvoice(order *models.OrderDetailsView, invoiceType models.InvoiceType) (*models.Invoice, []byte, error) {
+	iu := data.NewInvoiceRepository()
+
+	inv, err := iu.Get(app.DB(), order.ID, invoiceType)
+	if err == nil {
+		return readOrRenderInvoice(order, inv)
+	}
+	if !errors.IsRecordNotFoundError(err) {
+		return nil, nil, err
+	}
+
+	db := app.DB().Begin()
+
+	number, err := iu.NextNumber(db, order.StoreID, invoiceType)
+	if err != nil {
+		db.Rollback()
+		return nil, nil, err
+	}
+
+	// A concurrent request may have numbered the invoice while waiting for the lock
+	inv, err = iu.Get(db, order.ID, invoiceType)
+	if err == nil {
+		db.Rollback()
+		return readOrRenderInvoice(order, inv)
+	}
+	if !errors.IsRecordNotFoundError(err) {
+		db.Rollback()
+		return nil, nil, err
+	}
+
+	inv = &models.Invoice{
+		ID:        utils.NewUUID(),
+		StoreID:   order.StoreID,
+		OrderID:   order.ID,
+		Type:      invoiceType,
+		Number:    number,
+		Amount:    order.GrandTotal,
+		CreatedAt: time.Now().UTC(),
+	}
+	if invoiceType == models.InvoiceTypeCreditNote {
+		// Payment gateways keep the processing fee on refunds
+		inv.Amount = order.GrandTotal - order.PaymentProcessingFee
+	}
+	inv.Path = fmt.Sprintf("invoices/%s/%s", order.StoreID, inv.FileName())
+
+	if err := iu.Create(db, inv); err != nil {
+		db.Rollback()
+		return nil, nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, nil, err
+	}
+
+	b, err := renderInvoice(order, inv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inv, b, nil
+}
+
+// readOrRenderInvoice reads the PDF of the invoice, rendering it again when it wasn't stored
+// as the upload happens after the invoice is committed
+func readOrRenderInvoice(order *models.OrderDetailsView, inv *models.Invoice) (*models.Invoice, []byte, error) {
+	b, err := readInvoice(inv)
+	if err != nil {
+		log.Log().Errorln("Failed to read invoice", inv.ID, ", rendering it again :", err)
+
+		b, err = renderInvoice(order, inv)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return inv, b, nil
+}
+
+// renderInvoice renders the PDF of the numbered invoice and stores it in minio
+func renderInvoice(order *models.OrderDetailsView, inv *models.Invoice) ([]byte, error) {
+	pu := data.NewMarketplaceRepository()
+	settings, err := pu.GetSettings(app.DB())
+	if err != nil {
+		return nil, err
+	}
+
+	var origin *models.Invoice
+	if inv.Type == models.InvoiceTypeCreditNote {
+		origin, _, err = GenerateInvoice(order, models.InvoiceTypeInvoice)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b := templates.GenerateInvoicePDF(buildInvoicePDF(order, inv, origin, settings.Name))
+
+	if err := UploadToMinio(InvoiceObjectPath(inv),
+		InvoiceContentType, bytes.NewReader(b), int64(len(b))); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// InvoiceObjectPath returns the name of the minio object the invoice is stored at
+func InvoiceObjectPath(inv *models.Invoice) string {
+	return fmt.Sprintf("%s/%s", values.ReservedBucketName, inv.Path)
+}
+
+func readInvoice(inv *models.Invoice) ([]byte, error) {
+	o, err := ServeAsStreamFromMinio(InvoiceObjectPath(inv))
+	if err != nil {
+		return nil, err
+	}
+	defer o.Close()
+
+	return ioutil.ReadAll(o)
+}
+
+func formatAmount(v int64) string {
+	return fmt.Sprintf("%.2f", float64(v)/100)
+}
+
+func buildInvoicePDF(order *models.OrderDetailsView, inv, origin *models.Invoice, platformName string) *templates.InvoicePDF {
+	doc := &templates.InvoicePDF{
+		Title:        "Invoice",
+		Number:       inv.DisplayNumber(),
+		Date:         inv.CreatedAt.Format(utils.DateTimeFormatForDistribution),
+		OrderHash:    order.Hash,
+		PlatformName: platformName,
+		StoreName:    order.StoreName,
+		StoreAddress: fmt.Sprintf("%s, %s, %s - %s",
+			order.StoreAddress, order.StoreCity, order.StoreCountry, order.StorePostcode),
+		StoreEmail: order.StoreEmail,
+		BuyerName:  order.BillingName,
+		BillingAddress: fmt.Sprintf("%s, %s, %s - %s",
+			order.BillingAddress, order.BillingCity, order.BillingCountry, order.BillingPostcode),
+		ShippingAddress: "N/A",
+	}
+
+	if !order.IsAllDigitalProducts && order.ShippingAddress != nil {
+		doc.ShippingAddress = fmt.Sprintf("%s, %s, %s - %s",
+			*order.ShippingAddress, *order.ShippingCity, *order.ShippingCountry, *order.ShippingPostcode)
+	}
+
+	components := map[string][]models.OrderedItemView{}
+	for _, v := range order.Items {
+		if v.ParentID != nil {
+			components[*v.ParentID] = append(components[*v.ParentID], v)
+		}
+	}
+
+	for _, v := range order.Items {
+		if v.ParentID != nil {
+			continue
+		}
+
+		doc.Lines = append(doc.Lines, templates.InvoicePDFLine{
+			Name:     v.Name,
+			Quantity: fmt.Sprintf("%d", v.Quantity),
+			Price:    formatAmount(v.Price),
+			SubTotal: formatAmount(v.SubTotal),
+		})
+
+		for _, c := range components[v.ID] {
+			doc.Lines = append(doc.Lines, templates.InvoicePDFLine{
+				Name:        c.Name,
+				Quantity:    fmt.Sprintf("%d", c.Quantity),
+				IsComponent: true,
+			})
+		}
+	}
+
+	doc.Totals = append(doc.Totals, templates.InvoicePDFTotal{Label: "Sub Total:", Value: formatAmount(order.SubTotal)})
+	if order.DiscountedAmount != 0 {
+		doc.Totals = append(doc.Totals, templates.InvoicePDFTotal{
+			Label: fmt.Sprintf("Discount (%s):", order.CouponCode),
+			Value: fmt.Sprintf("-%s", formatAmount(order.DiscountedAmount)),
+		})
+	}
+	if !order.IsAllDigitalProducts {
+		doc.Totals = append(doc.Totals, templates.InvoicePDFTotal{Label: "Shipping Charge:", Value: formatAmount(order.ShippingCharge)})
+	}
+	if order.TaxAmount != 0 {
+		label := "Tax:"
+		if order.IncludedTaxAmount == order.TaxAmount {
+			label = "Tax (included):"
+		}
+		doc.Totals = append(doc.Totals, templates.InvoicePDFTotal{Label: label, Value: formatAmount(order.TaxAmount)})
+	}
+	if !order.PaymentMethodIsOffline {
+		doc.Totals = append(doc.Totals, templates.InvoicePDFTotal{Label: "Processing Fee:", Value: formatAmount(order.PaymentProcessingFee)})
+	}
+	doc.Totals = append(doc.Totals, templates.InvoicePDFTotal{Label: "Total:", Value: formatAmount(order.GrandTotal)})
+
+	if inv.Type == models.InvoiceTypeCreditNote {
+		doc.Title = "Credit Note"
+		doc.Totals = append(doc.Totals, templates.InvoicePDFTotal{Label: "Refunded:", Value: formatAmount(inv.Amount)})
+		if origin != nil {
+			doc.Notes = append(doc.Notes, fmt.Sprintf("Issued against invoice %s.", origin.DisplayNumber()))
+		}
+	} else {
+		doc.Notes = append(doc.Notes, fmt.Sprintf("Payment status: %s.", order.PaymentStatus))
+	}
+
+	if order.TaxExemptVatNumber != nil {
+		doc.Notes = append(doc.Notes, fmt.Sprintf("Tax exempt, VAT number %s.", *order.TaxExemptVatNumber))
+	}
+	return doc
+}
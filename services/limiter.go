@@ -0,0 +1,163 @@
+package services
+
+import (
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"sync"
+	"time"
+)
+
+// LimiterStore keeps the attempt counters of the login protection. Get returns nil for an unknown name.
+type LimiterStore interface {
+	Get(name string, now time.Time) (*models.LimiterCounter, error)
+	Hit(name string, window time.Duration, now time.Time) (*models.LimiterCounter, error)
+	Lock(name string, until time.Time) error
+	Reset(name string) error
+}
+
+var (
+	limiterStore     LimiterStore
+	limiterStoreOnce sync.Once
+)
+
+// GetLimiterStore returns the store selected by login_protection.limiter_store
+func GetLimiterStore() LimiterStore {
+	limiterStoreOnce.Do(func() {
+		if config.LoginProtection().LimiterStore == config.LimiterStoreDatabase {
+			limiterStore = &databaseLimiterStore{}
+			return
+		}
+		limiterStore = newMemoryLimiterStore()
+	})
+	return limiterStore
+}
+
+// memoryLimiterStore keeps the counters of this instance only, expired ones are dropped while counting
+type memoryLimiterStore struct {
+	mu        sync.Mutex
+	counters  map[string]*models.LimiterCounter
+	lastPurge time.Time
+}
+
+func newMemoryLimiterStore() *memoryLimiterStore {
+	return &memoryLimiterStore{
+		counters: map[string]*models.LimiterCounter{},
+	}
+}
+
+func (s *memoryLimiterStore) Get(name string, now time.Time) (*models.LimiterCounter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[name]
+	if !ok || c.ExpiresAt.Before(now) {
+		return nil, nil
+	}
+	v := *c
+	return &v, nil
+}
+
+func (s *memoryLimiterStore) Hit(name string, window time.Duration, now time.Time) (*models.LimiterCounter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purge(now)
+
+	c, ok := s.counters[name]
+	if !ok {
+		c = &models.LimiterCounter{Name: name, WindowStart: now}
+		s.counters[name] = c
+	}
+	if !c.WindowStart.After(now.Add(-window)) {
+		c.Count = 0
+		c.WindowStart = now
+	}
+	c.Count++
+	c.LastAt = now
+
+	c.ExpiresAt = now.Add(window)
+	if c.LockedUntil != nil && c.LockedUntil.After(c.ExpiresAt) {
+		c.ExpiresAt = *c.LockedUntil
+	}
+
+	v := *c
+	return &v, nil
+}
+
+func (s *memoryLimiterStore) Lock(name string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.counters[name]; ok {
+		c.LockedUntil = &until
+		if until.After(c.ExpiresAt) {
+			c.ExpiresAt = until
+		}
+	}
+	return nil
+}
+
+func (s *memoryLimiterStore) Reset(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, name)
+	return nil
+}
+
+// purge drops the expired counters at most once a minute, the caller holds the lock
+func (s *memoryLimiterStore) purge(now time.Time) {
+	if now.Sub(s.lastPurge) < time.Minute {
+		return
+	}
+	s.lastPurge = now
+
+	for name, c := range s.counters {
+		if c.ExpiresAt.Before(now) {
+			delete(s.counters, name)
+		}
+	}
+}
+
+// databaseLimiterStore shares the counters between the instances, expired ones are removed by CleanupLimiterCounters
+type databaseLimiterStore struct {
+}
+
+func (s *databaseLimiterStore) Get(name string, now time.Time) (*models.LimiterCounter, error) {
+	lu := data.NewLoginProtectionRepository()
+	c, err := lu.GetCounter(app.DB(), name)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if c.ExpiresAt.Before(now) {
+		return nil, nil
+	}
+	return c, nil
+}
+
+func (s *databaseLimiterStore) Hit(name string, window time.Duration, now time.Time) (*models.LimiterCounter, error) {
+	lu := data.NewLoginProtectionRepository()
+	return lu.HitCounter(app.DB(), name, window, now)
+}
+
+func (s *databaseLimiterStore) Lock(name string, until time.Time) error {
+	lu := data.NewLoginProtectionRepository()
+	return lu.LockCounter(app.DB(), name, until)
+}
+
+func (s *databaseLimiterStore) Reset(name string) error {
+	lu := data.NewLoginProtectionRepository()
+	return lu.DeleteCounter(app.DB(), name)
+}
+
+// CleanupLimiterCounters removes the expired counters of the database limiter store
+func CleanupLimiterCounters() error {
+	lu := data.NewLoginProtectionRepository()
+	return lu.DeleteExpiredCounters(app.DB(), time.Now().UTC())
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"strings"
+	"time"
+)
+
+var ErrUnlockTokenInvalid = errors.NewError("invalid or expired unlock token")
+
+// ThrottledError tells the client to retry after a while, Locked is set for a lockout rather than a delay
+type ThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many attempts, retry after %d seconds", e.RetryAfterSeconds())
+}
+
+func (e *ThrottledError) RetryAfterSeconds() int64 {
+	s := int64(e.RetryAfter / time.Second)
+	if e.RetryAfter%time.Second != 0 {
+		s++
+	}
+	return s
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginAccountCounter(email string) string {
+	return "login:account:" + normalizeEmail(email)
+}
+
+// loginIPCounter is keyed by the ip of the router's IPExtractor, which trusts X-Forwarded-For of the
+// configured proxies only, so a client can't pick the ip it's counted or locked under
+func loginIPCounter(ip string) string {
+	return "login:ip:" + ip
+}
+
+// CheckLoginAllowed refuses a login attempt while the account or the ip is locked, or before the
+// delay earned by the previous failures of the account has passed
+func CheckLoginAllowed(email, ip string) error {
+	cfg := config.LoginProtection()
+	store := GetLimiterStore()
+	now := time.Now().UTC()
+
+	for _, name := range []string{loginAccountCounter(email), loginIPCounter(ip)} {
+		c, err := store.Get(name, now)
+		if err != nil {
+			return err
+		}
+		if c != nil && c.IsLocked(now) {
+			return &ThrottledError{RetryAfter: c.LockedUntil.Sub(now), Locked: true}
+		}
+	}
+
+	c, err := store.Get(loginAccountCounter(email), now)
+	if err != nil {
+		return err
+	}
+	if c == nil || c.Count <= cfg.FreeAttempts {
+		return nil
+	}
+
+	if next := c.LastAt.Add(loginDelay(c.Count - cfg.FreeAttempts)); next.After(now) {
+		return &ThrottledError{RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+// loginDelay doubles the base delay for every failure past the free attempts, up to the max delay
+func loginDelay(failures int) time.Duration {
+	cfg := config.LoginProtection()
+
+	d := cfg.BaseDelay
+	for i := 1; i < failures && d < cfg.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > cfg.MaxDelay {
+		d = cfg.MaxDelay
+	}
+	return d
+}
+
+// RecordLoginFailure counts a failed login of the email from the ip and locks the account or the ip
+// once they reach their limit. It returns the end of the lockout when the account of u got locked,
+// for the unlock email to be sent.
+func RecordLoginFailure(email, ip, userAgent string, u *models.User) (*time.Time, error) {
+	cfg := config.LoginProtection()
+	store := GetLimiterStore()
+	now := time.Now().UTC()
+	until := now.Add(cfg.LockoutDuration)
+
+	var accountLockedUntil *time.Time
+
+	c, err := store.Hit(loginAccountCounter(email), cfg.Window, now)
+	if err != nil {
+		return nil, err
+	}
+	if c.Count >= cfg.AccountLockoutAttempts && !c.IsLocked(now) {
+		if err := store.Lock(c.Name, until); err != nil {
+			return nil, err
+		}
+
+		l := &models.SecurityAuditLog{
+			Event:       models.SecurityEventAccountLocked,
+			Email:       normalizeEmail(email),
+			IP:          ip,
+			UserAgent:   userAgent,
+			LockedUntil: &until,
+		}
+		if u != nil {
+			l.UserID = &u.ID
+		}
+		if err := RecordSecurityEvent(l); err != nil {
+			return nil, err
+		}
+		if u != nil {
+			accountLockedUntil = &until
+		}
+	}
+
+	c, err = store.Hit(loginIPCounter(ip), cfg.Window, now)
+	if err != nil {
+		return nil, err
+	}
+	if c.Count >= cfg.IPLockoutAttempts && !c.IsLocked(now) {
+		if err := store.Lock(c.Name, until); err != nil {
+			return nil, err
+		}
+
+		if err := RecordSecurityEvent(&models.SecurityAuditLog{
+			Event:       models.SecurityEventIPLocked,
+			Email:       normalizeEmail(email),
+			IP:          ip,
+			UserAgent:   userAgent,
+			LockedUntil: &until,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return accountLockedUntil, nil
+}
+
+// RecordLoginSuccess clears the failures of the account, the ones of the ip are kept
+func RecordLoginSuccess(email string) error {
+	return GetLimiterStore().Reset(loginAccountCounter(email))
+}
+
+// CheckResetPasswordAllowed counts a password reset request and refuses it once the email
+// or the ip made too many within the window
+func CheckResetPasswordAllowed(email, ip, userAgent string) error {
+	cfg := config.LoginProtection()
+	store := GetLimiterStore()
+	now := time.Now().UTC()
+
+	limits := map[string]int{
+		"reset_password:email:" + normalizeEmail(email): cfg.ResetPasswordPerEmail,
+		"reset_password:ip:" + ip:                       cfg.ResetPasswordPerIP,
+	}
+
+	for name, limit := range limits {
+		c, err := store.Hit(name, cfg.ResetPasswordWindow, now)
+		if err != nil {
+			return err
+		}
+		if c.Count <= limit {
+			continue
+		}
+
+		// Only the first refused request is recorded, the following ones would flood the log
+		if c.Count == limit+1 {
+			if err := RecordSecurityEvent(&models.SecurityAuditLog{
+				Event:     models.SecurityEventResetPasswordThrottled,
+				Email:     normalizeEmail(email),
+				IP:        ip,
+				UserAgent: userAgent,
+			}); err != nil {
+				return err
+			}
+		}
+		return &ThrottledError{RetryAfter: c.WindowStart.Add(cfg.ResetPasswordWindow).Sub(now)}
+	}
+	return nil
+}
+
+// NewAccountUnlockToken issues a token unlocking the account of the user and returns it, only the hash is kept
+func NewAccountUnlockToken(db *gorm.DB, userID string) (string, error) {
+	now := time.Now().UTC()
+	token := utils.NewSecret(32)
+
+	lu := data.NewLoginProtectionRepository()
+	if err := lu.CreateUnlockToken(db, &models.AccountUnlockToken{
+		ID:        utils.NewUUID(),
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(config.LoginProtection().UnlockTokenTTL),
+		CreatedAt: now,
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// UnlockAccount lifts the lockout of the account the token was issued for and clears its failures
+func UnlockAccount(db *gorm.DB, token, ip, userAgent string) error {
+	lu := data.NewLoginProtectionRepository()
+	ut, err := lu.GetUnlockToken(db, hashToken(token))
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return ErrUnlockTokenInvalid
+		}
+		return err
+	}
+	if time.Now().After(ut.ExpiresAt) {
+		return ErrUnlockTokenInvalid
+	}
+
+	uu := data.NewUserRepository()
+	u, err := uu.Get(db, ut.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := lu.DeleteUnlockTokens(db, u.ID); err != nil {
+		return err
+	}
+	if err := GetLimiterStore().Reset(loginAccountCounter(u.Email)); err != nil {
+		return err
+	}
+
+	return RecordSecurityEvent(&models.SecurityAuditLog{
+		Event:     models.SecurityEventAccountUnlocked,
+		UserID:    &u.ID,
+		Email:     normalizeEmail(u.Email),
+		IP:        ip,
+		UserAgent: userAgent,
+	})
+}
+
+// RecordSecurityEvent adds the entry to the security audit log, outside of any transaction
+// as the attempt it's about is usually rolled back
+func RecordSecurityEvent(l *models.SecurityAuditLog) error {
+	l.ID = utils.NewUUID()
+	l.CreatedAt = time.Now().UTC()
+
+	lu := data.NewLoginProtectionRepository()
+	return lu.CreateAuditLog(app.DB(), l)
+}
+
+// CleanupUnlockTokens removes the expired account unlock tokens
+func CleanupUnlockTokens() error {
+	lu := data.NewLoginProtectionRepository()
+	return lu.DeleteExpiredUnlockTokens(app.DB(), time.Now().UTC())
+}
@@ -40,3 +40,9 @@ func ServeAsStreamFromMinio(fileName string) (*minio.Object, error) {
 	}
 	return o, nil
 }
+
+func RemoveFromMinio(fileName string) error {
+	conn := app.Minio()
+	cfg := config.Minio()
+	return conn.RemoveObject(cfg.Bucket, fileName)
+}
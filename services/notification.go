@@ -0,0 +1,210 @@
+package services
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type Notification struct {
+	Event models.NotificationEvent
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// NotificationChannel delivers notifications to a user, next to the transactional emails
+type NotificationChannel interface {
+	Name() string
+	IsEnabled(np *models.NotificationPreference) bool
+	Send(db *gorm.DB, u *models.User, n *Notification) error
+}
+
+type smsChannel struct {
+	provider SMSProvider
+}
+
+func (c *smsChannel) Name() string {
+	return "sms"
+}
+
+func (c *smsChannel) IsEnabled(np *models.NotificationPreference) bool {
+	return np.IsSMSEnabled
+}
+
+func (c *smsChannel) Send(db *gorm.DB, u *models.User, n *Notification) error {
+	if u.Phone == nil || *u.Phone == "" {
+		return nil
+	}
+	return c.provider.Send(*u.Phone, fmt.Sprintf("%s: %s", n.Title, n.Body))
+}
+
+type pushChannel struct {
+	provider PushProvider
+}
+
+func (c *pushChannel) Name() string {
+	return "push"
+}
+
+func (c *pushChannel) IsEnabled(np *models.NotificationPreference) bool {
+	return np.IsPushEnabled
+}
+
+func (c *pushChannel) Send(db *gorm.DB, u *models.User, n *Notification) error {
+	nu := data.NewNotificationRepository()
+	devices, err := nu.ListPushDevices(db, u.ID)
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	var tokens []string
+	for _, d := range devices {
+		tokens = append(tokens, d.Token)
+	}
+
+	stale, err := c.provider.Send(tokens, n.Title, n.Body, n.Data)
+	for _, t := range stale {
+		if err := nu.DeletePushDeviceByToken(db, t); err != nil {
+			log.Log().Errorln(err)
+		}
+	}
+	return err
+}
+
+func NotificationChannels() []NotificationChannel {
+	return []NotificationChannel{
+		&smsChannel{provider: NewSMSProvider()},
+		&pushChannel{provider: NewPushProvider()},
+	}
+}
+
+// DefaultNotificationPreference is used until the user saves one, push is on as it needs a registered device anyway
+func DefaultNotificationPreference(userID string, event models.NotificationEvent) *models.NotificationPreference {
+	return &models.NotificationPreference{
+		UserID:        userID,
+		Event:         event,
+		IsSMSEnabled:  false,
+		IsPushEnabled: true,
+	}
+}
+
+// GetNotificationPreferences returns the preference of every event, filling the unsaved ones with the defaults
+func GetNotificationPreferences(db *gorm.DB, userID string) ([]models.NotificationPreference, error) {
+	nu := data.NewNotificationRepository()
+	saved, err := nu.ListPreferences(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byEvent := map[models.NotificationEvent]models.NotificationPreference{}
+	for _, np := range saved {
+		byEvent[np.Event] = np
+	}
+
+	var res []models.NotificationPreference
+	for _, e := range models.NotificationEvents() {
+		np, ok := byEvent[e]
+		if !ok {
+			np = *DefaultNotificationPreference(userID, e)
+		}
+		res = append(res, np)
+	}
+	return res, nil
+}
+
+// Notify fans the notification out to the channels the user enabled for its event.
+// Failing channels are logged and not retried, so the others don't get the notification twice.
+func Notify(u *models.User, n *Notification) error {
+	db := app.DB()
+
+	nu := data.NewNotificationRepository()
+	np, err := nu.GetPreference(db, u.ID, n.Event)
+	if err != nil {
+		if !errors.IsRecordNotFoundError(err) {
+			return err
+		}
+		np = DefaultNotificationPreference(u.ID, n.Event)
+	}
+
+	for _, c := range NotificationChannels() {
+		if !c.IsEnabled(np) {
+			continue
+		}
+		if err := c.Send(db, u, n); err != nil {
+			log.Log().Errorln("Failed to send", n.Event, "notification through", c.Name(), "to", u.ID, ":", err)
+		}
+	}
+	return nil
+}
+
+// SendNotification notifies the user the event of the referenced order or payout entry concerns
+func SendNotification(event models.NotificationEvent, referenceID string) error {
+	db := app.DB()
+
+	var userID string
+	n := &Notification{
+		Event: event,
+		Data: map[string]string{
+			"event": string(event),
+		},
+	}
+
+	switch event {
+	case models.NotificationOrderConfirmed, models.NotificationOrderShipped, models.NotificationPaymentReceived:
+		ou := data.NewOrderRepository()
+		o, err := ou.GetDetails(db, referenceID)
+		if err != nil {
+			return err
+		}
+
+		userID = o.UserID
+		n.Data["order_id"] = o.ID
+
+		switch event {
+		case models.NotificationOrderConfirmed:
+			n.Title = "Order confirmed"
+			n.Body = fmt.Sprintf("Your order #%s from %s has been confirmed.", o.Hash, o.StoreName)
+		case models.NotificationOrderShipped:
+			n.Title = "Order shipped"
+			n.Body = fmt.Sprintf("Your order #%s from %s is on its way.", o.Hash, o.StoreName)
+		default:
+			n.Title = "Payment received"
+			n.Body = fmt.Sprintf("We have received the payment of %s for your order #%s.", formatAmount(o.GrandTotal), o.Hash)
+		}
+	case models.NotificationPayoutCompleted:
+		pu := data.NewMarketplaceRepository()
+		ps, err := pu.GetPayoutEntryByID(db, referenceID)
+		if err != nil {
+			return err
+		}
+
+		su := data.NewStoreRepository()
+		creator, err := su.GetCreator(db, ps.StoreID)
+		if err != nil {
+			return err
+		}
+
+		userID = creator.UserID
+		n.Data["store_id"] = ps.StoreID
+		n.Data["payout_id"] = ps.ID
+		n.Title = "Payout completed"
+		n.Body = fmt.Sprintf("Your payout of %s has been completed.", formatAmount(ps.Amount))
+	default:
+		return fmt.Errorf("unknown notification event %s", event)
+	}
+
+	uu := data.NewUserRepository()
+	u, err := uu.Get(db, userID)
+	if err != nil {
+		return err
+	}
+	return Notify(u, n)
+}
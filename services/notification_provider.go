@@ -0,0 +1,214 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nahid/gohttp"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	NotificationProviderNone   = "none"
+	NotificationProviderFile   = "file"
+	NotificationProviderTwilio = "twilio"
+	NotificationProviderFCM    = "fcm"
+)
+
+// SMSProvider sends a text message to a phone number
+type SMSProvider interface {
+	Name() string
+	Send(phone, text string) error
+}
+
+// PushProvider sends a notification to device tokens, returning the tokens the provider no longer accepts
+type PushProvider interface {
+	Name() string
+	Send(tokens []string, title, body string, data map[string]string) ([]string, error)
+}
+
+type noopProvider struct {
+}
+
+func (p *noopProvider) Name() string {
+	return NotificationProviderNone
+}
+
+func (p *noopProvider) Send(to, text string) error {
+	log.Log().Infoln("Skipped notification to", to, ", no provider configured")
+	return nil
+}
+
+type noopPushProvider struct {
+	noopProvider
+}
+
+func (p *noopPushProvider) Send(tokens []string, title, body string, data map[string]string) ([]string, error) {
+	return nil, p.noopProvider.Send(strings.Join(tokens, ","), body)
+}
+
+// fileProvider appends every notification as a JSON line to a file of its channel, for local use
+type fileProvider struct {
+	dir     string
+	channel string
+}
+
+func (p *fileProvider) Name() string {
+	return NotificationProviderFile
+}
+
+func (p *fileProvider) write(v map[string]interface{}) error {
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(p.dir, fmt.Sprintf("%s.log", p.channel)), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	v["sent_at"] = time.Now().UTC()
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+func (p *fileProvider) Send(phone, text string) error {
+	return p.write(map[string]interface{}{
+		"to":   phone,
+		"text": text,
+	})
+}
+
+type filePushProvider struct {
+	fileProvider
+}
+
+func (p *filePushProvider) Send(tokens []string, title, body string, data map[string]string) ([]string, error) {
+	return nil, p.write(map[string]interface{}{
+		"tokens": tokens,
+		"title":  title,
+		"body":   body,
+		"data":   data,
+	})
+}
+
+type twilioProvider struct {
+	cfg config.TwilioCfg
+}
+
+func (p *twilioProvider) Name() string {
+	return NotificationProviderTwilio
+}
+
+func (p *twilioProvider) Send(phone, text string) error {
+	url := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", p.cfg.AccountSID)
+
+	resp, err := gohttp.NewRequest().
+		BasicAuth(p.cfg.AccountSID, p.cfg.AuthToken).
+		FormData(map[string]string{
+			"From": p.cfg.From,
+			"To":   phone,
+			"Body": text,
+		}).
+		Post(url)
+	if err != nil {
+		return err
+	}
+
+	if resp.GetStatusCode() >= 300 {
+		body, _ := resp.GetBodyAsString()
+		return fmt.Errorf("twilio responded with %d: %s", resp.GetStatusCode(), body)
+	}
+	return nil
+}
+
+type fcmProvider struct {
+	cfg config.FCMCfg
+}
+
+type resFCMSend struct {
+	Results []struct {
+		Error string `json:"error"`
+	} `json:"results"`
+}
+
+func (p *fcmProvider) Name() string {
+	return NotificationProviderFCM
+}
+
+func (p *fcmProvider) Send(tokens []string, title, body string, data map[string]string) ([]string, error) {
+	resp, err := gohttp.NewRequest().
+		Headers(map[string]string{
+			"Authorization": fmt.Sprintf("key=%s", p.cfg.ServerKey),
+		}).
+		JSON(map[string]interface{}{
+			"registration_ids": tokens,
+			"notification": map[string]string{
+				"title": title,
+				"body":  body,
+			},
+			"data": data,
+		}).
+		Post("https://fcm.googleapis.com/fcm/send")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.GetStatusCode() != 200 {
+		return nil, fmt.Errorf("fcm responded with %d", resp.GetStatusCode())
+	}
+
+	res := resFCMSend{}
+	if err := resp.UnmarshalBody(&res); err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	failed := 0
+	for i, r := range res.Results {
+		switch r.Error {
+		case "":
+		case "NotRegistered", "InvalidRegistration":
+			if i < len(tokens) {
+				stale = append(stale, tokens[i])
+			}
+		default:
+			failed++
+		}
+	}
+	if failed > 0 {
+		return stale, fmt.Errorf("fcm failed to deliver to %d devices", failed)
+	}
+	return stale, nil
+}
+
+func NewSMSProvider() SMSProvider {
+	cfg := config.Notification()
+	switch cfg.SMSProvider {
+	case NotificationProviderFile:
+		return &fileProvider{dir: cfg.FileSinkDir, channel: "sms"}
+	case NotificationProviderTwilio:
+		return &twilioProvider{cfg: cfg.Twilio}
+	}
+	return &noopProvider{}
+}
+
+func NewPushProvider() PushProvider {
+	cfg := config.Notification()
+	switch cfg.PushProvider {
+	case NotificationProviderFile:
+		return &filePushProvider{fileProvider{dir: cfg.FileSinkDir, channel: "push"}}
+	case NotificationProviderFCM:
+		return &fcmProvider{cfg: cfg.FCM}
+	}
+	return &noopPushProvider{}
+}
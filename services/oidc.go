@@ -0,0 +1,527 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	oidcTimeout          = time.Second * 10
+	oidcDiscoveryTTL     = time.Hour * 24
+	oidcKeysTTL          = time.Hour
+	oidcKeysMinRefresh   = time.Minute
+	oidcClockSkew        = time.Minute * 2
+	oidcAppleSecretTTL   = time.Minute * 5
+	oidcMaxResponseBytes = 1 << 20
+)
+
+var (
+	ErrOIDCProviderNotFound = errors.NewError("oidc provider not found")
+	ErrOIDCProviderFailed   = errors.NewError("oidc provider request failed")
+	ErrOIDCTokenInvalid     = errors.NewError("invalid id token")
+)
+
+var oidcClient = &http.Client{Timeout: oidcTimeout}
+
+var oidcProviders = map[string]*OIDCProvider{}
+var oidcProvidersMu sync.Mutex
+
+// OIDCIdentity is what a verified id token tells about the user
+type OIDCIdentity struct {
+	Subject         string
+	Email           string
+	IsEmailVerified bool
+	Name            string
+	Picture         string
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// OIDCProvider signs users in through an OpenID Connect issuer with the authorization code flow and PKCE.
+// The discovery document and the signing keys of the issuer are fetched lazily and cached.
+type OIDCProvider struct {
+	cfg config.OIDCProviderCfg
+
+	mu           sync.Mutex
+	discovery    *oidcDiscovery
+	discoveredAt time.Time
+	keys         map[string]interface{}
+	keysLoadedAt time.Time
+}
+
+func NewOIDCProvider(cfg config.OIDCProviderCfg) *OIDCProvider {
+	return &OIDCProvider{cfg: cfg}
+}
+
+// GetOIDCProvider returns the provider configured with the name
+func GetOIDCProvider(name string) (*OIDCProvider, error) {
+	oidcProvidersMu.Lock()
+	defer oidcProvidersMu.Unlock()
+
+	if p, ok := oidcProviders[name]; ok {
+		return p, nil
+	}
+
+	cfg, ok := config.OIDC().Providers[name]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+	p := NewOIDCProvider(cfg)
+	oidcProviders[name] = p
+	return p, nil
+}
+
+func OIDCProviderNames() []string {
+	names := []string{}
+	for name := range config.OIDC().Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.cfg.Name
+}
+
+// OIDCCodeChallenge is the S256 PKCE challenge of the verifier
+func OIDCCodeChallenge(verifier string) string {
+	h := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// AuthorizationURL is where the user agent is sent to authenticate with the provider
+func (p *OIDCProvider) AuthorizationURL(state, nonce, codeVerifier string) (string, error) {
+	d, err := p.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", OIDCCodeChallenge(codeVerifier))
+	q.Set("code_challenge_method", "S256")
+	if p.cfg.ResponseMode != "" {
+		q.Set("response_mode", p.cfg.ResponseMode)
+	}
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the identity of its verified id token
+func (p *OIDCProvider) Exchange(code, codeVerifier, nonce string) (*OIDCIdentity, error) {
+	d, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := p.clientSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if secret != "" {
+		form.Set("client_secret", secret)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res := struct {
+		IDToken string `json:"id_token"`
+	}{}
+	if err := oidcDo(req, &res); err != nil {
+		return nil, err
+	}
+	if res.IDToken == "" {
+		return nil, ErrOIDCTokenInvalid
+	}
+	return p.VerifyIDToken(res.IDToken, nonce)
+}
+
+// VerifyIDToken checks the signature of the id token against the keys of the issuer along with
+// its issuer, audience, lifetime and nonce
+func (p *OIDCProvider) VerifyIDToken(raw, nonce string) (*OIDCIdentity, error) {
+	d, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jwt.Parser{
+		ValidMethods: []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"},
+	}
+
+	claims := idTokenClaims{}
+	if _, err := parser.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.getKey(kid)
+	}); err != nil {
+		if ve, ok := err.(*jwt.ValidationError); ok && ve.Inner == ErrOIDCProviderFailed {
+			return nil, ErrOIDCProviderFailed
+		}
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	if !p.isIssuer(claims.Issuer, d) || claims.Subject == "" || claims.Nonce != nonce {
+		return nil, ErrOIDCTokenInvalid
+	}
+	if !claims.Audience.contains(p.cfg.ClientID) {
+		return nil, ErrOIDCTokenInvalid
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	return &OIDCIdentity{
+		Subject:         claims.Subject,
+		Email:           claims.Email,
+		IsEmailVerified: bool(claims.EmailVerified),
+		Name:            claims.Name,
+		Picture:         claims.Picture,
+	}, nil
+}
+
+// isIssuer matches the iss claim, Google may leave the scheme out of it
+func (p *OIDCProvider) isIssuer(iss string, d *oidcDiscovery) bool {
+	if iss == d.Issuer {
+		return true
+	}
+	return p.cfg.Name == config.OIDCGoogle && "https://"+iss == d.Issuer
+}
+
+// clientSecret returns the configured secret, for Apple it's a short lived JWT signed with the private key
+func (p *OIDCProvider) clientSecret() (string, error) {
+	if p.cfg.PrivateKey == "" {
+		return p.cfg.ClientSecret, nil
+	}
+
+	key, err := parseECPrivateKey(p.cfg.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.StandardClaims{
+		Issuer:    p.cfg.TeamID,
+		Subject:   p.cfg.ClientID,
+		Audience:  p.cfg.Issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(oidcAppleSecretTTL).Unix(),
+	})
+	t.Header["kid"] = p.cfg.KeyID
+	return t.SignedString(key)
+}
+
+// parseECPrivateKey accepts the PKCS#8 keys Apple hands out as well as SEC 1 ones
+func parseECPrivateKey(v string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(v))
+	if block == nil {
+		return nil, jwt.ErrKeyMustBePEMEncoded
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if k, ok := key.(*ecdsa.PrivateKey); ok {
+			return k, nil
+		}
+		return nil, jwt.ErrNotECPrivateKey
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func (p *OIDCProvider) getDiscovery() (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil && time.Since(p.discoveredAt) < oidcDiscoveryTTL {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d := oidcDiscovery{}
+	if err := oidcDo(req, &d); err != nil {
+		if p.discovery != nil {
+			return p.discovery, nil
+		}
+		return nil, err
+	}
+	if d.Issuer != p.cfg.Issuer || d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, ErrOIDCProviderFailed
+	}
+
+	p.discovery = &d
+	p.discoveredAt = time.Now()
+	return p.discovery, nil
+}
+
+// getKey looks the signing key up, reloading the key set when the key isn't known so rotations are picked up
+func (p *OIDCProvider) getKey(kid string) (interface{}, error) {
+	d, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.lookupKey(kid)
+	if ok && time.Since(p.keysLoadedAt) < oidcKeysTTL {
+		return key, nil
+	}
+	if !ok && p.keys != nil && time.Since(p.keysLoadedAt) < oidcKeysMinRefresh {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	keys, err := loadJSONWebKeys(d.JWKSURI)
+	if err != nil {
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+	p.keys = keys
+	p.keysLoadedAt = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, ErrOIDCTokenInvalid
+}
+
+func (p *OIDCProvider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	k, ok := p.keys[kid]
+	return k, ok
+}
+
+func loadJSONWebKeys(uri string) (map[string]interface{}, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := oidcDo(req, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]interface{}{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("key %s isn't on curve", k.Kid)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+func decodeBigInt(v string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(v, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func oidcDo(req *http.Request, v interface{}) error {
+	resp, err := oidcClient.Do(req)
+	if err != nil {
+		return ErrOIDCProviderFailed
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, oidcMaxResponseBytes))
+	if err != nil {
+		return ErrOIDCProviderFailed
+	}
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		// the code was rejected, expired or already redeemed
+		return ErrOIDCTokenInvalid
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ErrOIDCProviderFailed
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return ErrOIDCProviderFailed
+	}
+	return nil
+}
+
+type idTokenClaims struct {
+	Issuer          string       `json:"iss"`
+	Subject         string       `json:"sub"`
+	Audience        oidcAudience `json:"aud"`
+	AuthorizedParty string       `json:"azp"`
+	ExpiresAt       int64        `json:"exp"`
+	IssuedAt        int64        `json:"iat"`
+	NotBefore       int64        `json:"nbf"`
+	Nonce           string       `json:"nonce"`
+	Email           string       `json:"email"`
+	EmailVerified   oidcBool     `json:"email_verified"`
+	Name            string       `json:"name"`
+	Picture         string       `json:"picture"`
+}
+
+// Valid checks the lifetime of the token, allowing for some clock skew with the issuer
+func (c *idTokenClaims) Valid() error {
+	now := time.Now()
+	if c.ExpiresAt == 0 || now.Add(-oidcClockSkew).Unix() > c.ExpiresAt {
+		return ErrOIDCTokenInvalid
+	}
+	if c.IssuedAt != 0 && now.Add(oidcClockSkew).Unix() < c.IssuedAt {
+		return ErrOIDCTokenInvalid
+	}
+	if c.NotBefore != 0 && now.Add(oidcClockSkew).Unix() < c.NotBefore {
+		return ErrOIDCTokenInvalid
+	}
+	return nil
+}
+
+// oidcAudience is either a single audience or a list of them
+type oidcAudience []string
+
+func (a *oidcAudience) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = oidcAudience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func (a oidcAudience) contains(v string) bool {
+	for _, s := range a {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// oidcBool accepts "true" as well since Apple sends the booleans of its claims as strings
+type oidcBool bool
+
+func (ob *oidcBool) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case bool:
+		*ob = oidcBool(t)
+	case string:
+		*ob = oidcBool(t == "true")
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/shopicano/shopicano-backend/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// stubIssuer is a local OpenID Connect issuer serving discovery, keys and a token endpoint
+type stubIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+	claims jwt.MapClaims
+	// challenge is the PKCE challenge of the last authorization, the token endpoint checks the verifier against it
+	challenge string
+	keyLoads  int
+}
+
+func newStubIssuer(t *testing.T) *stubIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &stubIssuer{t: t, key: key, kid: "key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 s.server.URL,
+			"authorization_endpoint": s.server.URL + "/authorize",
+			"token_endpoint":         s.server.URL + "/token",
+			"jwks_uri":               s.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		s.keyLoads++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kid": s.kid,
+					"kty": "RSA",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("code") != "valid-code" || OIDCCodeChallenge(r.PostForm.Get("code_verifier")) != s.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access",
+			"id_token":     s.sign(s.claims),
+		})
+	})
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *stubIssuer) sign(claims jwt.MapClaims) string {
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	t.Header["kid"] = s.kid
+	v, err := t.SignedString(s.key)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return v
+}
+
+func (s *stubIssuer) idTokenClaims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            s.server.URL,
+		"sub":            "subject-1",
+		"aud":            "client-1",
+		"exp":            now.Add(time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          nonce,
+		"email":          "user@example.com",
+		"email_verified": "true",
+		"name":           "Stub User",
+	}
+}
+
+func (s *stubIssuer) provider() *OIDCProvider {
+	return NewOIDCProvider(config.OIDCProviderCfg{
+		Name:         "stub",
+		Issuer:       s.server.URL,
+		ClientID:     "client-1",
+		ClientSecret: "secret-1",
+		RedirectURL:  "https://shop.example.com/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+}
+
+// authorize follows the authorization url like the user agent would and keeps the PKCE challenge
+func (s *stubIssuer) authorize(t *testing.T, p *OIDCProvider, state, nonce, verifier string) {
+	uri, err := p.AuthorizationURL(state, nonce, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("state") != state || q.Get("nonce") != nonce {
+		t.Fatalf("unexpected authorization url %s", uri)
+	}
+	s.challenge = q.Get("code_challenge")
+}
+
+func TestOIDCExchange(t *testing.T) {
+	s := newStubIssuer(t)
+	defer s.server.Close()
+
+	p := s.provider()
+	s.authorize(t, p, "state-1", "nonce-1", "verifier-1")
+	s.claims = s.idTokenClaims("nonce-1")
+
+	id, err := p.Exchange("valid-code", "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Subject != "subject-1" || id.Email != "user@example.com" || !id.IsEmailVerified || id.Name != "Stub User" {
+		t.Fatalf("unexpected identity %+v", id)
+	}
+}
+
+func TestOIDCExchangeRejectsVerifier(t *testing.T) {
+	s := newStubIssuer(t)
+	defer s.server.Close()
+
+	p := s.provider()
+	s.authorize(t, p, "state-1", "nonce-1", "verifier-1")
+	s.claims = s.idTokenClaims("nonce-1")
+
+	if _, err := p.Exchange("valid-code", "another-verifier", "nonce-1"); err != ErrOIDCTokenInvalid {
+		t.Fatalf("expected %v, got %v", ErrOIDCTokenInvalid, err)
+	}
+}
+
+func TestOIDCVerifyIDToken(t *testing.T) {
+	s := newStubIssuer(t)
+	defer s.server.Close()
+
+	p := s.provider()
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]func() string{
+		"nonce": func() string {
+			return s.sign(s.idTokenClaims("another-nonce"))
+		},
+		"audience": func() string {
+			c := s.idTokenClaims("nonce-1")
+			c["aud"] = []string{"client-2"}
+			return s.sign(c)
+		},
+		"authorized party": func() string {
+			c := s.idTokenClaims("nonce-1")
+			c["aud"] = []string{"client-1", "client-2"}
+			c["azp"] = "client-2"
+			return s.sign(c)
+		},
+		"issuer": func() string {
+			c := s.idTokenClaims("nonce-1")
+			c["iss"] = "https://issuer.example.com"
+			return s.sign(c)
+		},
+		"expired": func() string {
+			c := s.idTokenClaims("nonce-1")
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+			return s.sign(c)
+		},
+		"signature": func() string {
+			t := jwt.NewWithClaims(jwt.SigningMethodRS256, s.idTokenClaims("nonce-1"))
+			t.Header["kid"] = s.kid
+			v, _ := t.SignedString(other)
+			return v
+		},
+		"algorithm": func() string {
+			t := jwt.NewWithClaims(jwt.SigningMethodHS256, s.idTokenClaims("nonce-1"))
+			t.Header["kid"] = s.kid
+			v, _ := t.SignedString([]byte("secret-1"))
+			return v
+		},
+	}
+
+	for name, token := range cases {
+		if _, err := p.VerifyIDToken(token(), "nonce-1"); err != ErrOIDCTokenInvalid {
+			t.Errorf("%s: expected %v, got %v", name, ErrOIDCTokenInvalid, err)
+		}
+	}
+
+	c := s.idTokenClaims("nonce-1")
+	c["aud"] = []string{"client-1", "client-2"}
+	c["azp"] = "client-1"
+	if _, err := p.VerifyIDToken(s.sign(c), "nonce-1"); err != nil {
+		t.Errorf("multiple audiences: %v", err)
+	}
+}
+
+func TestOIDCKeyRotation(t *testing.T) {
+	s := newStubIssuer(t)
+	defer s.server.Close()
+
+	p := s.provider()
+	if _, err := p.VerifyIDToken(s.sign(s.idTokenClaims("nonce-1")), "nonce-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.key = key
+	s.kid = "key-2"
+
+	// the unknown key is only looked up again once the minimum refresh interval has passed
+	if _, err := p.VerifyIDToken(s.sign(s.idTokenClaims("nonce-1")), "nonce-1"); err != ErrOIDCTokenInvalid {
+		t.Fatalf("expected %v, got %v", ErrOIDCTokenInvalid, err)
+	}
+
+	p.keysLoadedAt = time.Now().Add(-oidcKeysMinRefresh)
+	if _, err := p.VerifyIDToken(s.sign(s.idTokenClaims("nonce-1")), "nonce-1"); err != nil {
+		t.Fatal(err)
+	}
+	if s.keyLoads != 2 {
+		t.Fatalf("expected the keys to be loaded twice, loaded %d times", s.keyLoads)
+	}
+}
@@ -0,0 +1,515 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/values"
+	"time"
+)
+
+const (
+	DataExportContentType = "application/zip"
+
+	// DataExportBuildTimeout is how long the queue retries to build an export before giving up on it
+	DataExportBuildTimeout = time.Hour
+
+	personalDataPageSize = 100
+
+	accountDeletionPostponeDelay = time.Hour * 24
+	accountDeletionRetryDelay    = time.Hour
+)
+
+var (
+	ErrDataExportInProgress            = errors.NewError("a data export is already in progress")
+	ErrDataExportNotReady              = errors.NewError("data export isn't ready or has expired")
+	ErrAccountDeletionAlreadyRequested = errors.NewError("account deletion is already requested")
+	ErrAccountDeletionNotRequested     = errors.NewError("account deletion isn't requested")
+	ErrAccountDeletionTokenInvalid     = errors.NewError("invalid or expired account deletion token")
+	ErrAccountOwnsStore                = errors.NewError("account owns a store")
+)
+
+// RequestDataExport records a pending export of the personal data of the user along with its domain event,
+// the archive is built by the data export subscriber
+func RequestDataExport(db *gorm.DB, userID string) (*models.DataExport, error) {
+	pu := data.NewPersonalDataRepository()
+	ok, err := pu.HasPendingExport(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, ErrDataExportInProgress
+	}
+
+	de := &models.DataExport{
+		ID:        utils.NewUUID(),
+		UserID:    userID,
+		Status:    models.DataExportPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := pu.CreateExport(db, de); err != nil {
+		return nil, err
+	}
+
+	if err := RecordEvent(db, models.EventDataExportRequested, de.ID, nil); err != nil {
+		return nil, err
+	}
+	return de, nil
+}
+
+// BuildDataExport collects the personal data of the user of the export into a zip archive of json files,
+// stores it in minio and makes it downloadable until the export ttl. An export still failing after the
+// build timeout is marked failed so the user can request another one.
+func BuildDataExport(ID string) error {
+	db := app.DB()
+
+	pu := data.NewPersonalDataRepository()
+	de, err := pu.GetExportByID(db, ID)
+	if err != nil {
+		return err
+	}
+	if de.Status != models.DataExportPending {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if now.Sub(de.CreatedAt) > DataExportBuildTimeout {
+		de.Status = models.DataExportFailed
+		de.CompletedAt = &now
+		return pu.UpdateExport(db, de)
+	}
+
+	pd, err := CollectPersonalData(db, de.UserID)
+	if err != nil {
+		return err
+	}
+
+	b, err := buildDataExportArchive(pd)
+	if err != nil {
+		return err
+	}
+
+	path := DataExportObjectPath(de)
+	if err := UploadToMinio(path, DataExportContentType, bytes.NewReader(b), int64(len(b))); err != nil {
+		return err
+	}
+
+	now = time.Now().UTC()
+	expiresAt := now.Add(config.PersonalData().ExportTTL)
+
+	de.Status = models.DataExportReady
+	de.Path = &path
+	de.Size = int64(len(b))
+	de.ExpiresAt = &expiresAt
+	de.CompletedAt = &now
+	return pu.UpdateExport(db, de)
+}
+
+// DataExportObjectPath returns the name of the minio object the archive of the export is stored at
+func DataExportObjectPath(de *models.DataExport) string {
+	return fmt.Sprintf("%s/exports/%s/%s.zip", values.ReservedBucketName, de.UserID, de.ID)
+}
+
+// GetDownloadableDataExport returns the export of the user while its archive can be downloaded
+func GetDownloadableDataExport(db *gorm.DB, userID, ID string) (*models.DataExport, error) {
+	pu := data.NewPersonalDataRepository()
+	de, err := pu.GetExport(db, userID, ID)
+	if err != nil {
+		return nil, err
+	}
+	if !de.IsDownloadable(time.Now()) {
+		return nil, ErrDataExportNotReady
+	}
+	return de, nil
+}
+
+// CollectPersonalData gathers the profile, addresses, orders, reviews, sessions and linked identities of the user
+func CollectPersonalData(db *gorm.DB, userID string) (*models.PersonalData, error) {
+	pd := &models.PersonalData{
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	uu := data.NewUserRepository()
+	u, err := uu.Get(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	pd.Profile = u
+
+	au := data.NewAddressRepository()
+	for from := 0; ; from += personalDataPageSize {
+		addresses, err := au.ListAddresses(db, userID, from, personalDataPageSize)
+		if err != nil {
+			return nil, err
+		}
+		pd.Addresses = append(pd.Addresses, addresses...)
+		if len(addresses) < personalDataPageSize {
+			break
+		}
+	}
+
+	ou := data.NewOrderRepository()
+	for from := 0; ; from += personalDataPageSize {
+		orders, err := ou.List(db, userID, from, personalDataPageSize)
+		if err != nil {
+			return nil, err
+		}
+		pd.Orders = append(pd.Orders, orders...)
+		if len(orders) < personalDataPageSize {
+			break
+		}
+	}
+
+	ru := data.NewReviewRepository()
+	if pd.Reviews, err = ru.ListByUser(db, userID); err != nil {
+		return nil, err
+	}
+
+	su := data.NewSessionRepository()
+	if pd.Sessions, err = su.List(db, userID); err != nil {
+		return nil, err
+	}
+
+	iu := data.NewUserIdentityRepository()
+	if pd.Identities, err = iu.List(db, userID); err != nil {
+		return nil, err
+	}
+	return pd, nil
+}
+
+func buildDataExportArchive(pd *models.PersonalData) ([]byte, error) {
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", pd.Profile},
+		{"addresses.json", pd.Addresses},
+		{"orders.json", pd.Orders},
+		{"reviews.json", pd.Reviews},
+		{"sessions.json", pd.Sessions},
+		{"identities.json", pd.Identities},
+	}
+
+	buf := bytes.Buffer{}
+	zw := zip.NewWriter(&buf)
+
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: pd.GeneratedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExpireDataExports removes the archives of the exports past their ttl
+func ExpireDataExports() error {
+	pu := data.NewPersonalDataRepository()
+	exports, err := pu.ListExpiredExports(app.DB(), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for i := range exports {
+		paths, err := expireDataExport(app.DB(), &exports[i])
+		if err != nil {
+			return err
+		}
+		removeDataExportArchives(paths)
+	}
+	return nil
+}
+
+// expireDataExport marks the export expired and returns the path of its archive, to be removed once db is committed
+func expireDataExport(db *gorm.DB, de *models.DataExport) ([]string, error) {
+	var paths []string
+	if de.Path != nil {
+		paths = append(paths, *de.Path)
+	}
+
+	de.Status = models.DataExportExpired
+	de.Path = nil
+
+	pu := data.NewPersonalDataRepository()
+	if err := pu.UpdateExport(db, de); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func removeDataExportArchives(paths []string) {
+	for _, p := range paths {
+		if err := RemoveFromMinio(p); err != nil {
+			log.Log().Errorln("Failed to remove data export archive", p, ":", err)
+		}
+	}
+}
+
+// RequestAccountDeletion schedules the deletion of the account after the grace period, the confirmation
+// email is sent by the email subscriber of its domain event. Store owners have to hand over or close their stores first.
+func RequestAccountDeletion(db *gorm.DB, userID string) (*models.AccountDeletion, error) {
+	pu := data.NewPersonalDataRepository()
+	if _, err := pu.GetPendingDeletion(db, userID); err == nil {
+		return nil, ErrAccountDeletionAlreadyRequested
+	} else if !errors.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+
+	if err := checkAccountOwnsNoStore(db, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	ad := &models.AccountDeletion{
+		ID:          utils.NewUUID(),
+		UserID:      userID,
+		Status:      models.AccountDeletionPending,
+		ScheduledAt: now.Add(config.PersonalData().DeletionGracePeriod),
+		CreatedAt:   now,
+	}
+	if err := pu.CreateDeletion(db, ad); err != nil {
+		return nil, err
+	}
+
+	if err := RecordEvent(db, models.EventAccountDeletionRequested, ad.ID, nil); err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+// NewAccountDeletionCancelToken replaces the token cancelling a pending deletion and returns it, only the hash is kept
+func NewAccountDeletionCancelToken(db *gorm.DB, ID string) (string, error) {
+	token := utils.NewSecret(32)
+
+	pu := data.NewPersonalDataRepository()
+	if err := pu.SetDeletionCancelTokenHash(db, ID, hashToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// CancelAccountDeletion cancels the pending deletion of the signed in user
+func CancelAccountDeletion(db *gorm.DB, userID string) error {
+	pu := data.NewPersonalDataRepository()
+	ad, err := pu.GetPendingDeletion(db, userID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return ErrAccountDeletionNotRequested
+		}
+		return err
+	}
+	return pu.UpdateDeletionStatus(db, ad.ID, models.AccountDeletionCancelled, time.Now().UTC())
+}
+
+// CancelAccountDeletionWithToken cancels the pending deletion the token of the confirmation email was issued for
+func CancelAccountDeletionWithToken(db *gorm.DB, token string) error {
+	pu := data.NewPersonalDataRepository()
+	ad, err := pu.GetDeletionByCancelTokenHash(db, hashToken(token))
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return ErrAccountDeletionTokenInvalid
+		}
+		return err
+	}
+	if ad.Status != models.AccountDeletionPending {
+		return ErrAccountDeletionTokenInvalid
+	}
+
+	if err := pu.UpdateDeletionStatus(db, ad.ID, models.AccountDeletionCancelled, time.Now().UTC()); err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return ErrAccountDeletionTokenInvalid
+		}
+		return err
+	}
+	return nil
+}
+
+// ProcessAccountDeletions carries out the deletions past their grace period, each in its own transaction.
+// A deletion failing is postponed, so it doesn't hold back the ones due after it.
+func ProcessAccountDeletions() error {
+	pu := data.NewPersonalDataRepository()
+
+	for {
+		now := time.Now().UTC()
+
+		deletions, err := pu.ListDueDeletions(app.DB(), now, personalDataPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, ad := range deletions {
+			if err := processAccountDeletion(&ad); err != nil {
+				delay := accountDeletionRetryDelay
+
+				// A store owned since the request keeps the deletion pending until it's handed over
+				if err == ErrAccountOwnsStore {
+					delay = accountDeletionPostponeDelay
+					log.Log().Warnln("Account deletion of", ad.UserID, "postponed:", err)
+				} else {
+					log.Log().Errorln("Account deletion of", ad.UserID, "failed:", err)
+				}
+
+				if err := pu.PostponeDeletion(app.DB(), ad.ID, now.Add(delay)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(deletions) < personalDataPageSize {
+			return nil
+		}
+	}
+}
+
+func processAccountDeletion(ad *models.AccountDeletion) error {
+	db := app.DB().Begin()
+
+	archives, err := deleteAccount(db, ad)
+	if err != nil {
+		db.Rollback()
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+
+	removeDataExportArchives(archives)
+	return nil
+}
+
+// deleteAccount anonymises the personal data of the user and drops its logins. The user row, the addresses
+// and the reviews are kept anonymously as the orders of the stores refer to them. The archives of its
+// exports are returned to be removed once db is committed.
+func deleteAccount(db *gorm.DB, ad *models.AccountDeletion) ([]string, error) {
+	now := time.Now().UTC()
+	userID := ad.UserID
+
+	su := data.NewStoreRepository()
+	profiles, err := su.ListStoreUserProfiles(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range profiles {
+		if p.IsCreator {
+			return nil, ErrAccountOwnsStore
+		}
+		if err := su.DeleteStoreStuffPermission(db, p.StoreID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	ssu := data.NewSessionRepository()
+	if err := ssu.DeleteByUser(db, userID); err != nil {
+		return nil, err
+	}
+
+	tu := data.NewTwoFactorRepository()
+	if err := tu.Delete(db, userID); err != nil {
+		return nil, err
+	}
+
+	iu := data.NewUserIdentityRepository()
+	identities, err := iu.List(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ui := range identities {
+		if err := iu.Delete(db, userID, ui.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	nu := data.NewNotificationRepository()
+	devices, err := nu.ListPushDevices(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pd := range devices {
+		if err := nu.DeletePushDevice(db, userID, pd.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	lu := data.NewLoginProtectionRepository()
+	if err := lu.DeleteUnlockTokens(db, userID); err != nil {
+		return nil, err
+	}
+
+	pu := data.NewPersonalDataRepository()
+	exports, err := pu.ListReadyExports(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	var archives []string
+	for i := range exports {
+		paths, err := expireDataExport(db, &exports[i])
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, paths...)
+	}
+
+	txu := data.NewTaxRepository()
+	if err := txu.DeleteExemption(db, userID); err != nil {
+		return nil, err
+	}
+
+	au := data.NewAddressRepository()
+	if err := au.AnonymizeAddresses(db, userID, now); err != nil {
+		return nil, err
+	}
+
+	ru := data.NewReviewRepository()
+	if err := ru.AnonymizeByUser(db, userID, now); err != nil {
+		return nil, err
+	}
+
+	uu := data.NewUserRepository()
+	if err := uu.Anonymize(db, userID, now); err != nil {
+		return nil, err
+	}
+
+	if err := pu.UpdateDeletionStatus(db, ad.ID, models.AccountDeletionCompleted, now); err != nil {
+		return nil, err
+	}
+	return archives, nil
+}
+
+func checkAccountOwnsNoStore(db *gorm.DB, userID string) error {
+	su := data.NewStoreRepository()
+	profiles, err := su.ListStoreUserProfiles(db, userID)
+	if err != nil {
+		return err
+	}
+	for _, p := range profiles {
+		if p.IsCreator {
+			return ErrAccountOwnsStore
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"github.com/shopicano/shopicano-backend/config"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitResult is the state of the bucket after a request, Reset is the time until it's full again
+// and RetryAfter the time until the next request is allowed when it was refused
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter keeps a token bucket per client and group in memory of the instance
+type RateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastPurge time.Time
+}
+
+var (
+	rateLimiter     *RateLimiter
+	rateLimiterOnce sync.Once
+)
+
+func GetRateLimiter() *RateLimiter {
+	rateLimiterOnce.Do(func() {
+		rateLimiter = &RateLimiter{
+			buckets: map[string]*tokenBucket{},
+		}
+	})
+	return rateLimiter
+}
+
+// Take spends a token of the bucket of the client in the group, it's refused when the bucket is empty
+func (l *RateLimiter) Take(g config.RateLimitGroupCfg, client string, now time.Time) RateLimitResult {
+	rate := float64(g.Requests) / g.Period.Seconds()
+	capacity := float64(g.Burst)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.purge(now)
+
+	key := g.Name + ":" + client
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: capacity, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	r := RateLimitResult{
+		Limit: g.Burst,
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		r.Allowed = true
+	} else {
+		r.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+	r.Remaining = int(b.tokens)
+	r.Reset = secondsToDuration((capacity - b.tokens) / rate)
+	return r
+}
+
+// purge drops the buckets idle long enough to be full again at most once a minute, the caller holds the lock
+func (l *RateLimiter) purge(now time.Time) {
+	if now.Sub(l.lastPurge) < time.Minute {
+		return
+	}
+	l.lastPurge = now
+
+	var longest time.Duration
+	for _, g := range config.RateLimit().Groups {
+		if g.Requests > 0 {
+			if d := time.Duration(float64(g.Period) * float64(g.Burst) / float64(g.Requests)); d > longest {
+				longest = d
+			}
+		}
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > longest {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"github.com/shopicano/shopicano-backend/config"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterTake(t *testing.T) {
+	g := config.RateLimitGroupCfg{
+		Name:     "test",
+		Requests: 1,
+		Period:   2 * time.Second,
+		Burst:    3,
+	}
+	start := time.Unix(1600000000, 0)
+
+	cases := []struct {
+		name       string
+		client     string
+		after      time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{name: "full bucket", client: "ip:203.0.113.7", allowed: true, remaining: 2},
+		{name: "second token", client: "ip:203.0.113.7", allowed: true, remaining: 1},
+		{name: "last token", client: "ip:203.0.113.7", allowed: true, remaining: 0},
+		{name: "empty bucket", client: "ip:203.0.113.7", allowed: false, remaining: 0, retryAfter: 2 * time.Second},
+		{name: "other client", client: "ip:203.0.113.8", allowed: true, remaining: 2},
+		{name: "half a token refilled", client: "ip:203.0.113.7", after: time.Second, allowed: false, retryAfter: time.Second},
+		{name: "a token refilled", client: "ip:203.0.113.7", after: 2 * time.Second, allowed: true, remaining: 0},
+		{name: "refilled up to the burst", client: "ip:203.0.113.7", after: 30 * time.Second, allowed: true, remaining: 2},
+	}
+
+	l := &RateLimiter{
+		buckets: map[string]*tokenBucket{},
+	}
+	now := start
+	for _, c := range cases {
+		now = now.Add(c.after)
+		r := l.Take(g, c.client, now)
+		if r.Allowed != c.allowed || r.Remaining != c.remaining || r.RetryAfter != c.retryAfter || r.Limit != g.Burst {
+			t.Errorf("%s: got %+v, want allowed %v, remaining %d, retry after %s", c.name, r, c.allowed, c.remaining, c.retryAfter)
+		}
+	}
+}
+
+func TestRateLimiterTakeSeparatesGroups(t *testing.T) {
+	login := config.RateLimitGroupCfg{Name: "login", Requests: 1, Period: time.Minute, Burst: 1}
+	api := config.RateLimitGroupCfg{Name: "api", Requests: 1, Period: time.Minute, Burst: 1}
+
+	l := &RateLimiter{
+		buckets: map[string]*tokenBucket{},
+	}
+	now := time.Unix(1600000000, 0)
+
+	if r := l.Take(login, "ip:203.0.113.7", now); !r.Allowed {
+		t.Fatal("first login request: want allowed")
+	}
+	if r := l.Take(login, "ip:203.0.113.7", now); r.Allowed {
+		t.Error("second login request: want refused")
+	}
+	if r := l.Take(api, "ip:203.0.113.7", now); !r.Allowed {
+		t.Error("api request after the login bucket ran out: want allowed")
+	}
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+func SendAccountDeletionRequestedEmail(u *models.User, params map[string]interface{}) error {
+	subject, body, err := RenderEmail(models.EmailTemplateAccountDeletionRequested, u.Locale, nil, "Your account will be deleted", params)
+	if err != nil {
+		return err
+	}
+
+	return SendEmail(&OutgoingEmail{
+		Recipient: u.Email,
+		Subject:   subject,
+		Body:      body,
+		Template:  models.EmailTemplateAccountDeletionRequested,
+		UserID:    &u.ID,
+	})
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+func SendAccountLockedEmail(u *models.User, params map[string]interface{}) error {
+	subject, body, err := RenderEmail(models.EmailTemplateAccountLocked, u.Locale, nil, "Your account is locked", params)
+	if err != nil {
+		return err
+	}
+
+	return SendEmail(&OutgoingEmail{
+		Recipient: u.Email,
+		Subject:   subject,
+		Body:      body,
+		Template:  models.EmailTemplateAccountLocked,
+		UserID:    &u.ID,
+	})
+}
@@ -3,24 +3,14 @@ package services
 import (
 	"fmt"
 	"github.com/RichardKnop/machinery/v1/tasks"
-	"github.com/go-gomail/gomail"
-	"github.com/shopicano/shopicano-backend/app"
 	"github.com/shopicano/shopicano-backend/config"
-	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/models"
 	payment_gateways "github.com/shopicano/shopicano-backend/payment-gateways"
-	"github.com/shopicano/shopicano-backend/templates"
 	"github.com/shopicano/shopicano-backend/utils"
 )
 
-func SendOrderDetailsEmail(email, subject string, order *models.OrderDetailsView) error {
-	pu := data.NewMarketplaceRepository()
-	settings, err := pu.GetSettings(app.DB())
-	if err != nil {
-		return err
-	}
-
+func SendOrderDetailsEmail(u *models.User, subject string, order *models.OrderDetailsView, attachments ...EmailAttachment) error {
 	params := map[string]interface{}{}
 	params["greetings"] = fmt.Sprintf("Hi %s,", order.UserName)
 	params["intros"] = subject
@@ -46,9 +36,6 @@ func SendOrderDetailsEmail(email, subject string, order *models.OrderDetailsView
 	params["grandTotal"] = fmt.Sprintf("%.2f", float64(order.GrandTotal)/100)
 	params["isCouponApplied"] = false
 	params["isDigitalPayment"] = !order.PaymentMethodIsOffline
-	params["assetsUrl"] = fmt.Sprintf("%s/assets/", settings.Website)
-	params["siteUrl"] = fmt.Sprintf("%s", settings.Website)
-	params["platformName"] = settings.Name
 
 	pg, err := payment_gateways.GetPaymentGatewayByName(order.PaymentGateway)
 	if err != nil {
@@ -87,37 +74,67 @@ func SendOrderDetailsEmail(email, subject string, order *models.OrderDetailsView
 		params["isCouponApplied"] = true
 	}
 
+	params["isTaxApplied"] = false
+	if order.TaxAmount != 0 {
+		params["tax"] = fmt.Sprintf("%.2f", float64(order.TaxAmount)/100)
+		params["isTaxIncluded"] = order.IncludedTaxAmount == order.TaxAmount
+		params["isTaxApplied"] = true
+	}
+	if order.TaxExemptVatNumber != nil {
+		params["vatNumber"] = *order.TaxExemptVatNumber
+	}
+
 	var items []map[string]interface{}
 
+	// Bundle components are printed right after their bundle without any price
+	components := map[string][]models.OrderedItemView{}
 	for _, v := range order.Items {
+		if v.ParentID != nil {
+			components[*v.ParentID] = append(components[*v.ParentID], v)
+		}
+	}
+
+	for _, v := range order.Items {
+		if v.ParentID != nil {
+			continue
+		}
+
 		items = append(items, map[string]interface{}{
-			"name":     v.Name,
-			"quantity": v.Quantity,
-			"price":    fmt.Sprintf("%.2f", float64(v.Price)/100),
-			"subTotal": fmt.Sprintf("%.2f", float64(v.SubTotal)/100),
+			"name":        v.Name,
+			"quantity":    v.Quantity,
+			"price":       fmt.Sprintf("%.2f", float64(v.Price)/100),
+			"subTotal":    fmt.Sprintf("%.2f", float64(v.SubTotal)/100),
+			"isComponent": false,
 		})
+
+		for _, c := range components[v.ID] {
+			items = append(items, map[string]interface{}{
+				"name":        c.Name,
+				"quantity":    c.Quantity,
+				"isComponent": true,
+			})
+		}
 	}
 
 	params["orderedItems"] = items
 
-	body, err := templates.GenerateInvoiceEmailHTML(params)
+	subject, body, err := RenderEmail(models.EmailTemplateInvoice, u.Locale, &order.StoreID, subject, params)
 	if err != nil {
 		log.Log().Errorln(err)
 		return tasks.NewErrRetryTaskLater(err.Error(), TaskRetryDelay)
 	}
 
-	if err := SendEmail(subject, email, body); err != nil {
+	if err := SendEmail(&OutgoingEmail{
+		Recipient:   u.Email,
+		Subject:     subject,
+		Body:        body,
+		Template:    models.EmailTemplateInvoice,
+		UserID:      &u.ID,
+		OrderID:     &order.ID,
+		Attachments: attachments,
+	}); err != nil {
 		log.Log().Errorln(err)
 		return tasks.NewErrRetryTaskLater(err.Error(), TaskRetryDelay)
 	}
 	return nil
 }
-
-func SendEmail(subject, email, body string) error {
-	m := gomail.NewMessage()
-	m.SetHeader("From", fmt.Sprintf("%s", config.EmailService().FromEmailAddress))
-	m.SetHeader("To", email)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/html", body)
-	return EmailDialer().DialAndSend(m)
-}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+func SendResetPasswordEmail(u *models.User, params map[string]interface{}) error {
+	subject, body, err := RenderEmail(models.EmailTemplateResetPassword, u.Locale, nil, "Reset Password Requested", params)
+	if err != nil {
+		return err
+	}
+
+	return SendEmail(&OutgoingEmail{
+		Recipient: u.Email,
+		Subject:   subject,
+		Body:      body,
+		Template:  models.EmailTemplateResetPassword,
+		UserID:    &u.ID,
+	})
+}
+
+func SendResetPasswordConfirmationEmail(u *models.User, params map[string]interface{}) error {
+	subject, body, err := RenderEmail(models.EmailTemplateResetPasswordConfirmation, u.Locale, nil, "Your password changed", params)
+	if err != nil {
+		return err
+	}
+
+	return SendEmail(&OutgoingEmail{
+		Recipient: u.Email,
+		Subject:   subject,
+		Body:      body,
+		Template:  models.EmailTemplateResetPasswordConfirmation,
+		UserID:    &u.ID,
+	})
+}
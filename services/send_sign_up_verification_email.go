@@ -1,19 +1,22 @@
 package services
 
 import (
-	"fmt"
-	"github.com/go-gomail/gomail"
-	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/models"
 )
 
-func SendSignUpVerificationEmail(email, subject, body string) error {
-	m := gomail.NewMessage()
-	m.SetHeader("From", fmt.Sprintf("%s", config.EmailService().FromEmailAddress))
-	m.SetHeader("To", email)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/html", body)
+func SendSignUpVerificationEmail(u *models.User, params map[string]interface{}) error {
+	subject, body, err := RenderEmail(models.EmailTemplateVerifyEmail, u.Locale, nil, "Please verify your account", params)
+	if err != nil {
+		return err
+	}
 
-	if err := EmailDialer().DialAndSend(m); err != nil {
+	if err := SendEmail(&OutgoingEmail{
+		Recipient: u.Email,
+		Subject:   subject,
+		Body:      body,
+		Template:  models.EmailTemplateVerifyEmail,
+		UserID:    &u.ID,
+	}); err != nil {
 		return err
 	}
 	return nil
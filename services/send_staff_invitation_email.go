@@ -0,0 +1,28 @@
+package services
+
+import (
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+// SendStaffInvitationEmail sends the invitation with the branding of the inviting store, u is nil when the email isn't registered
+func SendStaffInvitationEmail(si *models.StaffInvitationDetails, u *models.User, params map[string]interface{}) error {
+	locale := models.DefaultLocale
+	var userID *string
+	if u != nil {
+		locale = u.Locale
+		userID = &u.ID
+	}
+
+	subject, body, err := RenderEmail(models.EmailTemplateStaffInvitation, locale, &si.StoreID, "You're invited to join {{ .storeName }}", params)
+	if err != nil {
+		return err
+	}
+
+	return SendEmail(&OutgoingEmail{
+		Recipient: si.Email,
+		Subject:   subject,
+		Body:      body,
+		Template:  models.EmailTemplateStaffInvitation,
+		UserID:    userID,
+	})
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"strings"
+	"time"
+)
+
+const (
+	// SessionRetention is how long revoked and expired sessions are kept before the cleanup removes them
+	SessionRetention = time.Hour * 24 * 7
+	// sessionTouchInterval limits how often a request updates the last seen info of its session
+	sessionTouchInterval = time.Minute
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.NewError("Invalid refresh token")
+	ErrRefreshTokenReused  = errors.NewError("Refresh token has already been used, the session is revoked")
+)
+
+type SessionTokens struct {
+	SessionID       string `json:"session_id"`
+	AccessToken     string `json:"access_token"`
+	RefreshToken    string `json:"refresh_token"`
+	ExpireOn        int64  `json:"expire_on"`
+	RefreshExpireOn int64  `json:"refresh_expire_on"`
+}
+
+func hashToken(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+// issueSessionTokens signs a new access token and generates a new refresh token for the session.
+// The refresh token carries the session ID, so that a rotated token can be told apart from an unknown one.
+func issueSessionTokens(s *models.Session) (*SessionTokens, error) {
+	accessToken, expireOn, err := utils.BuildJWTToken(s.UserID, s.ID, utils.UserScope(s.Scope))
+	if err != nil {
+		return nil, err
+	}
+
+	secret := utils.NewSecret(32)
+
+	s.AccessToken = accessToken
+	s.RefreshToken = hashToken(secret)
+	s.ExpireOn = time.Now().Add(config.App().RefreshTokenTTL).Unix()
+
+	return &SessionTokens{
+		SessionID:       s.ID,
+		AccessToken:     accessToken,
+		RefreshToken:    fmt.Sprintf("%s.%s", s.ID, secret),
+		ExpireOn:        expireOn,
+		RefreshExpireOn: s.ExpireOn,
+	}, nil
+}
+
+// CreateSession starts a session of the user on the device the request came from
+func CreateSession(db *gorm.DB, userID string, scope utils.UserScope, userAgent, ip string) (*SessionTokens, error) {
+	now := time.Now().UTC()
+
+	s := &models.Session{
+		ID:         utils.NewUUID(),
+		UserID:     userID,
+		Scope:      string(scope),
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  now,
+		LastSeenAt: &now,
+	}
+
+	tokens, err := issueSessionTokens(s)
+	if err != nil {
+		return nil, err
+	}
+
+	su := data.NewSessionRepository()
+	if err := su.Create(db, s); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RefreshSession rotates the tokens of the session the refresh token belongs to. A refresh token is
+// valid only once, presenting an already rotated one means it leaked and the whole session is revoked.
+func RefreshSession(refreshToken, userAgent, ip string) (*SessionTokens, error) {
+	parts := strings.SplitN(refreshToken, ".", 2)
+	if len(parts) != 2 {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	now := time.Now().UTC()
+
+	db := app.DB().Begin()
+
+	su := data.NewSessionRepository()
+	s, err := su.Get(db.Set("gorm:query_option", "FOR UPDATE"), parts[0])
+	if err != nil {
+		db.Rollback()
+		if errors.IsRecordNotFoundError(err) {
+			return nil, ErrRefreshTokenInvalid
+		}
+		return nil, err
+	}
+
+	if !s.IsActive(now) {
+		db.Rollback()
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashToken(parts[1])), []byte(s.RefreshToken)) != 1 {
+		if err := su.Revoke(db, s.UserID, s.ID, models.SessionRevokedRefreshReused, now); err != nil {
+			db.Rollback()
+			return nil, err
+		}
+		if err := db.Commit().Error; err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	tokens, err := issueSessionTokens(s)
+	if err != nil {
+		db.Rollback()
+		return nil, err
+	}
+
+	s.UserAgent = userAgent
+	s.IP = ip
+	s.LastSeenAt = &now
+
+	if err := su.Rotate(db, s); err != nil {
+		db.Rollback()
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// TouchSession records the device info of a request made with the session, at most once per interval
+func TouchSession(db *gorm.DB, s *models.Session, userAgent, ip string) error {
+	now := time.Now().UTC()
+	if s.LastSeenAt != nil && now.Sub(*s.LastSeenAt) < sessionTouchInterval &&
+		s.UserAgent == userAgent && s.IP == ip {
+		return nil
+	}
+
+	su := data.NewSessionRepository()
+	return su.Touch(db, s.ID, now, userAgent, ip)
+}
+
+// CleanupSessions removes the sessions expired or revoked longer than the retention ago
+func CleanupSessions() error {
+	su := data.NewSessionRepository()
+	return su.DeleteInactive(app.DB(), time.Now().UTC().Add(-SessionRetention))
+}
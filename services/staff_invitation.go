@@ -0,0 +1,277 @@
+package services
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"strings"
+	"time"
+)
+
+const StaffInvitationTTL = time.Hour * 24 * 7
+
+var (
+	ErrStaffInvitationInvalid       = errors.NewError("invalid or expired staff invitation")
+	ErrStaffInvitationEmailMismatch = errors.NewError("invitation is for another email")
+	ErrStaffInvitationAlreadyExists = errors.NewError("email is already invited")
+	ErrUserAlreadyStaff             = errors.NewError("user is already a staff of the store")
+)
+
+// InviteStaff records a pending invitation of the email along with its domain event, the email subscriber sends the token
+func InviteStaff(db *gorm.DB, storeID, actorID, email, permissionID string, roleID *string) (*models.StaffInvitation, error) {
+	now := time.Now().UTC()
+
+	uu := data.NewUserRepository()
+	u, err := uu.GetByEmail(db, email)
+	if err == nil {
+		su := data.NewStoreRepository()
+		ok, err := su.IsAlreadyStaff(db, storeID, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return nil, ErrUserAlreadyStaff
+		}
+	} else if !errors.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+
+	iu := data.NewStaffInvitationRepository()
+	ok, err := iu.HasPending(db, storeID, email, now)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, ErrStaffInvitationAlreadyExists
+	}
+
+	si := &models.StaffInvitation{
+		ID:           utils.NewUUID(),
+		StoreID:      storeID,
+		Email:        email,
+		PermissionID: permissionID,
+		RoleID:       roleID,
+		Status:       models.StaffInvitationPending,
+		InvitedBy:    actorID,
+		ExpiresAt:    now.Add(StaffInvitationTTL),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := iu.Create(db, si); err != nil {
+		return nil, err
+	}
+
+	if err := RecordStaffAudit(db, &models.StaffAuditLog{
+		StoreID:      storeID,
+		ActorID:      &actorID,
+		Action:       models.StaffAuditInvited,
+		TargetEmail:  email,
+		InvitationID: &si.ID,
+		PermissionID: &permissionID,
+		RoleID:       roleID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := RecordEvent(db, models.EventStaffInvited, si.ID, nil); err != nil {
+		return nil, err
+	}
+	return si, nil
+}
+
+// RevokeStaffInvitation withdraws a pending invitation of the store
+func RevokeStaffInvitation(db *gorm.DB, storeID, ID, actorID string) error {
+	iu := data.NewStaffInvitationRepository()
+	si, err := iu.Get(db, storeID, ID)
+	if err != nil {
+		return err
+	}
+
+	if err := iu.Respond(db, si.ID, models.StaffInvitationRevoked, time.Now().UTC()); err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return ErrStaffInvitationInvalid
+		}
+		return err
+	}
+
+	return RecordStaffAudit(db, &models.StaffAuditLog{
+		StoreID:      storeID,
+		ActorID:      &actorID,
+		Action:       models.StaffAuditInvitationRevoked,
+		TargetEmail:  si.Email,
+		InvitationID: &si.ID,
+	})
+}
+
+// NewStaffInvitationToken replaces the token of a pending invitation and returns it.
+// Only the hash is kept, so the token has to be sent right away.
+func NewStaffInvitationToken(db *gorm.DB, ID string) (string, error) {
+	token := utils.NewSecret(32)
+
+	iu := data.NewStaffInvitationRepository()
+	if err := iu.SetTokenHash(db, ID, hashToken(token), time.Now().UTC()); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetStaffInvitation returns the pending invitation of the token as shown to the recipient
+func GetStaffInvitation(db *gorm.DB, token string) (*models.StaffInvitationDetails, error) {
+	si, err := pendingStaffInvitation(db, token)
+	if err != nil {
+		return nil, err
+	}
+
+	iu := data.NewStaffInvitationRepository()
+	sid, err := iu.GetDetails(db, si.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	uu := data.NewUserRepository()
+	if _, err := uu.GetByEmail(db, si.Email); err == nil {
+		sid.IsRegistered = true
+	} else if !errors.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+	return sid, nil
+}
+
+// AcceptStaffInvitation adds the user to the staffs of the store with the permission of the invitation.
+// The invitation can only be accepted by the user of the invited email.
+func AcceptStaffInvitation(db *gorm.DB, token, userID string) (*models.StaffInvitation, error) {
+	si, err := pendingStaffInvitation(db.Set("gorm:query_option", "FOR UPDATE"), token)
+	if err != nil {
+		return nil, err
+	}
+
+	uu := data.NewUserRepository()
+	u, err := uu.Get(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(u.Email, si.Email) {
+		return nil, ErrStaffInvitationEmailMismatch
+	}
+
+	su := data.NewStoreRepository()
+	ok, err := su.IsAlreadyStaff(db, si.StoreID, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, ErrUserAlreadyStaff
+	}
+
+	if err := su.AddStoreStuff(db, &models.Staff{
+		UserID:       u.ID,
+		StoreID:      si.StoreID,
+		PermissionID: si.PermissionID,
+		RoleID:       si.RoleID,
+	}); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	iu := data.NewStaffInvitationRepository()
+	if err := iu.Respond(db, si.ID, models.StaffInvitationAccepted, now); err != nil {
+		return nil, err
+	}
+	si.Status = models.StaffInvitationAccepted
+	si.RespondedAt = &now
+
+	if err := RecordStaffAudit(db, &models.StaffAuditLog{
+		StoreID:      si.StoreID,
+		ActorID:      &u.ID,
+		Action:       models.StaffAuditInvitationAccepted,
+		TargetUserID: &u.ID,
+		TargetEmail:  si.Email,
+		InvitationID: &si.ID,
+		PermissionID: &si.PermissionID,
+		RoleID:       si.RoleID,
+	}); err != nil {
+		return nil, err
+	}
+	return si, nil
+}
+
+// DeclineStaffInvitation declines the invitation, holding the token is enough so the recipient doesn't need an account
+func DeclineStaffInvitation(db *gorm.DB, token string) error {
+	si, err := pendingStaffInvitation(db.Set("gorm:query_option", "FOR UPDATE"), token)
+	if err != nil {
+		return err
+	}
+
+	iu := data.NewStaffInvitationRepository()
+	if err := iu.Respond(db, si.ID, models.StaffInvitationDeclined, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	return RecordStaffAudit(db, &models.StaffAuditLog{
+		StoreID:      si.StoreID,
+		Action:       models.StaffAuditInvitationDeclined,
+		TargetEmail:  si.Email,
+		InvitationID: &si.ID,
+	})
+}
+
+// SignUpWithStaffInvitation registers the invited email and accepts the invitation. The email is taken as
+// verified since the token was delivered to it, and sign up doesn't need to be enabled for invited staffs.
+func SignUpWithStaffInvitation(db *gorm.DB, token string, u *models.User) (*models.StaffInvitation, error) {
+	si, err := pendingStaffInvitation(db, token)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(u.Email, si.Email) {
+		return nil, ErrStaffInvitationEmailMismatch
+	}
+
+	password, err := utils.GeneratePassword(u.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Password = password
+	u.Status = models.UserActive
+	u.IsEmailVerified = true
+
+	uu := data.NewUserRepository()
+	if err := uu.Register(db, u); err != nil {
+		return nil, err
+	}
+	return AcceptStaffInvitation(db, token, u.ID)
+}
+
+// RecordStaffAudit adds an entry to the staff audit trail of the store
+func RecordStaffAudit(db *gorm.DB, l *models.StaffAuditLog) error {
+	l.ID = utils.NewUUID()
+	l.CreatedAt = time.Now().UTC()
+
+	iu := data.NewStaffInvitationRepository()
+	return iu.CreateAuditLog(db, l)
+}
+
+func pendingStaffInvitation(db *gorm.DB, token string) (*models.StaffInvitation, error) {
+	iu := data.NewStaffInvitationRepository()
+	si, err := iu.GetByTokenHash(db, hashToken(token))
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return nil, ErrStaffInvitationInvalid
+		}
+		return nil, err
+	}
+	if si.Status != models.StaffInvitationPending || time.Now().After(si.ExpiresAt) {
+		return nil, ErrStaffInvitationInvalid
+	}
+	return si, nil
+}
+
+// ExpireStaffInvitations marks the pending invitations past their expiry expired
+func ExpireStaffInvitations() error {
+	si := data.NewStaffInvitationRepository()
+	return si.ExpirePending(app.DB(), time.Now().UTC())
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"strings"
+	"time"
+)
+
+// storeAPIKeyPrefixLength is how much of a key is kept in clear to tell the keys apart
+const storeAPIKeyPrefixLength = len(models.StoreAPIKeyPrefix) + 8
+
+var ErrStoreAPIKeyInvalid = errors.NewError("api key is invalid, expired or revoked")
+
+// IsStoreAPIKey tells whether the bearer token is a store api key rather than an access token
+func IsStoreAPIKey(token string) bool {
+	return strings.HasPrefix(token, models.StoreAPIKeyPrefix)
+}
+
+// CreateStoreAPIKey creates a key of the store and returns it along with the key itself, which can't be recovered later
+func CreateStoreAPIKey(db *gorm.DB, storeID, userID, name string, scopes []models.StorePerm, expiresAt *time.Time) (*models.StoreAPIKey, string, error) {
+	now := time.Now().UTC()
+	key := models.StoreAPIKeyPrefix + utils.NewSecret(24)
+
+	k := &models.StoreAPIKey{
+		ID:        utils.NewUUID(),
+		StoreID:   storeID,
+		Name:      name,
+		Prefix:    key[:storeAPIKeyPrefixLength],
+		KeyHash:   hashToken(key),
+		Scopes:    scopes,
+		CreatedBy: userID,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	ku := data.NewStoreAPIKeyRepository()
+	if err := ku.Create(db, k); err != nil {
+		return nil, "", err
+	}
+	return k, key, nil
+}
+
+// AuthenticateStoreAPIKey returns the active key matching the given one
+func AuthenticateStoreAPIKey(db *gorm.DB, key string) (*models.StoreAPIKey, error) {
+	ku := data.NewStoreAPIKeyRepository()
+	k, err := ku.GetByHash(db, hashToken(key))
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return nil, ErrStoreAPIKeyInvalid
+		}
+		return nil, err
+	}
+	if !k.IsActive(time.Now()) {
+		return nil, ErrStoreAPIKeyInvalid
+	}
+	return k, nil
+}
+
+// TouchStoreAPIKey records the use of the key, at most once per interval
+func TouchStoreAPIKey(db *gorm.DB, k *models.StoreAPIKey, ip string) error {
+	now := time.Now().UTC()
+	if k.LastUsedAt != nil && now.Sub(*k.LastUsedAt) < sessionTouchInterval && k.LastUsedIP == ip {
+		return nil
+	}
+
+	ku := data.NewStoreAPIKeyRepository()
+	return ku.Touch(db, k.ID, ip, now)
+}
+
+// StoreAPIKeyPermissions narrows the permissions of the staff who created the key down to its scopes,
+// so a key never grants more than its creator currently has
+func StoreAPIKeyPermissions(perms []models.StorePerm, k *models.StoreAPIKey) []models.StorePerm {
+	granted := []models.StorePerm{}
+	for _, p := range perms {
+		for _, s := range k.Scopes {
+			if p == s {
+				granted = append(granted, p)
+				break
+			}
+		}
+	}
+	return granted
+}
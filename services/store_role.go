@@ -0,0 +1,61 @@
+package services
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/values"
+)
+
+var (
+	ErrStaffSelfChange    = errors.NewError("Can't change your own permission or role")
+	ErrStaffGrantExceeded = errors.NewError("Can't grant a permission you don't hold")
+)
+
+// GetStaffPermissions resolves the permissions of a staff from its level and role, a role
+// only applies below the admin level
+func GetStaffPermissions(db *gorm.DB, level models.Permission, roleID *string) ([]models.StorePerm, error) {
+	if level == models.AdminPerm || roleID == nil {
+		return models.DefaultStorePerms(level), nil
+	}
+
+	sr := data.NewStoreRoleRepository()
+	return sr.ListPermissions(db, *roleID)
+}
+
+// StaffLevel returns the level of a staff permission id
+func StaffLevel(permissionID string) models.Permission {
+	if permissionID == values.AdminGroupID {
+		return models.AdminPerm
+	}
+	return models.ManagerPerm
+}
+
+// CheckStaffGrant refuses to give a staff a level or permissions the actor doesn't hold itself
+func CheckStaffGrant(db *gorm.DB, actorLevel models.Permission, actorPerms []models.StorePerm, level models.Permission, roleID *string) error {
+	if level == models.AdminPerm && actorLevel != models.AdminPerm {
+		return ErrStaffGrantExceeded
+	}
+
+	perms, err := GetStaffPermissions(db, level, roleID)
+	if err != nil {
+		return err
+	}
+	return CheckStorePermsHeld(actorPerms, perms)
+}
+
+// CheckStorePermsHeld refuses permissions missing from the ones the actor holds
+func CheckStorePermsHeld(actorPerms, perms []models.StorePerm) error {
+	held := map[models.StorePerm]bool{}
+	for _, p := range actorPerms {
+		held[p] = true
+	}
+
+	for _, p := range perms {
+		if !held[p] {
+			return ErrStaffGrantExceeded
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"strings"
+	"time"
+)
+
+const (
+	TwoFactorRecoveryCodeCount    = 10
+	TwoFactorChallengeTTL         = time.Minute * 5
+	TwoFactorChallengeMaxAttempts = 5
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled   = errors.NewError("Two factor authentication is already enabled")
+	ErrTwoFactorNotEnabled       = errors.NewError("Two factor authentication isn't enabled")
+	ErrTwoFactorCodeInvalid      = errors.NewError("Invalid two factor code")
+	ErrTwoFactorChallengeInvalid = errors.NewError("Invalid or expired two factor challenge")
+)
+
+func IsTwoFactorEnabled(db *gorm.DB, userID string) (bool, error) {
+	tu := data.NewTwoFactorRepository()
+	tf, err := tu.Get(db, userID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return tf.IsEnabled, nil
+}
+
+// IsTwoFactorRequired tells whether the platform requires two factor authentication of the user, which
+// applies to platform admins and managers, to store admins and to staff granted a sensitive permission
+// once enabled in the settings
+func IsTwoFactorRequired(db *gorm.DB, userID string, permission models.Permission) (bool, error) {
+	pu := data.NewMarketplaceRepository()
+	settings, err := pu.GetSettings(db)
+	if err != nil {
+		return false, err
+	}
+	if !settings.IsTwoFactorRequired {
+		return false, nil
+	}
+
+	if permission == models.AdminPerm || permission == models.ManagerPerm {
+		return true, nil
+	}
+
+	su := data.NewStoreRepository()
+	profiles, err := su.ListStoreUserProfiles(db, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range profiles {
+		if p.StaffPermission == models.AdminPerm {
+			return true, nil
+		}
+
+		perms, err := GetStaffPermissions(db, p.StaffPermission, p.StaffRoleID)
+		if err != nil {
+			return false, err
+		}
+		for _, perm := range perms {
+			if perm.RequiresTwoFactor() {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// EnrollTwoFactor generates a new secret for the user, it takes effect once confirmed with a code.
+// The provisioning URI is returned along with the enrollment.
+func EnrollTwoFactor(db *gorm.DB, u *models.User) (*models.TwoFactor, string, error) {
+	tu := data.NewTwoFactorRepository()
+
+	now := time.Now().UTC()
+
+	tf, err := tu.Get(db, u.ID)
+	if err != nil {
+		if !errors.IsRecordNotFoundError(err) {
+			return nil, "", err
+		}
+		tf = &models.TwoFactor{
+			UserID:    u.ID,
+			CreatedAt: now,
+		}
+	}
+	if tf.IsEnabled {
+		return nil, "", ErrTwoFactorAlreadyEnabled
+	}
+
+	tf.Secret = utils.NewTOTPSecret()
+	tf.LastUsedStep = 0
+	tf.UpdatedAt = now
+
+	if err := tu.Save(db, tf); err != nil {
+		return nil, "", err
+	}
+
+	pu := data.NewMarketplaceRepository()
+	settings, err := pu.GetSettings(db)
+	if err != nil {
+		return nil, "", err
+	}
+	return tf, utils.TOTPProvisioningURI(settings.Name, u.Email, tf.Secret), nil
+}
+
+// ConfirmTwoFactor enables the enrollment after verifying a code of it and returns the recovery codes
+func ConfirmTwoFactor(db *gorm.DB, userID, code string) ([]string, error) {
+	tu := data.NewTwoFactorRepository()
+	tf, err := tu.Get(db, userID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return nil, ErrTwoFactorNotEnabled
+		}
+		return nil, err
+	}
+	if tf.IsEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	now := time.Now().UTC()
+
+	step, ok := utils.ValidateTOTP(tf.Secret, code, now)
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	tf.IsEnabled = true
+	tf.LastUsedStep = step
+	tf.EnabledAt = &now
+	tf.UpdatedAt = now
+
+	if err := tu.Save(db, tf); err != nil {
+		return nil, err
+	}
+	return RegenerateRecoveryCodes(db, userID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user, the plain codes are only ever returned here
+func RegenerateRecoveryCodes(db *gorm.DB, userID string) ([]string, error) {
+	now := time.Now().UTC()
+
+	var codes []string
+	var rcs []models.TwoFactorRecoveryCode
+	for i := 0; i < TwoFactorRecoveryCodeCount; i++ {
+		s := utils.NewSecret(5)
+		code := fmt.Sprintf("%s-%s", s[:5], s[5:])
+
+		codes = append(codes, code)
+		rcs = append(rcs, models.TwoFactorRecoveryCode{
+			ID:        utils.NewUUID(),
+			UserID:    userID,
+			CodeHash:  hashToken(code),
+			CreatedAt: now,
+		})
+	}
+
+	tu := data.NewTwoFactorRepository()
+	if err := tu.ReplaceRecoveryCodes(db, userID, rcs); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyTwoFactor accepts a TOTP code not used before or, failing that, an unused recovery code of the user
+func VerifyTwoFactor(db *gorm.DB, userID, code string) error {
+	tu := data.NewTwoFactorRepository()
+	tf, err := tu.Get(db.Set("gorm:query_option", "FOR UPDATE"), userID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if !tf.IsEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	now := time.Now().UTC()
+
+	if step, ok := acceptTOTP(tf.Secret, code, tf.LastUsedStep, now); ok {
+		tf.LastUsedStep = step
+		tf.UpdatedAt = now
+		return tu.Save(db, tf)
+	}
+
+	code = strings.ToLower(strings.TrimSpace(code))
+	if err := tu.UseRecoveryCode(db, userID, hashToken(code), now); err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return ErrTwoFactorCodeInvalid
+		}
+		return err
+	}
+	return nil
+}
+
+// acceptTOTP validates the code and refuses it when its step isn't later than the last one used, so a code
+// can't be replayed within the skew
+func acceptTOTP(secret, code string, lastUsedStep int64, now time.Time) (int64, bool) {
+	step, ok := utils.ValidateTOTP(secret, code, now)
+	if !ok || step <= lastUsedStep {
+		return 0, false
+	}
+	return step, true
+}
+
+// StartTwoFactorChallenge holds a login back until the second factor is verified, returning the challenge token
+func StartTwoFactorChallenge(db *gorm.DB, userID string, scope utils.UserScope) (string, time.Time, error) {
+	now := time.Now().UTC()
+	token := utils.NewSecret(32)
+
+	c := &models.TwoFactorChallenge{
+		ID:        utils.NewUUID(),
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		Scope:     string(scope),
+		ExpiresAt: now.Add(TwoFactorChallengeTTL),
+		CreatedAt: now,
+	}
+
+	tu := data.NewTwoFactorRepository()
+	if err := tu.CreateChallenge(db, c); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, c.ExpiresAt, nil
+}
+
+// GetTwoFactorChallenge returns the challenge the token was issued for, without consuming it
+func GetTwoFactorChallenge(db *gorm.DB, token string) (*models.TwoFactorChallenge, error) {
+	tu := data.NewTwoFactorRepository()
+	c, err := tu.GetChallenge(db, hashToken(token))
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			return nil, ErrTwoFactorChallengeInvalid
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// CompleteTwoFactorChallenge verifies the code of the challenge and consumes it. Failed attempts are
+// counted and the challenge is dropped after too many of them, so the login has to start over.
+func CompleteTwoFactorChallenge(token, code string) (*models.TwoFactorChallenge, error) {
+	db := app.DB().Begin()
+
+	tu := data.NewTwoFactorRepository()
+	c, err := tu.GetChallenge(db.Set("gorm:query_option", "FOR UPDATE"), hashToken(token))
+	if err != nil {
+		db.Rollback()
+		if errors.IsRecordNotFoundError(err) {
+			return nil, ErrTwoFactorChallengeInvalid
+		}
+		return nil, err
+	}
+
+	if time.Now().After(c.ExpiresAt) || c.Attempts >= TwoFactorChallengeMaxAttempts {
+		if err := tu.DeleteChallenge(db, c.ID); err != nil {
+			db.Rollback()
+			return nil, err
+		}
+		if err := db.Commit().Error; err != nil {
+			return nil, err
+		}
+		return nil, ErrTwoFactorChallengeInvalid
+	}
+
+	if err := VerifyTwoFactor(db, c.UserID, code); err != nil {
+		if err != ErrTwoFactorCodeInvalid {
+			db.Rollback()
+			return nil, err
+		}
+		if err := tu.IncreaseChallengeAttempts(db, c.ID); err != nil {
+			db.Rollback()
+			return nil, err
+		}
+		if err := db.Commit().Error; err != nil {
+			return nil, err
+		}
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	if err := tu.DeleteChallenge(db, c.ID); err != nil {
+		db.Rollback()
+		return nil, err
+	}
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CleanupTwoFactorChallenges removes the expired two factor login challenges
+func CleanupTwoFactorChallenges() error {
+	tu := data.NewTwoFactorRepository()
+	return tu.DeleteExpiredChallenges(app.DB(), time.Now().UTC())
+}
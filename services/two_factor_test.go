@@ -0,0 +1,47 @@
+package services
+
+import (
+	"github.com/shopicano/shopicano-backend/utils"
+	"testing"
+	"time"
+)
+
+func TestAcceptTOTPRejectsReusedSteps(t *testing.T) {
+	secret := utils.NewTOTPSecret()
+	now := time.Now()
+	step := now.Unix() / utils.TOTPPeriod
+
+	codeAt := func(s int64) string {
+		c, err := utils.TOTPCode(secret, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	cases := []struct {
+		name         string
+		code         string
+		lastUsedStep int64
+		ok           bool
+	}{
+		{name: "never used", code: codeAt(step), ok: true},
+		{name: "later than the last used", code: codeAt(step), lastUsedStep: step - 1, ok: true},
+		{name: "same step reused", code: codeAt(step), lastUsedStep: step},
+		{name: "earlier step within skew", code: codeAt(step - 1), lastUsedStep: step},
+		{name: "next step after the current one was used", code: codeAt(step + 1), lastUsedStep: step, ok: true},
+		{name: "invalid code", code: "000000x"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := acceptTOTP(secret, c.code, c.lastUsedStep, now)
+			if ok != c.ok {
+				t.Fatalf("got %v, want %v", ok, c.ok)
+			}
+			if ok && got <= c.lastUsedStep {
+				t.Errorf("got step %d, not later than %d", got, c.lastUsedStep)
+			}
+		})
+	}
+}
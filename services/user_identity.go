@@ -0,0 +1,237 @@
+package services
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/values"
+	"strings"
+	"time"
+)
+
+const OIDCStateTTL = time.Minute * 10
+
+var (
+	ErrOIDCStateInvalid          = errors.NewError("invalid or expired oidc state")
+	ErrOIDCEmailNotVerified      = errors.NewError("email isn't verified by the provider")
+	ErrOIDCSignUpDisabled        = errors.NewError("sign up is disabled")
+	ErrUserIdentityAlreadyLinked = errors.NewError("identity is linked to another user")
+)
+
+// StartOIDCAuthorization records the state, nonce and PKCE verifier of a new authorization and returns
+// the url of the provider to send the user to. The identity is linked to userID if one is given.
+func StartOIDCAuthorization(db *gorm.DB, p *OIDCProvider, scope utils.UserScope, userID *string) (string, time.Time, error) {
+	now := time.Now().UTC()
+	state := utils.NewSecret(32)
+
+	s := &models.OIDCState{
+		ID:           utils.NewUUID(),
+		StateHash:    hashToken(state),
+		Provider:     p.Name(),
+		Nonce:        utils.NewSecret(16),
+		CodeVerifier: utils.NewSecret(32),
+		Scope:        string(scope),
+		UserID:       userID,
+		ExpiresAt:    now.Add(OIDCStateTTL),
+		CreatedAt:    now,
+	}
+
+	uri, err := p.AuthorizationURL(state, s.Nonce, s.CodeVerifier)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	iu := data.NewUserIdentityRepository()
+	if err := iu.CreateState(db, s); err != nil {
+		return "", time.Time{}, err
+	}
+	return uri, s.ExpiresAt, nil
+}
+
+// CompleteOIDCAuthorization consumes the state of the callback and redeems the code with the provider.
+// The state is dropped before the exchange so a callback can't be replayed.
+func CompleteOIDCAuthorization(p *OIDCProvider, state, code string) (*models.OIDCState, *OIDCIdentity, error) {
+	db := app.DB().Begin()
+
+	iu := data.NewUserIdentityRepository()
+	s, err := iu.GetState(db.Set("gorm:query_option", "FOR UPDATE"), hashToken(state))
+	if err != nil {
+		db.Rollback()
+		if errors.IsRecordNotFoundError(err) {
+			return nil, nil, ErrOIDCStateInvalid
+		}
+		return nil, nil, err
+	}
+
+	if err := iu.DeleteState(db, s.ID); err != nil {
+		db.Rollback()
+		return nil, nil, err
+	}
+	if err := db.Commit().Error; err != nil {
+		return nil, nil, err
+	}
+
+	if s.Provider != p.Name() || time.Now().After(s.ExpiresAt) {
+		return nil, nil, ErrOIDCStateInvalid
+	}
+
+	id, err := p.Exchange(code, s.CodeVerifier, s.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, id, nil
+}
+
+// SignInWithOIDC returns the user of the identity. An unknown identity is linked to the user with the same email
+// when the provider has verified it, otherwise a new user is signed up.
+func SignInWithOIDC(db *gorm.DB, provider string, id *OIDCIdentity) (*models.User, error) {
+	now := time.Now().UTC()
+
+	iu := data.NewUserIdentityRepository()
+	uc := data.NewUserRepository()
+
+	ui, err := iu.GetBySubject(db, provider, id.Subject)
+	if err == nil {
+		if err := iu.Touch(db, ui.ID, id.Email, id.IsEmailVerified, now); err != nil {
+			return nil, err
+		}
+		return uc.Get(db, ui.UserID)
+	}
+	if !errors.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+
+	if id.Email == "" || !id.IsEmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	u, err := uc.GetByEmail(db, id.Email)
+	if err != nil {
+		if !errors.IsRecordNotFoundError(err) {
+			return nil, err
+		}
+
+		ok, err := uc.IsSignUpEnabled(db)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrOIDCSignUpDisabled
+		}
+
+		u, err = newOIDCUser(id, now)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.Register(db, u); err != nil {
+			return nil, err
+		}
+	} else if !u.IsEmailVerified {
+		// Whoever registered the unverified account may not own the email, the provider has just proven
+		// who does so the password set by the other party is dropped
+		password, err := utils.GeneratePassword(utils.NewSecret(32))
+		if err != nil {
+			return nil, err
+		}
+
+		u.Password = password
+		u.VerificationToken = nil
+		u.IsEmailVerified = true
+		if u.Status == models.UserRegistered {
+			u.Status = models.UserActive
+		}
+		u.UpdatedAt = now
+		if err := uc.Update(db, u); err != nil {
+			return nil, err
+		}
+
+		su := data.NewSessionRepository()
+		if err := su.RevokeAll(db, u.ID, "", models.SessionRevokedPasswordChange, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := createUserIdentity(db, u.ID, provider, id, now); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// LinkOIDCIdentity links the identity to the user unless it's already linked to someone else
+func LinkOIDCIdentity(db *gorm.DB, userID, provider string, id *OIDCIdentity) (*models.UserIdentity, error) {
+	now := time.Now().UTC()
+
+	iu := data.NewUserIdentityRepository()
+	ui, err := iu.GetBySubject(db, provider, id.Subject)
+	if err == nil {
+		if ui.UserID != userID {
+			return nil, ErrUserIdentityAlreadyLinked
+		}
+		if err := iu.Touch(db, ui.ID, id.Email, id.IsEmailVerified, now); err != nil {
+			return nil, err
+		}
+		return iu.GetBySubject(db, provider, id.Subject)
+	}
+	if !errors.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+	return createUserIdentity(db, userID, provider, id, now)
+}
+
+func createUserIdentity(db *gorm.DB, userID, provider string, id *OIDCIdentity, now time.Time) (*models.UserIdentity, error) {
+	ui := &models.UserIdentity{
+		ID:              utils.NewUUID(),
+		UserID:          userID,
+		Provider:        provider,
+		Subject:         id.Subject,
+		Email:           id.Email,
+		IsEmailVerified: id.IsEmailVerified,
+		LastLoginAt:     &now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	iu := data.NewUserIdentityRepository()
+	if err := iu.Create(db, ui); err != nil {
+		return nil, err
+	}
+	return ui, nil
+}
+
+func newOIDCUser(id *OIDCIdentity, now time.Time) (*models.User, error) {
+	password, err := utils.GeneratePassword(utils.NewSecret(32))
+	if err != nil {
+		return nil, err
+	}
+
+	name := id.Name
+	if name == "" {
+		name = strings.Split(id.Email, "@")[0]
+	}
+
+	u := &models.User{
+		ID:              utils.NewUUID(),
+		Name:            name,
+		Email:           id.Email,
+		Password:        password,
+		Status:          models.UserActive,
+		IsEmailVerified: true,
+		PermissionID:    values.UserGroupID,
+		Locale:          models.DefaultLocale,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if id.Picture != "" {
+		u.ProfilePicture = &id.Picture
+	}
+	return u, nil
+}
+
+// CleanupOIDCStates removes the expired OIDC authorization states
+func CleanupOIDCStates() error {
+	iu := data.NewUserIdentityRepository()
+	return iu.DeleteExpiredStates(app.DB(), time.Now().UTC())
+}
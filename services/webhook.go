@@ -0,0 +1,325 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	WebhookMaxAttempts    = 10
+	webhookRetryBaseDelay = time.Second * 30
+	webhookRetryMaxDelay  = time.Hour * 6
+	webhookTimeout        = time.Second * 10
+)
+
+// webhookClient only connects to public addresses. The check is made on the address actually dialed, after
+// resolution, so a host rebinding to an internal address is refused too. Redirects aren't followed and the
+// proxy of the environment isn't used, the endpoint has to answer by itself.
+var webhookClient = &http.Client{
+	Timeout: webhookTimeout,
+	Transport: &http.Transport{
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: webhookTimeout,
+			Control: func(network, address string, c syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || !utils.IsPublicIP(ip) {
+					return utils.ErrAddressNotAllowed
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   webhookTimeout,
+		ResponseHeaderTimeout: webhookTimeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       time.Minute,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+type webhookEnvelope struct {
+	ID        string              `json:"id"`
+	Event     models.WebhookEvent `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      interface{}         `json:"data"`
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 of the timestamp and the body joined by a dot
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRetryDelay grows exponentially with the attempts made, up to six hours
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts && delay < webhookRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookRetryMaxDelay {
+		delay = webhookRetryMaxDelay
+	}
+	return delay
+}
+
+// PublishWebhookEvent records a delivery of the event for every webhook subscribed to it and makes the first attempts.
+// The payload is the current state of the referenced order, product or payout entry.
+func PublishWebhookEvent(event models.WebhookEvent, referenceID string) error {
+	db := app.DB()
+
+	storeID, payload, err := buildWebhookPayload(db, event, referenceID)
+	if err != nil {
+		return err
+	}
+
+	wu := data.NewWebhookRepository()
+	webhooks, err := wu.ListSubscribed(db, &storeID, event)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []string
+
+	tx := db.Begin()
+	for _, w := range webhooks {
+		wd, err := newWebhookDelivery(&w, event, payload)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := wu.CreateDelivery(tx, wd); err != nil {
+			tx.Rollback()
+			return err
+		}
+		deliveries = append(deliveries, wd.ID)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	for _, id := range deliveries {
+		if err := DeliverWebhook(id); err != nil {
+			log.Log().Errorln("Failed to deliver webhook", id, ":", err)
+		}
+	}
+	return nil
+}
+
+// PingWebhook records a ping delivery to the webhook, to be delivered by DeliverWebhook
+func PingWebhook(db *gorm.DB, w *models.Webhook) (*models.WebhookDelivery, error) {
+	wd, err := newWebhookDelivery(w, models.WebhookPing, map[string]interface{}{
+		"webhook_id": w.ID,
+		"url":        w.URL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wu := data.NewWebhookRepository()
+	if err := wu.CreateDelivery(db, wd); err != nil {
+		return nil, err
+	}
+	return wd, nil
+}
+
+// ResetWebhookDelivery makes a delivery due again with a fresh set of attempts, to be delivered by DeliverWebhook
+func ResetWebhookDelivery(db *gorm.DB, wd *models.WebhookDelivery) error {
+	now := time.Now().UTC()
+
+	wd.Status = models.WebhookDeliveryPending
+	wd.Attempts = 0
+	wd.NextAttemptAt = &now
+	wd.UpdatedAt = now
+
+	wu := data.NewWebhookRepository()
+	return wu.UpdateDelivery(db, wd)
+}
+
+// DeliverWebhook attempts the delivery if it's still due, the ones locked by another worker are skipped
+func DeliverWebhook(deliveryID string) error {
+	db := app.DB().Begin()
+
+	wu := data.NewWebhookRepository()
+	wd, err := wu.GetDelivery(db.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED"), deliveryID)
+	if err != nil {
+		db.Rollback()
+		if errors.IsRecordNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+	if wd.Status != models.WebhookDeliveryPending || wd.NextAttemptAt == nil || wd.NextAttemptAt.After(now) {
+		db.Rollback()
+		return nil
+	}
+
+	if err := deliverWebhook(db, wd); err != nil {
+		db.Rollback()
+		return err
+	}
+	return db.Commit().Error
+}
+
+// DeliverDueWebhooks retries the pending deliveries whose backoff has elapsed. Each one is attempted by
+// DeliverWebhook in its own transaction, so an outcome is kept even if a later attempt fails to be recorded.
+func DeliverDueWebhooks(limit int) error {
+	wu := data.NewWebhookRepository()
+	deliveries, err := wu.ListDueDeliveries(app.DB(), time.Now().UTC(), limit)
+	if err != nil {
+		return err
+	}
+
+	for _, wd := range deliveries {
+		if err := DeliverWebhook(wd.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newWebhookDelivery(w *models.Webhook, event models.WebhookEvent, payload interface{}) (*models.WebhookDelivery, error) {
+	now := time.Now().UTC()
+
+	wd := &models.WebhookDelivery{
+		ID:            utils.NewUUID(),
+		WebhookID:     w.ID,
+		Event:         event,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	body, err := json.Marshal(webhookEnvelope{
+		ID:        wd.ID,
+		Event:     event,
+		CreatedAt: now,
+		Data:      payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	wd.Payload = string(body)
+	return wd, nil
+}
+
+// deliverWebhook posts the signed payload and records the outcome of the attempt,
+// only failing to record it is returned
+func deliverWebhook(db *gorm.DB, wd *models.WebhookDelivery) error {
+	wu := data.NewWebhookRepository()
+	w, err := wu.GetByID(db, wd.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	status, sendErr := postWebhook(w, wd)
+	now := time.Now().UTC()
+
+	wd.Attempts++
+	wd.ResponseStatus = status
+	wd.Duration = now.Sub(start).Milliseconds()
+	wd.UpdatedAt = now
+
+	switch {
+	case sendErr == nil:
+		wd.Status = models.WebhookDeliverySucceeded
+		wd.Error = ""
+		wd.NextAttemptAt = nil
+		wd.DeliveredAt = &now
+	case wd.Attempts >= WebhookMaxAttempts || !w.IsActive:
+		wd.Status = models.WebhookDeliveryFailed
+		wd.Error = sendErr.Error()
+		wd.NextAttemptAt = nil
+	default:
+		next := now.Add(WebhookRetryDelay(wd.Attempts))
+		wd.Status = models.WebhookDeliveryPending
+		wd.Error = sendErr.Error()
+		wd.NextAttemptAt = &next
+	}
+
+	return wu.UpdateDelivery(db, wd)
+}
+
+// postWebhook sends the signed payload and returns the status of the response, its body is discarded
+// so the endpoint can't be used to read from the network of the platform
+func postWebhook(w *models.Webhook, wd *models.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	body := []byte(wd.Payload)
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Shopicano-Webhook")
+	req.Header.Set("X-Shopicano-Event", string(wd.Event))
+	req.Header.Set("X-Shopicano-Delivery", wd.ID)
+	req.Header.Set("X-Shopicano-Timestamp", timestamp)
+	req.Header.Set("X-Shopicano-Signature", fmt.Sprintf("sha256=%s", SignWebhookPayload(w.Secret, timestamp, body)))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func buildWebhookPayload(db *gorm.DB, event models.WebhookEvent, referenceID string) (string, interface{}, error) {
+	switch event {
+	case models.WebhookOrderCreated, models.WebhookOrderStatusChanged,
+		models.WebhookPaymentCompleted, models.WebhookPaymentReverted:
+		ou := data.NewOrderRepository()
+		o, err := ou.GetDetails(db, referenceID)
+		if err != nil {
+			return "", nil, err
+		}
+		return o.StoreID, o, nil
+	case models.WebhookProductUpdated:
+		pu := data.NewProductRepository()
+		p, err := pu.Get(db, referenceID)
+		if err != nil {
+			return "", nil, err
+		}
+		return p.StoreID, p, nil
+	case models.WebhookPayoutUpdated:
+		mu := data.NewMarketplaceRepository()
+		ps, err := mu.GetPayoutEntryByID(db, referenceID)
+		if err != nil {
+			return "", nil, err
+		}
+		return ps.StoreID, ps, nil
+	}
+	return "", nil, fmt.Errorf("unknown webhook event %s", event)
+}
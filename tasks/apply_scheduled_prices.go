@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"time"
+)
+
+const (
+	ApplyScheduledPricesTaskName = "apply_scheduled_prices"
+)
+
+func ApplyScheduledPricesFn() error {
+	db := app.DB().Begin()
+
+	now := time.Now().UTC()
+
+	priceDao := data.NewPriceRepository()
+	sps, err := priceDao.ListDueScheduledPrices(db, now, 100)
+	if err != nil {
+		db.Rollback()
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	productDao := data.NewProductRepository()
+	for _, sp := range sps {
+		p, err := productDao.Get(db, sp.ProductID)
+		if err != nil {
+			db.Rollback()
+			log.Log().Errorln(err)
+			return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+		}
+
+		if err := productDao.UpdatePrice(db, p.ID, sp.Price, now); err != nil {
+			db.Rollback()
+			log.Log().Errorln(err)
+			return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+		}
+
+		ph := &models.PriceHistory{
+			ID:        utils.NewUUID(),
+			ProductID: p.ID,
+			OldPrice:  p.Price,
+			NewPrice:  sp.Price,
+			Source:    models.PriceChangeScheduled,
+			ChangedBy: &sp.CreatedBy,
+			CreatedAt: now,
+		}
+		if err := priceDao.CreateHistory(db, ph); err != nil {
+			db.Rollback()
+			log.Log().Errorln(err)
+			return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+		}
+
+		if err := priceDao.MarkScheduledPriceApplied(db, sp.ID, now); err != nil {
+			db.Rollback()
+			log.Log().Errorln(err)
+			return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+		}
+
+		if err := services.RecordEvent(db, models.EventProductUpdated, p.ID, nil); err != nil {
+			db.Rollback()
+			log.Log().Errorln(err)
+			return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+		}
+	}
+
+	if err := db.Commit().Error; err != nil {
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	return nil
+}
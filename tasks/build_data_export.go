@@ -0,0 +1,20 @@
+package tasks
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/services"
+	"time"
+)
+
+const (
+	BuildDataExportTaskName = "build_data_export"
+)
+
+func BuildDataExportFn(exportID string) error {
+	if err := services.BuildDataExport(exportID); err != nil {
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Minute)
+	}
+	return nil
+}
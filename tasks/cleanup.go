@@ -0,0 +1,43 @@
+package tasks
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/services"
+	"time"
+)
+
+const (
+	CleanupTaskName = "cleanup"
+)
+
+type cleanup struct {
+	name string
+	run  func() error
+}
+
+// cleanups are the housekeeping jobs of the features, each one runs even when another fails
+var cleanups = []cleanup{
+	{name: "sessions", run: services.CleanupSessions},
+	{name: "two factor challenges", run: services.CleanupTwoFactorChallenges},
+	{name: "oidc states", run: services.CleanupOIDCStates},
+	{name: "staff invitations", run: services.ExpireStaffInvitations},
+	{name: "limiter counters", run: services.CleanupLimiterCounters},
+	{name: "unlock tokens", run: services.CleanupUnlockTokens},
+	{name: "data exports", run: services.ExpireDataExports},
+}
+
+func CleanupFn() error {
+	var failed error
+	for _, c := range cleanups {
+		if err := c.run(); err != nil {
+			log.Log().Errorln("Cleanup of", c.name, "failed:", err)
+			failed = err
+		}
+	}
+
+	if failed != nil {
+		return tasks.NewErrRetryTaskLater(failed.Error(), time.Minute*5)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package tasks
+
+import (
+	"encoding/json"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+)
+
+const (
+	HandleDomainEventTaskName = "handle_domain_event"
+)
+
+type domainEventSubscriber struct {
+	name   string
+	events []models.DomainEventType
+	handle func(e *models.DomainEvent) error
+}
+
+var domainEventSubscribers = []domainEventSubscriber{
+	{
+		name: "email",
+		events: []models.DomainEventType{models.EventOrderCreated, models.EventOrderStatusChanged,
+			models.EventOrderPaymentStatusChanged, models.EventPaymentCompleted, models.EventPaymentReverted,
+			models.EventStaffInvited, models.EventAccountDeletionRequested},
+		handle: sendDomainEventEmail,
+	},
+	{
+		name: "notification",
+		events: []models.DomainEventType{models.EventOrderCreated, models.EventOrderStatusChanged,
+			models.EventOrderPaymentStatusChanged, models.EventPaymentCompleted, models.EventPayoutUpdated},
+		handle: sendDomainEventNotification,
+	},
+	{
+		name: "webhook",
+		events: []models.DomainEventType{models.EventOrderCreated, models.EventOrderStatusChanged,
+			models.EventOrderPaymentStatusChanged, models.EventPaymentCompleted, models.EventPaymentReverted,
+			models.EventProductUpdated, models.EventPayoutUpdated},
+		handle: publishDomainEventWebhook,
+	},
+	{
+		name:   "stats",
+		events: []models.DomainEventType{models.EventOrderCreated},
+		handle: refreshDomainEventStats,
+	},
+	{
+		name: "search_index",
+		events: []models.DomainEventType{models.EventProductCreated, models.EventProductUpdated,
+			models.EventCategoryRenamed, models.EventCollectionProductsChanged},
+		handle: refreshDomainEventSearchIndex,
+	},
+	{
+		name:   "data_export",
+		events: []models.DomainEventType{models.EventDataExportRequested},
+		handle: buildDomainEventDataExport,
+	},
+}
+
+// DomainEventSubscribers returns the subscribers of the event type, each one handles the event in a task of its own
+func DomainEventSubscribers(t models.DomainEventType) []string {
+	var names []string
+	for _, s := range domainEventSubscribers {
+		for _, e := range s.events {
+			if e == t {
+				names = append(names, s.name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+const (
+	// DomainEventRetries is the number of retries of a failing subscriber, spaced by a growing delay
+	// starting from DomainEventRetryTimeout seconds
+	DomainEventRetries      = 8
+	DomainEventRetryTimeout = 30
+)
+
+// HandleDomainEventFn runs the subscriber on the event. Failures are retried by the RetryCount of the
+// task signature, the ones a retry can't fix, a deleted record or a broken payload, are dropped.
+func HandleDomainEventFn(eventID, subscriber string) error {
+	deu := data.NewDomainEventRepository()
+	e, err := deu.Get(app.DB(), eventID)
+	if err != nil {
+		if errors.IsRecordNotFoundError(err) {
+			log.Log().Errorln("Dropped domain event", eventID, "for", subscriber, ":", err)
+			return nil
+		}
+		log.Log().Errorln(err)
+		return errors.NewError(err.Error())
+	}
+
+	for _, s := range domainEventSubscribers {
+		if s.name != subscriber {
+			continue
+		}
+
+		if err := s.handle(e); err != nil {
+			if isPermanentDomainEventError(err) {
+				log.Log().Errorln("Subscriber", subscriber, "dropped", e.Type, e.ID, ":", err)
+				return nil
+			}
+			log.Log().Errorln("Subscriber", subscriber, "failed to handle", e.Type, e.ID, ":", err)
+			// ErrRetryTaskLater of the task functions would be retried forever
+			return errors.NewError(err.Error())
+		}
+		return nil
+	}
+	log.Log().Errorln("Unknown domain event subscriber", subscriber)
+	return nil
+}
+
+func isPermanentDomainEventError(err error) bool {
+	switch err.(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return true
+	}
+	return errors.IsRecordNotFoundError(err)
+}
+
+func statusChangeOf(e *models.DomainEvent) (*models.StatusChange, error) {
+	sc := models.StatusChange{}
+	if err := json.Unmarshal([]byte(e.Payload), &sc); err != nil {
+		return nil, err
+	}
+	return &sc, nil
+}
+
+func sendDomainEventEmail(e *models.DomainEvent) error {
+	switch e.Type {
+	case models.EventOrderCreated:
+		return SendOrderDetailsEmailFn(e.AggregateID, "Thanks for your purchase")
+	case models.EventOrderStatusChanged:
+		return SendOrderDetailsEmailFn(e.AggregateID, "Order status updated")
+	case models.EventOrderPaymentStatusChanged:
+		return SendOrderDetailsEmailFn(e.AggregateID, "Order payment status updated")
+	case models.EventPaymentCompleted:
+		return SendPaymentConfirmationEmailFn(e.AggregateID)
+	case models.EventPaymentReverted:
+		return SendPaymentRevertedEmailFn(e.AggregateID)
+	case models.EventStaffInvited:
+		return SendStaffInvitationEmailFn(e.AggregateID)
+	case models.EventAccountDeletionRequested:
+		return SendAccountDeletionEmailFn(e.AggregateID)
+	}
+	return nil
+}
+
+func sendDomainEventNotification(e *models.DomainEvent) error {
+	if e.Type == models.EventPaymentCompleted {
+		return services.SendNotification(models.NotificationPaymentReceived, e.AggregateID)
+	}
+
+	sc, err := statusChangeOf(e)
+	if err != nil {
+		return err
+	}
+
+	switch e.Type {
+	case models.EventOrderCreated, models.EventOrderStatusChanged:
+		if event, ok := models.NotificationEventOfOrderStatus(models.OrderStatus(sc.To)); ok {
+			return services.SendNotification(event, e.AggregateID)
+		}
+	case models.EventOrderPaymentStatusChanged:
+		if sc.To == string(models.PaymentCompleted) {
+			return services.SendNotification(models.NotificationPaymentReceived, e.AggregateID)
+		}
+	case models.EventPayoutUpdated:
+		if sc.From != sc.To && sc.To == string(models.PayoutSendStatusCompleted) {
+			return services.SendNotification(models.NotificationPayoutCompleted, e.AggregateID)
+		}
+	}
+	return nil
+}
+
+func publishDomainEventWebhook(e *models.DomainEvent) error {
+	var event models.WebhookEvent
+
+	switch e.Type {
+	case models.EventOrderCreated:
+		event = models.WebhookOrderCreated
+	case models.EventOrderStatusChanged:
+		event = models.WebhookOrderStatusChanged
+	case models.EventPaymentCompleted:
+		event = models.WebhookPaymentCompleted
+	case models.EventPaymentReverted:
+		event = models.WebhookPaymentReverted
+	case models.EventProductUpdated:
+		event = models.WebhookProductUpdated
+	case models.EventPayoutUpdated:
+		event = models.WebhookPayoutUpdated
+	case models.EventOrderPaymentStatusChanged:
+		sc, err := statusChangeOf(e)
+		if err != nil {
+			return err
+		}
+		switch models.PaymentStatus(sc.To) {
+		case models.PaymentCompleted:
+			event = models.WebhookPaymentCompleted
+		case models.PaymentReverted:
+			event = models.WebhookPaymentReverted
+		default:
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return services.PublishWebhookEvent(event, e.AggregateID)
+}
+
+// refreshDomainEventStats recounts the sold quantity of the products of a new order
+func refreshDomainEventStats(e *models.DomainEvent) error {
+	db := app.DB()
+	pu := data.NewProductRepository()
+
+	ids, err := pu.ListIDsByOrder(db, e.AggregateID)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return pu.RefreshSoldCount(db, ids)
+}
+
+// refreshDomainEventSearchIndex rebuilds the search text of the products the event touched
+func refreshDomainEventSearchIndex(e *models.DomainEvent) error {
+	db := app.DB()
+	pu := data.NewProductRepository()
+
+	var ids []string
+
+	switch e.Type {
+	case models.EventProductCreated, models.EventProductUpdated:
+		ids = []string{e.AggregateID}
+	case models.EventCategoryRenamed:
+		var err error
+		ids, err = pu.ListIDsByCategory(db, e.AggregateID)
+		if err != nil {
+			return err
+		}
+	case models.EventCollectionProductsChanged:
+		pc := models.ProductsChange{}
+		if err := json.Unmarshal([]byte(e.Payload), &pc); err != nil {
+			return err
+		}
+		ids = pc.ProductIDs
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+	return pu.RefreshSearchText(db, ids)
+}
+
+func buildDomainEventDataExport(e *models.DomainEvent) error {
+	return BuildDataExportFn(e.AggregateID)
+}
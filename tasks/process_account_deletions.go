@@ -0,0 +1,20 @@
+package tasks
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/services"
+	"time"
+)
+
+const (
+	ProcessAccountDeletionsTaskName = "process_account_deletions"
+)
+
+func ProcessAccountDeletionsFn() error {
+	if err := services.ProcessAccountDeletions(); err != nil {
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Minute*5)
+	}
+	return nil
+}
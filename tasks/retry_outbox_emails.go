@@ -0,0 +1,20 @@
+package tasks
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/services"
+	"time"
+)
+
+const (
+	RetryOutboxEmailsTaskName = "retry_outbox_emails"
+)
+
+func RetryOutboxEmailsFn() error {
+	if err := services.DeliverDueEmails(50); err != nil {
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+	return nil
+}
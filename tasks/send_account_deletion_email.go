@@ -0,0 +1,69 @@
+package tasks
+
+import (
+	"fmt"
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"time"
+)
+
+const (
+	SendAccountDeletionEmailTaskName = "send_account_deletion_email"
+)
+
+func SendAccountDeletionEmailFn(deletionID string) error {
+	db := app.DB().Begin()
+
+	pu := data.NewPersonalDataRepository()
+	ad, err := pu.GetDeletionByID(db, deletionID)
+	if err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+	if ad.Status != models.AccountDeletionPending {
+		db.Rollback()
+		return nil
+	}
+
+	userDao := data.NewUserRepository()
+	u, err := userDao.Get(db, ad.UserID)
+	if err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	token, err := services.NewAccountDeletionCancelToken(db, ad.ID)
+	if err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	if err := services.SendAccountDeletionRequestedEmail(u, map[string]interface{}{
+		"userName": u.Name,
+		"cancelUrl": fmt.Sprintf("%s%s?token=%s",
+			config.App().FrontStoreUrl, config.PathMappingCfg()["after_account_deletion_requested"], token),
+		"deletionDate": ad.ScheduledAt.Format(utils.DateTimeFormatForDistribution),
+	}); err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+	return nil
+}
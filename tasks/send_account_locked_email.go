@@ -0,0 +1,56 @@
+package tasks
+
+import (
+	"fmt"
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"time"
+)
+
+const (
+	SendAccountLockedEmailTaskName = "send_account_locked_email"
+)
+
+func SendAccountLockedEmailFn(userID, ip string, lockedUntil int64) error {
+	db := app.DB().Begin()
+
+	userDao := data.NewUserRepository()
+	u, err := userDao.Get(db, userID)
+	if err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	token, err := services.NewAccountUnlockToken(db, u.ID)
+	if err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	if err := services.SendAccountLockedEmail(u, map[string]interface{}{
+		"userName": u.Name,
+		"unlockUrl": fmt.Sprintf("%s%s?token=%s",
+			config.App().FrontStoreUrl, config.PathMappingCfg()["after_account_locked"], token),
+		"lockedUntil": time.Unix(lockedUntil, 0).UTC().Format(utils.DateTimeFormatForDistribution),
+		"ip":          ip,
+	}); err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+	return nil
+}
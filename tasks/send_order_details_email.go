@@ -5,6 +5,7 @@ import (
 	"github.com/shopicano/shopicano-backend/app"
 	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
 	"github.com/shopicano/shopicano-backend/services"
 	"time"
 )
@@ -30,7 +31,18 @@ func SendOrderDetailsEmailFn(orderID, subject string) error {
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
 
-	if err := services.SendOrderDetailsEmail(u.Email, subject, o); err != nil {
+	inv, b, err := services.GenerateInvoice(o, models.InvoiceTypeInvoice)
+	if err != nil {
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	if err := services.SendOrderDetailsEmail(u, subject, o, services.EmailAttachment{
+		Name:        inv.FileName(),
+		ContentType: services.InvoiceContentType,
+		Path:        services.InvoiceObjectPath(inv),
+		Data:        b,
+	}); err != nil {
 		log.Log().Errorln(err)
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
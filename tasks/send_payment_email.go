@@ -5,6 +5,7 @@ import (
 	"github.com/shopicano/shopicano-backend/app"
 	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
 	"github.com/shopicano/shopicano-backend/services"
 	"time"
 )
@@ -31,7 +32,7 @@ func SendPaymentConfirmationEmailFn(orderID string) error {
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
 
-	if err := services.SendOrderDetailsEmail(u.Email, "Your payment has been received.", o); err != nil {
+	if err := services.SendOrderDetailsEmail(u, "Your payment has been received.", o); err != nil {
 		log.Log().Errorln(err)
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
@@ -55,7 +56,18 @@ func SendPaymentRevertedEmailFn(orderID string) error {
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
 
-	if err := services.SendOrderDetailsEmail(u.Email, "Your payment has been refunded.", order); err != nil {
+	cn, b, err := services.GenerateInvoice(order, models.InvoiceTypeCreditNote)
+	if err != nil {
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	if err := services.SendOrderDetailsEmail(u, "Your payment has been refunded.", order, services.EmailAttachment{
+		Name:        cn.FileName(),
+		ContentType: services.InvoiceContentType,
+		Path:        services.InvoiceObjectPath(cn),
+		Data:        b,
+	}); err != nil {
 		log.Log().Errorln(err)
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
@@ -8,7 +8,6 @@ import (
 	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/services"
-	"github.com/shopicano/shopicano-backend/templates"
 	"github.com/shopicano/shopicano-backend/utils"
 	"time"
 )
@@ -21,15 +20,6 @@ const (
 func SendResetPasswordEmailFn(userID string) error {
 	db := app.DB().Begin()
 
-	adminDao := data.NewMarketplaceRepository()
-	settings, err := adminDao.GetSettings(db)
-	if err != nil {
-		db.Rollback()
-
-		log.Log().Errorln(err)
-		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
-	}
-
 	userDao := data.NewUserRepository()
 	u, err := userDao.Get(db, userID)
 	if err != nil {
@@ -52,21 +42,10 @@ func SendResetPasswordEmailFn(userID string) error {
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
 
-	content, err := templates.GenerateResetPasswordEmailHTML(map[string]interface{}{
+	if err := services.SendResetPasswordEmail(u, map[string]interface{}{
 		"resetPasswordUrl": fmt.Sprintf("%s%s?token=%s&email=%s",
 			config.App().FrontStoreUrl, config.PathMappingCfg()["after_password_reset_requested"], *u.ResetPasswordToken, u.Email),
-		"platformName":    settings.Name,
-		"platformWebsite": settings.Website,
-		"assetsUrl":       fmt.Sprintf("%s/assets/", settings.Website),
-	})
-	if err != nil {
-		db.Rollback()
-
-		log.Log().Errorln(err)
-		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
-	}
-
-	if err := services.SendEmail("Reset Password Requested", u.Email, content); err != nil {
+	}); err != nil {
 		db.Rollback()
 
 		log.Log().Errorln(err)
@@ -82,15 +61,6 @@ func SendResetPasswordEmailFn(userID string) error {
 func SendResetPasswordConfirmationEmailFn(userID string) error {
 	db := app.DB()
 
-	adminDao := data.NewMarketplaceRepository()
-	settings, err := adminDao.GetSettings(db)
-	if err != nil {
-		db.Rollback()
-
-		log.Log().Errorln(err)
-		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
-	}
-
 	userDao := data.NewUserRepository()
 	u, err := userDao.Get(db, userID)
 	if err != nil {
@@ -100,18 +70,9 @@ func SendResetPasswordConfirmationEmailFn(userID string) error {
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
 
-	content, err := templates.GenerateResetPasswordConfirmationEmailHTML(map[string]interface{}{
-		"platformName":    settings.Name,
-		"platformWebsite": settings.Website,
-		"userName":        u.Name,
-		"assetsUrl":       fmt.Sprintf("%s/assets/", settings.Website),
-	})
-	if err != nil {
-		log.Log().Errorln(err)
-		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
-	}
-
-	if err := services.SendEmail("Your password changed", u.Email, content); err != nil {
+	if err := services.SendResetPasswordConfirmationEmail(u, map[string]interface{}{
+		"userName": u.Name,
+	}); err != nil {
 		log.Log().Errorln(err)
 		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
@@ -8,7 +8,6 @@ import (
 	"github.com/shopicano/shopicano-backend/data"
 	"github.com/shopicano/shopicano-backend/log"
 	"github.com/shopicano/shopicano-backend/services"
-	"github.com/shopicano/shopicano-backend/templates"
 	"github.com/shopicano/shopicano-backend/utils"
 	"time"
 )
@@ -20,15 +19,6 @@ const (
 func SendSignUpVerificationEmailFn(userID string) error {
 	db := app.DB().Begin()
 
-	adminDao := data.NewMarketplaceRepository()
-	settings, err := adminDao.GetSettings(db)
-	if err != nil {
-		db.Rollback()
-
-		log.Log().Errorln(err)
-		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
-	}
-
 	userDao := data.NewUserRepository()
 	u, err := userDao.Get(db, userID)
 	if err != nil {
@@ -52,22 +42,11 @@ func SendSignUpVerificationEmailFn(userID string) error {
 		config.App().FrontStoreUrl, config.PathMappingCfg()["after_account_verification"], u.Email, *u.VerificationToken)
 
 	params := map[string]interface{}{
-		"platformName":    settings.Name,
-		"platformWebsite": settings.Website,
 		"verificationUrl": verificationUrl,
 		"userName":        u.Name,
-		"assetsUrl":       fmt.Sprintf("%s/assets/", settings.Website),
-	}
-
-	body, err := templates.GenerateActivateAccountEmailHTML(params)
-	if err != nil {
-		db.Rollback()
-
-		log.Log().Errorln(err)
-		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
 	}
 
-	if err := services.SendSignUpVerificationEmail(u.Email, "Please verify your account", body); err != nil {
+	if err := services.SendSignUpVerificationEmail(u, params); err != nil {
 		db.Rollback()
 
 		log.Log().Errorln(err)
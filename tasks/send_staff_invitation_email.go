@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"fmt"
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/app"
+	"github.com/shopicano/shopicano-backend/config"
+	"github.com/shopicano/shopicano-backend/data"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/services"
+	"github.com/shopicano/shopicano-backend/utils"
+	"net/url"
+	"time"
+)
+
+const (
+	SendStaffInvitationEmailTaskName = "send_staff_invitation_email"
+)
+
+func SendStaffInvitationEmailFn(invitationID string) error {
+	db := app.DB().Begin()
+
+	iu := data.NewStaffInvitationRepository()
+	si, err := iu.GetDetails(db, invitationID)
+	if err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	// A revoked or answered invitation is not sent anymore
+	if si.Status != models.StaffInvitationPending {
+		db.Rollback()
+		return nil
+	}
+
+	userDao := data.NewUserRepository()
+	u, err := userDao.GetByEmail(db, si.Email)
+	if err != nil {
+		if !errors.IsRecordNotFoundError(err) {
+			db.Rollback()
+
+			log.Log().Errorln(err)
+			return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+		}
+		u = nil
+	}
+
+	token, err := services.NewStaffInvitationToken(db, si.ID)
+	if err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	invitationUrl := fmt.Sprintf("%s%s?token=%s&email=%s&signup=%t",
+		config.App().FrontStoreUrl, config.PathMappingCfg()["after_staff_invited"], token, url.QueryEscape(si.Email), u == nil)
+
+	if err := services.SendStaffInvitationEmail(si, u, map[string]interface{}{
+		"storeName":     si.StoreName,
+		"inviterName":   si.InviterName,
+		"invitationUrl": invitationUrl,
+		"expireOn":      si.ExpiresAt.Format(utils.DateTimeFormatForDistribution),
+		"isRegistered":  u != nil,
+	}); err != nil {
+		db.Rollback()
+
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package tasks
+
+import (
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/shopicano/shopicano-backend/log"
+	"github.com/shopicano/shopicano-backend/services"
+	"time"
+)
+
+const (
+	DeliverWebhookTaskName         = "deliver_webhook"
+	RetryWebhookDeliveriesTaskName = "retry_webhook_deliveries"
+)
+
+func DeliverWebhookFn(deliveryID string) error {
+	if err := services.DeliverWebhook(deliveryID); err != nil {
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+	return nil
+}
+
+func RetryWebhookDeliveriesFn() error {
+	if err := services.DeliverDueWebhooks(50); err != nil {
+		log.Log().Errorln(err)
+		return tasks.NewErrRetryTaskLater(err.Error(), time.Second*30)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package templates
+
+var accountLockedTemplate = `
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0, maximum-scale=1">
+
+    <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/inter-ui@3.12.0/inter.min.css">
+
+    <title>{{ .platformName }} | Account Locked</title>
+
+    <style type="text/css" media="screen">
+    body { padding:0 !important; margin:0 auto !important; font-family: Inter; display:block !important; min-width:100% !important; width:100% !important; background: #f6f8fc;; -webkit-text-size-adjust:none }
+
+    p {
+        font-size: 16px;
+        font-weight: normal;
+        font-stretch: normal;
+        font-style: normal;
+        line-height: 1.5;
+        letter-spacing: normal;
+        color: #5a637c;
+        text-align: center;
+    }
+    a{color: {{ .primaryColor }}; word-break: break-all; text-align: left;}
+    h3{
+        font-size: 24px;
+        font-weight: 500;
+        font-stretch: normal;
+        font-style: normal;
+        line-height: normal;
+        letter-spacing: normal;
+        text-align: center;
+        color: #363b4a;
+    }
+    img { position: relative; margin: 0 !important; -ms-interpolation-mode: bicubic;}
+
+
+    .container{
+        border-radius: 3px;
+        box-shadow: -2px -3px 8px 0 rgba(255, 255, 255, 0.5);
+        border: solid 1px #e9eceb;
+        background-color: #ffffff;
+        padding: 48px 47px;
+    }
+    .my-28 {
+        margin-top: 28px;
+        margin-bottom: 28px;
+    }
+    .btn{
+        width: 100%;
+        border-radius: 3px;
+        background-color: {{ .primaryColor }};
+        padding-top: 21px;
+        padding-bottom: 21px;
+        font-size: 14px;
+        font-weight: bold;
+        font-stretch: normal;
+        font-style: normal;
+        line-height: normal;
+        letter-spacing: 0.53px;
+        text-align: center;
+        color: #ffffff;
+        font-weight: 400;
+        vertical-align: middle;
+        cursor: pointer;
+        -webkit-user-select: none;
+        -moz-user-select: none;
+        -ms-user-select: none;
+        user-select: none;
+        border: 1px solid transparent;
+    }
+    cp{
+        font-size: 14px;
+        font-weight: normal;
+        font-stretch: normal;
+        font-style: normal;
+        line-height: 1.29;
+        letter-spacing: normal;
+        color: #6b7694;
+        text-align: center!important;
+    }
+    </style>
+	<script>
+	function redirectUrl(u) {
+  		window.open(u, '_blank');
+	}
+	</script>
+</head>
+
+<body>
+    <center>
+        <table width="100%" border="0" cellspacing="0" cellpadding="0" style="margin: 0; width: 100%; height: 100%;">
+            <tr>
+                <td style="margin: 0; padding: 0; width: 100%; height: 100%;" align="center">
+                    <a href="{{ .platformWebsite }}" target="_blank"><img src="{{ .logoUrl }}" width="165px" height="42px" alt=""></a>
+                    <table width="600" border="0" cellspacing="0" cellpadding="0" style="margin-top: 38px; padding: 0;">
+                        <tr>
+                            <td class="container" style="width:600px; min-width:600px; width: 100%;" align="center">
+                                <img src="{{ .assetsUrl }}key.png" width="74" height="74" alt="">
+                                <h3 class="my-28">Your Account Is Locked</h3>
+
+                                <p>
+                                    Hi {{ .userName }}, we locked your account until {{ .lockedUntil }} after too many failed
+                                    sign in attempts, the last one from {{ .ip }}. If it was you, click the button below to unlock it now.
+                                    If it wasn't, we recommend resetting your password.
+                                </p>
+
+                                <button class="btn" onclick="redirectUrl('{{ .unlockUrl }}');">Unlock Account</button>
+
+                                <p class="my-28">
+                                    If you’re having trouble with the button ‘Unlock Account',
+                                    copy and paste the URL below into your web browser.
+                                </p>
+
+                                <a href="{{ .unlockUrl }}">{{ .unlockUrl }}</a>
+                            </td>
+                        </tr>
+                    </table>
+
+                    <table width="600" border="0" cellspacing="0" cellpadding="0" style="margin-top: 0; padding: 0;">
+                        <tr>
+                            <td style="width:600px; min-width:600px; width: 100%;" align="center">
+                                <p style="font-size: 14px;font-weight: normal;font-stretch: normal;font-style: normal;line-height: 1.29;letter-spacing: normal;color: #6b7694;text-align: center!important">
+                                    {{ .footerText }}
+                                </P>
+                                <p style="font-size: 14px;font-weight: normal;font-stretch: normal;font-style: normal;line-height: 1.29;letter-spacing: normal;color: #6b7694;text-align: center!important">
+                                    Powered by <a href="{{ .platformWebsite }}" target="_blank" style="text-decoration: none">{{ .platformName }}</a>
+                                </P>
+                            </td>
+                        </tr>
+                    </table>
+                </td>
+            </tr>
+        </table>
+    </center>
+</body>
+`
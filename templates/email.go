@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"bytes"
+	htmlTemplate "html/template"
+	textTemplate "text/template"
+)
+
+var defaultEmailTemplates = map[string]string{
+	"verify_email":                verifyEmailTemplate,
+	"reset_password":              resetPasswordTemplate,
+	"reset_password_confirmation": resetPasswordConfirmationTemplate,
+	"invoice":                     invoiceTemplate,
+	"staff_invitation":            staffInvitationTemplate,
+	"account_locked":              accountLockedTemplate,
+	"account_deletion_requested":  accountDeletionRequestedTemplate,
+}
+
+// DefaultEmailTemplate returns the built in body of the named email template
+func DefaultEmailTemplate(name string) (string, bool) {
+	body, ok := defaultEmailTemplates[name]
+	return body, ok
+}
+
+func ParseEmailHTML(name, body string) (*htmlTemplate.Template, error) {
+	return htmlTemplate.New(name).Parse(body)
+}
+
+func GenerateEmailHTML(name, body string, params map[string]interface{}) (string, error) {
+	t, err := ParseEmailHTML(name, body)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, params); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func GenerateEmailSubject(subject string, params map[string]interface{}) (string, error) {
+	t, err := textTemplate.New("subject").Parse(subject)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, params); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -1,10 +1,5 @@
 package templates
 
-import (
-	"bytes"
-	"html/template"
-)
-
 var invoiceTemplate = `
 <head>
     <meta charset="UTF-8">
@@ -27,7 +22,7 @@ var invoiceTemplate = `
         color: #5a637c;
         text-align: center;
     }
-    a{color: #3f71f4; word-break: break-all; text-align: left;}
+    a{color: {{ .primaryColor }}; word-break: break-all; text-align: left;}
     h3{
         font-size: 24px;
         font-weight: 500;
@@ -170,7 +165,7 @@ var invoiceTemplate = `
                     <table width="600" border="0" cellspacing="0" cellpadding="0" style="padding: 0;">
                         <tr>
                             <td class="container" style="width:600px; padding-bottom: 92px; min-width:600px; width: 100%;" align="center">
-                                <img src="{{ .assetsUrl }}group-26@3x.png" width="165px" height="42px" alt="">
+                                <img src="{{ .logoUrl }}" width="165px" height="42px" alt="">
                                 <h3 class="title">{{ .greetings }}</h3>
 
                                 <p>{{ .intros }}</p>
@@ -188,7 +183,7 @@ var invoiceTemplate = `
                                         <th style="text-align: right;">INVOICE DATE</th>
                                     </tr>
                                     <tr class="tbl-data">
-                                        <td class="td-border1st" style="color: #3f71f4;"><a href="{{ .orderUrl }}" target="_blank">#{{ .orderHash }}</a></td>
+                                        <td class="td-border1st" style="color: {{ .primaryColor }};"><a href="{{ .orderUrl }}" target="_blank">#{{ .orderHash }}</a></td>
                                         <td class="td-border1st">{{ .buyerName }}</td>
                                         <td class="td-border1st" style="text-align: right;">{{ .orderDate }}</td>
                                     </tr>
@@ -204,12 +199,21 @@ var invoiceTemplate = `
                                         <th style="text-align: right;">Sub Total</th>
                                     </tr>
                                     {{ range $item := .orderedItems }}
+                                        {{ if $item.isComponent }}
+                                        <tr class="tbl-data">
+                                            <td class="td-border2nd" style="padding: 3px 0 3px 20px; color: #777777;">&#8627; {{ $item.name }}</td>
+                                            <td class="td-border2nd" style="text-align: center; padding: 3px 0;"></td>
+                                            <td class="td-border2nd" style="text-align: center; padding: 3px 0; color: #777777;">{{ $item.quantity }}</td>
+                                            <td class="td-border2nd" style="text-align: right; padding: 3px 0;"></td>
+                                        </tr>
+                                        {{ else }}
                                         <tr class="tbl-data">
                                             <td class="td-border2nd" style="padding: 7px 0;">{{ $item.name }}</td>
                                             <td class="td-border2nd" style="text-align: center; padding: 7px 0;">{{ $item.price }}</td>
                                             <td class="td-border2nd" style="text-align: center; padding: 7px 0;">{{ $item.quantity }}</td>
                                             <td class="td-border2nd" style="text-align: right; padding: 7px 0;">{{ $item.subTotal }}</td>
                                         </tr>
+                                        {{end}}
                                     {{end}}
 
                                     <tr class="tbl-data">
@@ -246,6 +250,24 @@ var invoiceTemplate = `
                                         </tr>
                                     {{end}}
 
+                                    {{ if .isTaxApplied }}
+                                        <tr class="tbl-data">
+                                            <td class="border_bottom" style="padding: 7px 0;"></td>
+                                            <td class="border_bottom" style="text-align: center;"></td>
+                                            <td class="border_bottom" style="text-align: right; padding: 2px 0 10px 0;">Tax{{ if .isTaxIncluded }} (included){{end}}:</td>
+                                            <td class="border_bottom" style="text-align: right; padding: 2px 0 10px 0;">{{ .tax }}</td>
+                                        </tr>
+                                    {{end}}
+
+                                    {{ if .vatNumber }}
+                                        <tr class="tbl-data">
+                                            <td class="border_bottom" style="padding: 7px 0;"></td>
+                                            <td class="border_bottom" style="text-align: center;"></td>
+                                            <td class="border_bottom" style="text-align: right; padding: 2px 0 10px 0;">Tax Exempt VAT No:</td>
+                                            <td class="border_bottom" style="text-align: right; padding: 2px 0 10px 0;">{{ .vatNumber }}</td>
+                                        </tr>
+                                    {{end}}
+
                                     {{ if .isDigitalPayment }}
                                         <tr class="tbl-data">
                                             <td class="border_bottom" style="padding: 7px 0;"></td>
@@ -265,14 +287,14 @@ var invoiceTemplate = `
                                         <td style="text-align: left; padding: 1px 10px 1px 0;">{{ .shippingAddress }}</td>
                                         <td style="text-align: center;"></td>
                                         <td style="text-align: right; padding: 1px 0;">Payment Status:</td>
-                                        <td style="text-align: right; padding: 1px 0; color: #ff8124; font-weight: 500; font-size: 14px;">{{ .paymentStatus }}</td>
+                                        <td style="text-align: right; padding: 1px 0; color: {{ .accentColor }}; font-weight: 500; font-size: 14px;">{{ .paymentStatus }}</td>
                                     </tr>
 									{{ if .isShippable }}
                                     	<tr class="tbl-data">
                                         	<td style="text-align: left; padding: 1px 10px 1px 0;"></td>
                                         	<td style="text-align: center;"></td>
                                         	<td style="text-align: right; padding: 1px 0;">Order Status:</td>
-                                        	<td style="text-align: right; padding: 1px 0; color: #ff8124; font-weight: 500; font-size: 14px;">{{ .status }}</td>
+                                        	<td style="text-align: right; padding: 1px 0; color: {{ .accentColor }}; font-weight: 500; font-size: 14px;">{{ .status }}</td>
                                     	</tr>
 									{{end}}
                                 </table>
@@ -305,6 +327,7 @@ var invoiceTemplate = `
                                         </a>
                                     </li> -->
                                 </ul>
+                                <p style="clear: both; margin: 14px 0 0 0; font-size: 12px; color: #6b7694;">{{ .footerText }}</p>
                             </td>
                         </tr>
                     </table>
@@ -314,12 +337,3 @@ var invoiceTemplate = `
     </center>
 </body>
 `
-
-func GenerateInvoiceEmailHTML(params map[string]interface{}) (string, error) {
-	var buf bytes.Buffer
-	t := template.Must(template.New("InvoiceTemplate").Parse(invoiceTemplate))
-	if err := t.Execute(&buf, params); err != nil {
-		return "", err
-	}
-	return buf.String(), nil
-}
@@ -0,0 +1,142 @@
+package templates
+
+import (
+	"github.com/shopicano/shopicano-backend/pdf"
+)
+
+type InvoicePDF struct {
+	Title           string
+	Number          string
+	Date            string
+	OrderHash       string
+	PlatformName    string
+	StoreName       string
+	StoreAddress    string
+	StoreEmail      string
+	BuyerName       string
+	BillingAddress  string
+	ShippingAddress string
+	Lines           []InvoicePDFLine
+	Totals          []InvoicePDFTotal
+	Notes           []string
+}
+
+type InvoicePDFLine struct {
+	Name        string
+	Quantity    string
+	Price       string
+	SubTotal    string
+	IsComponent bool
+}
+
+type InvoicePDFTotal struct {
+	Label string
+	Value string
+}
+
+const (
+	invoicePDFMargin     = 50
+	invoicePDFLineHeight = 18
+)
+
+func GenerateInvoicePDF(inv *InvoicePDF) []byte {
+	doc := pdf.NewDocument(invoicePDFMargin)
+
+	left := doc.Margin()
+	right := pdf.PageWidth - doc.Margin()
+	width := right - left
+
+	// Columns of the items table, by their right edge except the name
+	qtyX := left + width*0.62
+	priceX := left + width*0.80
+	totalX := right
+
+	y := doc.Margin() + 10.0
+
+	doc.Text(left, y, pdf.Bold, 20, pdf.AlignLeft, inv.Title)
+	doc.Text(right, y, pdf.Bold, 12, pdf.AlignRight, inv.StoreName)
+	y += 18
+	doc.Text(left, y, pdf.Regular, 10, pdf.AlignLeft, "No: "+inv.Number)
+	doc.Text(right, y, pdf.Regular, 9, pdf.AlignRight, pdf.Truncate(pdf.Regular, 9, width/2, inv.StoreAddress))
+	y += 14
+	doc.Text(left, y, pdf.Regular, 10, pdf.AlignLeft, "Date: "+inv.Date)
+	doc.Text(right, y, pdf.Regular, 9, pdf.AlignRight, inv.StoreEmail)
+	y += 14
+	doc.Text(left, y, pdf.Regular, 10, pdf.AlignLeft, "Order: "+inv.OrderHash)
+
+	y += 30
+	doc.Text(left, y, pdf.Bold, 10, pdf.AlignLeft, "Bill To")
+	doc.Text(left+width/2, y, pdf.Bold, 10, pdf.AlignLeft, "Ship To")
+	y += 14
+	doc.Text(left, y, pdf.Regular, 9, pdf.AlignLeft, inv.BuyerName)
+	y += 12
+	doc.Text(left, y, pdf.Regular, 9, pdf.AlignLeft, pdf.Truncate(pdf.Regular, 9, width/2-10, inv.BillingAddress))
+	doc.Text(left+width/2, y-12, pdf.Regular, 9, pdf.AlignLeft, pdf.Truncate(pdf.Regular, 9, width/2, inv.ShippingAddress))
+
+	header := func() {
+		doc.FillRect(left, y-12, width, invoicePDFLineHeight, 0.9)
+		doc.Text(left+4, y, pdf.Bold, 9, pdf.AlignLeft, "Item")
+		doc.Text(qtyX, y, pdf.Bold, 9, pdf.AlignRight, "Qty")
+		doc.Text(priceX, y, pdf.Bold, 9, pdf.AlignRight, "Price")
+		doc.Text(totalX-4, y, pdf.Bold, 9, pdf.AlignRight, "Total")
+		y += invoicePDFLineHeight
+	}
+
+	// Keeps the content off the bottom margin by moving it to a fresh page
+	ensure := func(space float64, withHeader bool) {
+		if y+space <= pdf.PageHeight-doc.Margin() {
+			return
+		}
+		doc.AddPage()
+		y = doc.Margin() + 10
+		if withHeader {
+			header()
+		}
+	}
+
+	y += 30
+	header()
+
+	for _, l := range inv.Lines {
+		ensure(invoicePDFLineHeight, true)
+
+		if l.IsComponent {
+			name := pdf.Truncate(pdf.Regular, 8, qtyX-left-60, "- "+l.Name)
+			doc.Text(left+16, y, pdf.Regular, 8, pdf.AlignLeft, name)
+			doc.Text(qtyX, y, pdf.Regular, 8, pdf.AlignRight, l.Quantity)
+		} else {
+			name := pdf.Truncate(pdf.Regular, 9, qtyX-left-50, l.Name)
+			doc.Text(left+4, y, pdf.Regular, 9, pdf.AlignLeft, name)
+			doc.Text(qtyX, y, pdf.Regular, 9, pdf.AlignRight, l.Quantity)
+			doc.Text(priceX, y, pdf.Regular, 9, pdf.AlignRight, l.Price)
+			doc.Text(totalX-4, y, pdf.Regular, 9, pdf.AlignRight, l.SubTotal)
+		}
+		doc.Line(left, y+6, right, y+6, 0.3)
+		y += invoicePDFLineHeight
+	}
+
+	y += 10
+	for i, t := range inv.Totals {
+		ensure(invoicePDFLineHeight, false)
+
+		font := pdf.Regular
+		if i == len(inv.Totals)-1 {
+			font = pdf.Bold
+		}
+		doc.Text(priceX, y, font, 10, pdf.AlignRight, t.Label)
+		doc.Text(totalX-4, y, font, 10, pdf.AlignRight, t.Value)
+		y += invoicePDFLineHeight
+	}
+
+	y += 20
+	for _, n := range inv.Notes {
+		ensure(14, false)
+		doc.Text(left, y, pdf.Regular, 8, pdf.AlignLeft, pdf.Truncate(pdf.Regular, 8, width, n))
+		y += 14
+	}
+
+	if inv.PlatformName != "" {
+		doc.Text(pdf.PageWidth/2, pdf.PageHeight-doc.Margin()/2, pdf.Regular, 8, pdf.AlignCenter, inv.PlatformName)
+	}
+	return doc.Bytes()
+}
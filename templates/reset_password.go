@@ -1,10 +1,5 @@
 package templates
 
-import (
-	"bytes"
-	"html/template"
-)
-
 var resetPasswordTemplate = `
 <head>
     <meta charset="UTF-8">
@@ -27,7 +22,7 @@ var resetPasswordTemplate = `
         color: #5a637c;
         text-align: center;
     }
-    a{color: #3f71f4; word-break: break-all; text-align: left;}
+    a{color: {{ .primaryColor }}; word-break: break-all; text-align: left;}
     h3{
         font-size: 24px;
         font-weight: 500;
@@ -55,7 +50,7 @@ var resetPasswordTemplate = `
     .btn{
         width: 100%;
         border-radius: 3px;
-        background-color: #3f71f4;
+        background-color: {{ .primaryColor }};
         padding-top: 21px;
         padding-bottom: 21px;
         font-size: 14px;
@@ -98,7 +93,7 @@ var resetPasswordTemplate = `
         <table width="100%" border="0" cellspacing="0" cellpadding="0" style="margin: 0; width: 100%; height: 100%;">
             <tr>
                 <td style="margin: 0; padding: 0; width: 100%; height: 100%;" align="center">
-                    <a href="{{ .platformWebsite }}" target="_blank"><img src="{{ .assetsUrl }}group-26@3x.png" width="165px" height="42px" alt=""></a>
+                    <a href="{{ .platformWebsite }}" target="_blank"><img src="{{ .logoUrl }}" width="165px" height="42px" alt=""></a>
                     <table width="600" border="0" cellspacing="0" cellpadding="0" style="margin-top: 38px; padding: 0;">
                         <tr>
                             <td class="container" style="width:600px; min-width:600px; width: 100%;" align="center">
@@ -126,7 +121,7 @@ var resetPasswordTemplate = `
                         <tr>
                             <td style="width:600px; min-width:600px; width: 100%;" align="center">
                                 <p style="font-size: 14px;font-weight: normal;font-stretch: normal;font-style: normal;line-height: 1.29;letter-spacing: normal;color: #6b7694;text-align: center!important">
-                                    © 2020 {{ .platformName }}. All rights reserved.
+                                    {{ .footerText }}
                                 </P>
                                 <p style="font-size: 14px;font-weight: normal;font-stretch: normal;font-style: normal;line-height: 1.29;letter-spacing: normal;color: #6b7694;text-align: center!important">
                                     Powered by <a href="{{ .platformWebsite }}" target="_blank" style="text-decoration: none">{{ .platformName }}</a>
@@ -140,12 +135,3 @@ var resetPasswordTemplate = `
     </center>
 </body>
 `
-
-func GenerateResetPasswordEmailHTML(params map[string]interface{}) (string, error) {
-	var buf bytes.Buffer
-	t := template.Must(template.New("ResetPasswordTemplate").Parse(resetPasswordTemplate))
-	if err := t.Execute(&buf, params); err != nil {
-		return "", err
-	}
-	return buf.String(), nil
-}
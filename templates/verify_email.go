@@ -1,10 +1,5 @@
 package templates
 
-import (
-	"bytes"
-	"html/template"
-)
-
 var verifyEmailTemplate = `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -28,7 +23,7 @@ var verifyEmailTemplate = `<!DOCTYPE html>
         color: #5a637c;
         text-align: center;
     }
-    a{color: #3f71f4; word-wrap: break-word;word-break: break-all; text-align: left; text-align-last: left;}
+    a{color: {{ .primaryColor }}; word-wrap: break-word;word-break: break-all; text-align: left; text-align-last: left;}
     h3{
         font-size: 24px;
         font-weight: 500;
@@ -56,7 +51,7 @@ var verifyEmailTemplate = `<!DOCTYPE html>
     .btn{
         width: 100%;
         border-radius: 3px;
-        background-color: #3f71f4;
+        background-color: {{ .primaryColor }};
         padding-top: 21px;
         padding-bottom: 21px;
         font-size: 14px;
@@ -100,7 +95,7 @@ var verifyEmailTemplate = `<!DOCTYPE html>
         <table width="100%" border="0" cellspacing="0" cellpadding="0" style="margin: 0; padding-top: 138px; width: 100%; height: 100%;">
             <tr>
                 <td style="margin: 0; padding: 0; width: 100%; height: 100%;" align="center">
-                    <a href="{{ .platformWebsite }}" target="_blank"><img src="{{ .assetsUrl }}group-26@3x.png" width="165px" height="42px" alt="{{ .platformName }}"></a>
+                    <a href="{{ .platformWebsite }}" target="_blank"><img src="{{ .logoUrl }}" width="165px" height="42px" alt="{{ .platformName }}"></a>
                     <table width="600" border="0" cellspacing="0" cellpadding="0" style="margin-top: 38px; padding: 0;">
                         <tr>
                             <td class="container" style="width:600px; min-width:600px; width: 100%;" align="center">
@@ -123,7 +118,7 @@ var verifyEmailTemplate = `<!DOCTYPE html>
                         <tr>
                             <td style="width:600px; min-width:600px; width: 100%;" align="center">
                                 <p style="font-size: 14px;font-weight: normal;font-stretch: normal;font-style: normal;line-height: 1.29;letter-spacing: normal;color: #6b7694;text-align: center!important">
-                                    © 2020 {{ .platformName }}. All rights reserved.
+                                    {{ .footerText }}
                                 </P>
                                 <p style="font-size: 14px;font-weight: normal;font-stretch: normal;font-style: normal;line-height: 1.29;letter-spacing: normal;color: #6b7694;text-align: center!important">
                                     Powered by <a href="{{ .platformWebsite }}" style="text-decoration: none">{{ .platformName }}</a>
@@ -138,12 +133,3 @@ var verifyEmailTemplate = `<!DOCTYPE html>
 </body>
 </html>
 `
-
-func GenerateActivateAccountEmailHTML(params map[string]interface{}) (string, error) {
-	var buf bytes.Buffer
-	t := template.Must(template.New("ActivateAccountTemplate").Parse(verifyEmailTemplate))
-	if err := t.Execute(&buf, params); err != nil {
-		return "", err
-	}
-	return buf.String(), nil
-}
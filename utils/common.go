@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"github.com/satori/go.uuid"
 	"github.com/teris-io/shortid"
@@ -31,3 +33,12 @@ func NewToken() string {
 	token := fmt.Sprintf("%s_%d_%s", NewUUID(), time.Now().Unix(), time.Now().UTC())
 	return base64.StdEncoding.EncodeToString([]byte(token))
 }
+
+// NewSecret returns n cryptographically random bytes, hex encoded
+func NewSecret(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -16,11 +16,17 @@ const (
 
 	StoreID         = "store_id"
 	StorePermission = "store_permission"
+	StorePerms      = "store_perms"
 	StoreStatus     = "store_status"
 	UserID          = "user_id"
+	SessionID       = "session_id"
 	UserPermission  = "user_permission"
 	UserStatus      = "user_status"
 	Scope           = "user_scope"
+	APIKey          = "api_key"
+
+	// StoreIDHeader selects the store of a staff acting on one of several stores
+	StoreIDHeader = "X-Store-ID"
 )
 
 const (
@@ -32,7 +38,8 @@ const (
 type UserScope string
 
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
 	jwt.StandardClaims
 }
 
@@ -73,6 +80,10 @@ func GetUserID(ctx echo.Context) string {
 	return ctx.Get(UserID).(string)
 }
 
+func GetSessionID(ctx echo.Context) string {
+	return ctx.Get(SessionID).(string)
+}
+
 func GetUserStatus(ctx echo.Context) models.UserStatus {
 	return ctx.Get(UserStatus).(models.UserStatus)
 }
@@ -89,16 +100,44 @@ func GetStorePermission(ctx echo.Context) models.Permission {
 	return ctx.Get(StorePermission).(models.Permission)
 }
 
-func BuildJWTToken(userID string, scope UserScope) (string, error) {
+func GetStorePerms(ctx echo.Context) []models.StorePerm {
+	perms, _ := ctx.Get(StorePerms).([]models.StorePerm)
+	return perms
+}
+
+// GetAPIKey returns the store api key the request is authenticated with, nil for a user access token
+func GetAPIKey(ctx echo.Context) *models.StoreAPIKey {
+	k, _ := ctx.Get(APIKey).(*models.StoreAPIKey)
+	return k
+}
+
+func HasStorePerm(ctx echo.Context, perm models.StorePerm) bool {
+	for _, p := range GetStorePerms(ctx) {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildJWTToken signs a short lived access token of the session, along with the unix time it expires at
+func BuildJWTToken(userID, sessionID string, scope UserScope) (string, int64, error) {
+	expiresAt := time.Now().Add(config.App().AccessTokenTTL).Unix()
+
 	claims := Claims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
 		StandardClaims: jwt.StandardClaims{
 			Audience:  string(scope),
 			IssuedAt:  time.Now().Unix(),
-			ExpiresAt: time.Now().Add(time.Hour * 24 * 7).Unix(),
+			ExpiresAt: expiresAt,
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.App().JWTKey))
+	signed, err := token.SignedString([]byte(config.App().JWTKey))
+	if err != nil {
+		return "", 0, err
+	}
+	return signed, expiresAt, nil
 }
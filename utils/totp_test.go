@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed of the test vectors of RFC 6238 appendix B, "12345678901234567890" in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfc6238Vectors are the SHA1 vectors of RFC 6238 appendix B truncated to TOTPDigits
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCode(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		got, err := TOTPCode(rfc6238Secret, v.unix/TOTPPeriod)
+		if err != nil {
+			t.Fatalf("T=%d: %v", v.unix, err)
+		}
+		if got != v.code {
+			t.Errorf("T=%d: got %s, want %s", v.unix, got, v.code)
+		}
+	}
+
+	if got, err := TOTPCode("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", 1); err != nil || got != "287082" {
+		t.Errorf("lower case secret: got %s, %v, want 287082", got, err)
+	}
+	if _, err := TOTPCode("not base32!", 1); err == nil {
+		t.Error("invalid secret: want an error")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	step := at.Unix() / TOTPPeriod
+
+	codeAt := func(s int64) string {
+		c, err := TOTPCode(rfc6238Secret, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	cases := []struct {
+		name string
+		code string
+		step int64
+		ok   bool
+	}{
+		{name: "current step", code: "050471", step: step, ok: true},
+		{name: "surrounded by spaces", code: " 050471 ", step: step, ok: true},
+		{name: "previous step within skew", code: codeAt(step - TOTPSkew), step: step - TOTPSkew, ok: true},
+		{name: "next step within skew", code: codeAt(step + TOTPSkew), step: step + TOTPSkew, ok: true},
+		{name: "previous step beyond skew", code: codeAt(step - TOTPSkew - 1)},
+		{name: "next step beyond skew", code: codeAt(step + TOTPSkew + 1)},
+		{name: "wrong code", code: "000000"},
+		{name: "too short", code: "50471"},
+		{name: "eight digits", code: "14050471"},
+		{name: "empty", code: ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := ValidateTOTP(rfc6238Secret, c.code, at)
+			if ok != c.ok || got != c.step {
+				t.Errorf("got step %d, %v, want %d, %v", got, ok, c.step, c.ok)
+			}
+		})
+	}
+}
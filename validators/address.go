@@ -12,6 +12,8 @@ type ReqAddressCreate struct {
 	State     string `json:"state"`
 	City      string `json:"city" valid:"required"`
 	CountryID int64  `json:"country_id" valid:"required"`
+	StateID   *int64 `json:"state_id"`
+	CityID    *int64 `json:"city_id"`
 	Postcode  string `json:"postcode" valid:"required"`
 	Email     string `json:"email"`
 	Phone     string `json:"phone"`
@@ -11,10 +11,11 @@ import (
 
 func ValidateCreateCategory(ctx echo.Context) (*models.Category, error) {
 	pld := struct {
-		Name        string `json:"name" valid:"required,stringlength(1|20)"`
-		Description string `json:"description" valid:"required,stringlength(1|50)"`
-		Image       string `json:"image"`
-		IsPublished bool   `json:"is_published"`
+		Name             string  `json:"name" valid:"required,stringlength(1|20)"`
+		Description      string  `json:"description" valid:"required,stringlength(1|50)"`
+		Image            string  `json:"image"`
+		IsPublished      bool    `json:"is_published"`
+		GlobalCategoryID *string `json:"global_category_id"`
 	}{}
 
 	if err := ctx.Bind(&pld); err != nil {
@@ -24,13 +25,14 @@ func ValidateCreateCategory(ctx echo.Context) (*models.Category, error) {
 	ok, err := govalidator.ValidateStruct(&pld)
 	if ok {
 		return &models.Category{
-			ID:          utils.NewUUID(),
-			Name:        pld.Name,
-			Description: pld.Description,
-			Image:       pld.Image,
-			IsPublished: pld.IsPublished,
-			CreatedAt:   time.Now().UTC(),
-			UpdatedAt:   time.Now().UTC(),
+			ID:               utils.NewUUID(),
+			Name:             pld.Name,
+			Description:      pld.Description,
+			Image:            pld.Image,
+			IsPublished:      pld.IsPublished,
+			GlobalCategoryID: pld.GlobalCategoryID,
+			CreatedAt:        time.Now().UTC(),
+			UpdatedAt:        time.Now().UTC(),
 		}, nil
 	}
 
@@ -44,10 +46,11 @@ func ValidateCreateCategory(ctx echo.Context) (*models.Category, error) {
 }
 
 type ReqCategoryUpdate struct {
-	Name        *string `json:"name"`
-	Description *string `json:"description"`
-	Image       *string `json:"image"`
-	IsPublished *bool   `json:"is_published"`
+	Name             *string `json:"name"`
+	Description      *string `json:"description"`
+	Image            *string `json:"image"`
+	IsPublished      *bool   `json:"is_published"`
+	GlobalCategoryID *string `json:"global_category_id"`
 }
 
 func ValidateUpdateCategory(ctx echo.Context) (*ReqCategoryUpdate, error) {
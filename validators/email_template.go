@@ -0,0 +1,134 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/templates"
+	"regexp"
+)
+
+var (
+	localeRegex   = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+	hexColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+func isValidLocale(locale string) bool {
+	return localeRegex.MatchString(locale)
+}
+
+type ReqEmailTemplate struct {
+	Name     models.EmailTemplateName `json:"name"`
+	Locale   string                   `json:"locale"`
+	Subject  string                   `json:"subject" valid:"stringlength(0|200)"`
+	Body     string                   `json:"body" valid:"required"`
+	IsActive bool                     `json:"is_active"`
+}
+
+func ValidateEmailTemplate(ctx echo.Context) (*ReqEmailTemplate, error) {
+	pld := ReqEmailTemplate{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if !ok {
+		for k, v := range govalidator.ErrorsByField(err) {
+			ve.Add(k, v)
+		}
+	}
+
+	validateEmailTemplateContent(&ve, pld.Name, pld.Subject, pld.Body)
+
+	if pld.Locale == "" {
+		pld.Locale = models.DefaultLocale
+	}
+	if !isValidLocale(pld.Locale) {
+		ve.Add("locale", "is invalid")
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
+
+type ReqEmailTemplatePreview struct {
+	Name    models.EmailTemplateName `json:"name"`
+	StoreID *string                  `json:"store_id"`
+	Subject string                   `json:"subject"`
+	Body    string                   `json:"body"`
+}
+
+func ValidateEmailTemplatePreview(ctx echo.Context) (*ReqEmailTemplatePreview, error) {
+	pld := ReqEmailTemplatePreview{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	validateEmailTemplateContent(&ve, pld.Name, pld.Subject, pld.Body)
+
+	if pld.StoreID != nil && *pld.StoreID == "" {
+		pld.StoreID = nil
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
+
+func validateEmailTemplateContent(ve *errors.ValidationError, name models.EmailTemplateName, subject, body string) {
+	if !name.IsValid() {
+		ve.Add("name", "is invalid")
+	}
+	if _, err := templates.ParseEmailHTML(string(name), body); err != nil {
+		ve.Add("body", err.Error())
+	}
+	if _, err := templates.GenerateEmailSubject(subject, nil); err != nil {
+		ve.Add("subject", err.Error())
+	}
+}
+
+type ReqStoreEmailBranding struct {
+	LogoUrl      string `json:"logo_url" valid:"url"`
+	PrimaryColor string `json:"primary_color"`
+	AccentColor  string `json:"accent_color"`
+	FooterText   string `json:"footer_text" valid:"stringlength(0|500)"`
+}
+
+func ValidateStoreEmailBranding(ctx echo.Context) (*ReqStoreEmailBranding, error) {
+	pld := ReqStoreEmailBranding{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if !ok {
+		for k, v := range govalidator.ErrorsByField(err) {
+			ve.Add(k, v)
+		}
+	}
+
+	if pld.PrimaryColor != "" && !hexColorRegex.MatchString(pld.PrimaryColor) {
+		ve.Add("primary_color", "must be a hex color")
+	}
+	if pld.AccentColor != "" && !hexColorRegex.MatchString(pld.AccentColor) {
+		ve.Add("accent_color", "must be a hex color")
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
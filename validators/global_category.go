@@ -0,0 +1,74 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+)
+
+type ReqCreateGlobalCategory struct {
+	Name        string  `json:"name" valid:"required,stringlength(1|100)"`
+	ParentID    *string `json:"parent_id"`
+	Description string  `json:"description" valid:"stringlength(0|500)"`
+	Image       string  `json:"image"`
+	IsPublished bool    `json:"is_published"`
+}
+
+func ValidateCreateGlobalCategory(ctx echo.Context) (*ReqCreateGlobalCategory, error) {
+	pld := ReqCreateGlobalCategory{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+type ReqUpdateGlobalCategory struct {
+	Name        *string `json:"name"`
+	ParentID    *string `json:"parent_id"`
+	Description *string `json:"description"`
+	Image       *string `json:"image"`
+	IsPublished *bool   `json:"is_published"`
+}
+
+func ValidateUpdateGlobalCategory(ctx echo.Context) (*ReqUpdateGlobalCategory, error) {
+	pld := ReqUpdateGlobalCategory{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	if pld.Name != nil {
+		ok := len(*pld.Name) >= 1 && len(*pld.Name) <= 100
+		if !ok {
+			ve.Add("name", "must be between 1 to 100 characters")
+		}
+	}
+	if pld.Description != nil {
+		ok := len(*pld.Description) <= 500
+		if !ok {
+			ve.Add("description", "must be less than 500 characters")
+		}
+	}
+	if pld.ParentID != nil && *pld.ParentID == ctx.Param("gc_id") {
+		ve.Add("parent_id", "can't be the category itself")
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
@@ -0,0 +1,20 @@
+package validators
+
+import (
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/values"
+	"strings"
+)
+
+// isUploadedImagePath reports whether the image is a path returned by the upload of /fs/, i.e. an image
+// in a public bucket, rather than an outside url or an object of the reserved bucket
+func isUploadedImagePath(path string) bool {
+	i := strings.Index(path, "/")
+	if i <= 0 || i == len(path)-1 {
+		return false
+	}
+	if path[:i] == values.ReservedBucketName || strings.Contains(path, "..") || strings.Contains(path, ":") {
+		return false
+	}
+	return utils.IsImage(strings.ToLower(path))
+}
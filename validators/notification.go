@@ -0,0 +1,81 @@
+package validators
+
+import (
+	"fmt"
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type ReqNotificationPreference struct {
+	Event         models.NotificationEvent `json:"event"`
+	IsSMSEnabled  bool                     `json:"is_sms_enabled"`
+	IsPushEnabled bool                     `json:"is_push_enabled"`
+}
+
+type ReqUpdateNotificationPreferences struct {
+	Preferences []ReqNotificationPreference `json:"preferences"`
+}
+
+func ValidateUpdateNotificationPreferences(ctx echo.Context) (*ReqUpdateNotificationPreferences, error) {
+	pld := ReqUpdateNotificationPreferences{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	if len(pld.Preferences) == 0 {
+		ve.Add("preferences", "is required")
+	}
+
+	seen := map[models.NotificationEvent]bool{}
+	for i, p := range pld.Preferences {
+		if !p.Event.IsValid() {
+			ve.Add(fmt.Sprintf("preferences[%d].event", i), "is invalid")
+			continue
+		}
+		if seen[p.Event] {
+			ve.Add(fmt.Sprintf("preferences[%d].event", i), "must be unique")
+		}
+		seen[p.Event] = true
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
+
+type ReqRegisterPushDevice struct {
+	Token    string              `json:"token" valid:"required,stringlength(1|4096)"`
+	Platform models.PushPlatform `json:"platform"`
+}
+
+func ValidateRegisterPushDevice(ctx echo.Context) (*ReqRegisterPushDevice, error) {
+	pld := ReqRegisterPushDevice{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if !ok {
+		for k, v := range govalidator.ErrorsByField(err) {
+			ve.Add(k, v)
+		}
+	}
+
+	if !pld.Platform.IsValid() {
+		ve.Add("platform", "is invalid")
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
@@ -0,0 +1,44 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/utils"
+)
+
+type ReqOIDCAuthorize struct {
+	Scope utils.UserScope `json:"scope"`
+}
+
+// ReqOIDCCallback is bound from a form as well, for the providers posting the callback
+type ReqOIDCCallback struct {
+	State string `json:"state" form:"state" valid:"required"`
+	Code  string `json:"code" form:"code" valid:"required"`
+}
+
+func ValidateOIDCAuthorize(ctx echo.Context) (*ReqOIDCAuthorize, error) {
+	pld := ReqOIDCAuthorize{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
+
+func ValidateOIDCCallback(ctx echo.Context) (*ReqOIDCCallback, error) {
+	pld := ReqOIDCCallback{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+	return nil, &ve
+}
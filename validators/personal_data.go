@@ -0,0 +1,49 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+)
+
+// ReqAccountDeletion confirms the deletion request with the password of the user
+type ReqAccountDeletion struct {
+	Password string `json:"password" valid:"required"`
+}
+
+type ReqAccountDeletionCancel struct {
+	Token string `json:"token" valid:"required"`
+}
+
+func validatePersonalData(ctx echo.Context, pld interface{}) error {
+	if err := ctx.Bind(pld); err != nil {
+		return err
+	}
+
+	ok, err := govalidator.ValidateStruct(pld)
+	if ok {
+		return nil
+	}
+
+	ve := errors.ValidationError{}
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+	return &ve
+}
+
+func ValidateAccountDeletion(ctx echo.Context) (*ReqAccountDeletion, error) {
+	pld := ReqAccountDeletion{}
+	if err := validatePersonalData(ctx, &pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
+
+func ValidateAccountDeletionCancel(ctx echo.Context) (*ReqAccountDeletionCancel, error) {
+	pld := ReqAccountDeletionCancel{}
+	if err := validatePersonalData(ctx, &pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
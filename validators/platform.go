@@ -37,13 +37,24 @@ func ValidateCreatePaymentMethod(ctx echo.Context) (*ReqPaymentMethodCreate, err
 	return nil, &ve
 }
 
+type ReqShippingWeightBracket struct {
+	MinWeight int   `json:"min_weight" valid:"range(0|100000000)"`
+	MaxWeight int   `json:"max_weight" valid:"range(0|100000000)"`
+	Charge    int64 `json:"charge" valid:"range(0|100000000)"`
+}
+
 type ReqShippingMethodCreate struct {
-	Name                    string            `json:"name" valid:"required"`
-	ApproximateDeliveryTime int               `json:"approximate_delivery_time" valid:"required"`
-	DeliveryCharge          int64             `json:"delivery_charge" valid:"required"`
-	IsPublished             bool              `json:"is_published"`
-	IsFlat                  bool              `json:"is_flat"`
-	WeightUnit              models.WeightUnit `json:"weight_unit" valid:"required"`
+	Name                    string                     `json:"name" valid:"required"`
+	ApproximateDeliveryTime int                        `json:"approximate_delivery_time" valid:"required"`
+	HandlingTime            int                        `json:"handling_time" valid:"range(0|365)"`
+	DeliveryCharge          int64                      `json:"delivery_charge" valid:"required"`
+	IsPublished             bool                       `json:"is_published"`
+	IsFlat                  bool                       `json:"is_flat"`
+	WeightUnit              models.WeightUnit          `json:"weight_unit" valid:"required"`
+	PerKgRate               int64                      `json:"per_kg_rate" valid:"range(0|100000000)"`
+	VolumetricDivisor       int                        `json:"volumetric_divisor" valid:"range(0|1000000)"`
+	FreeShippingThreshold   int64                      `json:"free_shipping_threshold" valid:"range(0|100000000)"`
+	WeightBrackets          []ReqShippingWeightBracket `json:"weight_brackets"`
 }
 
 func ValidateCreateShippingMethod(ctx echo.Context) (*ReqShippingMethodCreate, error) {
@@ -52,24 +63,38 @@ func ValidateCreateShippingMethod(ctx echo.Context) (*ReqShippingMethodCreate, e
 		return nil, err
 	}
 
+	if err := validateShippingMethod(&pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
+
+func validateShippingMethod(pld *ReqShippingMethodCreate) *errors.ValidationError {
 	ve := errors.ValidationError{}
 
-	ok, err := govalidator.ValidateStruct(&pld)
+	ok, err := govalidator.ValidateStruct(pld)
 	if !ok {
 		for k, v := range govalidator.ErrorsByField(err) {
 			ve.Add(k, v)
 		}
 	}
 
-	if pld.WeightUnit.IsValid() {
+	if !pld.WeightUnit.IsValid() {
 		ve.Add("weight_unit", "is invalid")
 	}
 
+	for _, b := range pld.WeightBrackets {
+		if b.MaxWeight != 0 && b.MaxWeight <= b.MinWeight {
+			ve.Add("weight_brackets", "max_weight must be greater than min_weight")
+			break
+		}
+	}
+
 	if len(ve) == 0 {
-		return &pld, nil
+		return nil
 	}
 
-	return nil, &ve
+	return &ve
 }
 
 type ReqSettingsUpdate struct {
@@ -82,6 +107,7 @@ type ReqSettingsUpdate struct {
 	IsStoreCreationEnabled       *bool                  `json:"is_store_creation_enabled"`
 	DefaultCommissionRate        *int64                 `json:"default_commission_rate"`
 	TagLine                      *string                `json:"tag_line"`
+	IsTwoFactorRequired          *bool                  `json:"is_two_factor_required"`
 }
 
 func ValidateUpdateSettings(ctx echo.Context) (*ReqSettingsUpdate, error) {
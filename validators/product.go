@@ -4,23 +4,34 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/labstack/echo/v4"
 	"github.com/shopicano/shopicano-backend/errors"
+	"time"
 )
 
 type ReqProductCreate struct {
-	Name             string   `json:"name" valid:"required,stringlength(3|100)"`
-	Description      string   `json:"description" valid:"required,stringlength(3|100000)"`
-	IsPublished      bool     `json:"is_published"`
-	CategoryID       *string  `json:"category_id"`
-	Image            string   `json:"image"`
-	IsShippable      bool     `json:"is_shippable"`
-	IsDigital        bool     `json:"is_digital"`
-	SKU              string   `json:"sku" valid:"required,stringlength(1|100)"`
-	Stock            int      `json:"stock" valid:"range(0|100000)"`
-	Unit             string   `json:"unit" valid:"required,stringlength(1|20)"`
-	Price            int64    `json:"price" valid:"range(0|10000000)"`
-	MaxQuantityCount int      `json:"max_quantity_count" valid:"range(0,10000)"`
-	ProductCost      int64    `json:"product_cost" valid:"range(0|10000000)"`
-	AdditionalImages []string `json:"additional_images"`
+	Name             string     `json:"name" valid:"required,stringlength(3|100)"`
+	Description      string     `json:"description" valid:"required,stringlength(3|100000)"`
+	IsPublished      bool       `json:"is_published"`
+	CategoryID       *string    `json:"category_id"`
+	GlobalCategoryID *string    `json:"global_category_id"`
+	TaxClassID       *string    `json:"tax_class_id"`
+	Image            string     `json:"image"`
+	IsShippable      bool       `json:"is_shippable"`
+	IsDigital        bool       `json:"is_digital"`
+	IsBundle         bool       `json:"is_bundle"`
+	Weight           int        `json:"weight" valid:"range(0|10000000)"`
+	Length           int        `json:"length" valid:"range(0|100000)"`
+	Width            int        `json:"width" valid:"range(0|100000)"`
+	Height           int        `json:"height" valid:"range(0|100000)"`
+	SKU              string     `json:"sku" valid:"required,stringlength(1|100)"`
+	Stock            int        `json:"stock" valid:"range(0|100000)"`
+	Unit             string     `json:"unit" valid:"required,stringlength(1|20)"`
+	Price            int64      `json:"price" valid:"range(0|10000000)"`
+	SalePrice        int64      `json:"sale_price" valid:"range(0|10000000)"`
+	SaleStartsAt     *time.Time `json:"sale_starts_at"`
+	SaleEndsAt       *time.Time `json:"sale_ends_at"`
+	MaxQuantityCount int        `json:"max_quantity_count" valid:"range(0,10000)"`
+	ProductCost      int64      `json:"product_cost" valid:"range(0|10000000)"`
+	AdditionalImages []string   `json:"additional_images"`
 }
 
 func ValidateCreateProduct(ctx echo.Context) (*ReqProductCreate, error) {
@@ -32,6 +43,9 @@ func ValidateCreateProduct(ctx echo.Context) (*ReqProductCreate, error) {
 
 	ok, err := govalidator.ValidateStruct(&pld)
 	if ok {
+		if err := validateSaleWindow(pld.Price, pld.SalePrice, pld.SaleStartsAt, pld.SaleEndsAt); err != nil {
+			return nil, err
+		}
 		return &pld, nil
 	}
 
@@ -44,22 +58,46 @@ func ValidateCreateProduct(ctx echo.Context) (*ReqProductCreate, error) {
 	return nil, &ve
 }
 
+func validateSaleWindow(price, salePrice int64, startsAt, endsAt *time.Time) error {
+	ve := errors.ValidationError{}
+
+	if salePrice > 0 && salePrice >= price {
+		ve.Add("sale_price", "must be less than price")
+		return &ve
+	}
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		ve.Add("sale_ends_at", "must be after sale_starts_at")
+		return &ve
+	}
+	return nil
+}
+
 type ReqProductUpdate struct {
-	Name                *string  `json:"name" valid:"required,stringlength(3|100)"`
-	Description         *string  `json:"description" valid:"required,stringlength(3|100000)"`
-	IsPublished         *bool    `json:"is_published"`
-	CategoryID          *string  `json:"category_id"`
-	Image               *string  `json:"image"`
-	IsShippable         *bool    `json:"is_shippable"`
-	IsDigital           *bool    `json:"is_digital"`
-	SKU                 *string  `json:"sku" valid:"required,stringlength(1|100)"`
-	Stock               *int     `json:"stock" valid:"range(0|100000)"`
-	Unit                *string  `json:"unit" valid:"required,stringlength(1|20)"`
-	Price               *int64   `json:"price" valid:"range(0|10000000)"`
-	ProductCost         *int64   `json:"product_cost" valid:"range(0|10000000)"`
-	MaxQuantityCount    *int     `json:"max_quantity_count" valid:"range(0,10000)"`
-	DigitalDownloadLink *string  `json:"digital_download_link" valid:"stringlength(1|1000000)"`
-	AdditionalImages    []string `json:"additional_images"`
+	Name                *string    `json:"name" valid:"required,stringlength(3|100)"`
+	Description         *string    `json:"description" valid:"required,stringlength(3|100000)"`
+	IsPublished         *bool      `json:"is_published"`
+	CategoryID          *string    `json:"category_id"`
+	GlobalCategoryID    *string    `json:"global_category_id"`
+	TaxClassID          *string    `json:"tax_class_id"`
+	Image               *string    `json:"image"`
+	IsShippable         *bool      `json:"is_shippable"`
+	IsDigital           *bool      `json:"is_digital"`
+	IsBundle            *bool      `json:"is_bundle"`
+	Weight              *int       `json:"weight" valid:"range(0|10000000)"`
+	Length              *int       `json:"length" valid:"range(0|100000)"`
+	Width               *int       `json:"width" valid:"range(0|100000)"`
+	Height              *int       `json:"height" valid:"range(0|100000)"`
+	SKU                 *string    `json:"sku" valid:"required,stringlength(1|100)"`
+	Stock               *int       `json:"stock" valid:"range(0|100000)"`
+	Unit                *string    `json:"unit" valid:"required,stringlength(1|20)"`
+	Price               *int64     `json:"price" valid:"range(0|10000000)"`
+	SalePrice           *int64     `json:"sale_price" valid:"range(0|10000000)"`
+	SaleStartsAt        *time.Time `json:"sale_starts_at"`
+	SaleEndsAt          *time.Time `json:"sale_ends_at"`
+	ProductCost         *int64     `json:"product_cost" valid:"range(0|10000000)"`
+	MaxQuantityCount    *int       `json:"max_quantity_count" valid:"range(0,10000)"`
+	DigitalDownloadLink *string    `json:"digital_download_link" valid:"stringlength(1|1000000)"`
+	AdditionalImages    []string   `json:"additional_images"`
 }
 
 func ValidateUpdateProduct(ctx echo.Context) (*ReqProductUpdate, error) {
@@ -84,8 +122,12 @@ func ValidateUpdateProduct(ctx echo.Context) (*ReqProductUpdate, error) {
 }
 
 type ReqAddProductAttribute struct {
-	Key   string `json:"key" valid:"required"`
-	Value string `json:"value" valid:"required"`
+	Key    string `json:"key" valid:"required"`
+	Value  string `json:"value" valid:"required"`
+	Weight *int   `json:"weight" valid:"range(0|10000000)"`
+	Length *int   `json:"length" valid:"range(0|100000)"`
+	Width  *int   `json:"width" valid:"range(0|100000)"`
+	Height *int   `json:"height" valid:"range(0|100000)"`
 }
 
 func ValidateAddProductAttribute(ctx echo.Context) (*ReqAddProductAttribute, error) {
@@ -108,3 +150,73 @@ func ValidateAddProductAttribute(ctx echo.Context) (*ReqAddProductAttribute, err
 
 	return nil, &ve
 }
+
+type ReqCreateScheduledPrice struct {
+	Price       int64     `json:"price" valid:"range(0|10000000)"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+func ValidateCreateScheduledPrice(ctx echo.Context) (*ReqCreateScheduledPrice, error) {
+	pld := ReqCreateScheduledPrice{}
+
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		if !pld.EffectiveAt.After(time.Now()) {
+			ve := errors.ValidationError{}
+			ve.Add("effective_at", "must be in future")
+			return nil, &ve
+		}
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+type ReqBundleItem struct {
+	ProductID string `json:"product_id" valid:"required"`
+	Quantity  int    `json:"quantity" valid:"required,range(1|1000)"`
+}
+
+type ReqSetBundleItems struct {
+	Items []ReqBundleItem `json:"items" valid:"required"`
+}
+
+func ValidateSetBundleItems(ctx echo.Context) (*ReqSetBundleItems, error) {
+	pld := ReqSetBundleItems{}
+
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		seen := map[string]bool{}
+		for _, v := range pld.Items {
+			if seen[v.ProductID] {
+				ve := errors.ValidationError{}
+				ve.Add("items", "duplicate product_id "+v.ProductID)
+				return nil, &ve
+			}
+			seen[v.ProductID] = true
+		}
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
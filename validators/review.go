@@ -31,3 +31,94 @@ func ValidateCreateReview(ctx echo.Context) (*ReqReviewCreate, error) {
 
 	return nil, &ve
 }
+
+type ReqProductReviewCreate struct {
+	Rating      int      `json:"rating" valid:"required,range(1|5)"`
+	Description string   `json:"description" valid:"required,stringlength(2|100000)"`
+	Images      []string `json:"images"`
+}
+
+func ValidateCreateProductReview(ctx echo.Context) (*ReqProductReviewCreate, error) {
+	pld := ReqProductReviewCreate{}
+
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		if len(pld.Images) > 5 {
+			ve := errors.ValidationError{}
+			ve.Add("images", "can't have more than 5 images")
+			return nil, &ve
+		}
+		for _, img := range pld.Images {
+			if !isUploadedImagePath(img) {
+				ve := errors.ValidationError{}
+				ve.Add("images", "must be images uploaded to /fs/")
+				return nil, &ve
+			}
+		}
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+type ReqProductReviewReply struct {
+	Reply string `json:"reply" valid:"required,stringlength(2|100000)"`
+}
+
+func ValidateProductReviewReply(ctx echo.Context) (*ReqProductReviewReply, error) {
+	pld := ReqProductReviewReply{}
+
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+type ReqProductReviewModerate struct {
+	IsHidden   *bool   `json:"is_hidden"`
+	IsFlagged  *bool   `json:"is_flagged"`
+	FlagReason *string `json:"flag_reason" valid:"stringlength(0|1000)"`
+}
+
+func ValidateProductReviewModerate(ctx echo.Context) (*ReqProductReviewModerate, error) {
+	pld := ReqProductReviewModerate{}
+
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
@@ -0,0 +1,134 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+)
+
+type ReqCreateShippingZone struct {
+	Name        string `json:"name" valid:"required,stringlength(1|100)"`
+	Description string `json:"description" valid:"stringlength(0|500)"`
+	IsPublished bool   `json:"is_published"`
+}
+
+func ValidateCreateShippingZone(ctx echo.Context) (*ReqCreateShippingZone, error) {
+	pld := ReqCreateShippingZone{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+type ReqUpdateShippingZone struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	IsPublished *bool   `json:"is_published"`
+}
+
+func ValidateUpdateShippingZone(ctx echo.Context) (*ReqUpdateShippingZone, error) {
+	pld := ReqUpdateShippingZone{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	if pld.Name != nil {
+		ok := len(*pld.Name) >= 1 && len(*pld.Name) <= 100
+		if !ok {
+			ve.Add("name", "must be between 1 to 100 characters")
+		}
+	}
+	if pld.Description != nil {
+		ok := len(*pld.Description) <= 500
+		if !ok {
+			ve.Add("description", "must be less than 500 characters")
+		}
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
+
+type ReqSetShippingZoneLocations struct {
+	LocationIDs []int64 `json:"location_ids"`
+}
+
+func ValidateSetShippingZoneLocations(ctx echo.Context) (*ReqSetShippingZoneLocations, error) {
+	pld := ReqSetShippingZoneLocations{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	seen := map[int64]bool{}
+	for _, id := range pld.LocationIDs {
+		if seen[id] {
+			ve.Add("location_ids", "must be unique")
+			break
+		}
+		seen[id] = true
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
+
+type ReqSetShippingZoneRate struct {
+	ShippingMethodID      string `json:"shipping_method_id" valid:"required"`
+	DeliveryCharge        int64  `json:"delivery_charge"`
+	PerKgRate             int64  `json:"per_kg_rate"`
+	FreeShippingThreshold int64  `json:"free_shipping_threshold"`
+}
+
+func ValidateSetShippingZoneRate(ctx echo.Context) (*ReqSetShippingZoneRate, error) {
+	pld := ReqSetShippingZoneRate{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if !ok {
+		for k, v := range govalidator.ErrorsByField(err) {
+			ve.Add(k, v)
+		}
+	}
+
+	if pld.DeliveryCharge < 0 {
+		ve.Add("delivery_charge", "can't be negative")
+	}
+	if pld.PerKgRate < 0 {
+		ve.Add("per_kg_rate", "can't be negative")
+	}
+	if pld.FreeShippingThreshold < 0 {
+		ve.Add("free_shipping_threshold", "can't be negative")
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
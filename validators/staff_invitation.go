@@ -0,0 +1,113 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"github.com/shopicano/shopicano-backend/values"
+	"time"
+)
+
+type ReqStaffInvitation struct {
+	Email        string  `json:"email" valid:"required,email"`
+	PermissionID string  `json:"permission_id" valid:"required"`
+	RoleID       *string `json:"role_id"`
+}
+
+type ReqStaffInvitationToken struct {
+	Token string `json:"token" valid:"required"`
+}
+
+func ValidateStaffInvitation(ctx echo.Context) (*ReqStaffInvitation, error) {
+	pld := ReqStaffInvitation{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		if pld.PermissionID == values.AdminGroupID || pld.PermissionID == values.ManagerGroupID {
+			return &pld, nil
+		}
+
+		ve.Add("permission_id", "is invalid")
+	}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+func ValidateStaffInvitationToken(ctx echo.Context) (*ReqStaffInvitationToken, error) {
+	pld := ReqStaffInvitationToken{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+// ValidateStaffInvitationSignUp validates the sign up of an invited staff, the email is pre-filled from the invitation
+func ValidateStaffInvitationSignUp(ctx echo.Context) (*models.User, string, error) {
+	ur := struct {
+		Token          string  `json:"token" valid:"required"`
+		Name           string  `json:"name" valid:"required,stringlength(3|100)"`
+		Email          string  `json:"email" valid:"required,email"`
+		ProfilePicture *string `json:"profile_picture"`
+		Phone          *string `json:"phone"`
+		Password       string  `json:"password" valid:"required,stringlength(8|100)"`
+		Locale         string  `json:"locale"`
+	}{}
+
+	if err := ctx.Bind(&ur); err != nil {
+		return nil, "", err
+	}
+
+	if ur.Locale == "" {
+		ur.Locale = models.DefaultLocale
+	}
+
+	ok, err := govalidator.ValidateStruct(&ur)
+	if ok && isValidLocale(ur.Locale) {
+		return &models.User{
+			ID:             utils.NewUUID(),
+			Name:           ur.Name,
+			Email:          ur.Email,
+			Password:       ur.Password,
+			Phone:          ur.Phone,
+			ProfilePicture: ur.ProfilePicture,
+			PermissionID:   values.UserGroupID,
+			Locale:         ur.Locale,
+			CreatedAt:      time.Now().UTC(),
+			UpdatedAt:      time.Now().UTC(),
+		}, ur.Token, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+	if !isValidLocale(ur.Locale) {
+		ve.Add("locale", "is invalid")
+	}
+
+	return nil, "", &ve
+}
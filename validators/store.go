@@ -87,34 +87,6 @@ func ValidateUpdateStore(ctx echo.Context) (*reqStoreUpdate, error) {
 	return nil, &ve
 }
 
-func ValidateCreateStoreStaff(ctx echo.Context) (*string, *string, error) {
-	pld := struct {
-		Email        string `json:"email" valid:"required,email"`
-		PermissionID string `json:"permission_id" valid:"required"`
-	}{}
-
-	if err := ctx.Bind(&pld); err != nil {
-		return nil, nil, err
-	}
-
-	ve := errors.ValidationError{}
-
-	ok, err := govalidator.ValidateStruct(&pld)
-	if ok {
-		if pld.PermissionID == values.AdminGroupID || pld.PermissionID == values.ManagerGroupID {
-			return &pld.Email, &pld.PermissionID, nil
-		}
-
-		ve.Add("permission_id", "is invalid")
-	}
-
-	for k, v := range govalidator.ErrorsByField(err) {
-		ve.Add(k, v)
-	}
-
-	return nil, nil, &ve
-}
-
 func ValidateUpdateStoreStaff(ctx echo.Context) (*string, error) {
 	pld := struct {
 		PermissionID string `json:"permission_id" valid:"required"`
@@ -0,0 +1,58 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"time"
+)
+
+type ReqStoreAPIKey struct {
+	Name      string             `json:"name" valid:"required,stringlength(1|100)"`
+	Scopes    []models.StorePerm `json:"scopes"`
+	ExpiresAt *time.Time         `json:"expires_at"`
+}
+
+func ValidateStoreAPIKey(ctx echo.Context) (*ReqStoreAPIKey, error) {
+	pld := ReqStoreAPIKey{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if !ok {
+		for k, v := range govalidator.ErrorsByField(err) {
+			ve.Add(k, v)
+		}
+	}
+
+	seen := map[models.StorePerm]bool{}
+	var scopes []models.StorePerm
+	for _, p := range pld.Scopes {
+		if !p.IsValid() {
+			ve.Add("scopes", "is invalid")
+			break
+		}
+		if !seen[p] {
+			seen[p] = true
+			scopes = append(scopes, p)
+		}
+	}
+	if len(pld.Scopes) == 0 {
+		ve.Add("scopes", "is required")
+	}
+	pld.Scopes = scopes
+
+	if pld.ExpiresAt != nil && !pld.ExpiresAt.After(time.Now()) {
+		ve.Add("expires_at", "must be in the future")
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
@@ -0,0 +1,64 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+)
+
+type ReqStoreRole struct {
+	Name        string             `json:"name" valid:"required,stringlength(1|100)"`
+	Permissions []models.StorePerm `json:"permissions"`
+}
+
+type ReqStoreStaffRole struct {
+	RoleID *string `json:"role_id"`
+}
+
+func ValidateStoreRole(ctx echo.Context) (*ReqStoreRole, error) {
+	pld := ReqStoreRole{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if !ok {
+		for k, v := range govalidator.ErrorsByField(err) {
+			ve.Add(k, v)
+		}
+	}
+
+	seen := map[models.StorePerm]bool{}
+	var perms []models.StorePerm
+	for _, p := range pld.Permissions {
+		if !p.IsValid() {
+			ve.Add("permissions", "is invalid")
+			break
+		}
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+	if len(pld.Permissions) == 0 {
+		ve.Add("permissions", "is required")
+	}
+	pld.Permissions = perms
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
+
+func ValidateStoreStaffRole(ctx echo.Context) (*ReqStoreStaffRole, error) {
+	pld := ReqStoreStaffRole{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
@@ -0,0 +1,62 @@
+package validators
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+)
+
+type ReqStoreShippingMethodCreate struct {
+	ReqShippingMethodCreate
+	CarrierID string `json:"carrier_id"`
+}
+
+func ValidateCreateStoreShippingMethod(ctx echo.Context) (*ReqStoreShippingMethodCreate, error) {
+	pld := ReqStoreShippingMethodCreate{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	if err := validateShippingMethod(&pld.ReqShippingMethodCreate); err != nil {
+		ve = *err
+	}
+
+	if pld.CarrierID == "" {
+		ve.Add("carrier_id", "is required")
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
+
+type ReqSetShippingMethodLocations struct {
+	LocationIDs []int64 `json:"location_ids"`
+}
+
+func ValidateSetShippingMethodLocations(ctx echo.Context) (*ReqSetShippingMethodLocations, error) {
+	pld := ReqSetShippingMethodLocations{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	seen := map[int64]bool{}
+	for _, id := range pld.LocationIDs {
+		if seen[id] {
+			ve.Add("location_ids", "must be unique")
+			break
+		}
+		seen[id] = true
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
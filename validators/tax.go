@@ -0,0 +1,172 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	vatNumberRegex = regexp.MustCompile(`^[A-Z]{2}[0-9A-Z+*]{2,13}$`)
+	// vatNumberFormats holds the formats known per country, numbers of the other countries are only
+	// checked against the generic format
+	vatNumberFormats = map[string]*regexp.Regexp{
+		"AT": regexp.MustCompile(`^ATU[0-9]{8}$`),
+		"BE": regexp.MustCompile(`^BE[01][0-9]{9}$`),
+		"BG": regexp.MustCompile(`^BG[0-9]{9,10}$`),
+		"CY": regexp.MustCompile(`^CY[0-9]{8}[A-Z]$`),
+		"CZ": regexp.MustCompile(`^CZ[0-9]{8,10}$`),
+		"DE": regexp.MustCompile(`^DE[0-9]{9}$`),
+		"DK": regexp.MustCompile(`^DK[0-9]{8}$`),
+		"EE": regexp.MustCompile(`^EE[0-9]{9}$`),
+		"ES": regexp.MustCompile(`^ES[0-9A-Z][0-9]{7}[0-9A-Z]$`),
+		"FI": regexp.MustCompile(`^FI[0-9]{8}$`),
+		"FR": regexp.MustCompile(`^FR[0-9A-HJ-NP-Z]{2}[0-9]{9}$`),
+		"GB": regexp.MustCompile(`^GB([0-9]{9}|[0-9]{12}|GD[0-9]{3}|HA[0-9]{3})$`),
+		"GR": regexp.MustCompile(`^EL[0-9]{9}$`),
+		"HR": regexp.MustCompile(`^HR[0-9]{11}$`),
+		"HU": regexp.MustCompile(`^HU[0-9]{8}$`),
+		"IE": regexp.MustCompile(`^IE([0-9]{7}[A-W][A-I]?|[0-9][A-Z+*][0-9]{5}[A-W])$`),
+		"IT": regexp.MustCompile(`^IT[0-9]{11}$`),
+		"LT": regexp.MustCompile(`^LT([0-9]{9}|[0-9]{12})$`),
+		"LU": regexp.MustCompile(`^LU[0-9]{8}$`),
+		"LV": regexp.MustCompile(`^LV[0-9]{11}$`),
+		"MT": regexp.MustCompile(`^MT[0-9]{8}$`),
+		"NL": regexp.MustCompile(`^NL[0-9]{9}B[0-9]{2}$`),
+		"PL": regexp.MustCompile(`^PL[0-9]{10}$`),
+		"PT": regexp.MustCompile(`^PT[0-9]{9}$`),
+		"RO": regexp.MustCompile(`^RO[0-9]{2,10}$`),
+		"SE": regexp.MustCompile(`^SE[0-9]{10}01$`),
+		"SI": regexp.MustCompile(`^SI[0-9]{8}$`),
+		"SK": regexp.MustCompile(`^SK[0-9]{10}$`),
+	}
+	// vatNumberPrefixes holds the countries whose VAT numbers aren't prefixed with their ISO code
+	vatNumberPrefixes = map[string]string{
+		"GR": "EL",
+	}
+)
+
+type ReqTaxClass struct {
+	Name        string `json:"name" valid:"required,stringlength(1|100)"`
+	Description string `json:"description" valid:"stringlength(0|500)"`
+}
+
+func ValidateTaxClass(ctx echo.Context) (*ReqTaxClass, error) {
+	pld := ReqTaxClass{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+type ReqTaxRate struct {
+	TaxClassID  *string `json:"tax_class_id"`
+	LocationID  int64   `json:"location_id" valid:"required"`
+	Name        string  `json:"name" valid:"required,stringlength(1|100)"`
+	Rate        float64 `json:"rate"`
+	Priority    int     `json:"priority" valid:"range(0|100)"`
+	IsCompound  bool    `json:"is_compound"`
+	IsInclusive bool    `json:"is_inclusive"`
+}
+
+func ValidateTaxRate(ctx echo.Context) (*ReqTaxRate, error) {
+	pld := ReqTaxRate{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if !ok {
+		for k, v := range govalidator.ErrorsByField(err) {
+			ve.Add(k, v)
+		}
+	}
+
+	if pld.Rate < 0 || pld.Rate > 100 {
+		ve.Add("rate", "must be between 0 to 100")
+	}
+	if pld.TaxClassID != nil && *pld.TaxClassID == "" {
+		pld.TaxClassID = nil
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}
+
+type ReqTaxExemption struct {
+	CountryID int64  `json:"country_id" valid:"required"`
+	VatNumber string `json:"vat_number" valid:"required,stringlength(4|20)"`
+}
+
+func ValidateTaxExemption(ctx echo.Context) (*ReqTaxExemption, error) {
+	pld := ReqTaxExemption{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	pld.VatNumber = NormalizeVatNumber(pld.VatNumber)
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if ok {
+		return &pld, nil
+	}
+
+	ve := errors.ValidationError{}
+
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+
+	return nil, &ve
+}
+
+type ReqTaxExemptionVerification struct {
+	IsVerified bool `json:"is_verified"`
+}
+
+func ValidateTaxExemptionVerification(ctx echo.Context) (*ReqTaxExemptionVerification, error) {
+	pld := ReqTaxExemptionVerification{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
+
+// NormalizeVatNumber uppercases the VAT number and drops the separators people write it with
+func NormalizeVatNumber(vatNumber string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", ".", "", "-", "").Replace(vatNumber))
+}
+
+// IsValidVatNumber reports whether the normalized VAT number is formatted as the ones issued by the country
+func IsValidVatNumber(countryISO, vatNumber string) bool {
+	prefix, ok := vatNumberPrefixes[countryISO]
+	if !ok {
+		prefix = countryISO
+	}
+	if len(prefix) != 2 || !strings.HasPrefix(vatNumber, prefix) || !vatNumberRegex.MatchString(vatNumber) {
+		return false
+	}
+	if f, ok := vatNumberFormats[countryISO]; ok {
+		return f.MatchString(vatNumber)
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package validators
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+)
+
+type ReqTwoFactorCode struct {
+	Code string `json:"code" valid:"required"`
+}
+
+// ReqTwoFactorReauth confirms a sensitive change with the password and a current code
+type ReqTwoFactorReauth struct {
+	Password string `json:"password" valid:"required"`
+	Code     string `json:"code" valid:"required"`
+}
+
+type ReqTwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token" valid:"required"`
+	Code           string `json:"code" valid:"required"`
+}
+
+func validateTwoFactor(ctx echo.Context, pld interface{}) error {
+	if err := ctx.Bind(pld); err != nil {
+		return err
+	}
+
+	ok, err := govalidator.ValidateStruct(pld)
+	if ok {
+		return nil
+	}
+
+	ve := errors.ValidationError{}
+	for k, v := range govalidator.ErrorsByField(err) {
+		ve.Add(k, v)
+	}
+	return &ve
+}
+
+func ValidateTwoFactorCode(ctx echo.Context) (*ReqTwoFactorCode, error) {
+	pld := ReqTwoFactorCode{}
+	if err := validateTwoFactor(ctx, &pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
+
+func ValidateTwoFactorReauth(ctx echo.Context) (*ReqTwoFactorReauth, error) {
+	pld := ReqTwoFactorReauth{}
+	if err := validateTwoFactor(ctx, &pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
+
+func ValidateTwoFactorLogin(ctx echo.Context) (*ReqTwoFactorLogin, error) {
+	pld := ReqTwoFactorLogin{}
+	if err := validateTwoFactor(ctx, &pld); err != nil {
+		return nil, err
+	}
+	return &pld, nil
+}
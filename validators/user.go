@@ -45,14 +45,19 @@ func ValidateRegister(ctx echo.Context) (*models.User, error) {
 		ProfilePicture *string `json:"profile_picture"`
 		Phone          *string `json:"phone"`
 		Password       string  `json:"password" valid:"required,stringlength(8|100)"`
+		Locale         string  `json:"locale"`
 	}{}
 
 	if err := ctx.Bind(&ur); err != nil {
 		return nil, err
 	}
 
+	if ur.Locale == "" {
+		ur.Locale = models.DefaultLocale
+	}
+
 	ok, err := govalidator.ValidateStruct(&ur)
-	if ok {
+	if ok && isValidLocale(ur.Locale) {
 		return &models.User{
 			ID:             utils.NewUUID(),
 			Name:           ur.Name,
@@ -62,6 +67,7 @@ func ValidateRegister(ctx echo.Context) (*models.User, error) {
 			ProfilePicture: ur.ProfilePicture,
 			Status:         models.UserRegistered,
 			PermissionID:   values.UserGroupID,
+			Locale:         ur.Locale,
 			CreatedAt:      time.Now().UTC(),
 			UpdatedAt:      time.Now().UTC(),
 		}, nil
@@ -72,6 +78,9 @@ func ValidateRegister(ctx echo.Context) (*models.User, error) {
 	for k, v := range govalidator.ErrorsByField(err) {
 		ve.Add(k, v)
 	}
+	if !isValidLocale(ur.Locale) {
+		ve.Add("locale", "is invalid")
+	}
 
 	return nil, &ve
 }
@@ -83,6 +92,7 @@ type reqUserUpdate struct {
 	CurrentPassword  *string `json:"current_password"`
 	NewPassword      *string `json:"new_password"`
 	NewPasswordAgain *string `json:"new_password_repeat"`
+	Locale           *string `json:"locale"`
 }
 
 func ValidateUserUpdate(ctx echo.Context) (*reqUserUpdate, error) {
@@ -100,6 +110,10 @@ func ValidateUserUpdate(ctx echo.Context) (*reqUserUpdate, error) {
 		}
 	}
 
+	if body.Locale != nil && !isValidLocale(*body.Locale) {
+		ve.Add("locale", "is invalid")
+	}
+
 	if len(ve) > 0 {
 		return nil, &ve
 	}
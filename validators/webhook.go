@@ -0,0 +1,64 @@
+package validators
+
+import (
+	"fmt"
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"github.com/shopicano/shopicano-backend/errors"
+	"github.com/shopicano/shopicano-backend/models"
+	"github.com/shopicano/shopicano-backend/utils"
+	"net/url"
+)
+
+type ReqWebhook struct {
+	URL      string                `json:"url" valid:"required,url"`
+	Events   []models.WebhookEvent `json:"events"`
+	IsActive bool                  `json:"is_active"`
+}
+
+func ValidateWebhook(ctx echo.Context) (*ReqWebhook, error) {
+	pld := ReqWebhook{}
+	if err := ctx.Bind(&pld); err != nil {
+		return nil, err
+	}
+
+	ve := errors.ValidationError{}
+
+	ok, err := govalidator.ValidateStruct(&pld)
+	if !ok {
+		for k, v := range govalidator.ErrorsByField(err) {
+			ve.Add(k, v)
+		}
+	}
+
+	if u, err := url.Parse(pld.URL); err == nil {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			ve.Add("url", "must be a http or https url")
+		} else if err := utils.CheckPublicHost(u.Hostname()); err != nil {
+			// The deliveries are checked again when connecting, the host may resolve differently by then
+			ve.Add("url", "must resolve to a public address")
+		}
+	}
+
+	if len(pld.Events) == 0 {
+		ve.Add("events", "is required")
+	}
+
+	seen := map[models.WebhookEvent]bool{}
+	for i, e := range pld.Events {
+		if !e.IsValid() {
+			ve.Add(fmt.Sprintf("events[%d]", i), "is invalid")
+			continue
+		}
+		if seen[e] {
+			ve.Add(fmt.Sprintf("events[%d]", i), "must be unique")
+		}
+		seen[e] = true
+	}
+
+	if len(ve) == 0 {
+		return &pld, nil
+	}
+
+	return nil, &ve
+}